@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	"github.com/spf13/cobra"
+)
+
+type CompactArgs struct {
+	DryRun          bool
+	OlderThanDays   int
+	DropSubscribers []string
+	Yes             bool
+}
+
+func newAdminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Maintenance commands for the droner daemon",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newAdminCompactCmd())
+	return cmd
+}
+
+func newAdminCompactCmd() *cobra.Command {
+	args := CompactArgs{}
+	cmd := &cobra.Command{
+		Use:   "compact",
+		Short: "Remove events of long-deleted sessions and their unlinked pull requests",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if args.OlderThanDays < 0 {
+				return errors.New("--older-than-days must not be negative")
+			}
+			client := sdk.NewClient()
+			if err := cliutil.EnsureDaemonRunning(client); err != nil {
+				return err
+			}
+			if !args.DryRun && !args.Yes {
+				if !isInteractiveTerminal() {
+					return errors.New("refusing to compact the event log without --dangerously-skip-confirmation in non-interactive mode")
+				}
+				confirmed, err := confirmAction("This permanently removes events of deleted sessions. Continue? [y/N]: ")
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Aborted.")
+					return nil
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondLong)
+			defer cancel()
+			response, err := client.CompactEventLog(ctx, schemas.AdminCompactRequest{DryRun: args.DryRun, OlderThanDays: args.OlderThanDays, DropSubscribers: args.DropSubscribers})
+			if err != nil {
+				return err
+			}
+			return printResult(cmd, response, func() { printCompactReport(os.Stdout, response) })
+		},
+	}
+
+	cmd.Flags().BoolVar(&args.DryRun, "dry-run", false, "report what would be removed without changing anything")
+	cmd.Flags().IntVar(&args.OlderThanDays, "older-than-days", 0, "only compact sessions deleted more than this many days ago (defaults to retention.deletedSessionDays)")
+	cmd.Flags().StringSliceVar(&args.DropSubscribers, "drop-subscriber", nil, "forget the checkpoint of a subscriber that no longer runs so it stops pinning the horizon (repeatable)")
+	cmd.Flags().BoolVar(&args.Yes, "dangerously-skip-confirmation", false, "skip confirmation prompt")
+	return cmd
+}
+
+func printCompactReport(out io.Writer, response *schemas.AdminCompactResponse) {
+	if response.DryRun {
+		fmt.Fprintln(out, "dry run: nothing was removed")
+	}
+	fmt.Fprintf(out, "cutoff: %s\n", response.Cutoff.Format(time.RFC3339))
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "topic\tlatest\thorizon\tpinned by")
+	for _, topic := range response.Topics {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s\n", topic.Topic, topic.Latest, topic.Horizon, pinnedBy(topic))
+	}
+	writer.Flush()
+	for _, topic := range response.Topics {
+		for _, checkpoint := range topic.Dropped {
+			fmt.Fprintf(out, "dropped checkpoint: %s %s@%d (last updated %s)\n", topic.Topic, checkpoint.Subscriber, checkpoint.Sequence, checkpoint.UpdatedAt.Format(time.RFC3339))
+		}
+	}
+
+	if len(response.Streams) == 0 {
+		fmt.Fprintln(out, "No streams to compact.")
+		return
+	}
+	fmt.Fprintln(out)
+	writer = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "action\ttopic\tstream\tevents\treason")
+	for _, stream := range response.Streams {
+		reason := stream.Reason
+		if stream.Detail != "" {
+			reason += " (" + stream.Detail + ")"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\n", stream.Action, stream.Topic, stream.StreamID, stream.Events, reason)
+	}
+	writer.Flush()
+
+	if response.DryRun {
+		fmt.Fprintf(out, "events that would be removed: %d\n", response.EventsRemoved)
+		return
+	}
+	fmt.Fprintf(out, "events removed: %d\n", response.EventsRemoved)
+}
+
+// pinnedBy names the subscriber holding the horizon back, with the time it
+// last checkpointed so stale subscribers stand out.
+func pinnedBy(topic schemas.AdminCompactTopic) string {
+	for _, checkpoint := range topic.Checkpoints {
+		if string(checkpoint.Subscriber) == topic.PinnedBy {
+			return fmt.Sprintf("%s@%d (updated %s)", checkpoint.Subscriber, checkpoint.Sequence, checkpoint.UpdatedAt.Format(time.RFC3339))
+		}
+	}
+	return "-"
+}
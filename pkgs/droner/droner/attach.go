@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
+	"github.com/Oudwins/droner/pkgs/droner/internals/env"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	"github.com/Oudwins/droner/pkgs/droner/tui"
+	"github.com/spf13/cobra"
+)
+
+const tmuxBindingsFile = "tmux.conf"
+
+var (
+	attachTmuxSession  = tui.AttachSession
+	pickSession        = tui.PickSession
+	currentTmuxSession = tmuxSessionName
+)
+
+// attachStatuses are the sessions that have a tmux session to attach to.
+// Completing a session keeps its worktree but kills its tmux session.
+var attachStatuses = []sdk.SessionStatus{
+	sdk.SessionStatusActiveIdle,
+	sdk.SessionStatusActiveBusy,
+}
+
+func newAttachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "attach [branch]",
+		Short: "Attach to a session's tmux session, picking one when no branch is given",
+		Long:  "Attach to a session's tmux session. Inside tmux the current client switches to it. Without a branch a fuzzy picker lists the sessions.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, inputs []string) error {
+			client := sdk.NewClient()
+			if err := cliutil.EnsureDaemonRunning(client); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondLong)
+			defer cancel()
+
+			var session schemas.SessionListItem
+			if len(inputs) > 0 {
+				found, err := resolveAttachableSession(ctx, client, schemas.NewSBranch(strings.TrimSpace(inputs[0])))
+				if err != nil {
+					return err
+				}
+				session = found
+			} else {
+				if !isInteractiveTerminal() {
+					return errors.New("a branch is required outside an interactive terminal")
+				}
+				response, err := client.ListSessionsWithParams(ctx, attachStatuses, 0, "", "")
+				if err != nil {
+					return err
+				}
+				if len(response.Sessions) == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "No sessions to attach to.")
+					return nil
+				}
+				picked, err := pickSession(response.Sessions)
+				if err != nil || picked == nil {
+					return err
+				}
+				session = *picked
+			}
+			return attachSession(session)
+		},
+	}
+}
+
+func newNavigateSessionCmd(use string, short string, navigate func(*sdk.Client, context.Context, string) (*schemas.SessionListResponse, error)) *cobra.Command {
+	var from string
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client := sdk.NewClient()
+			if err := cliutil.EnsureDaemonRunning(client); err != nil {
+				return err
+			}
+			if from == "" {
+				from = currentTmuxSession()
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondShort)
+			defer cancel()
+			response, err := navigate(client, ctx, from)
+			if err != nil {
+				return err
+			}
+			if len(response.Sessions) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No idle sessions.")
+				return nil
+			}
+			return attachSession(response.Sessions[0])
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "tmux session to move from (defaults to the current one)")
+	return cmd
+}
+
+func newNextCmd() *cobra.Command {
+	return newNavigateSessionCmd("next", "Switch to the next idle session", (*sdk.Client).NextSession)
+}
+
+func newPrevCmd() *cobra.Command {
+	return newNavigateSessionCmd("prev", "Switch to the previous idle session", (*sdk.Client).PrevSession)
+}
+
+// resolveAttachableSession finds the active session of branch, leaving out
+// completed sessions that share it.
+func resolveAttachableSession(ctx context.Context, client *sdk.Client, branch schemas.SBranch) (schemas.SessionListItem, error) {
+	response, err := client.ListSessionsWithParams(ctx, attachStatuses, 0, "", "")
+	if err != nil {
+		return schemas.SessionListItem{}, err
+	}
+	for _, session := range response.Sessions {
+		if session.Branch != nil && *session.Branch == branch {
+			return session, nil
+		}
+	}
+	return schemas.SessionListItem{}, fmt.Errorf("no active session for branch %q", branch)
+}
+
+func attachSession(session schemas.SessionListItem) error {
+	if session.TmuxSession == "" {
+		return fmt.Errorf("session %s has no tmux session yet (status=%s)", session.ID, session.State)
+	}
+	return attachTmuxSession(session.TmuxSession)
+}
+
+// tmuxSessionName returns the tmux session droner runs in, if any.
+func tmuxSessionName() string {
+	if os.Getenv("TMUX") == "" {
+		return ""
+	}
+	output, err := exec.Command("tmux", "display-message", "-p", "#S").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+type TmuxBindingsArgs struct {
+	NextKey  string
+	PrevKey  string
+	PickKey  string
+	TmuxConf string
+	Print    bool
+}
+
+func newTmuxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tmux",
+		Short: "Integrate droner with tmux",
+	}
+	cmd.AddCommand(newTmuxInstallBindingsCmd())
+	return cmd
+}
+
+func newTmuxInstallBindingsCmd() *cobra.Command {
+	args := TmuxBindingsArgs{}
+	cmd := &cobra.Command{
+		Use:   "install-bindings",
+		Short: "Add tmux key bindings that move between sessions",
+		Long:  "Write the droner key bindings to $DRONERD_DATA_DIR/tmux.conf and source that file from your tmux config. Running it again rewrites the bindings without adding a second source line.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			bindings := tmuxBindings(args)
+			if args.Print {
+				_, err := io.WriteString(cmd.OutOrStdout(), bindings)
+				return err
+			}
+			tmuxConf := args.TmuxConf
+			if tmuxConf == "" {
+				tmuxConf = defaultTmuxConfPath()
+			}
+			bindingsPath := filepath.Join(filepath.Clean(env.Get().DATA_DIR), tmuxBindingsFile)
+			added, err := installTmuxBindings(bindingsPath, tmuxConf, bindings)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "bindings: %s\n", bindingsPath)
+			if added {
+				fmt.Fprintf(out, "sourced from: %s\n", tmuxConf)
+			} else {
+				fmt.Fprintf(out, "already sourced from: %s\n", tmuxConf)
+			}
+			fmt.Fprintf(out, "reload with: tmux source-file %s\n", tmuxConf)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&args.NextKey, "next-key", "N", "key after the prefix that switches to the next session")
+	cmd.Flags().StringVar(&args.PrevKey, "prev-key", "P", "key after the prefix that switches to the previous session")
+	cmd.Flags().StringVar(&args.PickKey, "pick-key", "A", "key after the prefix that opens the session picker in a popup")
+	cmd.Flags().StringVar(&args.TmuxConf, "tmux-conf", "", "tmux config to source the bindings from (defaults to ~/.tmux.conf or ~/.config/tmux/tmux.conf)")
+	cmd.Flags().BoolVar(&args.Print, "print", false, "print the bindings instead of installing them")
+	return cmd
+}
+
+func tmuxBindings(args TmuxBindingsArgs) string {
+	lines := []string{
+		"# Installed by `droner tmux install-bindings`; rerun it to change the keys.",
+		fmt.Sprintf("bind-key %s run-shell -b \"droner next --from '#{session_name}'\"", args.NextKey),
+		fmt.Sprintf("bind-key %s run-shell -b \"droner prev --from '#{session_name}'\"", args.PrevKey),
+		fmt.Sprintf("bind-key %s display-popup -E -w 80%% -h 60%% \"droner attach\"", args.PickKey),
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// installTmuxBindings writes bindings to bindingsPath and makes tmuxConf
+// source it. It reports whether the source line had to be added.
+func installTmuxBindings(bindingsPath string, tmuxConf string, bindings string) (bool, error) {
+	if err := os.MkdirAll(filepath.Dir(bindingsPath), 0o755); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(bindingsPath, []byte(bindings), 0o644); err != nil {
+		return false, err
+	}
+
+	sourceLine := fmt.Sprintf("source-file %q", bindingsPath)
+	contents, err := os.ReadFile(tmuxConf)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		if strings.TrimSpace(line) == sourceLine {
+			return false, nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(tmuxConf), 0o755); err != nil {
+		return false, err
+	}
+	file, err := os.OpenFile(tmuxConf, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	prefix := ""
+	if len(contents) > 0 && !strings.HasSuffix(string(contents), "\n") {
+		prefix = "\n"
+	}
+	if _, err := fmt.Fprintf(file, "%s\n# droner session bindings\n%s\n", prefix, sourceLine); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// defaultTmuxConfPath prefers an existing XDG tmux config over ~/.tmux.conf.
+func defaultTmuxConfPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".tmux.conf"
+	}
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		configDir = filepath.Join(home, ".config")
+	}
+	if xdgConf := filepath.Join(configDir, "tmux", "tmux.conf"); fileExists(xdgConf) {
+		return xdgConf
+	}
+	return filepath.Join(home, ".tmux.conf")
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
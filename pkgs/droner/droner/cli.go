@@ -12,7 +12,6 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
-	"text/tabwriter"
 
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/eventdebug"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/server"
@@ -74,8 +73,7 @@ func newRootCmd() *cobra.Command {
 		Short: "Droner CLI",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if showVersion {
-				printVersionInfo(cmd)
-				return nil
+				return printVersionInfo(cmd)
 			}
 			if len(args) > 0 {
 				return cmd.Usage()
@@ -85,16 +83,25 @@ func newRootCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&showVersion, "version", false, "print CLI/server versions and exit")
+	addOutputFlag(cmd)
 
 	cmd.AddCommand(
 		newServeCmd(),
 		newDebuggerCmd(),
 		newTUICmd(),
+		newDashboardCmd(),
 		newNewCmd(),
 		newDelCmd(),
 		newCompleteCmd(),
 		newNukeCmd(),
 		newSessionsCmd(),
+		newPRCmd(),
+		newAttachCmd(),
+		newNextCmd(),
+		newPrevCmd(),
+		newTmuxCmd(),
+		newDoctorCmd(),
+		newAdminCmd(),
 	)
 
 	return cmd
@@ -102,40 +109,77 @@ func newRootCmd() *cobra.Command {
 
 var runEventDebugServer = eventdebug.Run
 
+var runTUI = tui.Run
+
 func newTUICmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "tui [repo[@branch]|@branch]",
 		Short: "Open the Droner TUI",
+		Long:  "Open the Droner TUI. Outside a repo, or with --pick, it first asks for a project under config.projects.parentPaths and a branch.",
 		Args:  cobra.MaximumNArgs(1),
 		RunE:  runTUICmd,
 	}
+	cmd.Flags().BoolP("pick", "p", false, "Pick the project and branch even inside a repo")
+	return cmd
 }
 
 func runTUICmd(cmd *cobra.Command, inputs []string) error {
 	if !isInteractiveTerminal() {
 		return cmd.Usage()
 	}
+	client := sdk.NewClient()
+	pick, _ := cmd.Flags().GetBool("pick")
+	if pick && len(inputs) == 0 {
+		return runTUI(client, "", "")
+	}
 	target, err := resolveSessionTargetFromInputs(inputs)
 	if err != nil {
+		if len(inputs) > 0 {
+			return err
+		}
+		// Outside a repo the TUI starts with the project picker.
+		return runTUI(client, "", "")
+	}
+	return runTUI(client, target.RepoPath, target.Branch)
+}
+
+func newDashboardCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dashboard [repo]",
+		Short: "Show live sessions and act on them",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runDashboardCmd,
+	}
+}
+
+func runDashboardCmd(cmd *cobra.Command, inputs []string) error {
+	if !isInteractiveTerminal() {
+		return cmd.Usage()
+	}
+	// The repo only decides where new sessions go, so the dashboard also
+	// opens outside a repo.
+	repoPath := ""
+	target, err := resolveSessionTargetFromInputs(inputs)
+	if err == nil {
+		repoPath = target.RepoPath
+	} else if len(inputs) > 0 {
 		return err
 	}
-	client := sdk.NewClient()
-	return tui.Run(client, target.RepoPath, target.Branch)
+	return tui.RunDashboard(sdk.NewClient(), repoPath)
 }
 
-func printVersionInfo(cmd *cobra.Command) {
-	out := cmd.OutOrStdout()
-	_, _ = fmt.Fprintf(out, "cli: %s\n", strings.TrimSpace(version.Version()))
+func printVersionInfo(cmd *cobra.Command) error {
+	info := versionInfo{CLI: strings.TrimSpace(version.Version())}
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeouts.Probe)
 	defer cancel()
 
-	serverVersion, err := sdk.NewClient().Version(ctx)
-	if err != nil || strings.TrimSpace(serverVersion) == "" {
-		_, _ = fmt.Fprintln(out, "server: (not running)")
-		return
+	if serverVersion, err := sdk.NewClient().Version(ctx); err == nil {
+		info.Server = strings.TrimSpace(serverVersion)
 	}
-	_, _ = fmt.Fprintf(out, "server: %s\n", strings.TrimSpace(serverVersion))
+	return printResult(cmd, info, func() {
+		_, _ = io.WriteString(cmd.OutOrStdout(), formatVersionInfo(info))
+	})
 }
 
 func newCompleteCmd() *cobra.Command {
@@ -159,8 +203,7 @@ func newCompleteCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			printOperationSummary(response)
-			return nil
+			return printResult(cmd, response, func() { printOperationSummary(response) })
 		},
 	}
 	return cmd
@@ -211,7 +254,7 @@ func newNewCmd() *cobra.Command {
 
 			includeAgentConfig := cmd.Flags().Changed("model") || cmd.Flags().Changed("agent") || cmd.Flags().Changed("prompt") || args.Model != "" || args.AgentName != "" || args.Prompt != ""
 
-			return runCreateSession(resolveOptionalInput(inputs), &args, includeAgentConfig)
+			return runCreateSession(cmd, resolveOptionalInput(inputs), &args, includeAgentConfig)
 		},
 	}
 
@@ -242,8 +285,7 @@ func newDelCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			printOperationSummary(response)
-			return nil
+			return printResult(cmd, response, func() { printOperationSummary(response) })
 		},
 	}
 	return cmd
@@ -279,8 +321,7 @@ func newNukeCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			printOperationSummary(response)
-			return nil
+			return printResult(cmd, response, func() { printOperationSummary(response) })
 		},
 	}
 
@@ -288,55 +329,6 @@ func newNukeCmd() *cobra.Command {
 	return cmd
 }
 
-func newSessionsCmd() *cobra.Command {
-	var all bool
-	cmd := &cobra.Command{
-		Use:   "sessions",
-		Short: "List sessions (defaults to active)",
-		Args:  cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, _ []string) error {
-			client := sdk.NewClient()
-			if err := cliutil.EnsureDaemonRunning(client); err != nil {
-				return err
-			}
-			ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondShort)
-			defer cancel()
-			var response *schemas.SessionListResponse
-			var err error
-			if all {
-				// Explicitly request any status: pass nil statuses and no cursor
-				response, err = client.ListSessionsWithParams(ctx, nil, 0, "", "")
-			} else {
-				response, err = client.ListSessionsWithParams(ctx, []sdk.SessionStatus{sdk.SessionStatusQueued, sdk.SessionStatusActiveIdle, sdk.SessionStatusActiveBusy}, 0, "", "")
-			}
-			if err != nil {
-				return err
-			}
-			if len(response.Sessions) == 0 {
-				if all {
-					fmt.Println("No sessions.")
-				} else {
-					fmt.Println("No active sessions.")
-				}
-				return nil
-			}
-			writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintln(writer, "id\trepo\tremoteUrl\tbranch\tstate")
-			for _, session := range response.Sessions {
-				branch := ""
-				if session.Branch != nil {
-					branch = session.Branch.String()
-				}
-				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", session.ID, session.Repo, session.RemoteURL, branch, session.State)
-			}
-			writer.Flush()
-			return nil
-		},
-	}
-	cmd.Flags().BoolVar(&all, "all", false, "list last 100 sessions of any status (no status filter). By default lists active sessions")
-	return cmd
-}
-
 func validateNewArgs(payload *NewArgs) error {
 	if issues := newArgsSchema.Validate(payload); len(issues) > 0 {
 		return fmt.Errorf("invalid arguments:\n%s", z.Issues.Prettify(issues))
@@ -351,7 +343,7 @@ func validateDelArgs(payload *DelArgs) error {
 	return nil
 }
 
-func runCreateSession(locator string, args *NewArgs, includeAgentConfig bool) error {
+func runCreateSession(cmd *cobra.Command, locator string, args *NewArgs, includeAgentConfig bool) error {
 	client := sdk.NewClient()
 	target, err := cliutil.ResolveSessionTarget(locator)
 	if err != nil {
@@ -378,8 +370,7 @@ func runCreateSession(locator string, args *NewArgs, includeAgentConfig bool) er
 	if err != nil {
 		return err
 	}
-	cliutil.PrintSessionCreated(response)
-	return nil
+	return printResult(cmd, response, func() { cliutil.PrintSessionCreated(response) })
 }
 
 func resolveSessionTargetFromInputs(inputs []string) (cliutil.SessionTarget, error) {
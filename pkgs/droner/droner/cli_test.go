@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -240,6 +242,477 @@ func TestCLIDebuggerCommand(t *testing.T) {
 	}
 }
 
+func TestCLIAdminCompactDryRun(t *testing.T) {
+	var received schemas.AdminCompactRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/version":
+			_, _ = w.Write([]byte("test-version"))
+		case "/admin/compact":
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Fatalf("decode compact request: %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schemas.AdminCompactResponse{
+				DryRun: true,
+				Topics: []schemas.AdminCompactTopic{{
+					Topic:       "sessions",
+					Latest:      12,
+					Horizon:     10,
+					PinnedBy:    "hooks",
+					Checkpoints: []schemas.AdminCompactCheckpoint{{Subscriber: "projection", Sequence: 12}, {Subscriber: "hooks", Sequence: 10}},
+					Dropped:     []schemas.AdminCompactCheckpoint{{Subscriber: "webhooks", Sequence: 2}},
+				}},
+				Streams:       []schemas.AdminCompactStream{{Topic: "sessions", StreamID: "stream-1", Reason: "session feature deleted", Events: 4, Action: "purge"}},
+				EventsRemoved: 4,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCLIEnv(t, server.URL)
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"admin", "compact", "--dry-run", "--older-than-days", "7", "--drop-subscriber", "webhooks"})
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !received.DryRun || received.OlderThanDays != 7 || len(received.DropSubscribers) != 1 || received.DropSubscribers[0] != "webhooks" {
+		t.Fatalf("unexpected compact request: %+v", received)
+	}
+	for _, want := range []string{"dry run: nothing was removed", "pinned by", "hooks@10", "dropped checkpoint: sessions webhooks@2", "stream-1", "events that would be removed: 4"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got %q", want, output)
+		}
+	}
+}
+
+func TestCLIPRResolvesBranchAndOpensPullRequest(t *testing.T) {
+	var received schemas.SessionPullRequestRequest
+	requestedPath := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte("test-version"))
+		case r.Method == http.MethodGet && r.URL.Path == "/sessions":
+			done := schemas.NewSBranch("feature/login")
+			active := schemas.NewSBranch("feature/login")
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schemas.SessionListResponse{Sessions: []schemas.SessionListItem{
+				{ID: "old", Branch: &done, State: schemas.SessionPublicStateCompleted},
+				{ID: "current", Branch: &active, State: schemas.SessionPublicStateActiveIdle},
+			}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pr"):
+			requestedPath = r.URL.Path
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Fatalf("decode pr request: %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(schemas.SessionPullRequestResponse{ID: "current", Branch: "feature/login", Number: 5, URL: "https://github.com/owner/repo/pull/5", BaseRef: "main", Draft: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCLIEnv(t, server.URL)
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"pr", "feature.login", "--draft", "--body-from-agent"})
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if requestedPath != "/sessions/current/pr" {
+		t.Fatalf("unexpected pr path: %q", requestedPath)
+	}
+	if !received.Draft || !received.BodyFromAgent || received.Title != "" {
+		t.Fatalf("unexpected pr request: %+v", received)
+	}
+	for _, want := range []string{"pull request: #5", "https://github.com/owner/repo/pull/5", "feature/login -> main"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got %q", want, output)
+		}
+	}
+}
+
+func TestCLISessionsPaginatesFiltersAndPrintsJSON(t *testing.T) {
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte("test-version"))
+		case r.Method == http.MethodGet && r.URL.Path == "/sessions":
+			queries = append(queries, r.URL.RawQuery)
+			offset := 0
+			if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+				offset = sessionsPageSize
+			}
+			page := schemas.SessionListResponse{}
+			for i := offset; i < offset+sessionsPageSize && i < 150; i++ {
+				repo := "api"
+				if i%2 == 1 {
+					repo = "web"
+				}
+				branch := schemas.NewSBranch(fmt.Sprintf("feature/%d", i))
+				page.Sessions = append(page.Sessions, schemas.SessionListItem{ID: fmt.Sprintf("s%03d", i), Repo: repo, Branch: &branch, State: schemas.SessionPublicStateCompleted})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(page)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCLIEnv(t, server.URL)
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"sessions", "--status", "completed", "--repo", "web", "--filter", "FEATURE/1", "--limit", "0", "-o", "json"})
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(queries) != 2 || queries[0] != "status=completed&limit=100" || queries[1] != "status=completed&limit=100&cursor=s099&direction=after" {
+		t.Fatalf("unexpected session queries: %v", queries)
+	}
+	var response schemas.SessionListResponse
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		t.Fatalf("decode output %q: %v", output, err)
+	}
+	ids := []string{}
+	for _, session := range response.Sessions {
+		ids = append(ids, session.ID)
+	}
+	// Odd sessions are in web, and only some of those branches contain "feature/1".
+	want := "s001 s011 s013 s015 s017 s019 s101 s103 s105 s107 s109 s111 s113 s115 s117 s119 s121 s123 s125 s127 s129 s131 s133 s135 s137 s139 s141 s143 s145 s147 s149"
+	if strings.Join(ids, " ") != want {
+		t.Fatalf("unexpected sessions: %v", ids)
+	}
+
+	if err := executeCLI([]string{"sessions", "--status", "running"}); err == nil || !strings.Contains(err.Error(), `unknown status "running"`) {
+		t.Fatalf("expected unknown status error, got %v", err)
+	}
+	if err := executeCLI([]string{"sessions", "-o", "xml"}); err == nil || !strings.Contains(err.Error(), `unknown output format "xml"`) {
+		t.Fatalf("expected unknown output format error, got %v", err)
+	}
+}
+
+func TestCLIDeletePrintsTaskResponseAsYAML(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte("test-version"))
+		case r.Method == http.MethodDelete && r.URL.Path == "/sessions":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(schemas.TaskResponse{TaskID: "task-1", Type: "session_delete", Status: schemas.TaskStatusPending, Result: &schemas.TaskResult{Branch: "feature/login"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCLIEnv(t, server.URL)
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"del", "feature/login", "--output", "yaml"})
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	for _, want := range []string{"task_id: task-1\n", "type: session_delete\n", "result:\n  branch: feature/login\n"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got %q", want, output)
+		}
+	}
+}
+
+func stubAttach(t *testing.T) *[]string {
+	attached := []string{}
+	origAttach := attachTmuxSession
+	origCurrent := currentTmuxSession
+	attachTmuxSession = func(tmuxSession string) error {
+		attached = append(attached, tmuxSession)
+		return nil
+	}
+	currentTmuxSession = func() string { return "" }
+	t.Cleanup(func() {
+		attachTmuxSession = origAttach
+		currentTmuxSession = origCurrent
+	})
+	return &attached
+}
+
+func TestCLIAttachResolvesBranchToTmuxSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte("test-version"))
+		case r.Method == http.MethodGet && r.URL.Path == "/sessions":
+			branch := schemas.NewSBranch("feature/login")
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schemas.SessionListResponse{Sessions: []schemas.SessionListItem{
+				{ID: "current", Branch: &branch, TmuxSession: "repo#feature/login", State: schemas.SessionPublicStateActiveBusy},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCLIEnv(t, server.URL)
+	attached := stubAttach(t)
+
+	if _, err := captureOutput(t, func() error {
+		return executeCLI([]string{"attach", "feature/login"})
+	}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(*attached) != 1 || (*attached)[0] != "repo#feature/login" {
+		t.Fatalf("unexpected attach calls: %v", *attached)
+	}
+}
+
+// newAttachTestServer lists sessions honoring the status filter, the way the
+// daemon does.
+func newAttachTestServer(t *testing.T, sessions []schemas.SessionListItem) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte("test-version"))
+		case r.Method == http.MethodGet && r.URL.Path == "/sessions":
+			statuses := r.URL.Query()["status"]
+			listed := []schemas.SessionListItem{}
+			for _, session := range sessions {
+				if len(statuses) == 0 || slices.Contains(statuses, string(session.State)) {
+					listed = append(listed, session)
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schemas.SessionListResponse{Sessions: listed})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCLIAttachSkipsCompletedSessionOnSameBranch(t *testing.T) {
+	branch := schemas.NewSBranch("feature/login")
+	server := newAttachTestServer(t, []schemas.SessionListItem{
+		{ID: "old", Branch: &branch, TmuxSession: "repo#feature/login-old", State: schemas.SessionPublicStateCompleted},
+		{ID: "current", Branch: &branch, TmuxSession: "repo#feature/login", State: schemas.SessionPublicStateActiveIdle},
+	})
+
+	setupCLIEnv(t, server.URL)
+	attached := stubAttach(t)
+
+	if _, err := captureOutput(t, func() error {
+		return executeCLI([]string{"attach", "feature/login"})
+	}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(*attached) != 1 || (*attached)[0] != "repo#feature/login" {
+		t.Fatalf("unexpected attach calls: %v", *attached)
+	}
+}
+
+func TestCLIAttachRejectsBranchWithOnlyCompletedSessions(t *testing.T) {
+	branch := schemas.NewSBranch("feature/login")
+	server := newAttachTestServer(t, []schemas.SessionListItem{
+		{ID: "old", Branch: &branch, TmuxSession: "repo#feature/login", State: schemas.SessionPublicStateCompleted},
+	})
+
+	setupCLIEnv(t, server.URL)
+	attached := stubAttach(t)
+
+	_, err := captureOutput(t, func() error {
+		return executeCLI([]string{"attach", "feature/login"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "no active session") {
+		t.Fatalf("expected no active session error, got %v", err)
+	}
+	if len(*attached) != 0 {
+		t.Fatalf("expected no attach calls, got %v", *attached)
+	}
+}
+
+func TestCLINextPassesTmuxSessionAndAttaches(t *testing.T) {
+	requested := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			_, _ = w.Write([]byte("test-version"))
+		case r.URL.Path == "/_session/next":
+			requested = r.URL.Query().Get("tmuxsession")
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schemas.SessionListResponse{Sessions: []schemas.SessionListItem{
+				{ID: "next", TmuxSession: "repo#next", State: schemas.SessionPublicStateActiveIdle},
+			}})
+		case r.URL.Path == "/_session/prev":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schemas.SessionListResponse{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCLIEnv(t, server.URL)
+	attached := stubAttach(t)
+
+	if _, err := captureOutput(t, func() error {
+		return executeCLI([]string{"next", "--from", "repo#current"})
+	}); err != nil {
+		t.Fatalf("run next: %v", err)
+	}
+	if requested != "repo#current" {
+		t.Fatalf("expected tmuxsession=repo#current, got %q", requested)
+	}
+	if len(*attached) != 1 || (*attached)[0] != "repo#next" {
+		t.Fatalf("unexpected attach calls: %v", *attached)
+	}
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"prev"})
+	})
+	if err != nil {
+		t.Fatalf("run prev: %v", err)
+	}
+	if !strings.Contains(output, "No idle sessions.") || len(*attached) != 1 {
+		t.Fatalf("expected no attach for empty prev, output=%q attached=%v", output, *attached)
+	}
+}
+
+func TestCLITmuxInstallBindingsIsIdempotent(t *testing.T) {
+	dataDir := t.TempDir()
+	currentEnv := env.Get()
+	origDataDir := currentEnv.DATA_DIR
+	currentEnv.DATA_DIR = dataDir
+	t.Cleanup(func() { currentEnv.DATA_DIR = origDataDir })
+
+	tmuxConf := filepath.Join(t.TempDir(), "tmux.conf")
+	if err := os.WriteFile(tmuxConf, []byte("set -g mouse on"), 0o644); err != nil {
+		t.Fatalf("write tmux conf: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := captureOutput(t, func() error {
+			return executeCLI([]string{"tmux", "install-bindings", "--tmux-conf", tmuxConf, "--next-key", "J"})
+		}); err != nil {
+			t.Fatalf("install %d: %v", i, err)
+		}
+	}
+
+	bindings, err := os.ReadFile(filepath.Join(dataDir, "tmux.conf"))
+	if err != nil {
+		t.Fatalf("read bindings: %v", err)
+	}
+	if !strings.Contains(string(bindings), `bind-key J run-shell -b "droner next --from '#{session_name}'"`) {
+		t.Fatalf("unexpected bindings: %q", bindings)
+	}
+	contents, err := os.ReadFile(tmuxConf)
+	if err != nil {
+		t.Fatalf("read tmux conf: %v", err)
+	}
+	if !strings.HasPrefix(string(contents), "set -g mouse on\n") || strings.Count(string(contents), "source-file") != 1 {
+		t.Fatalf("expected a single source-file line, got %q", contents)
+	}
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"tmux", "install-bindings", "--print"})
+	})
+	if err != nil {
+		t.Fatalf("print: %v", err)
+	}
+	if !strings.Contains(output, `display-popup -E -w 80% -h 60% "droner attach"`) {
+		t.Fatalf("unexpected printed bindings: %q", output)
+	}
+}
+
+func TestCLIDoctorFlagsVersionMismatchAndFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/version":
+			_, _ = w.Write([]byte("old-version"))
+		case "/diagnostics":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(schemas.DiagnosticsResponse{
+				Version: "old-version",
+				Checks: []schemas.DiagnosticCheck{
+					{Name: "tmux", Status: schemas.DiagnosticStatusFail, Detail: "tmux not found on PATH", Fix: "Install tmux"},
+				},
+				Databases: []schemas.DiagnosticDatabase{
+					{Name: "projections", Driver: "sqlite", Status: schemas.DiagnosticStatusOK, Version: 4, Latest: 4},
+				},
+				Subscribers: []schemas.DiagnosticSubscriber{
+					{Topic: "sessions", Subscriber: "hooks", Status: schemas.DiagnosticStatusWarn, Sequence: 10, Latest: 500, Lag: 490, Fix: "Look for hooks errors"},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	setupCLIEnv(t, server.URL)
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"doctor"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 diagnostic(s) failed") {
+		t.Fatalf("expected a failed diagnostic, got %v", err)
+	}
+	for _, want := range []string{"daemon version", "daemon old-version, cli test-version", "projections", "4/4", "10/500", "- tmux: Install tmux", "- hooks: Look for hooks errors", "droner sessions"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got %q", want, output)
+		}
+	}
+
+	output, _ = captureOutput(t, func() error {
+		return executeCLI([]string{"doctor", "-o", "json"})
+	})
+	var report schemas.DiagnosticsResponse
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("decode output %q: %v", output, err)
+	}
+	if len(report.Checks) != 3 || report.Checks[0].Name != "daemon" || report.Checks[1].Status != schemas.DiagnosticStatusWarn || report.Checks[2].Name != "tmux" {
+		t.Fatalf("unexpected checks: %#v", report.Checks)
+	}
+}
+
+func TestCLIDoctorChecksLocallyWithoutDaemon(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseURL := server.URL
+	server.Close()
+	setupCLIEnv(t, baseURL)
+
+	origChecks := runEnvironmentChecks
+	runEnvironmentChecks = func(context.Context, *conf.Config) []schemas.DiagnosticCheck {
+		return []schemas.DiagnosticCheck{{Name: "tmux", Status: schemas.DiagnosticStatusOK, Detail: "/usr/bin/tmux"}}
+	}
+	t.Cleanup(func() { runEnvironmentChecks = origChecks })
+
+	output, err := captureOutput(t, func() error {
+		return executeCLI([]string{"doctor"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 diagnostic(s) failed") {
+		t.Fatalf("expected the daemon check to fail, got %v", err)
+	}
+	for _, want := range []string{"/usr/bin/tmux", "- daemon: Start it with `droner serve --detach`"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got %q", want, output)
+		}
+	}
+}
+
 func TestDebugServerPort(t *testing.T) {
 	if got := debugServerPort("localhost:57877"); got != "57877" {
 		t.Fatalf("port = %q, want %q", got, "57877")
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/diagnostics"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/env"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkDaemon        = "daemon"
+	checkDaemonVersion = "daemon version"
+)
+
+var runEnvironmentChecks = diagnostics.EnvironmentChecks
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the tools, daemon and databases sessions depend on",
+		Long:  "Check the tools, daemon and databases sessions depend on and print how to fix what is wrong. Unlike other commands it does not start or restart the daemon; without a daemon it runs the environment checks locally.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			report := runDoctor(sdk.NewClient())
+			if err := printResult(cmd, report, func() { printDoctorReport(report) }); err != nil {
+				return err
+			}
+			if failed := countFailedDiagnostics(report); failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d diagnostic(s) failed", failed)
+			}
+			return nil
+		},
+	}
+}
+
+func runDoctor(client *sdk.Client) schemas.DiagnosticsResponse {
+	localVersion := strings.TrimSpace(conf.GetConfig().Version)
+	baseURL := env.Get().BASE_URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeouts.Probe)
+	remoteVersion, err := client.Version(ctx)
+	cancel()
+	if err != nil {
+		// Without a daemon the environment is checked from the CLI, which
+		// usually shares the daemon's PATH and config.
+		envCtx, envCancel := context.WithTimeout(context.Background(), timeouts.SecondLong)
+		defer envCancel()
+		daemon := schemas.DiagnosticCheck{
+			Name:   checkDaemon,
+			Status: schemas.DiagnosticStatusFail,
+			Detail: fmt.Sprintf("not reachable at %s: %v", baseURL, err),
+			Fix:    "Start it with `droner serve --detach`; database and subscriber checks need a running daemon",
+		}
+		return schemas.DiagnosticsResponse{
+			Checks:      append([]schemas.DiagnosticCheck{daemon}, runEnvironmentChecks(envCtx, conf.GetConfig())...),
+			Databases:   []schemas.DiagnosticDatabase{},
+			Subscribers: []schemas.DiagnosticSubscriber{},
+		}
+	}
+
+	remoteVersion = strings.TrimSpace(remoteVersion)
+	checks := []schemas.DiagnosticCheck{{Name: checkDaemon, Status: schemas.DiagnosticStatusOK, Detail: "running at " + baseURL}}
+	if remoteVersion == localVersion {
+		checks = append(checks, schemas.DiagnosticCheck{Name: checkDaemonVersion, Status: schemas.DiagnosticStatusOK, Detail: remoteVersion})
+	} else {
+		checks = append(checks, schemas.DiagnosticCheck{
+			Name:   checkDaemonVersion,
+			Status: schemas.DiagnosticStatusWarn,
+			Detail: fmt.Sprintf("daemon %s, cli %s", remoteVersion, localVersion),
+			Fix:    "Run any droner command that talks to the daemon (e.g. `droner sessions`) to restart it on the cli version; if the old daemon refuses to stop, kill it and run `droner serve --detach`",
+		})
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), timeouts.SecondLong)
+	defer cancel()
+	report, err := client.Diagnostics(ctx)
+	if err != nil {
+		checks = append(checks, schemas.DiagnosticCheck{
+			Name:   "diagnostics",
+			Status: schemas.DiagnosticStatusFail,
+			Detail: err.Error(),
+			Fix:    "Restart the daemon on the cli version (see daemon version) so it serves GET /diagnostics",
+		})
+		return schemas.DiagnosticsResponse{Version: remoteVersion, Checks: checks, Databases: []schemas.DiagnosticDatabase{}, Subscribers: []schemas.DiagnosticSubscriber{}}
+	}
+	report.Checks = append(checks, report.Checks...)
+	return *report
+}
+
+func countFailedDiagnostics(report schemas.DiagnosticsResponse) int {
+	failed := 0
+	for _, check := range report.Checks {
+		if check.Status == schemas.DiagnosticStatusFail {
+			failed++
+		}
+	}
+	for _, database := range report.Databases {
+		if database.Status == schemas.DiagnosticStatusFail {
+			failed++
+		}
+	}
+	for _, subscriber := range report.Subscribers {
+		if subscriber.Status == schemas.DiagnosticStatusFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+func printDoctorReport(report schemas.DiagnosticsResponse) {
+	fixes := []string{}
+	addFix := func(name string, fix string) {
+		if fix != "" {
+			fixes = append(fixes, fmt.Sprintf("- %s: %s", name, fix))
+		}
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "status\tcheck\tdetail")
+	for _, check := range report.Checks {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", check.Status, check.Name, check.Detail)
+		addFix(check.Name, check.Fix)
+	}
+	writer.Flush()
+
+	if len(report.Databases) > 0 {
+		fmt.Println()
+		writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "status\tdatabase\tdriver\tmigration")
+		for _, database := range report.Databases {
+			migration := fmt.Sprintf("%d/%d", database.Version, database.Latest)
+			if database.Error != "" {
+				migration = database.Error
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", database.Status, database.Name, database.Driver, migration)
+			addFix(database.Name+" database", database.Fix)
+		}
+		writer.Flush()
+	}
+
+	if len(report.Subscribers) > 0 {
+		fmt.Println()
+		writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "status\ttopic\tsubscriber\tcheckpoint\tlag")
+		for _, subscriber := range report.Subscribers {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%d/%d\t%d\n", subscriber.Status, subscriber.Topic, subscriber.Subscriber, subscriber.Sequence, subscriber.Latest, subscriber.Lag)
+			addFix(subscriber.Subscriber, subscriber.Fix)
+		}
+		writer.Flush()
+	}
+
+	if len(fixes) == 0 {
+		fmt.Println("\nEverything looks good.")
+		return
+	}
+	fmt.Println("\nFixes:")
+	for _, fix := range fixes {
+		fmt.Println(fix)
+	}
+}
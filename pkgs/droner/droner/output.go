@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
+	"github.com/spf13/cobra"
+)
+
+const outputFlag = "output"
+
+// versionInfo is the structured form of --version.
+type versionInfo struct {
+	CLI    string `json:"cli"`
+	Server string `json:"server,omitempty"`
+}
+
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(outputFlag, "o", string(cliutil.OutputTable), "output format of command results: table, json or yaml")
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		_, err := commandOutputFormat(cmd)
+		return err
+	}
+}
+
+func commandOutputFormat(cmd *cobra.Command) (cliutil.OutputFormat, error) {
+	value, err := cmd.Flags().GetString(outputFlag)
+	if err != nil {
+		// Commands built outside the root command only print tables.
+		return cliutil.OutputTable, nil
+	}
+	return cliutil.ParseOutputFormat(value)
+}
+
+// printResult writes value as JSON or YAML when --output asks for it, and
+// calls printTable otherwise.
+func printResult(cmd *cobra.Command, value any, printTable func()) error {
+	format, err := commandOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format == cliutil.OutputTable {
+		printTable()
+		return nil
+	}
+	return cliutil.WriteStructured(cmd.OutOrStdout(), format, value)
+}
+
+func formatVersionInfo(info versionInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cli: %s\n", info.CLI)
+	if info.Server == "" {
+		b.WriteString("server: (not running)\n")
+	} else {
+		fmt.Fprintf(&b, "server: %s\n", info.Server)
+	}
+	return b.String()
+}
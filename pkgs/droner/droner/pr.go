@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	"github.com/spf13/cobra"
+)
+
+type PRArgs struct {
+	Title         string
+	Body          string
+	Base          string
+	Draft         bool
+	BodyFromAgent bool
+}
+
+func newPRCmd() *cobra.Command {
+	args := PRArgs{}
+	cmd := &cobra.Command{
+		Use:   "pr <branch>",
+		Short: "Push a session branch and open a pull request for it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, inputs []string) error {
+			client := sdk.NewClient()
+			if err := validateDelArgs(&DelArgs{ID: inputs[0]}); err != nil {
+				return err
+			}
+			if err := cliutil.EnsureDaemonRunning(client); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondLong)
+			defer cancel()
+			id, err := resolveSessionIDByBranch(ctx, client, schemas.NewSBranch(inputs[0]))
+			if err != nil {
+				return err
+			}
+			response, err := client.CreateSessionPullRequest(ctx, id, schemas.SessionPullRequestRequest{
+				Title:         args.Title,
+				Body:          args.Body,
+				Base:          args.Base,
+				Draft:         args.Draft,
+				BodyFromAgent: args.BodyFromAgent,
+			})
+			if err != nil {
+				return err
+			}
+			return printResult(cmd, response, func() { printPullRequestCreated(os.Stdout, response) })
+		},
+	}
+
+	cmd.Flags().StringVar(&args.Title, "title", "", "pull request title (defaults to the first line of the session prompt)")
+	cmd.Flags().StringVar(&args.Body, "body", "", "pull request body")
+	cmd.Flags().StringVar(&args.Base, "base", "", "branch to merge into (defaults to the branch the session was created from)")
+	cmd.Flags().BoolVar(&args.Draft, "draft", false, "open the pull request as a draft")
+	cmd.Flags().BoolVar(&args.BodyFromAgent, "body-from-agent", false, "use the session prompt as the pull request body")
+	return cmd
+}
+
+// resolveSessionIDByBranch finds the newest session on branch, preferring
+// active sessions over finished ones.
+func resolveSessionIDByBranch(ctx context.Context, client *sdk.Client, branch schemas.SBranch) (string, error) {
+	response, err := client.ListSessionsWithParams(ctx, nil, 0, "", "")
+	if err != nil {
+		return "", err
+	}
+	id := ""
+	for _, session := range response.Sessions {
+		if session.Branch == nil || *session.Branch != branch {
+			continue
+		}
+		switch session.State {
+		case schemas.SessionPublicStateActiveIdle, schemas.SessionPublicStateActiveBusy:
+			return session.ID, nil
+		case schemas.SessionPublicStateCompleted:
+			if id == "" {
+				id = session.ID
+			}
+		}
+	}
+	if id == "" {
+		return "", fmt.Errorf("no active or completed session for branch %q", branch)
+	}
+	return id, nil
+}
+
+func printPullRequestCreated(out io.Writer, response *schemas.SessionPullRequestResponse) {
+	fmt.Fprintf(out, "pull request: #%d\n", response.Number)
+	if response.URL != "" {
+		fmt.Fprintf(out, "url: %s\n", response.URL)
+	}
+	fmt.Fprintf(out, "branch: %s -> %s\n", response.Branch, response.BaseRef)
+	if response.Draft {
+		fmt.Fprintln(out, "draft: true")
+	}
+}
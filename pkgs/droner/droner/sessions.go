@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	"github.com/spf13/cobra"
+)
+
+// sessionsPageSize is how many sessions each GET /sessions request asks for
+// while paginating.
+const sessionsPageSize = 100
+
+type SessionsArgs struct {
+	All    bool
+	Status []string
+	Repo   string
+	Filter string
+	Limit  int
+}
+
+var activeSessionStatuses = []sdk.SessionStatus{sdk.SessionStatusQueued, sdk.SessionStatusActiveIdle, sdk.SessionStatusActiveBusy}
+
+func newSessionsCmd() *cobra.Command {
+	args := SessionsArgs{}
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List sessions (defaults to active)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			statuses, err := sessionsStatuses(args)
+			if err != nil {
+				return err
+			}
+			if args.Limit < 0 {
+				return errors.New("--limit must not be negative")
+			}
+			client := sdk.NewClient()
+			if err := cliutil.EnsureDaemonRunning(client); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondLong)
+			defer cancel()
+			sessions, err := listSessionPages(ctx, client, statuses, args.Limit, sessionMatcher(args))
+			if err != nil {
+				return err
+			}
+			response := schemas.SessionListResponse{Sessions: sessions}
+			return printResult(cmd, response, func() { printSessionsTable(response, args.All || len(args.Status) > 0) })
+		},
+	}
+	cmd.Flags().BoolVar(&args.All, "all", false, "list sessions of any status. By default lists active sessions")
+	cmd.Flags().StringSliceVar(&args.Status, "status", nil, "only list sessions in these states (repeatable, e.g. --status active.idle --status completed)")
+	cmd.Flags().StringVar(&args.Repo, "repo", "", "only list sessions of this repo, by name or path")
+	cmd.Flags().StringVar(&args.Filter, "filter", "", "only list sessions whose id, branch, repo or tmux session contains this text")
+	cmd.Flags().IntVar(&args.Limit, "limit", sessionsPageSize, "maximum number of sessions to list, 0 for all")
+	return cmd
+}
+
+// sessionsStatuses maps the flags to the status query param. --status wins
+// over --all, and nil requests every status.
+func sessionsStatuses(args SessionsArgs) ([]sdk.SessionStatus, error) {
+	if len(args.Status) == 0 {
+		if args.All {
+			return nil, nil
+		}
+		return activeSessionStatuses, nil
+	}
+	known := schemas.SessionPublicStates()
+	statuses := make([]sdk.SessionStatus, 0, len(args.Status))
+	for _, status := range args.Status {
+		state := schemas.SessionPublicState(strings.TrimSpace(status))
+		if !slices.Contains(known, state) {
+			names := make([]string, 0, len(known))
+			for _, name := range known {
+				names = append(names, string(name))
+			}
+			return nil, fmt.Errorf("unknown status %q (expected one of %s)", status, strings.Join(names, ", "))
+		}
+		statuses = append(statuses, sdk.SessionStatus(state))
+	}
+	return statuses, nil
+}
+
+// sessionMatcher applies the filters the server has no query param for.
+func sessionMatcher(args SessionsArgs) func(schemas.SessionListItem) bool {
+	repo := strings.TrimSpace(args.Repo)
+	repoPath := ""
+	if repo != "" {
+		if abs, err := filepath.Abs(repo); err == nil {
+			repoPath = abs
+		}
+	}
+	filter := strings.ToLower(strings.TrimSpace(args.Filter))
+	return func(session schemas.SessionListItem) bool {
+		if repo != "" && session.Repo != repo && (session.RepoPath == "" || filepath.Clean(session.RepoPath) != repoPath) {
+			return false
+		}
+		if filter == "" {
+			return true
+		}
+		branch := ""
+		if session.Branch != nil {
+			branch = session.Branch.String()
+		}
+		for _, field := range []string{session.ID, branch, session.Repo, session.TmuxSession} {
+			if strings.Contains(strings.ToLower(field), filter) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// listSessionPages follows the newest-first cursor of GET /sessions until
+// limit sessions matched keep (0 for no limit) or the list ends.
+func listSessionPages(ctx context.Context, client *sdk.Client, statuses []sdk.SessionStatus, limit int, keep func(schemas.SessionListItem) bool) ([]schemas.SessionListItem, error) {
+	sessions := []schemas.SessionListItem{}
+	cursor := ""
+	direction := ""
+	for {
+		page, err := client.ListSessionsWithParams(ctx, statuses, sessionsPageSize, cursor, direction)
+		if err != nil {
+			return nil, err
+		}
+		for _, session := range page.Sessions {
+			if !keep(session) {
+				continue
+			}
+			sessions = append(sessions, session)
+			if limit > 0 && len(sessions) == limit {
+				return sessions, nil
+			}
+		}
+		if len(page.Sessions) < sessionsPageSize {
+			return sessions, nil
+		}
+		cursor = page.Sessions[len(page.Sessions)-1].ID
+		direction = string(schemas.SessionListDirectionAfter)
+	}
+}
+
+func printSessionsTable(response schemas.SessionListResponse, anyStatus bool) {
+	if len(response.Sessions) == 0 {
+		if anyStatus {
+			fmt.Println("No sessions.")
+		} else {
+			fmt.Println("No active sessions.")
+		}
+		return
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "id\trepo\tremoteUrl\tbranch\tstate")
+	for _, session := range response.Sessions {
+		branch := ""
+		if session.Branch != nil {
+			branch = session.Branch.String()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", session.ID, session.Repo, session.RemoteURL, branch, session.State)
+	}
+	writer.Flush()
+}
@@ -11,14 +11,17 @@ import (
 	"strings"
 
 	coredb "github.com/Oudwins/droner/pkgs/droner/dronerd/db"
+	postgresdb "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/postgres/db"
 	backenddb "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/sqlite3/db"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionslog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/env"
 	"github.com/pressly/goose/v3"
 )
 
 type migrationTarget struct {
 	name        string
+	label       string
 	path        string
 	open        func(string) (*sql.DB, error)
 	newProvider func(*sql.DB) (*goose.Provider, error)
@@ -27,12 +30,15 @@ type migrationTarget struct {
 func main() {
 	ctx := context.Background()
 	runtimeEnv := env.Get()
+	storage := conf.GetConfig().Storage
 
 	fs := flag.NewFlagSet("dronerd-migrate", flag.ExitOnError)
 	target := fs.String("target", "all", "migration target: main, sessionslog, or all")
 	dataDir := fs.String("data-dir", runtimeEnv.DATA_DIR, "droner data directory")
+	driver := fs.String("driver", storage.Driver.String(), "storage driver: sqlite or postgres")
+	dsn := fs.String("dsn", storage.Postgres.DSN, "postgres connection string (postgres driver only)")
 	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "Usage: %s [--target main|sessionslog|all] [--data-dir path] <up|down|status|version>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Usage: %s [--target main|sessionslog|all] [--data-dir path] [--driver sqlite|postgres] [--dsn dsn] <up|down|status|version>\n", os.Args[0])
 		fs.PrintDefaults()
 	}
 	if err := fs.Parse(os.Args[1:]); err != nil {
@@ -44,14 +50,16 @@ func main() {
 	}
 
 	command := strings.ToLower(strings.TrimSpace(fs.Arg(0)))
-	if err := run(ctx, command, strings.ToLower(strings.TrimSpace(*target)), *dataDir); err != nil {
+	storage.Driver = conf.StorageDriver(strings.ToLower(strings.TrimSpace(*driver)))
+	storage.Postgres.DSN = strings.TrimSpace(*dsn)
+	if err := run(ctx, command, strings.ToLower(strings.TrimSpace(*target)), *dataDir, storage); err != nil {
 		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, command string, target string, dataDir string) error {
-	targets, err := selectTargets(target, dataDir)
+func run(ctx context.Context, command string, target string, dataDir string, storage conf.StorageConfig) error {
+	targets, err := selectTargets(target, dataDir, storage)
 	if err != nil {
 		return err
 	}
@@ -66,18 +74,41 @@ func run(ctx context.Context, command string, target string, dataDir string) err
 	return nil
 }
 
-func selectTargets(target string, dataDir string) ([]migrationTarget, error) {
-	main := migrationTarget{
-		name:        "main",
-		path:        coredb.DBPath(dataDir),
-		open:        coredb.OpenSQLiteDBWithoutMigrations,
-		newProvider: coredb.NewMigrationProvider,
-	}
-	sessions := migrationTarget{
-		name:        "sessionslog",
-		path:        sessionslog.DBPath(dataDir),
-		open:        backenddb.OpenSQLiteDBWithoutMigrations,
-		newProvider: backenddb.NewMigrationProvider,
+func selectTargets(target string, dataDir string, storage conf.StorageConfig) ([]migrationTarget, error) {
+	var main, sessions migrationTarget
+	switch storage.Driver {
+	case "", conf.StorageDriverSQLite:
+		main = migrationTarget{
+			name:        "main",
+			label:       coredb.DBPath(dataDir),
+			path:        coredb.DBPath(dataDir),
+			open:        coredb.OpenSQLiteDBWithoutMigrations,
+			newProvider: coredb.NewMigrationProvider,
+		}
+		sessions = migrationTarget{
+			name:        "sessionslog",
+			label:       sessionslog.DBPath(dataDir),
+			path:        sessionslog.DBPath(dataDir),
+			open:        backenddb.OpenSQLiteDBWithoutMigrations,
+			newProvider: backenddb.NewMigrationProvider,
+		}
+	case conf.StorageDriverPostgres:
+		main = migrationTarget{
+			name:        "main",
+			label:       "postgres " + coredb.PostgresMigrationsTable,
+			path:        storage.Postgres.DSN,
+			open:        coredb.OpenPostgresDBWithoutMigrations,
+			newProvider: coredb.NewPostgresMigrationProvider,
+		}
+		sessions = migrationTarget{
+			name:        "sessionslog",
+			label:       "postgres " + postgresdb.MigrationsTable,
+			path:        storage.Postgres.DSN,
+			open:        postgresdb.OpenPostgresDBWithoutMigrations,
+			newProvider: postgresdb.NewMigrationProvider,
+		}
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", storage.Driver)
 	}
 	switch target {
 	case "main":
@@ -92,7 +123,7 @@ func selectTargets(target string, dataDir string) ([]migrationTarget, error) {
 }
 
 func runTarget(ctx context.Context, command string, target migrationTarget) error {
-	fmt.Printf("== %s (%s) ==\n", target.name, target.path)
+	fmt.Printf("== %s (%s) ==\n", target.name, target.label)
 
 	conn, err := target.open(target.path)
 	if err != nil {
@@ -2,6 +2,7 @@ package core
 
 import (
 	"database/sql"
+	"fmt"
 	"log/slog"
 	"os"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventlogs"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/hooks"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/retention"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/backends"
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
@@ -16,18 +18,20 @@ import (
 )
 
 type BaseServer struct {
-	Config       *conf.Config
-	Env          *env.EnvStruct
-	Logger       *slog.Logger
-	LogFile      *os.File
-	DB           *sql.DB
-	Queries      *coredb.Queries
-	EventLogs    *eventlogs.Registry
-	Sessions     *sessionevents.SQLiteProjectionStore
-	Hooks        *hooks.SQLiteSessionLookupStore
-	PRSessions   *pullrequestevents.SQLiteSessionLookupStore
-	PRSnapshots  *pullrequestevents.SQLitePullRequestSnapshotStore
-	BackendStore *backends.Store
+	Config        *conf.Config
+	Env           *env.EnvStruct
+	Logger        *slog.Logger
+	LogFile       *os.File
+	DB            *sql.DB
+	Queries       coredb.Querier
+	EventLogs     *eventlogs.Registry
+	Sessions      *sessionevents.DBProjectionStore
+	Hooks         *hooks.DBSessionLookupStore
+	HookSchedules *hooks.DBScheduleStore
+	PRSessions    *pullrequestevents.DBSessionLookupStore
+	PRSnapshots   *pullrequestevents.DBPullRequestSnapshotStore
+	Retention     *retention.DBStore
+	BackendStore  *backends.Store
 }
 
 func New() *BaseServer {
@@ -35,28 +39,29 @@ func New() *BaseServer {
 	config := conf.GetConfig()
 
 	logger, logFile := InitLogger(env)
-	db, err := coredb.OpenSQLiteDB(coredb.DBPath(env.DATA_DIR))
+	db, queries, err := openProjectionDB(env.DATA_DIR, config.Storage)
 	if err != nil {
 		panic(err)
 	}
-	eventLogs, err := eventlogs.Open(env.DATA_DIR)
+	eventLogs, err := eventlogs.OpenStorage(env.DATA_DIR, config.Storage)
 	if err != nil {
 		_ = db.Close()
 		panic(err)
 	}
-	queries := coredb.New(db)
 	base := &BaseServer{
-		Config:      config,
-		Env:         env,
-		Logger:      logger,
-		LogFile:     logFile,
-		DB:          db,
-		Queries:     queries,
-		EventLogs:   eventLogs,
-		Sessions:    sessionevents.NewSQLiteProjectionStore(queries),
-		Hooks:       hooks.NewSQLiteSessionLookupStore(queries),
-		PRSessions:  pullrequestevents.NewSQLiteSessionLookupStore(queries),
-		PRSnapshots: pullrequestevents.NewSQLitePullRequestSnapshotStore(queries),
+		Config:        config,
+		Env:           env,
+		Logger:        logger,
+		LogFile:       logFile,
+		DB:            db,
+		Queries:       queries,
+		EventLogs:     eventLogs,
+		Sessions:      sessionevents.NewProjectionStore(queries),
+		Hooks:         hooks.NewSessionLookupStore(queries),
+		HookSchedules: hooks.NewScheduleStore(queries),
+		PRSessions:    pullrequestevents.NewSessionLookupStore(queries),
+		PRSnapshots:   pullrequestevents.NewPullRequestSnapshotStore(queries),
+		Retention:     retention.NewStore(queries),
 	}
 
 	base.BackendStore = backends.NewStore(config)
@@ -64,6 +69,25 @@ func New() *BaseServer {
 	return base
 }
 
+func openProjectionDB(dataDir string, storage conf.StorageConfig) (*sql.DB, coredb.Querier, error) {
+	switch storage.Driver {
+	case "", conf.StorageDriverSQLite:
+		db, err := coredb.OpenSQLiteDB(coredb.DBPath(dataDir))
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, coredb.New(db), nil
+	case conf.StorageDriverPostgres:
+		db, err := coredb.OpenPostgresDB(storage.Postgres.DSN)
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, coredb.NewPostgres(db), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage driver %q", storage.Driver)
+	}
+}
+
 func (b *BaseServer) Close() {
 	if b.EventLogs != nil {
 		_ = b.EventLogs.Close()
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS session_projection (
+  stream_id TEXT PRIMARY KEY,
+  harness TEXT NOT NULL,
+  branch TEXT,
+  backend_id TEXT NOT NULL,
+  repo_path TEXT NOT NULL,
+  worktree_path TEXT,
+  remote_url TEXT NOT NULL DEFAULT '',
+  agent_config TEXT NOT NULL DEFAULT '',
+  lifecycle_state TEXT NOT NULL,
+  public_state TEXT NOT NULL,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  pr_number BIGINT,
+  pr_state TEXT,
+  pr_ci_state TEXT,
+  pr_updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS session_projection_public_state_idx
+  ON session_projection(public_state, updated_at DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS session_projection_active_branch_unique_idx
+  ON session_projection(branch)
+  WHERE branch IS NOT NULL
+    AND public_state IN ('queued', 'active.idle', 'active.busy', 'completing', 'deleting');
+
+-- +goose Down
+DROP INDEX IF EXISTS session_projection_active_branch_unique_idx;
+DROP INDEX IF EXISTS session_projection_public_state_idx;
+DROP TABLE IF EXISTS session_projection;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS hook_schedule (
+  rule TEXT NOT NULL,
+  cause_id TEXT NOT NULL,
+  topic TEXT NOT NULL,
+  cause_json TEXT NOT NULL,
+  fire_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (rule, cause_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS hook_schedule;
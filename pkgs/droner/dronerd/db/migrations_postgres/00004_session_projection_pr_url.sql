@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE session_projection ADD COLUMN IF NOT EXISTS pr_url TEXT;
+
+UPDATE session_projection
+SET pr_url = (
+  SELECT s.snapshot_json::jsonb ->> 'htmlUrl'
+  FROM pr_latest_snapshot s
+  WHERE s.remote_url = session_projection.remote_url
+    AND s.head_ref = session_projection.branch
+    AND s.number = session_projection.pr_number
+  ORDER BY s.observed_at DESC
+  LIMIT 1
+)
+WHERE pr_number IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE session_projection DROP COLUMN IF EXISTS pr_url;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE session_projection ADD COLUMN base_branch TEXT;
+
+-- +goose Down
+ALTER TABLE session_projection DROP COLUMN base_branch;
@@ -9,6 +9,14 @@ import (
 	"time"
 )
 
+type HookSchedule struct {
+	Rule      string
+	CauseID   string
+	Topic     string
+	CauseJson string
+	FireAt    time.Time
+}
+
 type PrLatestSnapshot struct {
 	StreamID     string
 	Provider     string
@@ -40,4 +48,6 @@ type SessionProjection struct {
 	PrState        sql.NullString
 	PrCiState      sql.NullString
 	PrUpdatedAt    sql.NullTime
+	PrUrl          sql.NullString
+	BaseBranch     sql.NullString
 }
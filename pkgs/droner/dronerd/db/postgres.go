@@ -0,0 +1,257 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/db/postgresdb"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// PostgresMigrationsTable keeps the projection migrations apart from the
+// eventlog migrations when both live in the same Postgres database.
+const PostgresMigrationsTable = "droner_db_version"
+
+//go:embed migrations_postgres/*.sql
+var postgresMigrationFS embed.FS
+
+func OpenPostgresDB(dsn string) (*sql.DB, error) {
+	conn, err := OpenPostgresDBWithoutMigrations(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePostgresMigrations(context.Background(), conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func OpenPostgresDBWithoutMigrations(dsn string) (*sql.DB, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, errors.New("postgres dsn is required")
+	}
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	return conn, nil
+}
+
+func NewPostgresMigrationProvider(conn *sql.DB) (*goose.Provider, error) {
+	files, err := fs.Sub(postgresMigrationFS, "migrations_postgres")
+	if err != nil {
+		return nil, fmt.Errorf("open postgres db migrations: %w", err)
+	}
+	store, err := database.NewStore(database.DialectPostgres, PostgresMigrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("create postgres db migration store: %w", err)
+	}
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create postgres db migration locker: %w", err)
+	}
+	provider, err := goose.NewProvider("", conn, files, goose.WithStore(store), goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("create postgres db migration provider: %w", err)
+	}
+	return provider, nil
+}
+
+func ensurePostgresMigrations(ctx context.Context, conn *sql.DB) error {
+	provider, err := NewPostgresMigrationProvider(conn)
+	if err != nil {
+		return err
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("run postgres db migrations: %w", err)
+	}
+	return nil
+}
+
+// NewPostgres returns the projection queries generated from postgresdb/queries
+// behind the same Querier the sqlite queries implement. The generated rows
+// share their fields with the sqlite ones, so they convert one to one.
+func NewPostgres(conn postgresdb.DBTX) Querier {
+	return postgresQueries{queries: postgresdb.New(conn)}
+}
+
+type postgresQueries struct {
+	queries *postgresdb.Queries
+}
+
+var _ Querier = postgresQueries{}
+
+func (p postgresQueries) DeleteHookSchedule(ctx context.Context, arg DeleteHookScheduleParams) error {
+	return p.queries.DeleteHookSchedule(ctx, postgresdb.DeleteHookScheduleParams(arg))
+}
+
+func (p postgresQueries) DeletePRLatestSnapshot(ctx context.Context, streamID string) error {
+	return p.queries.DeletePRLatestSnapshot(ctx, streamID)
+}
+
+func (p postgresQueries) DeleteSessionProjection(ctx context.Context, streamID string) error {
+	return p.queries.DeleteSessionProjection(ctx, streamID)
+}
+
+func (p postgresQueries) GetBlockedSessionProjectionByRepoPathAndBranch(ctx context.Context, arg GetBlockedSessionProjectionByRepoPathAndBranchParams) (SessionProjection, error) {
+	row, err := p.queries.GetBlockedSessionProjectionByRepoPathAndBranch(ctx, postgresdb.GetBlockedSessionProjectionByRepoPathAndBranchParams(arg))
+	return SessionProjection(row), err
+}
+
+func (p postgresQueries) GetCurrentSessionProjectionByBranch(ctx context.Context, branch sql.NullString) (SessionProjection, error) {
+	row, err := p.queries.GetCurrentSessionProjectionByBranch(ctx, branch)
+	return SessionProjection(row), err
+}
+
+func (p postgresQueries) GetLatestNavigationSessionProjectionByBranch(ctx context.Context, branch sql.NullString) (SessionProjection, error) {
+	row, err := p.queries.GetLatestNavigationSessionProjectionByBranch(ctx, branch)
+	return SessionProjection(row), err
+}
+
+func (p postgresQueries) GetPRLatestSnapshotByRepoAndNumber(ctx context.Context, arg GetPRLatestSnapshotByRepoAndNumberParams) (PrLatestSnapshot, error) {
+	row, err := p.queries.GetPRLatestSnapshotByRepoAndNumber(ctx, postgresdb.GetPRLatestSnapshotByRepoAndNumberParams(arg))
+	return PrLatestSnapshot(row), err
+}
+
+func (p postgresQueries) GetPRLatestSnapshotByStreamID(ctx context.Context, streamID string) (PrLatestSnapshot, error) {
+	row, err := p.queries.GetPRLatestSnapshotByStreamID(ctx, streamID)
+	return PrLatestSnapshot(row), err
+}
+
+func (p postgresQueries) GetSessionProjectionByStreamID(ctx context.Context, streamID string) (SessionProjection, error) {
+	row, err := p.queries.GetSessionProjectionByStreamID(ctx, streamID)
+	return SessionProjection(row), err
+}
+
+func (p postgresQueries) GetSessionProjectionByWorktreePath(ctx context.Context, worktreePath sql.NullString) (SessionProjection, error) {
+	row, err := p.queries.GetSessionProjectionByWorktreePath(ctx, worktreePath)
+	return SessionProjection(row), err
+}
+
+func (p postgresQueries) InsertHookSchedule(ctx context.Context, arg InsertHookScheduleParams) error {
+	return p.queries.InsertHookSchedule(ctx, postgresdb.InsertHookScheduleParams(arg))
+}
+
+func (p postgresQueries) ListActiveSessionProjectionRefs(ctx context.Context) ([]SessionProjection, error) {
+	rows, err := p.queries.ListActiveSessionProjectionRefs(ctx)
+	return convertRows(rows, err, func(row postgresdb.SessionProjection) SessionProjection { return SessionProjection(row) })
+}
+
+func (p postgresQueries) ListAllSessionProjectionItems(ctx context.Context) ([]ListAllSessionProjectionItemsRow, error) {
+	rows, err := p.queries.ListAllSessionProjectionItems(ctx)
+	return convertRows(rows, err, func(row postgresdb.ListAllSessionProjectionItemsRow) ListAllSessionProjectionItemsRow {
+		return ListAllSessionProjectionItemsRow(row)
+	})
+}
+
+func (p postgresQueries) ListDeletedSessionProjectionRefs(ctx context.Context) ([]SessionProjection, error) {
+	rows, err := p.queries.ListDeletedSessionProjectionRefs(ctx)
+	return convertRows(rows, err, func(row postgresdb.SessionProjection) SessionProjection { return SessionProjection(row) })
+}
+
+func (p postgresQueries) ListHookSchedules(ctx context.Context) ([]HookSchedule, error) {
+	rows, err := p.queries.ListHookSchedules(ctx)
+	return convertRows(rows, err, func(row postgresdb.HookSchedule) HookSchedule { return HookSchedule(row) })
+}
+
+func (p postgresQueries) ListHydratableSessionProjectionRefs(ctx context.Context) ([]SessionProjection, error) {
+	rows, err := p.queries.ListHydratableSessionProjectionRefs(ctx)
+	return convertRows(rows, err, func(row postgresdb.SessionProjection) SessionProjection { return SessionProjection(row) })
+}
+
+func (p postgresQueries) ListPRLatestSnapshots(ctx context.Context) ([]PrLatestSnapshot, error) {
+	rows, err := p.queries.ListPRLatestSnapshots(ctx)
+	return convertRows(rows, err, func(row postgresdb.PrLatestSnapshot) PrLatestSnapshot { return PrLatestSnapshot(row) })
+}
+
+func (p postgresQueries) ListPRLatestSnapshotsByRemoteAndBranch(ctx context.Context, arg ListPRLatestSnapshotsByRemoteAndBranchParams) ([]PrLatestSnapshot, error) {
+	rows, err := p.queries.ListPRLatestSnapshotsByRemoteAndBranch(ctx, postgresdb.ListPRLatestSnapshotsByRemoteAndBranchParams(arg))
+	return convertRows(rows, err, func(row postgresdb.PrLatestSnapshot) PrLatestSnapshot { return PrLatestSnapshot(row) })
+}
+
+func (p postgresQueries) ListReusableSessionProjectionRefs(ctx context.Context, arg ListReusableSessionProjectionRefsParams) ([]SessionProjection, error) {
+	rows, err := p.queries.ListReusableSessionProjectionRefs(ctx, postgresdb.ListReusableSessionProjectionRefsParams(arg))
+	return convertRows(rows, err, func(row postgresdb.SessionProjection) SessionProjection { return SessionProjection(row) })
+}
+
+// The sqlite list queries repeat each argument for every placeholder;
+// Column2 carries the statuses and StreamID the cursor.
+
+func (p postgresQueries) ListSessionProjectionItemsAfterCursorByStatuses(ctx context.Context, arg ListSessionProjectionItemsAfterCursorByStatusesParams) ([]ListSessionProjectionItemsAfterCursorByStatusesRow, error) {
+	rows, err := p.queries.ListSessionProjectionItemsAfterCursorByStatuses(ctx, postgresdb.ListSessionProjectionItemsAfterCursorByStatusesParams{
+		Statuses: arg.Column2.String,
+		Cursor:   arg.StreamID,
+		PageSize: int32(arg.Limit),
+	})
+	return convertRows(rows, err, func(row postgresdb.ListSessionProjectionItemsAfterCursorByStatusesRow) ListSessionProjectionItemsAfterCursorByStatusesRow {
+		return ListSessionProjectionItemsAfterCursorByStatusesRow(row)
+	})
+}
+
+func (p postgresQueries) ListSessionProjectionItemsBeforeCursorByStatuses(ctx context.Context, arg ListSessionProjectionItemsBeforeCursorByStatusesParams) ([]ListSessionProjectionItemsBeforeCursorByStatusesRow, error) {
+	rows, err := p.queries.ListSessionProjectionItemsBeforeCursorByStatuses(ctx, postgresdb.ListSessionProjectionItemsBeforeCursorByStatusesParams{
+		Statuses: arg.Column2.String,
+		Cursor:   arg.StreamID,
+		PageSize: int32(arg.Limit),
+	})
+	return convertRows(rows, err, func(row postgresdb.ListSessionProjectionItemsBeforeCursorByStatusesRow) ListSessionProjectionItemsBeforeCursorByStatusesRow {
+		return ListSessionProjectionItemsBeforeCursorByStatusesRow(row)
+	})
+}
+
+func (p postgresQueries) ListSessionProjectionItemsOldestByStatuses(ctx context.Context, arg ListSessionProjectionItemsOldestByStatusesParams) ([]ListSessionProjectionItemsOldestByStatusesRow, error) {
+	rows, err := p.queries.ListSessionProjectionItemsOldestByStatuses(ctx, postgresdb.ListSessionProjectionItemsOldestByStatusesParams{
+		Statuses: arg.Column2.String,
+		PageSize: int32(arg.Limit),
+	})
+	return convertRows(rows, err, func(row postgresdb.ListSessionProjectionItemsOldestByStatusesRow) ListSessionProjectionItemsOldestByStatusesRow {
+		return ListSessionProjectionItemsOldestByStatusesRow(row)
+	})
+}
+
+func (p postgresQueries) ListLiveSessionProjectionStreamIDsByRemoteAndBranch(ctx context.Context, arg ListLiveSessionProjectionStreamIDsByRemoteAndBranchParams) ([]string, error) {
+	return p.queries.ListLiveSessionProjectionStreamIDsByRemoteAndBranch(ctx, postgresdb.ListLiveSessionProjectionStreamIDsByRemoteAndBranchParams(arg))
+}
+
+func (p postgresQueries) ListVisibleSessionProjectionItems(ctx context.Context) ([]ListVisibleSessionProjectionItemsRow, error) {
+	rows, err := p.queries.ListVisibleSessionProjectionItems(ctx)
+	return convertRows(rows, err, func(row postgresdb.ListVisibleSessionProjectionItemsRow) ListVisibleSessionProjectionItemsRow {
+		return ListVisibleSessionProjectionItemsRow(row)
+	})
+}
+
+func (p postgresQueries) PatchSessionProjection(ctx context.Context, arg PatchSessionProjectionParams) error {
+	return p.queries.PatchSessionProjection(ctx, postgresdb.PatchSessionProjectionParams(arg))
+}
+
+func (p postgresQueries) UpsertPRLatestSnapshot(ctx context.Context, arg UpsertPRLatestSnapshotParams) error {
+	return p.queries.UpsertPRLatestSnapshot(ctx, postgresdb.UpsertPRLatestSnapshotParams(arg))
+}
+
+func (p postgresQueries) UpsertSessionProjection(ctx context.Context, arg UpsertSessionProjectionParams) error {
+	return p.queries.UpsertSessionProjection(ctx, postgresdb.UpsertSessionProjectionParams(arg))
+}
+
+func convertRows[T any, U any](rows []T, err error, convert func(T) U) ([]U, error) {
+	if err != nil {
+		return nil, err
+	}
+	items := make([]U, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, convert(row))
+	}
+	return items, nil
+}
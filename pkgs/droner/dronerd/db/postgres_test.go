@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+)
+
+// Set DRONER_TEST_POSTGRES_DSN to run the projection queries against Postgres.
+func TestPostgresProjectionQueries(t *testing.T) {
+	dsn := os.Getenv("DRONER_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("DRONER_TEST_POSTGRES_DSN not set")
+	}
+	conn, err := OpenPostgresDB(dsn)
+	if err != nil {
+		t.Fatalf("OpenPostgresDB: %v", err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+	if _, err := conn.ExecContext(ctx, `TRUNCATE session_projection, pr_latest_snapshot, hook_schedule`); err != nil {
+		t.Fatalf("truncate projection tables: %v", err)
+	}
+
+	queries := NewPostgres(conn)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if err := queries.UpsertSessionProjection(ctx, UpsertSessionProjectionParams{
+		StreamID:       "session-a",
+		Harness:        "opencode",
+		Branch:         sql.NullString{String: "feature-a", Valid: true},
+		BackendID:      "local",
+		RepoPath:       "/repo",
+		LifecycleState: "active.idle",
+		PublicState:    "active.idle",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}); err != nil {
+		t.Fatalf("UpsertSessionProjection: %v", err)
+	}
+
+	row, err := queries.GetSessionProjectionByStreamID(ctx, "session-a")
+	if err != nil {
+		t.Fatalf("GetSessionProjectionByStreamID: %v", err)
+	}
+	if row.Branch.String != "feature-a" || !row.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected projection row: %+v", row)
+	}
+
+	if err := queries.UpsertSessionProjection(ctx, UpsertSessionProjectionParams{
+		StreamID:       "session-b",
+		Harness:        "opencode",
+		Branch:         sql.NullString{String: "feature-b", Valid: true},
+		BackendID:      "local",
+		RepoPath:       "/repo",
+		LifecycleState: "completed",
+		PublicState:    "completed",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}); err != nil {
+		t.Fatalf("UpsertSessionProjection: %v", err)
+	}
+
+	items, err := queries.ListSessionProjectionItemsAfterCursorByStatuses(ctx, ListSessionProjectionItemsAfterCursorByStatusesParams{
+		Column1: "active.idle",
+		Column2: sql.NullString{String: "active.idle", Valid: true},
+		Column3: "",
+		Limit:   10,
+	})
+	if err != nil {
+		t.Fatalf("ListSessionProjectionItemsAfterCursorByStatuses: %v", err)
+	}
+	if len(items) != 1 || items[0].StreamID != "session-a" {
+		t.Fatalf("unexpected list items: %+v", items)
+	}
+
+	items, err = queries.ListSessionProjectionItemsAfterCursorByStatuses(ctx, ListSessionProjectionItemsAfterCursorByStatusesParams{
+		Column1:  "",
+		Column2:  sql.NullString{String: "", Valid: true},
+		Column3:  "session-b",
+		StreamID: "session-b",
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatalf("ListSessionProjectionItemsAfterCursorByStatuses: %v", err)
+	}
+	if len(items) != 1 || items[0].StreamID != "session-a" {
+		t.Fatalf("unexpected items after cursor: %+v", items)
+	}
+
+	schedule := InsertHookScheduleParams{Rule: "idle", CauseID: "event-1", Topic: "sessions", CauseJson: `{}`, FireAt: now}
+	for i := 0; i < 2; i++ {
+		if err := queries.InsertHookSchedule(ctx, schedule); err != nil {
+			t.Fatalf("InsertHookSchedule: %v", err)
+		}
+	}
+	schedules, err := queries.ListHookSchedules(ctx)
+	if err != nil {
+		t.Fatalf("ListHookSchedules: %v", err)
+	}
+	if len(schedules) != 1 || !schedules[0].FireAt.Equal(now) {
+		t.Fatalf("unexpected hook schedules: %+v", schedules)
+	}
+	if err := queries.DeleteHookSchedule(ctx, DeleteHookScheduleParams{Rule: "idle", CauseID: "event-1"}); err != nil {
+		t.Fatalf("DeleteHookSchedule: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+-- name: UpsertPRLatestSnapshot :exec
+INSERT INTO pr_latest_snapshot (
+  stream_id,
+  provider,
+  remote_url,
+  repo_owner,
+  repo_name,
+  number,
+  head_ref,
+  head_sha,
+  observed_at,
+  snapshot_json
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT(stream_id) DO UPDATE SET
+  provider = excluded.provider,
+  remote_url = excluded.remote_url,
+  repo_owner = excluded.repo_owner,
+  repo_name = excluded.repo_name,
+  number = excluded.number,
+  head_ref = excluded.head_ref,
+  head_sha = excluded.head_sha,
+  observed_at = excluded.observed_at,
+  snapshot_json = excluded.snapshot_json;
+
+-- name: GetPRLatestSnapshotByStreamID :one
+SELECT *
+FROM pr_latest_snapshot
+WHERE stream_id = $1;
+
+-- name: GetPRLatestSnapshotByRepoAndNumber :one
+SELECT *
+FROM pr_latest_snapshot
+WHERE provider = $1
+  AND repo_owner = $2
+  AND repo_name = $3
+  AND number = $4;
+
+-- name: ListPRLatestSnapshotsByRemoteAndBranch :many
+SELECT *
+FROM pr_latest_snapshot
+WHERE remote_url = $1
+  AND head_ref = $2
+ORDER BY observed_at DESC;
+
+-- name: ListPRLatestSnapshots :many
+SELECT *
+FROM pr_latest_snapshot
+ORDER BY observed_at ASC;
+
+-- name: DeletePRLatestSnapshot :exec
+DELETE FROM pr_latest_snapshot
+WHERE stream_id = $1;
@@ -0,0 +1,177 @@
+-- name: UpsertSessionProjection :exec
+INSERT INTO session_projection (
+  stream_id,
+  harness,
+  branch,
+  backend_id,
+  repo_path,
+  worktree_path,
+  remote_url,
+  agent_config,
+  lifecycle_state,
+  public_state,
+  last_error,
+  pr_number,
+  pr_state,
+  pr_ci_state,
+  pr_updated_at,
+  pr_url,
+  base_branch,
+  created_at,
+  updated_at
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  $11,
+  $12,
+  $13,
+  $14,
+  $15,
+  $16,
+  $17,
+  $18,
+  $19
+)
+ON CONFLICT(stream_id) DO UPDATE SET
+  harness = excluded.harness,
+  branch = excluded.branch,
+  backend_id = excluded.backend_id,
+  repo_path = excluded.repo_path,
+  worktree_path = excluded.worktree_path,
+  remote_url = excluded.remote_url,
+  agent_config = excluded.agent_config,
+  lifecycle_state = excluded.lifecycle_state,
+  public_state = excluded.public_state,
+  last_error = excluded.last_error,
+  pr_number = excluded.pr_number,
+  pr_state = excluded.pr_state,
+  pr_ci_state = excluded.pr_ci_state,
+  pr_updated_at = excluded.pr_updated_at,
+  pr_url = excluded.pr_url,
+  base_branch = excluded.base_branch,
+  updated_at = excluded.updated_at;
+
+-- name: PatchSessionProjection :exec
+UPDATE session_projection
+SET lifecycle_state = $1,
+    public_state = $2,
+    last_error = $3,
+    updated_at = $4
+WHERE stream_id = $5;
+
+-- name: DeleteSessionProjection :exec
+DELETE FROM session_projection
+WHERE stream_id = $1;
+
+-- name: GetSessionProjectionByStreamID :one
+SELECT *
+FROM session_projection
+WHERE stream_id = $1;
+
+-- name: GetCurrentSessionProjectionByBranch :one
+SELECT *
+FROM session_projection
+WHERE branch = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetSessionProjectionByWorktreePath :one
+SELECT *
+FROM session_projection
+WHERE worktree_path = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetBlockedSessionProjectionByRepoPathAndBranch :one
+SELECT *
+FROM session_projection
+WHERE repo_path = $1
+  AND branch = $2
+  AND public_state IN ('queued', 'active.idle', 'active.busy', 'completing', 'deleting')
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetLatestNavigationSessionProjectionByBranch :one
+SELECT *
+FROM session_projection
+WHERE branch = $1
+  AND public_state IN ('active.idle', 'active.busy', 'completed')
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListVisibleSessionProjectionItems :many
+SELECT stream_id, repo_path, remote_url, branch, public_state
+FROM session_projection
+WHERE public_state IN ('queued', 'active.idle', 'active.busy', 'completing')
+ORDER BY updated_at DESC
+LIMIT 100;
+
+-- name: ListAllSessionProjectionItems :many
+SELECT stream_id, repo_path, remote_url, branch, public_state
+FROM session_projection
+ORDER BY updated_at DESC
+LIMIT 100;
+
+-- name: ListActiveSessionProjectionRefs :many
+SELECT *
+FROM session_projection
+WHERE public_state IN ('queued', 'active.idle', 'active.busy')
+ORDER BY updated_at DESC;
+
+-- name: ListHydratableSessionProjectionRefs :many
+SELECT *
+FROM session_projection
+WHERE public_state IN ('queued', 'active.idle', 'active.busy', 'completing', 'deleting')
+ORDER BY updated_at DESC;
+
+-- name: ListReusableSessionProjectionRefs :many
+SELECT *
+FROM session_projection
+WHERE public_state = 'completed'
+  AND repo_path = $1
+  AND backend_id = $2
+ORDER BY updated_at DESC;
+
+-- name: ListDeletedSessionProjectionRefs :many
+SELECT *
+FROM session_projection
+WHERE public_state = 'deleted'
+ORDER BY updated_at ASC;
+
+-- name: ListLiveSessionProjectionStreamIDsByRemoteAndBranch :many
+SELECT stream_id
+FROM session_projection
+WHERE remote_url = $1
+  AND branch = $2
+  AND public_state IN ('queued', 'active.idle', 'active.busy', 'completing', 'deleting');
+
+-- name: ListSessionProjectionItemsAfterCursorByStatuses :many
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
+FROM session_projection
+WHERE (sqlc.arg(statuses)::TEXT = '' OR public_state = ANY(string_to_array(sqlc.arg(statuses)::TEXT, ',')))
+  AND (sqlc.arg(cursor)::TEXT = '' OR stream_id < sqlc.arg(cursor)::TEXT)
+ORDER BY stream_id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListSessionProjectionItemsBeforeCursorByStatuses :many
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
+FROM session_projection
+WHERE (sqlc.arg(statuses)::TEXT = '' OR public_state = ANY(string_to_array(sqlc.arg(statuses)::TEXT, ',')))
+  AND (sqlc.arg(cursor)::TEXT = '' OR stream_id > sqlc.arg(cursor)::TEXT)
+ORDER BY stream_id ASC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListSessionProjectionItemsOldestByStatuses :many
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
+FROM session_projection
+WHERE (sqlc.arg(statuses)::TEXT = '' OR public_state = ANY(string_to_array(sqlc.arg(statuses)::TEXT, ',')))
+ORDER BY stream_id ASC
+LIMIT sqlc.arg(page_size);
@@ -10,6 +10,16 @@ import (
 	"time"
 )
 
+const deletePRLatestSnapshot = `-- name: DeletePRLatestSnapshot :exec
+DELETE FROM pr_latest_snapshot
+WHERE stream_id = ?
+`
+
+func (q *Queries) DeletePRLatestSnapshot(ctx context.Context, streamID string) error {
+	_, err := q.db.ExecContext(ctx, deletePRLatestSnapshot, streamID)
+	return err
+}
+
 const getPRLatestSnapshotByRepoAndNumber = `-- name: GetPRLatestSnapshotByRepoAndNumber :one
 SELECT stream_id, provider, remote_url, repo_owner, repo_name, number, head_ref, head_sha, observed_at, snapshot_json
 FROM pr_latest_snapshot
@@ -73,6 +83,46 @@ func (q *Queries) GetPRLatestSnapshotByStreamID(ctx context.Context, streamID st
 	return i, err
 }
 
+const listPRLatestSnapshots = `-- name: ListPRLatestSnapshots :many
+SELECT stream_id, provider, remote_url, repo_owner, repo_name, number, head_ref, head_sha, observed_at, snapshot_json
+FROM pr_latest_snapshot
+ORDER BY observed_at ASC
+`
+
+func (q *Queries) ListPRLatestSnapshots(ctx context.Context) ([]PrLatestSnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, listPRLatestSnapshots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PrLatestSnapshot
+	for rows.Next() {
+		var i PrLatestSnapshot
+		if err := rows.Scan(
+			&i.StreamID,
+			&i.Provider,
+			&i.RemoteUrl,
+			&i.RepoOwner,
+			&i.RepoName,
+			&i.Number,
+			&i.HeadRef,
+			&i.HeadSha,
+			&i.ObservedAt,
+			&i.SnapshotJson,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPRLatestSnapshotsByRemoteAndBranch = `-- name: ListPRLatestSnapshotsByRemoteAndBranch :many
 SELECT stream_id, provider, remote_url, repo_owner, repo_name, number, head_ref, head_sha, observed_at, snapshot_json
 FROM pr_latest_snapshot
@@ -0,0 +1,19 @@
+-- name: InsertHookSchedule :exec
+INSERT INTO hook_schedule (
+  rule,
+  cause_id,
+  topic,
+  cause_json,
+  fire_at
+) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(rule, cause_id) DO NOTHING;
+
+-- name: ListHookSchedules :many
+SELECT *
+FROM hook_schedule
+ORDER BY fire_at ASC;
+
+-- name: DeleteHookSchedule :exec
+DELETE FROM hook_schedule
+WHERE rule = ?
+  AND cause_id = ?;
@@ -41,3 +41,12 @@ FROM pr_latest_snapshot
 WHERE remote_url = ?
   AND head_ref = ?
 ORDER BY observed_at DESC;
+
+-- name: ListPRLatestSnapshots :many
+SELECT *
+FROM pr_latest_snapshot
+ORDER BY observed_at ASC;
+
+-- name: DeletePRLatestSnapshot :exec
+DELETE FROM pr_latest_snapshot
+WHERE stream_id = ?;
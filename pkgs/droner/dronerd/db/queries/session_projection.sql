@@ -15,6 +15,8 @@ INSERT INTO session_projection (
   pr_state,
   pr_ci_state,
   pr_updated_at,
+  pr_url,
+  base_branch,
   created_at,
   updated_at
 ) VALUES (
@@ -34,6 +36,8 @@ INSERT INTO session_projection (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 )
 ON CONFLICT(stream_id) DO UPDATE SET
@@ -51,6 +55,8 @@ ON CONFLICT(stream_id) DO UPDATE SET
   pr_state = excluded.pr_state,
   pr_ci_state = excluded.pr_ci_state,
   pr_updated_at = excluded.pr_updated_at,
+  pr_url = excluded.pr_url,
+  base_branch = excluded.base_branch,
   updated_at = excluded.updated_at;
 
 -- name: PatchSessionProjection :exec
@@ -115,7 +121,7 @@ ORDER BY updated_at DESC
 LIMIT 100;
 
 -- name: ListSessionProjectionItemsAfterCursorByStatuses :many
-SELECT stream_id, repo_path, remote_url, branch, public_state
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
 FROM session_projection
 WHERE ((? = '') OR (',' || ? || ',') LIKE '%,' || public_state || ',%')
   AND (? = '' OR stream_id < ?)
@@ -123,7 +129,7 @@ ORDER BY stream_id DESC
 LIMIT ?;
 
 -- name: ListSessionProjectionItemsBeforeCursorByStatuses :many
-SELECT stream_id, repo_path, remote_url, branch, public_state
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
 FROM session_projection
 WHERE ((? = '') OR (',' || ? || ',') LIKE '%,' || public_state || ',%')
   AND (? = '' OR stream_id > ?)
@@ -131,7 +137,7 @@ ORDER BY stream_id ASC
 LIMIT ?;
 
 -- name: ListSessionProjectionItemsOldestByStatuses :many
-SELECT stream_id, repo_path, remote_url, branch, public_state
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
 FROM session_projection
 WHERE ((? = '') OR (',' || ? || ',') LIKE '%,' || public_state || ',%')
 ORDER BY stream_id ASC
@@ -156,3 +162,16 @@ WHERE public_state = 'completed'
   AND repo_path = ?
   AND backend_id = ?
 ORDER BY updated_at DESC;
+
+-- name: ListDeletedSessionProjectionRefs :many
+SELECT *
+FROM session_projection
+WHERE public_state = 'deleted'
+ORDER BY updated_at ASC;
+
+-- name: ListLiveSessionProjectionStreamIDsByRemoteAndBranch :many
+SELECT stream_id
+FROM session_projection
+WHERE remote_url = ?
+  AND branch = ?
+  AND public_state IN ('queued', 'active.idle', 'active.busy', 'completing', 'deleting');
@@ -22,7 +22,7 @@ func (q *Queries) DeleteSessionProjection(ctx context.Context, streamID string)
 }
 
 const getBlockedSessionProjectionByRepoPathAndBranch = `-- name: GetBlockedSessionProjectionByRepoPathAndBranch :one
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE repo_path = ?
   AND branch = ?
@@ -57,12 +57,14 @@ func (q *Queries) GetBlockedSessionProjectionByRepoPathAndBranch(ctx context.Con
 		&i.PrState,
 		&i.PrCiState,
 		&i.PrUpdatedAt,
+		&i.PrUrl,
+		&i.BaseBranch,
 	)
 	return i, err
 }
 
 const getCurrentSessionProjectionByBranch = `-- name: GetCurrentSessionProjectionByBranch :one
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE branch = ?
 ORDER BY created_at DESC
@@ -90,12 +92,14 @@ func (q *Queries) GetCurrentSessionProjectionByBranch(ctx context.Context, branc
 		&i.PrState,
 		&i.PrCiState,
 		&i.PrUpdatedAt,
+		&i.PrUrl,
+		&i.BaseBranch,
 	)
 	return i, err
 }
 
 const getLatestNavigationSessionProjectionByBranch = `-- name: GetLatestNavigationSessionProjectionByBranch :one
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE branch = ?
   AND public_state IN ('active.idle', 'active.busy', 'completed')
@@ -124,12 +128,14 @@ func (q *Queries) GetLatestNavigationSessionProjectionByBranch(ctx context.Conte
 		&i.PrState,
 		&i.PrCiState,
 		&i.PrUpdatedAt,
+		&i.PrUrl,
+		&i.BaseBranch,
 	)
 	return i, err
 }
 
 const getSessionProjectionByStreamID = `-- name: GetSessionProjectionByStreamID :one
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE stream_id = ?
 `
@@ -155,12 +161,14 @@ func (q *Queries) GetSessionProjectionByStreamID(ctx context.Context, streamID s
 		&i.PrState,
 		&i.PrCiState,
 		&i.PrUpdatedAt,
+		&i.PrUrl,
+		&i.BaseBranch,
 	)
 	return i, err
 }
 
 const getSessionProjectionByWorktreePath = `-- name: GetSessionProjectionByWorktreePath :one
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE worktree_path = ?
 ORDER BY created_at DESC
@@ -188,12 +196,14 @@ func (q *Queries) GetSessionProjectionByWorktreePath(ctx context.Context, worktr
 		&i.PrState,
 		&i.PrCiState,
 		&i.PrUpdatedAt,
+		&i.PrUrl,
+		&i.BaseBranch,
 	)
 	return i, err
 }
 
 const listActiveSessionProjectionRefs = `-- name: ListActiveSessionProjectionRefs :many
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE public_state IN ('queued', 'active.idle', 'active.busy')
 ORDER BY updated_at DESC
@@ -226,6 +236,8 @@ func (q *Queries) ListActiveSessionProjectionRefs(ctx context.Context) ([]Sessio
 			&i.PrState,
 			&i.PrCiState,
 			&i.PrUpdatedAt,
+			&i.PrUrl,
+			&i.BaseBranch,
 		); err != nil {
 			return nil, err
 		}
@@ -284,8 +296,58 @@ func (q *Queries) ListAllSessionProjectionItems(ctx context.Context) ([]ListAllS
 	return items, nil
 }
 
+const listDeletedSessionProjectionRefs = `-- name: ListDeletedSessionProjectionRefs :many
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
+FROM session_projection
+WHERE public_state = 'deleted'
+ORDER BY updated_at ASC
+`
+
+func (q *Queries) ListDeletedSessionProjectionRefs(ctx context.Context) ([]SessionProjection, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedSessionProjectionRefs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SessionProjection
+	for rows.Next() {
+		var i SessionProjection
+		if err := rows.Scan(
+			&i.StreamID,
+			&i.Harness,
+			&i.Branch,
+			&i.BackendID,
+			&i.RepoPath,
+			&i.WorktreePath,
+			&i.RemoteUrl,
+			&i.AgentConfig,
+			&i.LifecycleState,
+			&i.PublicState,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PrNumber,
+			&i.PrState,
+			&i.PrCiState,
+			&i.PrUpdatedAt,
+			&i.PrUrl,
+			&i.BaseBranch,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listHydratableSessionProjectionRefs = `-- name: ListHydratableSessionProjectionRefs :many
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE public_state IN ('queued', 'active.idle', 'active.busy', 'completing', 'deleting')
 ORDER BY updated_at DESC
@@ -318,6 +380,8 @@ func (q *Queries) ListHydratableSessionProjectionRefs(ctx context.Context) ([]Se
 			&i.PrState,
 			&i.PrCiState,
 			&i.PrUpdatedAt,
+			&i.PrUrl,
+			&i.BaseBranch,
 		); err != nil {
 			return nil, err
 		}
@@ -332,8 +396,44 @@ func (q *Queries) ListHydratableSessionProjectionRefs(ctx context.Context) ([]Se
 	return items, nil
 }
 
+const listLiveSessionProjectionStreamIDsByRemoteAndBranch = `-- name: ListLiveSessionProjectionStreamIDsByRemoteAndBranch :many
+SELECT stream_id
+FROM session_projection
+WHERE remote_url = ?
+  AND branch = ?
+  AND public_state IN ('queued', 'active.idle', 'active.busy', 'completing', 'deleting')
+`
+
+type ListLiveSessionProjectionStreamIDsByRemoteAndBranchParams struct {
+	RemoteUrl string
+	Branch    sql.NullString
+}
+
+func (q *Queries) ListLiveSessionProjectionStreamIDsByRemoteAndBranch(ctx context.Context, arg ListLiveSessionProjectionStreamIDsByRemoteAndBranchParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listLiveSessionProjectionStreamIDsByRemoteAndBranch, arg.RemoteUrl, arg.Branch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var stream_id string
+		if err := rows.Scan(&stream_id); err != nil {
+			return nil, err
+		}
+		items = append(items, stream_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listReusableSessionProjectionRefs = `-- name: ListReusableSessionProjectionRefs :many
-SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at
+SELECT stream_id, harness, branch, backend_id, repo_path, worktree_path, remote_url, agent_config, lifecycle_state, public_state, last_error, created_at, updated_at, pr_number, pr_state, pr_ci_state, pr_updated_at, pr_url, base_branch
 FROM session_projection
 WHERE public_state = 'completed'
   AND repo_path = ?
@@ -373,6 +473,8 @@ func (q *Queries) ListReusableSessionProjectionRefs(ctx context.Context, arg Lis
 			&i.PrState,
 			&i.PrCiState,
 			&i.PrUpdatedAt,
+			&i.PrUrl,
+			&i.BaseBranch,
 		); err != nil {
 			return nil, err
 		}
@@ -388,7 +490,7 @@ func (q *Queries) ListReusableSessionProjectionRefs(ctx context.Context, arg Lis
 }
 
 const listSessionProjectionItemsAfterCursorByStatuses = `-- name: ListSessionProjectionItemsAfterCursorByStatuses :many
-SELECT stream_id, repo_path, remote_url, branch, public_state
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
 FROM session_projection
 WHERE ((? = '') OR (',' || ? || ',') LIKE '%,' || public_state || ',%')
   AND (? = '' OR stream_id < ?)
@@ -410,6 +512,10 @@ type ListSessionProjectionItemsAfterCursorByStatusesRow struct {
 	RemoteUrl   string
 	Branch      sql.NullString
 	PublicState string
+	PrNumber    sql.NullInt64
+	PrState     sql.NullString
+	PrCiState   sql.NullString
+	PrUrl       sql.NullString
 }
 
 func (q *Queries) ListSessionProjectionItemsAfterCursorByStatuses(ctx context.Context, arg ListSessionProjectionItemsAfterCursorByStatusesParams) ([]ListSessionProjectionItemsAfterCursorByStatusesRow, error) {
@@ -433,6 +539,10 @@ func (q *Queries) ListSessionProjectionItemsAfterCursorByStatuses(ctx context.Co
 			&i.RemoteUrl,
 			&i.Branch,
 			&i.PublicState,
+			&i.PrNumber,
+			&i.PrState,
+			&i.PrCiState,
+			&i.PrUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -448,7 +558,7 @@ func (q *Queries) ListSessionProjectionItemsAfterCursorByStatuses(ctx context.Co
 }
 
 const listSessionProjectionItemsBeforeCursorByStatuses = `-- name: ListSessionProjectionItemsBeforeCursorByStatuses :many
-SELECT stream_id, repo_path, remote_url, branch, public_state
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
 FROM session_projection
 WHERE ((? = '') OR (',' || ? || ',') LIKE '%,' || public_state || ',%')
   AND (? = '' OR stream_id > ?)
@@ -470,6 +580,10 @@ type ListSessionProjectionItemsBeforeCursorByStatusesRow struct {
 	RemoteUrl   string
 	Branch      sql.NullString
 	PublicState string
+	PrNumber    sql.NullInt64
+	PrState     sql.NullString
+	PrCiState   sql.NullString
+	PrUrl       sql.NullString
 }
 
 func (q *Queries) ListSessionProjectionItemsBeforeCursorByStatuses(ctx context.Context, arg ListSessionProjectionItemsBeforeCursorByStatusesParams) ([]ListSessionProjectionItemsBeforeCursorByStatusesRow, error) {
@@ -493,6 +607,10 @@ func (q *Queries) ListSessionProjectionItemsBeforeCursorByStatuses(ctx context.C
 			&i.RemoteUrl,
 			&i.Branch,
 			&i.PublicState,
+			&i.PrNumber,
+			&i.PrState,
+			&i.PrCiState,
+			&i.PrUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -508,7 +626,7 @@ func (q *Queries) ListSessionProjectionItemsBeforeCursorByStatuses(ctx context.C
 }
 
 const listSessionProjectionItemsOldestByStatuses = `-- name: ListSessionProjectionItemsOldestByStatuses :many
-SELECT stream_id, repo_path, remote_url, branch, public_state
+SELECT stream_id, repo_path, remote_url, branch, public_state, pr_number, pr_state, pr_ci_state, pr_url
 FROM session_projection
 WHERE ((? = '') OR (',' || ? || ',') LIKE '%,' || public_state || ',%')
 ORDER BY stream_id ASC
@@ -527,6 +645,10 @@ type ListSessionProjectionItemsOldestByStatusesRow struct {
 	RemoteUrl   string
 	Branch      sql.NullString
 	PublicState string
+	PrNumber    sql.NullInt64
+	PrState     sql.NullString
+	PrCiState   sql.NullString
+	PrUrl       sql.NullString
 }
 
 func (q *Queries) ListSessionProjectionItemsOldestByStatuses(ctx context.Context, arg ListSessionProjectionItemsOldestByStatusesParams) ([]ListSessionProjectionItemsOldestByStatusesRow, error) {
@@ -544,6 +666,10 @@ func (q *Queries) ListSessionProjectionItemsOldestByStatuses(ctx context.Context
 			&i.RemoteUrl,
 			&i.Branch,
 			&i.PublicState,
+			&i.PrNumber,
+			&i.PrState,
+			&i.PrCiState,
+			&i.PrUrl,
 		); err != nil {
 			return nil, err
 		}
@@ -648,6 +774,8 @@ INSERT INTO session_projection (
   pr_state,
   pr_ci_state,
   pr_updated_at,
+  pr_url,
+  base_branch,
   created_at,
   updated_at
 ) VALUES (
@@ -667,6 +795,8 @@ INSERT INTO session_projection (
   ?,
   ?,
   ?,
+  ?,
+  ?,
   ?
 )
 ON CONFLICT(stream_id) DO UPDATE SET
@@ -684,6 +814,8 @@ ON CONFLICT(stream_id) DO UPDATE SET
   pr_state = excluded.pr_state,
   pr_ci_state = excluded.pr_ci_state,
   pr_updated_at = excluded.pr_updated_at,
+  pr_url = excluded.pr_url,
+  base_branch = excluded.base_branch,
   updated_at = excluded.updated_at
 `
 
@@ -703,6 +835,8 @@ type UpsertSessionProjectionParams struct {
 	PrState        sql.NullString
 	PrCiState      sql.NullString
 	PrUpdatedAt    sql.NullTime
+	PrUrl          sql.NullString
+	BaseBranch     sql.NullString
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -724,6 +858,8 @@ func (q *Queries) UpsertSessionProjection(ctx context.Context, arg UpsertSession
 		arg.PrState,
 		arg.PrCiState,
 		arg.PrUpdatedAt,
+		arg.PrUrl,
+		arg.BaseBranch,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -0,0 +1,122 @@
+package eventdebug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	defaultGraphMaxNodes   = 200
+	defaultCorrelatedLimit = 200
+	maxCausationDepth      = 64
+)
+
+type GraphOptions struct {
+	MaxNodes        int
+	CorrelatedLimit int
+}
+
+type CausationNode struct {
+	Event       Event            `json:"event"`
+	Depth       int              `json:"depth"`
+	SinceParent string           `json:"sinceParent,omitempty"`
+	SinceRoot   string           `json:"sinceRoot"`
+	Focus       bool             `json:"focus,omitempty"`
+	Children    []*CausationNode `json:"children,omitempty"`
+}
+
+// CausationGraph is the causation tree an event belongs to, rooted at its
+// oldest known ancestor. Correlated lists every event sharing the focus
+// event's correlation ID, which also covers events that were emitted without
+// an explicit causation link.
+type CausationGraph struct {
+	FocusID            string         `json:"focusId"`
+	Root               *CausationNode `json:"root"`
+	NodeCount          int            `json:"nodeCount"`
+	Truncated          bool           `json:"truncated"`
+	MissingCausationID string         `json:"missingCausationId,omitempty"`
+	Correlated         []Event        `json:"correlated"`
+}
+
+// BuildCausationGraph walks causation links up from eventID to the root cause
+// and then down again to every event the root led to, across topics.
+func BuildCausationGraph(ctx context.Context, store Store, eventID string, opts GraphOptions) (CausationGraph, error) {
+	maxNodes := opts.MaxNodes
+	if maxNodes <= 0 {
+		maxNodes = defaultGraphMaxNodes
+	}
+	correlatedLimit := opts.CorrelatedLimit
+	if correlatedLimit <= 0 {
+		correlatedLimit = defaultCorrelatedLimit
+	}
+
+	focus, err := store.LoadEvent(ctx, eventID)
+	if err != nil {
+		return CausationGraph{}, err
+	}
+	graph := CausationGraph{FocusID: focus.ID, Correlated: make([]Event, 0)}
+
+	// The path from the root to the focus event is always kept, even when the
+	// node limit cuts the rest of the tree short.
+	path := map[string]bool{focus.ID: true}
+	root := focus
+	for root.CausationID != "" {
+		if len(path) > maxCausationDepth || path[root.CausationID] {
+			graph.Truncated = true
+			break
+		}
+		parent, err := store.LoadEvent(ctx, root.CausationID)
+		if errors.Is(err, ErrEventNotFound) {
+			graph.MissingCausationID = root.CausationID
+			break
+		}
+		if err != nil {
+			return CausationGraph{}, fmt.Errorf("load causation %s: %w", root.CausationID, err)
+		}
+		path[parent.ID] = true
+		root = parent
+	}
+
+	graph.Root = &CausationNode{Event: root, SinceRoot: fmtElapsedBetween(root.OccurredAt, root.OccurredAt), Focus: root.ID == focus.ID}
+	graph.NodeCount = 1
+	visited := map[string]bool{root.ID: true}
+	queue := []*CausationNode{graph.Root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		children, err := store.ListCausedBy(ctx, node.Event.ID)
+		if err != nil {
+			return CausationGraph{}, err
+		}
+		for _, child := range children {
+			if visited[child.ID] {
+				continue
+			}
+			if graph.NodeCount >= maxNodes && !path[child.ID] {
+				graph.Truncated = true
+				continue
+			}
+			visited[child.ID] = true
+			childNode := &CausationNode{
+				Event:       child,
+				Depth:       node.Depth + 1,
+				SinceParent: fmtElapsedBetween(node.Event.OccurredAt, child.OccurredAt),
+				SinceRoot:   fmtElapsedBetween(root.OccurredAt, child.OccurredAt),
+				Focus:       child.ID == focus.ID,
+			}
+			node.Children = append(node.Children, childNode)
+			queue = append(queue, childNode)
+			graph.NodeCount++
+		}
+	}
+
+	if focus.CorrelationID != "" {
+		correlated, err := store.ListCorrelated(ctx, focus.CorrelationID, correlatedLimit)
+		if err != nil {
+			return CausationGraph{}, err
+		}
+		graph.Correlated = correlated
+	}
+	return graph, nil
+}
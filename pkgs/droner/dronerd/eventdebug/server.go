@@ -20,6 +20,7 @@ type ServerOptions struct {
 	DefaultListLimit   int
 	DefaultStreamLimit int
 	MainServerURL      string
+	TailPollInterval   time.Duration
 }
 
 type Server struct {
@@ -28,6 +29,7 @@ type Server struct {
 	defaultListLimit   int
 	defaultStreamLimit int
 	mainServerURL      string
+	tailPollInterval   time.Duration
 	tmpl               *template.Template
 	mux                *http.ServeMux
 }
@@ -45,6 +47,10 @@ func NewServer(store Store, opts ServerOptions) *Server {
 	if streamLimit <= 0 {
 		streamLimit = 500
 	}
+	tailPollInterval := opts.TailPollInterval
+	if tailPollInterval <= 0 {
+		tailPollInterval = defaultTailPollInterval
+	}
 
 	s := &Server{
 		store:              store,
@@ -52,6 +58,7 @@ func NewServer(store Store, opts ServerOptions) *Server {
 		defaultListLimit:   listLimit,
 		defaultStreamLimit: streamLimit,
 		mainServerURL:      strings.TrimRight(strings.TrimSpace(opts.MainServerURL), "/"),
+		tailPollInterval:   tailPollInterval,
 		tmpl: template.Must(template.New("page").Funcs(template.FuncMap{
 			"pathEscape":              url.PathEscape,
 			"prettyJSON":              prettyJSON,
@@ -60,6 +67,8 @@ func NewServer(store Store, opts ServerOptions) *Server {
 			"fmtElapsedSincePrevious": fmtElapsedSincePrevious,
 			"topicSelected":           topicSelected,
 			"streamLink":              streamLink,
+			"eventStreamLink":         eventStreamLink,
+			"eventLink":               eventLink,
 		}).Parse(pageTemplate)),
 		mux: http.NewServeMux(),
 	}
@@ -104,6 +113,11 @@ func (s *Server) routes() {
 	s.mux.HandleFunc("/streams/", s.handleStreamPage)
 	s.mux.HandleFunc("/api/streams", s.handleListAPI)
 	s.mux.HandleFunc("/api/streams/", s.handleStreamAPI)
+	s.mux.HandleFunc("/events/", s.handleEventPage)
+	s.mux.HandleFunc("/api/events/", s.handleEventGraphAPI)
+	s.mux.HandleFunc("/tail", s.handleTailPage)
+	s.mux.HandleFunc("/api/tail", s.handleTailAPI)
+	s.mux.HandleFunc("/api/tail/stream", s.handleTailStream)
 	s.mux.HandleFunc("/healthz", s.handleHealth)
 }
 
@@ -275,6 +289,74 @@ func (s *Server) handleStreamAPI(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, stream)
 }
 
+func (s *Server) handleEventPage(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := trimPathPrefix(r.URL.Path, "/events/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	limit := parseLimit(r, s.defaultListLimit)
+	topics := r.URL.Query()["topic"]
+	streams, err := s.store.ListStreams(r.Context(), ListOptions{
+		Topics: topics,
+		Query:  r.URL.Query().Get("q"),
+		Limit:  limit,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list streams: %v", err), http.StatusInternalServerError)
+		return
+	}
+	graph, err := BuildCausationGraph(r.Context(), s.store, eventID, GraphOptions{})
+	if err != nil {
+		if errors.Is(err, ErrEventNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to build causation graph: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	data := pageData{
+		Title:       s.title,
+		Topics:      topics,
+		Query:       r.URL.Query().Get("q"),
+		Streams:     streams,
+		Graph:       &graph,
+		ListLimit:   limit,
+		StreamLimit: parseStreamLimit(r, s.defaultStreamLimit),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.tmpl.Execute(w, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to render page: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleEventGraphAPI(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/graph") {
+		http.NotFound(w, r)
+		return
+	}
+	eventID, ok := trimPathPrefix(strings.TrimSuffix(r.URL.Path, "/graph"), "/api/events/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	graph, err := BuildCausationGraph(r.Context(), s.store, eventID, GraphOptions{
+		MaxNodes:        parsePositiveInt(r.URL.Query().Get("max_nodes"), defaultGraphMaxNodes),
+		CorrelatedLimit: parseLimit(r, defaultCorrelatedLimit),
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrEventNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, map[string]any{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, graph)
+}
+
 func trimPathPrefix(path string, prefix string) (string, bool) {
 	if !strings.HasPrefix(path, prefix) {
 		return "", false
@@ -334,6 +416,17 @@ func streamLink(topics []string, query string, listLimit int, streamLimit int, s
 	return "/?" + q.Encode()
 }
 
+func eventStreamLink(event Event) string {
+	q := url.Values{}
+	q.Set("stream_topic", event.Topic)
+	q.Set("stream", event.StreamID)
+	return "/?" + q.Encode()
+}
+
+func eventLink(eventID string) string {
+	return "/events/" + url.PathEscape(eventID)
+}
+
 func selectedTopicForLoad(topics []string) string {
 	normalized := normalizeTopics(topics)
 	if len(normalized) == 1 {
@@ -417,7 +510,7 @@ func buildEventGroups(events []Event) []eventGroupView {
 			view.ElapsedSincePrevious = fmtElapsedSincePrevious(i, events)
 		}
 
-		if len(groups) == 0 || groups[len(groups)-1].Action != action {
+		if len(groups) == 0 || groups[len(groups)-1].Action != action || !sameStream(groups[len(groups)-1].Events[0].Event, event) {
 			groups = append(groups, eventGroupView{Action: action})
 		}
 
@@ -440,6 +533,10 @@ func buildEventGroups(events []Event) []eventGroupView {
 	return groups
 }
 
+func sameStream(a Event, b Event) bool {
+	return a.Topic == b.Topic && a.StreamID == b.StreamID
+}
+
 func formatVersionRange(first int64, last int64) string {
 	if first == last {
 		return fmt.Sprintf("v%d", first)
@@ -468,6 +565,8 @@ type pageData struct {
 	SelectedGroups []eventGroupView
 	SelectedStream string
 	SelectedTopic  string
+	Graph          *CausationGraph
+	Tail           *tailFilter
 	ListLimit      int
 	StreamLimit    int
 }
@@ -704,6 +803,52 @@ const pageTemplate = `<!doctype html>
       font-size: 12px;
       line-height: 1.45;
     }
+    .tree {
+      list-style: none;
+      margin: 0;
+      padding-left: 22px;
+      border-left: 1px dashed var(--line);
+      display: grid;
+      gap: 10px;
+    }
+    .tree.root {
+      padding-left: 0;
+      border-left: 0;
+    }
+    .tree .event.focus {
+      border-color: var(--accent);
+      box-shadow: 0 0 0 2px var(--accent-soft);
+    }
+    .tree > li > .event {
+      margin-bottom: 10px;
+    }
+    .correlated {
+      display: grid;
+      gap: 6px;
+      font-size: 12px;
+    }
+    .correlated div {
+      display: flex;
+      justify-content: space-between;
+      gap: 12px;
+      padding: 8px 10px;
+      border: 1px solid #ece4d6;
+      border-radius: 10px;
+      background: var(--panel);
+    }
+    .tail-controls {
+      display: flex;
+      flex-wrap: wrap;
+      gap: 8px;
+      align-items: center;
+    }
+    .tail-controls input {
+      width: auto;
+      flex: 1 1 160px;
+    }
+    .tail-controls button.paused {
+      background: #8a3d2b;
+    }
     .empty {
       padding: 28px;
       border: 1px dashed var(--line);
@@ -726,6 +871,7 @@ const pageTemplate = `<!doctype html>
     <aside class="sidebar">
       <h1>{{.Title}}</h1>
       <p class="muted">Internal event stream browser for debugging and replay inspection.</p>
+      <p><a href="/tail">live tail</a></p>
       <form class="search" method="get" action="/">
         <div class="topic-options">
           <label><input type="checkbox" name="topic" value="all" {{if topicSelected .Topics "all"}}checked{{end}}> all topics</label>
@@ -756,10 +902,95 @@ const pageTemplate = `<!doctype html>
       </div>
     </aside>
     <main class="content">
-      {{if .Selected}}
+      {{if .Tail}}
+        <section class="hero">
+          <h2>live tail</h2>
+          <form class="tail-controls" method="get" action="/tail">
+            <input type="text" name="stream" value="{{.Tail.StreamID}}" placeholder="stream id">
+            <input type="text" name="type" value="{{.Tail.EventTypePrefix}}" placeholder="event type prefix">
+            <input type="text" name="search" value="{{.Tail.Search}}" placeholder="payload search">
+            {{range .Tail.Topics}}<input type="hidden" name="topic" value="{{.}}">{{end}}
+            <button type="submit">Apply</button>
+            <button type="button" id="tail-toggle">Pause</button>
+          </form>
+          <p class="muted" id="tail-status">connecting</p>
+        </section>
+        <section class="events" id="tail-events" data-source="{{.Tail.EventsURL}}"></section>
+        <script>
+          (function () {
+            var container = document.getElementById("tail-events");
+            var status = document.getElementById("tail-status");
+            var toggle = document.getElementById("tail-toggle");
+            var paused = false;
+            var pending = [];
+
+            function apply(update) {
+              if (update.reset) {
+                container.innerHTML = "";
+              }
+              if (update.replaceLast && container.lastElementChild) {
+                container.lastElementChild.remove();
+              }
+              if (update.html) {
+                container.insertAdjacentHTML("beforeend", update.html);
+                window.scrollTo(0, document.body.scrollHeight);
+              }
+              status.textContent = "live, cursor " + update.cursor;
+            }
+
+            toggle.addEventListener("click", function () {
+              paused = !paused;
+              toggle.textContent = paused ? "Resume" : "Pause";
+              toggle.classList.toggle("paused", paused);
+              if (paused) {
+                status.textContent = "paused";
+                return;
+              }
+              pending.splice(0).forEach(apply);
+            });
+
+            var source = new EventSource(container.dataset.source);
+            source.onmessage = function (message) {
+              var update = JSON.parse(message.data);
+              if (paused) {
+                pending.push(update);
+                status.textContent = "paused, " + pending.length + " updates waiting";
+                return;
+              }
+              apply(update);
+            };
+            source.addEventListener("tail-error", function (message) {
+              status.textContent = "error: " + JSON.parse(message.data);
+            });
+            source.onerror = function () {
+              status.textContent = "reconnecting";
+            };
+          })();
+        </script>
+      {{else if .Graph}}
+        <section class="hero">
+          <h2>causation of {{.Graph.FocusID}}</h2>
+          <p class="muted">{{.Graph.NodeCount}} events caused by {{.Graph.Root.Event.EventType}} on {{.Graph.Root.Event.Topic}}/{{.Graph.Root.Event.StreamID}}.{{if .Graph.Truncated}} The graph was truncated.{{end}}</p>
+          {{with .Graph.MissingCausationID}}<p class="muted">root was caused by {{.}}, which is no longer in the event log.</p>{{end}}
+        </section>
+        <section class="events">
+          <ul class="tree root">{{template "causation-node" .Graph.Root}}</ul>
+          {{if .Graph.Correlated}}
+            <h3>correlation {{(index .Graph.Correlated 0).CorrelationID}}</h3>
+            <div class="correlated">
+              {{range .Graph.Correlated}}
+                <div>
+                  <a href="{{eventLink .ID}}">{{.Topic}} v{{.StreamVersion}} {{.EventType}}</a>
+                  <span class="muted">{{fmtTime .OccurredAt}}</span>
+                </div>
+              {{end}}
+            </div>
+          {{end}}
+        </section>
+      {{else if .Selected}}
         <section class="hero">
           <h2>{{.Selected.Summary.StreamID}}</h2>
-          <p class="muted">topic {{.Selected.Topic}}</p>
+          <p class="muted">topic {{.Selected.Topic}} | <a href="/tail?topic={{.Selected.Topic}}&amp;stream={{.Selected.Summary.StreamID}}">live tail</a></p>
           <p class="muted">{{.Selected.Summary.EventCount}} total events. {{fmtElapsedBetween .Selected.Summary.FirstOccurredAt .Selected.Summary.LastOccurredAt}} total. Showing up to {{$.StreamLimit}}.</p>
           <p class="muted">first {{fmtTime .Selected.Summary.FirstOccurredAt}} | last {{fmtTime .Selected.Summary.LastOccurredAt}}</p>
         </section>
@@ -794,8 +1025,8 @@ const pageTemplate = `<!doctype html>
                       </div>
                       <div class="event-body">
                        <div class="kv">
-                         <div><strong>event id</strong><br>{{$event.ID}}</div>
-                         <div><strong>causation</strong><br>{{if $event.CausationID}}{{$event.CausationID}}{{else}}-{{end}}</div>
+                         <div><strong>event id</strong><br><a href="{{eventLink $event.ID}}">{{$event.ID}}</a></div>
+                         <div><strong>causation</strong><br>{{if $event.CausationID}}<a href="{{eventLink $event.CausationID}}">{{$event.CausationID}}</a>{{else}}-{{end}}</div>
                          <div><strong>correlation</strong><br>{{if $event.CorrelationID}}{{$event.CorrelationID}}{{else}}-{{end}}</div>
                        </div>
                         {{if eq $.Selected.Topic "sessions"}}
@@ -834,8 +1065,8 @@ const pageTemplate = `<!doctype html>
                 </div>
                 <div class="event-body">
                   <div class="kv">
-                    <div><strong>event id</strong><br>{{$event.ID}}</div>
-                    <div><strong>causation</strong><br>{{if $event.CausationID}}{{$event.CausationID}}{{else}}-{{end}}</div>
+                    <div><strong>event id</strong><br><a href="{{eventLink $event.ID}}">{{$event.ID}}</a></div>
+                    <div><strong>causation</strong><br>{{if $event.CausationID}}<a href="{{eventLink $event.CausationID}}">{{$event.CausationID}}</a>{{else}}-{{end}}</div>
                     <div><strong>correlation</strong><br>{{if $event.CorrelationID}}{{$event.CorrelationID}}{{else}}-{{end}}</div>
                   </div>
                   {{if eq $.Selected.Topic "sessions"}}
@@ -867,4 +1098,67 @@ const pageTemplate = `<!doctype html>
     </main>
   </div>
 </body>
-</html>`
+</html>
+{{define "tail-group"}}
+{{if gt .EventCount 1}}
+<details class="event-group">
+  <summary class="event-group-summary">
+    <div class="event-head-main">
+      <div class="event-type">{{.VersionLabel}} {{.Action}}</div>
+      {{with index .Events 0}}<div class="muted"><a href="{{eventStreamLink .Event}}">{{.Event.Topic}}/{{.Event.StreamID}}</a></div>{{end}}
+      <div class="muted">{{if .Statuses}}{{range $i, $status := .Statuses}}{{if $i}} / {{end}}{{$status}}{{end}}{{else}}single event{{end}}</div>
+    </div>
+    <div class="event-head-side">
+      <div class="muted">{{.EventCount}} events</div>
+      <div class="muted">exec {{.ExecTime}}</div>
+      {{with .IdleTime}}<div class="muted">idle {{.}}</div>{{end}}
+    </div>
+  </summary>
+  <div class="event-group-body">
+    {{range .Events}}{{template "tail-event" .}}{{end}}
+  </div>
+</details>
+{{else}}
+{{template "tail-event" index .Events 0}}
+{{end}}
+{{end}}
+{{define "tail-event"}}
+<article class="event">
+  <div class="event-head">
+    <div class="event-head-main">
+      <div class="event-type">v{{.Event.StreamVersion}} {{.Event.EventType}}</div>
+      <div class="muted"><a href="{{eventStreamLink .Event}}">{{.Event.Topic}}/{{.Event.StreamID}}</a></div>
+    </div>
+    <div class="event-head-side">
+      <div class="muted">{{fmtTime .Event.OccurredAt}}</div>
+      {{with .ElapsedSincePrevious}}<div class="muted">{{.}}</div>{{end}}
+    </div>
+  </div>
+  <div class="event-body">
+    <div class="kv">
+      <div><strong>event id</strong><br><a href="{{eventLink .Event.ID}}">{{.Event.ID}}</a></div>
+      <div><strong>causation</strong><br>{{if .Event.CausationID}}<a href="{{eventLink .Event.CausationID}}">{{.Event.CausationID}}</a>{{else}}-{{end}}</div>
+      <div><strong>correlation</strong><br>{{if .Event.CorrelationID}}{{.Event.CorrelationID}}{{else}}-{{end}}</div>
+    </div>
+    <pre>{{prettyJSON .Event.Payload}}</pre>
+  </div>
+</article>
+{{end}}
+{{define "causation-node"}}
+<li>
+  <article class="event {{if .Focus}}focus{{end}}">
+    <div class="event-head">
+      <div class="event-head-main">
+        <div class="event-type"><a href="{{eventLink .Event.ID}}">{{.Event.EventType}}</a></div>
+        <div class="muted"><a href="{{eventStreamLink .Event}}">{{.Event.Topic}}/{{.Event.StreamID}}</a> v{{.Event.StreamVersion}}</div>
+      </div>
+      <div class="event-head-side">
+        <div class="muted">{{fmtTime .Event.OccurredAt}}</div>
+        <div class="muted">{{with .SinceParent}}+{{.}} after parent, {{end}}+{{.SinceRoot}} from root</div>
+      </div>
+    </div>
+    {{if .Focus}}<div class="event-body"><pre>{{prettyJSON .Event.Payload}}</pre></div>{{end}}
+  </article>
+  {{if .Children}}<ul class="tree">{{range .Children}}{{template "causation-node" .}}{{end}}</ul>{{end}}
+</li>
+{{end}}`
@@ -3,10 +3,12 @@ package eventdebug
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -55,6 +57,67 @@ func (m memoryStore) LoadStream(_ context.Context, streamID string, opts StreamO
 	return stream, nil
 }
 
+func (m memoryStore) LoadEvent(_ context.Context, eventID string) (Event, error) {
+	for _, event := range m.events() {
+		if event.ID == eventID {
+			return event, nil
+		}
+	}
+	return Event{}, ErrEventNotFound
+}
+
+func (m memoryStore) ListCausedBy(_ context.Context, eventID string) ([]Event, error) {
+	caused := make([]Event, 0)
+	for _, event := range m.events() {
+		if event.CausationID == eventID {
+			caused = append(caused, event)
+		}
+	}
+	return caused, nil
+}
+
+func (m memoryStore) ListCorrelated(_ context.Context, correlationID string, limit int) ([]Event, error) {
+	correlated := make([]Event, 0)
+	for _, event := range m.events() {
+		if event.CorrelationID == correlationID && len(correlated) < limit {
+			correlated = append(correlated, event)
+		}
+	}
+	return correlated, nil
+}
+
+func (m memoryStore) TailEvents(_ context.Context, opts TailOptions) (TailBatch, error) {
+	batch := TailBatch{Cursor: TailCursor{}, Events: make([]Event, 0)}
+	for _, event := range m.events() {
+		batch.Cursor[event.Topic]++
+		if batch.Cursor[event.Topic] <= opts.AfterCursor[event.Topic] || (opts.StreamID != "" && event.StreamID != opts.StreamID) || !strings.HasPrefix(event.EventType, opts.EventTypePrefix) || !strings.Contains(string(event.Payload), opts.Search) {
+			continue
+		}
+		batch.Events = append(batch.Events, event)
+	}
+	return batch, nil
+}
+
+func (m memoryStore) events() []Event {
+	keys := make([]string, 0, len(m.byID))
+	for key := range m.byID {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	seen := map[string]bool{}
+	events := make([]Event, 0)
+	for _, key := range keys {
+		for _, event := range m.byID[key].Events {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				events = append(events, event)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+	return events
+}
+
 func TestServerListAPI(t *testing.T) {
 	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
 	server := NewServer(memoryStore{
@@ -256,6 +319,235 @@ func TestServerDefaultsToAllTopicsAndHidesResetForPullRequests(t *testing.T) {
 	}
 }
 
+func causationStore(now time.Time) memoryStore {
+	return memoryStore{
+		streams: []StreamSummary{{Topic: topicSessions, StreamID: "session/a", EventCount: 2, FirstOccurredAt: now, LastOccurredAt: now}},
+		byID: map[string]Stream{
+			"pullrequests:github:owner/repo#1": {
+				Topic: topicPullRequests,
+				Events: []Event{
+					{ID: "pr-1", Topic: topicPullRequests, StreamID: "github:owner/repo#1", StreamVersion: 1, EventType: "pr.merged", OccurredAt: now, CorrelationID: "github:owner/repo#1", Payload: json.RawMessage(`{}`)},
+				},
+			},
+			"sessions:session/a": {
+				Topic: topicSessions,
+				Events: []Event{
+					{ID: "evt-1", Topic: topicSessions, StreamID: "session/a", StreamVersion: 1, EventType: "session.pr.merged", OccurredAt: now.Add(40 * time.Millisecond), CausationID: "pr-1", CorrelationID: "session/a", Payload: json.RawMessage(`{}`)},
+					{ID: "evt-2", Topic: topicSessions, StreamID: "session/a", StreamVersion: 2, EventType: "session.completion.requested", OccurredAt: now.Add(90 * time.Millisecond), CausationID: "evt-1", CorrelationID: "session/a", Payload: json.RawMessage(`{"reason":"merged"}`)},
+					{ID: "evt-3", Topic: topicSessions, StreamID: "session/a", StreamVersion: 3, EventType: "session.completed", OccurredAt: now.Add(300 * time.Millisecond), CausationID: "evt-2", CorrelationID: "session/a", Payload: json.RawMessage(`{}`)},
+				},
+			},
+		},
+	}
+}
+
+func TestBuildCausationGraphWalksAcrossTopics(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+	graph, err := BuildCausationGraph(context.Background(), causationStore(now), "evt-3", GraphOptions{})
+	if err != nil {
+		t.Fatalf("BuildCausationGraph: %v", err)
+	}
+	if graph.Root == nil || graph.Root.Event.ID != "pr-1" {
+		t.Fatalf("root = %#v, want pr-1", graph.Root)
+	}
+	if graph.NodeCount != 4 || graph.Truncated {
+		t.Fatalf("node count = %d truncated = %v, want 4 and false", graph.NodeCount, graph.Truncated)
+	}
+	node := graph.Root
+	for _, want := range []string{"evt-1", "evt-2", "evt-3"} {
+		if len(node.Children) != 1 || node.Children[0].Event.ID != want {
+			t.Fatalf("children of %s = %#v, want %s", node.Event.ID, node.Children, want)
+		}
+		node = node.Children[0]
+	}
+	if !node.Focus || node.SinceParent != "210ms" || node.SinceRoot != "300ms" {
+		t.Fatalf("focus node = %#v, want focus with 210ms after parent and 300ms from root", node)
+	}
+	if len(graph.Correlated) != 3 {
+		t.Fatalf("correlated = %#v, want the three session events", graph.Correlated)
+	}
+}
+
+func TestBuildCausationGraphKeepsFocusPathWhenTruncated(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+	store := causationStore(now)
+	stream := store.byID["sessions:session/a"]
+	stream.Events = append(stream.Events, Event{ID: "evt-4", Topic: topicSessions, StreamID: "session/a", StreamVersion: 4, EventType: "session.prompt.sent", OccurredAt: now.Add(50 * time.Millisecond), CausationID: "evt-1", CorrelationID: "session/a"})
+	store.byID["sessions:session/a"] = stream
+
+	graph, err := BuildCausationGraph(context.Background(), store, "evt-3", GraphOptions{MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("BuildCausationGraph: %v", err)
+	}
+	if !graph.Truncated {
+		t.Fatalf("expected graph to be truncated")
+	}
+	if graph.NodeCount != 4 {
+		t.Fatalf("node count = %d, want root path of 4", graph.NodeCount)
+	}
+
+	_, err = BuildCausationGraph(context.Background(), store, "missing", GraphOptions{})
+	if !errors.Is(err, ErrEventNotFound) {
+		t.Fatalf("err = %v, want ErrEventNotFound", err)
+	}
+}
+
+func TestBuildCausationGraphReportsMissingParent(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+	store := causationStore(now)
+	delete(store.byID, "pullrequests:github:owner/repo#1")
+
+	graph, err := BuildCausationGraph(context.Background(), store, "evt-2", GraphOptions{})
+	if err != nil {
+		t.Fatalf("BuildCausationGraph: %v", err)
+	}
+	if graph.Root.Event.ID != "evt-1" || graph.MissingCausationID != "pr-1" {
+		t.Fatalf("root = %s missing = %q, want evt-1 and pr-1", graph.Root.Event.ID, graph.MissingCausationID)
+	}
+}
+
+func TestServerEventGraphAPIAndPage(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+	server := NewServer(causationStore(now), ServerOptions{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events/evt-3/graph", nil)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body=%s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var payload CausationGraph
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if payload.FocusID != "evt-3" || payload.Root == nil || payload.Root.Event.ID != "pr-1" {
+		t.Fatalf("unexpected graph: %#v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/events/missing/graph", nil)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/events/evt-3", nil)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("page status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "causation of evt-3") || !strings.Contains(body, "4 events caused by pr.merged") {
+		t.Fatalf("expected graph hero, body=%s", body)
+	}
+	if !strings.Contains(body, "+210ms after parent, +300ms from root") {
+		t.Fatalf("expected node timings, body=%s", body)
+	}
+	if !strings.Contains(body, `class="event focus"`) {
+		t.Fatalf("expected focused node, body=%s", body)
+	}
+}
+
+func TestTailViewGroupsLikeStreamPage(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+	events := []Event{
+		{ID: "evt-1", Topic: topicSessions, StreamID: "session/a", StreamVersion: 1, EventType: "session.environment_provisioning.started", OccurredAt: now},
+		{ID: "evt-2", Topic: topicSessions, StreamID: "session/a", StreamVersion: 2, EventType: "session.environment_provisioning.success", OccurredAt: now.Add(time.Second)},
+		{ID: "evt-3", Topic: topicSessions, StreamID: "session/b", StreamVersion: 1, EventType: "session.environment_provisioning.started", OccurredAt: now.Add(2 * time.Second)},
+	}
+	view := &tailView{capacity: 3}
+
+	groups, reset, replace := view.push(events[:1])
+	if len(groups) != 1 || reset || replace {
+		t.Fatalf("first push = %d groups reset=%v replace=%v", len(groups), reset, replace)
+	}
+	groups, reset, replace = view.push(events[1:])
+	if reset || !replace {
+		t.Fatalf("second push reset=%v replace=%v, want replace only", reset, replace)
+	}
+	if len(groups) != 2 || groups[0].EventCount != 2 || groups[1].Events[0].Event.StreamID != "session/b" {
+		t.Fatalf("second push groups = %#v, want extended group plus session/b", groups)
+	}
+
+	groups, reset, _ = view.push([]Event{{ID: "evt-4", Topic: topicSessions, StreamID: "session/b", StreamVersion: 2, EventType: "session.environment_provisioning.success", OccurredAt: now.Add(3 * time.Second)}})
+	if !reset || len(groups) != 2 || groups[0].VersionLabel != "v2" {
+		t.Fatalf("overflow push reset=%v groups=%#v, want regrouped window", reset, groups)
+	}
+}
+
+func TestServerTailStreamSendsFilteredBackfill(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+	store := causationStore(now)
+	server := httptest.NewServer(NewServer(store, ServerOptions{TailPollInterval: 10 * time.Millisecond}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/tail/stream?stream=session%2Fa&type=session.completion", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("tail request: %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("content type = %q, want text/event-stream", got)
+	}
+
+	buf := make([]byte, 64*1024)
+	n, err := resp.Body.Read(buf)
+	if err != nil {
+		t.Fatalf("read first message: %v", err)
+	}
+	message := string(buf[:n])
+	if !strings.HasPrefix(message, "id: pullrequests:1,sessions:3\ndata: ") {
+		t.Fatalf("first message = %q, want cursor pullrequests:1,sessions:3", message)
+	}
+	var update tailUpdate
+	if err := json.Unmarshal([]byte(strings.TrimSpace(strings.SplitN(message, "data: ", 2)[1])), &update); err != nil {
+		t.Fatalf("unmarshal update: %v", err)
+	}
+	if !update.Reset || update.ReplaceLast {
+		t.Fatalf("first update = %#v, want reset", update)
+	}
+	if !strings.Contains(update.HTML, "v2 session.completion.requested") || strings.Contains(update.HTML, "session.pr.merged") {
+		t.Fatalf("first update html = %s, want only the filtered event", update.HTML)
+	}
+}
+
+func TestServerTailPageAndAPI(t *testing.T) {
+	now := time.Date(2026, 3, 29, 12, 0, 0, 0, time.UTC)
+	server := NewServer(causationStore(now), ServerOptions{})
+
+	req := httptest.NewRequest(http.MethodGet, "/tail?stream=session%2Fa&search=merged", nil)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("page status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `data-source="/api/tail/stream?search=merged&amp;stream=session%2Fa"`) {
+		t.Fatalf("expected filtered event source, body=%s", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/tail?after=pullrequests:1,sessions:1", nil)
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("api status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var batch TailBatch
+	if err := json.Unmarshal(rec.Body.Bytes(), &batch); err != nil {
+		t.Fatalf("unmarshal batch: %v", err)
+	}
+	if batch.Cursor.String() != "pullrequests:1,sessions:3" || len(batch.Events) != 2 || batch.Events[0].ID != "evt-2" {
+		t.Fatalf("batch = %#v, want evt-2 and evt-3 with cursor pullrequests:1,sessions:3", batch)
+	}
+}
+
 func TestServerResetProxyForwardsToMainServer(t *testing.T) {
 	var (
 		called  bool
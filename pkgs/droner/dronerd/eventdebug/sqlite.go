@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -150,43 +151,9 @@ func (s *SQLiteStore) LoadStream(ctx context.Context, streamID string, opts Stre
 	}
 	defer rows.Close()
 
-	events := make([]Event, 0)
-	for rows.Next() {
-		var evt Event
-		var occurredAtRaw any
-		var causationID sql.NullString
-		var correlationID sql.NullString
-		var payloadRaw any
-		if err := rows.Scan(
-			&evt.ID,
-			&evt.Topic,
-			&evt.StreamID,
-			&evt.StreamVersion,
-			&evt.EventType,
-			&evt.SchemaVersion,
-			&occurredAtRaw,
-			&causationID,
-			&correlationID,
-			&payloadRaw,
-		); err != nil {
-			return Stream{}, fmt.Errorf("scan event: %w", err)
-		}
-		occurredAt, err := parseSQLiteTime(occurredAtRaw)
-		if err != nil {
-			return Stream{}, fmt.Errorf("parse event time: %w", err)
-		}
-		payload, err := normalizeJSONPayload(payloadRaw)
-		if err != nil {
-			return Stream{}, fmt.Errorf("normalize payload: %w", err)
-		}
-		evt.OccurredAt = occurredAt
-		evt.CausationID = causationID.String
-		evt.CorrelationID = correlationID.String
-		evt.Payload = payload
-		events = append(events, evt)
-	}
-	if err := rows.Err(); err != nil {
-		return Stream{}, fmt.Errorf("iterate events: %w", err)
+	events, err := scanEvents(rows)
+	if err != nil {
+		return Stream{}, err
 	}
 	if len(events) == 0 {
 		return Stream{}, ErrStreamNotFound
@@ -212,6 +179,247 @@ func (s *SQLiteStore) LoadStream(ctx context.Context, streamID string, opts Stre
 	return stream, nil
 }
 
+func (s *SQLiteStore) LoadEvent(ctx context.Context, eventID string) (Event, error) {
+	trimmed := strings.TrimSpace(eventID)
+	if trimmed == "" {
+		return Event{}, ErrEventNotFound
+	}
+	events, err := s.queryEvents(ctx, "id = ?", "", 1, trimmed)
+	if err != nil {
+		return Event{}, fmt.Errorf("load event: %w", err)
+	}
+	if len(events) == 0 {
+		return Event{}, ErrEventNotFound
+	}
+	return events[0], nil
+}
+
+func (s *SQLiteStore) ListCausedBy(ctx context.Context, eventID string) ([]Event, error) {
+	events, err := s.queryEvents(ctx, "causation_id = ?", "occurred_at ASC, topic ASC, stream_version ASC", 0, strings.TrimSpace(eventID))
+	if err != nil {
+		return nil, fmt.Errorf("list caused events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *SQLiteStore) ListCorrelated(ctx context.Context, correlationID string, limit int) ([]Event, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	events, err := s.queryEvents(ctx, "correlation_id = ?", "occurred_at ASC, topic ASC, stream_version ASC", limit, strings.TrimSpace(correlationID))
+	if err != nil {
+		return nil, fmt.Errorf("list correlated events: %w", err)
+	}
+	return events, nil
+}
+
+// TailEvents pages through each topic by sequence. The head is read first so
+// a batch never includes events appended while it was being read.
+func (s *SQLiteStore) TailEvents(ctx context.Context, opts TailOptions) (TailBatch, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 200
+	}
+	head, err := s.tailHead(ctx, normalizeTopics(opts.Topics))
+	if err != nil {
+		return TailBatch{}, fmt.Errorf("load tail head: %w", err)
+	}
+
+	cursor := TailCursor{}
+	for topic, sequence := range opts.AfterCursor {
+		cursor[topic] = sequence
+	}
+	batch := TailBatch{Cursor: cursor, Events: make([]Event, 0)}
+	if len(head) == 0 {
+		return batch, nil
+	}
+
+	topics := make([]string, 0, len(head))
+	for topic := range head {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	ranges := make([]string, 0, len(topics))
+	args := make([]any, 0, len(topics)*3+4)
+	for _, topic := range topics {
+		ranges = append(ranges, "(topic = ? AND sequence > ? AND sequence <= ?)")
+		args = append(args, topic, opts.AfterCursor[topic], head[topic])
+	}
+	where := []string{"(" + strings.Join(ranges, " OR ") + ")"}
+	if streamID := strings.TrimSpace(opts.StreamID); streamID != "" {
+		where = append(where, "stream_id = ?")
+		args = append(args, streamID)
+	}
+	if prefix := strings.TrimSpace(opts.EventTypePrefix); prefix != "" {
+		where = append(where, "event_type LIKE ? ESCAPE '\\'")
+		args = append(args, escapeLike(prefix)+"%")
+	}
+	if search := strings.TrimSpace(opts.Search); search != "" {
+		where = append(where, "CAST(payload AS TEXT) LIKE ? ESCAPE '\\'")
+		args = append(args, "%"+escapeLike(search)+"%")
+	}
+	// Without a cursor the tail starts with the most recent matching events.
+	// The rowid only breaks ties between events that share a timestamp.
+	initial := len(opts.AfterCursor) == 0
+	order := "occurred_at ASC, rowid ASC"
+	if initial {
+		order = "occurred_at DESC, rowid DESC"
+	}
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT sequence, id, topic, stream_id, stream_version, event_type, schema_version, occurred_at, causation_id, correlation_id, payload
+		FROM %s
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?
+	`, s.tableName, strings.Join(where, " AND "), order), args...)
+	if err != nil {
+		return TailBatch{}, fmt.Errorf("tail events: %w", err)
+	}
+	defer rows.Close()
+
+	seen := TailCursor{}
+	for rows.Next() {
+		var sequence int64
+		evt, err := scanEvent(rows, &sequence)
+		if err != nil {
+			return TailBatch{}, err
+		}
+		batch.Events = append(batch.Events, evt)
+		seen[evt.Topic] = max(seen[evt.Topic], sequence)
+	}
+	if err := rows.Err(); err != nil {
+		return TailBatch{}, fmt.Errorf("iterate tail events: %w", err)
+	}
+	switch {
+	case initial:
+		for i, j := 0, len(batch.Events)-1; i < j; i, j = i+1, j-1 {
+			batch.Events[i], batch.Events[j] = batch.Events[j], batch.Events[i]
+		}
+		for topic, sequence := range head {
+			cursor[topic] = sequence
+		}
+	case len(batch.Events) == limit:
+		// A full page may have stopped short of the head, so only move past
+		// what it returned.
+		for topic, sequence := range seen {
+			cursor[topic] = sequence
+		}
+	default:
+		for topic, sequence := range head {
+			cursor[topic] = sequence
+		}
+	}
+	return batch, nil
+}
+
+func (s *SQLiteStore) tailHead(ctx context.Context, topics []string) (TailCursor, error) {
+	query := fmt.Sprintf(`SELECT topic, MAX(sequence) FROM %s`, s.tableName)
+	args := make([]any, 0, len(topics))
+	if len(topics) > 0 {
+		query += fmt.Sprintf(" WHERE topic IN (%s)", queryPlaceholders(len(topics)))
+		for _, topic := range topics {
+			args = append(args, topic)
+		}
+	}
+	rows, err := s.db.QueryContext(ctx, query+" GROUP BY topic", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	head := TailCursor{}
+	for rows.Next() {
+		var (
+			topic    string
+			sequence int64
+		)
+		if err := rows.Scan(&topic, &sequence); err != nil {
+			return nil, err
+		}
+		head[topic] = sequence
+	}
+	return head, rows.Err()
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (s *SQLiteStore) queryEvents(ctx context.Context, where string, orderBy string, limit int, args ...any) ([]Event, error) {
+	query := fmt.Sprintf(`
+		SELECT id, topic, stream_id, stream_version, event_type, schema_version, occurred_at, causation_id, correlation_id, payload
+		FROM %s
+		WHERE %s
+	`, s.tableName, where)
+	if orderBy != "" {
+		query += " ORDER BY " + orderBy
+	}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanEvents(rows)
+}
+
+func scanEvents(rows *sql.Rows) ([]Event, error) {
+	events := make([]Event, 0)
+	for rows.Next() {
+		evt, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, evt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate events: %w", err)
+	}
+	return events, nil
+}
+
+// scanEvent reads the event columns of the current row, after any leading
+// columns the query selected first.
+func scanEvent(rows *sql.Rows, leading ...any) (Event, error) {
+	var evt Event
+	var occurredAtRaw any
+	var causationID sql.NullString
+	var correlationID sql.NullString
+	var payloadRaw any
+	dest := append(leading,
+		&evt.ID,
+		&evt.Topic,
+		&evt.StreamID,
+		&evt.StreamVersion,
+		&evt.EventType,
+		&evt.SchemaVersion,
+		&occurredAtRaw,
+		&causationID,
+		&correlationID,
+		&payloadRaw,
+	)
+	if err := rows.Scan(dest...); err != nil {
+		return Event{}, fmt.Errorf("scan event: %w", err)
+	}
+	occurredAt, err := parseSQLiteTime(occurredAtRaw)
+	if err != nil {
+		return Event{}, fmt.Errorf("parse event time: %w", err)
+	}
+	payload, err := normalizeJSONPayload(payloadRaw)
+	if err != nil {
+		return Event{}, fmt.Errorf("normalize payload: %w", err)
+	}
+	evt.OccurredAt = occurredAt
+	evt.CausationID = causationID.String
+	evt.CorrelationID = correlationID.String
+	evt.Payload = payload
+	return evt, nil
+}
+
 func (s *SQLiteStore) resolveStreamTopic(ctx context.Context, streamID string) (string, error) {
 	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
 		SELECT DISTINCT topic
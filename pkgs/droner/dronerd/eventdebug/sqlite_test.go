@@ -3,6 +3,7 @@ package eventdebug
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ func TestSQLiteStore(t *testing.T) {
 	_, err = db.Exec(`
 		CREATE TABLE event_log (
 			topic TEXT NOT NULL,
+			sequence INTEGER NOT NULL,
 			id TEXT PRIMARY KEY,
 			stream_id TEXT NOT NULL,
 			stream_version INTEGER NOT NULL,
@@ -37,12 +39,12 @@ func TestSQLiteStore(t *testing.T) {
 	ts1 := time.Date(2026, 3, 29, 10, 0, 0, 0, time.UTC).Format(time.RFC3339Nano)
 	ts2 := time.Date(2026, 3, 29, 10, 1, 0, 0, time.UTC).Format(time.RFC3339Nano)
 	_, err = db.Exec(`
-		INSERT INTO event_log (topic, id, stream_id, stream_version, event_type, schema_version, occurred_at, causation_id, correlation_id, payload)
+		INSERT INTO event_log (topic, sequence, id, stream_id, stream_version, event_type, schema_version, occurred_at, causation_id, correlation_id, payload)
 		VALUES
-			('sessions', 'evt-1', 'session/a', 1, 'session.queued', 1, ?, '', 'corr-1', '{"repoPath":"/tmp/repo"}'),
-			('sessions', 'evt-2', 'session/a', 2, 'session.ready', 1, ?, 'evt-1', 'corr-1', '{"worktreePath":"/tmp/wt"}'),
-			('sessions', 'evt-3', 'session/b', 1, 'session.queued', 1, ?, '', 'corr-2', '{"repoPath":"/tmp/repo2"}'),
-			('pullrequests', 'evt-4', 'github:owner/repo#1', 1, 'pr.observed', 1, ?, '', 'corr-3', '{"number":1}');
+			('sessions', 1, 'evt-1', 'session/a', 1, 'session.queued', 1, ?, '', 'corr-1', '{"repoPath":"/tmp/repo"}'),
+			('sessions', 2, 'evt-2', 'session/a', 2, 'session.ready', 1, ?, 'evt-1', 'corr-1', '{"worktreePath":"/tmp/wt"}'),
+			('sessions', 3, 'evt-3', 'session/b', 1, 'session.queued', 1, ?, '', 'corr-2', '{"repoPath":"/tmp/repo2"}'),
+			('pullrequests', 1, 'evt-4', 'github:owner/repo#1', 1, 'pr.observed', 1, ?, '', 'corr-3', '{"number":1}');
 	`, ts1, ts2, ts2, ts2)
 	if err != nil {
 		t.Fatalf("insert rows: %v", err)
@@ -98,6 +100,74 @@ func TestSQLiteStore(t *testing.T) {
 	if stream.Events[0].Topic != topicSessions {
 		t.Fatalf("event topic = %q, want sessions", stream.Events[0].Topic)
 	}
+
+	event, err := store.LoadEvent(context.Background(), "evt-2")
+	if err != nil {
+		t.Fatalf("LoadEvent: %v", err)
+	}
+	if event.CausationID != "evt-1" || event.StreamID != "session/a" {
+		t.Fatalf("unexpected event: %#v", event)
+	}
+	if _, err := store.LoadEvent(context.Background(), "evt-missing"); !errors.Is(err, ErrEventNotFound) {
+		t.Fatalf("LoadEvent missing err = %v, want ErrEventNotFound", err)
+	}
+
+	caused, err := store.ListCausedBy(context.Background(), "evt-1")
+	if err != nil {
+		t.Fatalf("ListCausedBy: %v", err)
+	}
+	if len(caused) != 1 || caused[0].ID != "evt-2" {
+		t.Fatalf("caused events = %#v, want evt-2", caused)
+	}
+
+	tail, err := store.TailEvents(context.Background(), TailOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("TailEvents: %v", err)
+	}
+	if tail.Cursor.String() != "pullrequests:1,sessions:3" || len(tail.Events) != 2 || tail.Events[0].ID != "evt-3" || tail.Events[1].ID != "evt-4" {
+		t.Fatalf("tail = %#v, want the two most recent events with the head cursor", tail)
+	}
+	tail, err = store.TailEvents(context.Background(), TailOptions{AfterCursor: TailCursor{topicSessions: 1}, Topics: []string{topicSessions}, Search: "/tmp/", Limit: 1})
+	if err != nil {
+		t.Fatalf("TailEvents after cursor: %v", err)
+	}
+	if tail.Cursor.String() != "sessions:2" || len(tail.Events) != 1 || tail.Events[0].ID != "evt-2" {
+		t.Fatalf("tail page = %#v, want evt-2 with cursor sessions:2", tail)
+	}
+	tail, err = store.TailEvents(context.Background(), TailOptions{AfterCursor: TailCursor{topicSessions: 2, topicPullRequests: 1}, EventTypePrefix: "session.que"})
+	if err != nil {
+		t.Fatalf("TailEvents prefix: %v", err)
+	}
+	if tail.Cursor.String() != "pullrequests:1,sessions:3" || len(tail.Events) != 1 || tail.Events[0].ID != "evt-3" {
+		t.Fatalf("tail prefix = %#v, want evt-3", tail)
+	}
+
+	// Deleting the newest rows lets sqlite hand their rowids out again; the
+	// sequence cursor must still only return the new event.
+	if _, err := db.Exec(`DELETE FROM event_log WHERE id = 'evt-3'`); err != nil {
+		t.Fatalf("delete evt-3: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO event_log (topic, sequence, id, stream_id, stream_version, event_type, schema_version, occurred_at, causation_id, correlation_id, payload)
+		VALUES ('sessions', 4, 'evt-5', 'session/c', 1, 'session.queued', 1, ?, '', '', '{}');
+	`, ts2); err != nil {
+		t.Fatalf("insert evt-5: %v", err)
+	}
+	tail, err = store.TailEvents(context.Background(), TailOptions{AfterCursor: tail.Cursor})
+	if err != nil {
+		t.Fatalf("TailEvents after delete: %v", err)
+	}
+	if tail.Cursor.String() != "pullrequests:1,sessions:4" || len(tail.Events) != 1 || tail.Events[0].ID != "evt-5" {
+		t.Fatalf("tail after delete = %#v, want only evt-5", tail)
+	}
+
+	correlated, err := store.ListCorrelated(context.Background(), "corr-1", 10)
+	if err != nil {
+		t.Fatalf("ListCorrelated: %v", err)
+	}
+	if len(correlated) != 2 || correlated[0].ID != "evt-1" || correlated[1].ID != "evt-2" {
+		t.Fatalf("correlated events = %#v, want evt-1 and evt-2", correlated)
+	}
 }
 
 func TestSQLiteStoreLoadStreamAmbiguousTopic(t *testing.T) {
@@ -110,6 +180,7 @@ func TestSQLiteStoreLoadStreamAmbiguousTopic(t *testing.T) {
 	_, err = db.Exec(`
 		CREATE TABLE event_log (
 			topic TEXT NOT NULL,
+			sequence INTEGER NOT NULL,
 			id TEXT PRIMARY KEY,
 			stream_id TEXT NOT NULL,
 			stream_version INTEGER NOT NULL,
@@ -127,10 +198,10 @@ func TestSQLiteStoreLoadStreamAmbiguousTopic(t *testing.T) {
 
 	ts := time.Date(2026, 3, 29, 10, 0, 0, 0, time.UTC).Format(time.RFC3339Nano)
 	_, err = db.Exec(`
-		INSERT INTO event_log (topic, id, stream_id, stream_version, event_type, schema_version, occurred_at, causation_id, correlation_id, payload)
+		INSERT INTO event_log (topic, sequence, id, stream_id, stream_version, event_type, schema_version, occurred_at, causation_id, correlation_id, payload)
 		VALUES
-			('sessions', 'evt-1', 'shared', 1, 'session.queued', 1, ?, '', '', '{}'),
-			('pullrequests', 'evt-2', 'shared', 1, 'pr.observed', 1, ?, '', '', '{}');
+			('sessions', 1, 'evt-1', 'shared', 1, 'session.queued', 1, ?, '', '', '{}'),
+			('pullrequests', 1, 'evt-2', 'shared', 1, 'pr.observed', 1, ?, '', '', '{}');
 	`, ts, ts)
 	if err != nil {
 		t.Fatalf("insert rows: %v", err)
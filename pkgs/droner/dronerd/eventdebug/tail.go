@@ -0,0 +1,231 @@
+package eventdebug
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTailPollInterval = 500 * time.Millisecond
+	defaultTailWindow       = 500
+	tailKeepAliveInterval   = 15 * time.Second
+)
+
+type tailFilter struct {
+	Topics          []string
+	StreamID        string
+	EventTypePrefix string
+	Search          string
+}
+
+func parseTailFilter(r *http.Request) tailFilter {
+	q := r.URL.Query()
+	return tailFilter{
+		Topics:          q["topic"],
+		StreamID:        strings.TrimSpace(q.Get("stream")),
+		EventTypePrefix: strings.TrimSpace(q.Get("type")),
+		Search:          strings.TrimSpace(q.Get("search")),
+	}
+}
+
+func (f tailFilter) options(after TailCursor, limit int) TailOptions {
+	return TailOptions{
+		AfterCursor:     after,
+		Topics:          f.Topics,
+		StreamID:        f.StreamID,
+		EventTypePrefix: f.EventTypePrefix,
+		Search:          f.Search,
+		Limit:           limit,
+	}
+}
+
+func (f tailFilter) query() url.Values {
+	q := url.Values{}
+	for _, topic := range normalizeTopics(f.Topics) {
+		q.Add("topic", topic)
+	}
+	if f.StreamID != "" {
+		q.Set("stream", f.StreamID)
+	}
+	if f.EventTypePrefix != "" {
+		q.Set("type", f.EventTypePrefix)
+	}
+	if f.Search != "" {
+		q.Set("search", f.Search)
+	}
+	return q
+}
+
+// EventsURL is the SSE endpoint the tail page subscribes to.
+func (f tailFilter) EventsURL() string {
+	return "/api/tail/stream?" + f.query().Encode()
+}
+
+// tailUpdate tells the page how to apply a batch of rendered groups: Reset
+// clears what is on screen, ReplaceLast swaps out the last group because new
+// events extended it.
+type tailUpdate struct {
+	Cursor      TailCursor `json:"cursor"`
+	Reset       bool       `json:"reset"`
+	ReplaceLast bool       `json:"replaceLast"`
+	HTML        string     `json:"html"`
+}
+
+// tailView keeps the events a single subscriber has on screen so new events
+// are grouped exactly like buildEventGroups groups a stream page.
+type tailView struct {
+	window   []Event
+	groups   int
+	capacity int
+}
+
+func (v *tailView) push(events []Event) ([]eventGroupView, bool, bool) {
+	v.window = append(v.window, events...)
+	reset := false
+	if len(v.window) > v.capacity {
+		v.window = append([]Event(nil), v.window[len(v.window)-v.capacity:]...)
+		reset = true
+	}
+	groups := buildEventGroups(v.window)
+	from := 0
+	replace := false
+	if !reset && v.groups > 0 {
+		from = v.groups - 1
+		replace = true
+	}
+	v.groups = len(groups)
+	return groups[from:], reset, replace
+}
+
+func (s *Server) handleTailPage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tail" {
+		http.NotFound(w, r)
+		return
+	}
+	limit := parseLimit(r, s.defaultListLimit)
+	streams, err := s.store.ListStreams(r.Context(), ListOptions{Limit: limit})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list streams: %v", err), http.StatusInternalServerError)
+		return
+	}
+	filter := parseTailFilter(r)
+	data := pageData{
+		Title:       s.title,
+		Streams:     streams,
+		Tail:        &filter,
+		ListLimit:   limit,
+		StreamLimit: parseStreamLimit(r, s.defaultStreamLimit),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.tmpl.Execute(w, data); err != nil {
+		http.Error(w, fmt.Sprintf("failed to render page: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleTailAPI(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/tail" {
+		http.NotFound(w, r)
+		return
+	}
+	after := ParseTailCursor(r.URL.Query().Get("after"))
+	batch, err := s.store.TailEvents(r.Context(), parseTailFilter(r).options(after, parseLimit(r, s.defaultStreamLimit)))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, batch)
+}
+
+func (s *Server) handleTailStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	filter := parseTailFilter(r)
+	limit := parseLimit(r, s.defaultStreamLimit)
+
+	// Browsers resend the last cursor when they reconnect, in which case the
+	// page keeps what it already shows.
+	cursor := ParseTailCursor(r.Header.Get("Last-Event-ID"))
+	resume := len(cursor) > 0
+	batch, err := s.store.TailEvents(r.Context(), filter.options(cursor, limit))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to tail events: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	view := &tailView{capacity: defaultTailWindow}
+	groups, _, _ := view.push(batch.Events)
+	if err := s.writeTailUpdate(w, tailUpdate{Cursor: batch.Cursor, Reset: !resume}, groups); err != nil {
+		return
+	}
+	flusher.Flush()
+	cursor = batch.Cursor
+
+	ticker := time.NewTicker(s.tailPollInterval)
+	defer ticker.Stop()
+	lastWrite := time.Now()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		batch, err := s.store.TailEvents(r.Context(), filter.options(cursor, limit))
+		if err != nil {
+			if r.Context().Err() == nil {
+				_, _ = fmt.Fprintf(w, "event: tail-error\ndata: %s\n\n", strconv.Quote(err.Error()))
+				flusher.Flush()
+			}
+			return
+		}
+		cursor = batch.Cursor
+		if len(batch.Events) == 0 {
+			if time.Since(lastWrite) >= tailKeepAliveInterval {
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+				lastWrite = time.Now()
+			}
+			continue
+		}
+
+		groups, reset, replace := view.push(batch.Events)
+		if err := s.writeTailUpdate(w, tailUpdate{Cursor: cursor, Reset: reset, ReplaceLast: replace}, groups); err != nil {
+			return
+		}
+		flusher.Flush()
+		lastWrite = time.Now()
+	}
+}
+
+func (s *Server) writeTailUpdate(w http.ResponseWriter, update tailUpdate, groups []eventGroupView) error {
+	var html bytes.Buffer
+	for _, group := range groups {
+		if err := s.tmpl.ExecuteTemplate(&html, "tail-group", group); err != nil {
+			return err
+		}
+	}
+	update.HTML = html.String()
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\ndata: %s\n\n", update.Cursor, data)
+	return err
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 var ErrStreamNotFound = errors.New("event stream not found")
+var ErrEventNotFound = errors.New("event not found")
 
 const (
 	topicAll          = "all"
@@ -53,7 +58,76 @@ type StreamOptions struct {
 	Limit int
 }
 
+// TailCursor is the last sequence the tail has seen in each topic. Sequences
+// only grow within a topic, unlike rowids, which sqlite hands out again once
+// retention deletes the newest rows.
+type TailCursor map[string]int64
+
+// ParseTailCursor reads a cursor written by TailCursor.String. Malformed
+// entries are dropped, which restarts those topics at their latest events.
+func ParseTailCursor(raw string) TailCursor {
+	cursor := TailCursor{}
+	for _, entry := range strings.Split(strings.TrimSpace(raw), ",") {
+		topic, value, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		topic, err := url.QueryUnescape(topic)
+		if err != nil || topic == "" {
+			continue
+		}
+		sequence, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || sequence <= 0 {
+			continue
+		}
+		cursor[topic] = sequence
+	}
+	return cursor
+}
+
+func (c TailCursor) String() string {
+	topics := make([]string, 0, len(c))
+	for topic := range c {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	entries := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		entries = append(entries, url.QueryEscape(topic)+":"+strconv.FormatInt(c[topic], 10))
+	}
+	return strings.Join(entries, ",")
+}
+
+func (c TailCursor) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *TailCursor) UnmarshalText(text []byte) error {
+	*c = ParseTailCursor(string(text))
+	return nil
+}
+
+type TailOptions struct {
+	// AfterCursor is the cursor of the last batch; an empty cursor starts at
+	// the most recent matching events.
+	AfterCursor     TailCursor
+	Topics          []string
+	StreamID        string
+	EventTypePrefix string
+	Search          string
+	Limit           int
+}
+
+type TailBatch struct {
+	Cursor TailCursor `json:"cursor"`
+	Events []Event    `json:"events"`
+}
+
 type Store interface {
 	ListStreams(ctx context.Context, opts ListOptions) ([]StreamSummary, error)
 	LoadStream(ctx context.Context, streamID string, opts StreamOptions) (Stream, error)
+	LoadEvent(ctx context.Context, eventID string) (Event, error)
+	ListCausedBy(ctx context.Context, eventID string) ([]Event, error)
+	ListCorrelated(ctx context.Context, correlationID string, limit int) ([]Event, error)
+	TailEvents(ctx context.Context, opts TailOptions) (TailBatch, error)
 }
@@ -0,0 +1,368 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/google/uuid"
+)
+
+var ErrClosed = errors.New("memory eventlog is closed")
+
+type Backend struct {
+	mu          sync.Mutex
+	events      map[eventlog.Topic][]eventlog.Envelope
+	checkpoints map[eventlog.Topic]map[eventlog.SubscriberID]eventlog.Checkpoint
+	signal      chan struct{}
+	closed      bool
+}
+
+func New() *Backend {
+	return &Backend{
+		events:      make(map[eventlog.Topic][]eventlog.Envelope),
+		checkpoints: make(map[eventlog.Topic]map[eventlog.SubscriberID]eventlog.Checkpoint),
+		signal:      make(chan struct{}),
+	}
+}
+
+func (b *Backend) Append(ctx context.Context, topic eventlog.Topic, evt eventlog.PendingEvent) (eventlog.Envelope, error) {
+	if err := ctx.Err(); err != nil {
+		return eventlog.Envelope{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return eventlog.Envelope{}, ErrClosed
+	}
+
+	envelope := eventlog.Envelope{
+		ID:            eventlog.EventID(uuid.NewString()),
+		Topic:         topic,
+		StreamID:      evt.StreamID,
+		StreamVersion: b.nextStreamVersionLocked(topic, evt.StreamID),
+		Sequence:      b.nextSequenceLocked(topic),
+		Type:          evt.Type,
+		SchemaVersion: evt.SchemaVersion,
+		OccurredAt:    time.Now().UTC(),
+		CausationID:   evt.CausationID,
+		CorrelationID: evt.CorrelationID,
+		Payload:       clonePayload(evt.Payload),
+	}
+	b.appendLocked(envelope)
+	return cloneEnvelope(envelope), nil
+}
+
+func (b *Backend) ResetStreamToEvent(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, eventID eventlog.EventID) (eventlog.Envelope, error) {
+	if err := ctx.Err(); err != nil {
+		return eventlog.Envelope{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return eventlog.Envelope{}, ErrClosed
+	}
+
+	var target eventlog.Envelope
+	found := false
+	for _, evt := range b.events[topic] {
+		if evt.StreamID == streamID && evt.ID == eventID {
+			target = evt
+			found = true
+			break
+		}
+	}
+	if !found {
+		return eventlog.Envelope{}, eventlog.ErrEventNotFound
+	}
+
+	kept := b.events[topic][:0]
+	for _, evt := range b.events[topic] {
+		if evt.StreamID == streamID && evt.StreamVersion >= target.StreamVersion {
+			continue
+		}
+		kept = append(kept, evt)
+	}
+	b.events[topic] = kept
+
+	replayed := cloneEnvelope(target)
+	replayed.ID = eventlog.EventID(uuid.NewString())
+	replayed.Sequence = b.nextSequenceLocked(topic)
+	replayed.StreamVersion = b.nextStreamVersionLocked(topic, streamID)
+	replayed.OccurredAt = time.Now().UTC()
+	b.appendLocked(replayed)
+	return cloneEnvelope(replayed), nil
+}
+
+func (b *Backend) LoadStream(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, opts eventlog.LoadStreamOptions) ([]eventlog.Envelope, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 500
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	items := make([]eventlog.Envelope, 0)
+	for _, evt := range b.events[topic] {
+		if evt.StreamID != streamID || evt.StreamVersion <= opts.AfterVersion {
+			continue
+		}
+		items = append(items, cloneEnvelope(evt))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].StreamVersion < items[j].StreamVersion
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (b *Backend) ReadGlobal(ctx context.Context, topic eventlog.Topic, afterSequence int64, limit int) ([]eventlog.Envelope, error) {
+	if limit <= 0 {
+		limit = 64
+	}
+	for {
+		events, signal, err := b.readAvailable(topic, afterSequence, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			return events, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-signal:
+		}
+	}
+}
+
+func (b *Backend) LoadCheckpoint(ctx context.Context, topic eventlog.Topic, subscriber eventlog.SubscriberID) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, ErrClosed
+	}
+	return b.checkpoints[topic][subscriber].Sequence, nil
+}
+
+func (b *Backend) StoreCheckpoint(ctx context.Context, topic eventlog.Topic, subscriber eventlog.SubscriberID, sequence int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	checkpoints, ok := b.checkpoints[topic]
+	if !ok {
+		checkpoints = make(map[eventlog.SubscriberID]eventlog.Checkpoint)
+		b.checkpoints[topic] = checkpoints
+	}
+	checkpoints[subscriber] = eventlog.Checkpoint{Subscriber: subscriber, Sequence: sequence, UpdatedAt: time.Now().UTC()}
+	return nil
+}
+
+func (b *Backend) DeleteCheckpoint(ctx context.Context, topic eventlog.Topic, subscriber eventlog.SubscriberID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	delete(b.checkpoints[topic], subscriber)
+	return nil
+}
+
+func (b *Backend) ListCheckpoints(ctx context.Context, topic eventlog.Topic) ([]eventlog.Checkpoint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	checkpoints := make([]eventlog.Checkpoint, 0, len(b.checkpoints[topic]))
+	for _, checkpoint := range b.checkpoints[topic] {
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Subscriber < checkpoints[j].Subscriber
+	})
+	return checkpoints, nil
+}
+
+func (b *Backend) LatestSequence(ctx context.Context, topic eventlog.Topic) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, ErrClosed
+	}
+	events := b.events[topic]
+	if len(events) == 0 {
+		return 0, nil
+	}
+	return events[len(events)-1].Sequence, nil
+}
+
+func (b *Backend) StreamInfo(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID) (eventlog.StreamInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return eventlog.StreamInfo{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return eventlog.StreamInfo{}, ErrClosed
+	}
+	info := eventlog.StreamInfo{}
+	for _, evt := range b.events[topic] {
+		if evt.StreamID != streamID {
+			continue
+		}
+		info.Events++
+		if evt.Sequence > info.LastSequence {
+			info.LastSequence = evt.Sequence
+		}
+	}
+	return info, nil
+}
+
+func (b *Backend) DeleteStream(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, through int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, ErrClosed
+	}
+	deleted := int64(0)
+	kept := b.events[topic][:0]
+	for _, evt := range b.events[topic] {
+		if evt.StreamID == streamID && evt.Sequence <= through {
+			deleted++
+			continue
+		}
+		kept = append(kept, evt)
+	}
+	b.events[topic] = kept
+	return deleted, nil
+}
+
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	close(b.signal)
+	return nil
+}
+
+func (b *Backend) readAvailable(topic eventlog.Topic, afterSequence int64, limit int) ([]eventlog.Envelope, <-chan struct{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, ErrClosed
+	}
+
+	items := make([]eventlog.Envelope, 0)
+	for _, evt := range b.events[topic] {
+		if evt.Sequence <= afterSequence {
+			continue
+		}
+		items = append(items, cloneEnvelope(evt))
+		if len(items) == limit {
+			break
+		}
+	}
+	return items, b.signal, nil
+}
+
+// appendLocked keeps each topic ordered by sequence and wakes blocked readers.
+func (b *Backend) appendLocked(envelope eventlog.Envelope) {
+	events := b.events[envelope.Topic]
+	index := len(events)
+	for index > 0 && events[index-1].Sequence > envelope.Sequence {
+		index--
+	}
+	events = append(events, eventlog.Envelope{})
+	copy(events[index+1:], events[index:])
+	events[index] = envelope
+	b.events[envelope.Topic] = events
+
+	close(b.signal)
+	b.signal = make(chan struct{})
+}
+
+// nextSequenceLocked mirrors the sqlite backend: the next sequence follows the
+// highest stored event, but never lands at or below a subscriber checkpoint so
+// that events re-appended after a reset are not skipped.
+func (b *Backend) nextSequenceLocked(topic eventlog.Topic) int64 {
+	next := int64(1)
+	for _, evt := range b.events[topic] {
+		if evt.Sequence >= next {
+			next = evt.Sequence + 1
+		}
+	}
+	for _, checkpoint := range b.checkpoints[topic] {
+		if checkpoint.Sequence >= next {
+			next = checkpoint.Sequence + 1
+		}
+	}
+	return next
+}
+
+func (b *Backend) nextStreamVersionLocked(topic eventlog.Topic, streamID eventlog.StreamID) int64 {
+	next := int64(1)
+	for _, evt := range b.events[topic] {
+		if evt.StreamID == streamID && evt.StreamVersion >= next {
+			next = evt.StreamVersion + 1
+		}
+	}
+	return next
+}
+
+func cloneEnvelope(envelope eventlog.Envelope) eventlog.Envelope {
+	envelope.Payload = clonePayload(envelope.Payload)
+	return envelope
+}
+
+func clonePayload(payload []byte) []byte {
+	if payload == nil {
+		return []byte("null")
+	}
+	return append([]byte(nil), payload...)
+}
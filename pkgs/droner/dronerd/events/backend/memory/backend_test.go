@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog/eventlogtest"
+)
+
+func TestBackendConformance(t *testing.T) {
+	eventlogtest.RunBackendSuite(t, func(t *testing.T) eventlogtest.Backend {
+		backend := New()
+		t.Cleanup(func() {
+			if err := backend.Close(); err != nil {
+				t.Fatalf("backend.Close: %v", err)
+			}
+		})
+		return backend
+	})
+}
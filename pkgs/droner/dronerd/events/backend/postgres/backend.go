@@ -0,0 +1,452 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	backenddb "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/postgres/db"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+)
+
+// NotifyChannel is the LISTEN/NOTIFY channel appends publish their topic on.
+const NotifyChannel = "droner_eventlog"
+
+type Config struct {
+	DSN string
+	DB  *sql.DB
+	// PollInterval is the fallback re-read interval used when a notification
+	// is missed, e.g. while the listener connection is being re-established.
+	PollInterval time.Duration
+}
+
+type Backend struct {
+	db        *sql.DB
+	queries   *backenddb.Queries
+	ownsDB    bool
+	pollEvery time.Duration
+
+	mu     sync.Mutex
+	signal chan struct{}
+
+	cancelListen context.CancelFunc
+	listenDone   chan struct{}
+}
+
+func New(cfg Config) (*Backend, error) {
+	if cfg.DB == nil && cfg.DSN == "" {
+		return nil, errors.New("postgres eventlog requires a db or dsn")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+
+	db := cfg.DB
+	ownsDB := false
+	if db == nil {
+		opened, err := backenddb.OpenPostgresDB(cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+		db = opened
+		ownsDB = true
+	} else if err := backenddb.EnsureMigrations(context.Background(), db); err != nil {
+		return nil, err
+	}
+
+	listenCtx, cancel := context.WithCancel(context.Background())
+	b := &Backend{
+		db:           db,
+		queries:      backenddb.New(db),
+		ownsDB:       ownsDB,
+		pollEvery:    cfg.PollInterval,
+		signal:       make(chan struct{}),
+		cancelListen: cancel,
+		listenDone:   make(chan struct{}),
+	}
+	go b.listen(listenCtx)
+	return b, nil
+}
+
+func (b *Backend) Append(ctx context.Context, topic eventlog.Topic, evt eventlog.PendingEvent) (eventlog.Envelope, error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return eventlog.Envelope{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	qtx := b.queries.WithTx(tx)
+	if err = lockTopic(ctx, qtx, topic); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	sequence, err := qtx.GetNextTopicSequence(ctx, string(topic))
+	if err != nil {
+		return eventlog.Envelope{}, err
+	}
+	streamVersion, err := qtx.GetNextStreamVersion(ctx, backenddb.GetNextStreamVersionParams{
+		Topic:    string(topic),
+		StreamID: string(evt.StreamID),
+	})
+	if err != nil {
+		return eventlog.Envelope{}, err
+	}
+
+	envelope := eventlog.Envelope{
+		ID:            eventlog.EventID(uuid.NewString()),
+		Topic:         topic,
+		StreamID:      evt.StreamID,
+		StreamVersion: streamVersion,
+		Sequence:      sequence,
+		Type:          evt.Type,
+		SchemaVersion: evt.SchemaVersion,
+		OccurredAt:    now(),
+		CausationID:   evt.CausationID,
+		CorrelationID: evt.CorrelationID,
+		Payload:       clonePayload(evt.Payload),
+	}
+	if err = insertEnvelope(ctx, qtx, envelope); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	if err = notifyTopic(ctx, qtx, topic); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	b.wake()
+	return envelope, nil
+}
+
+func (b *Backend) ResetStreamToEvent(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, eventID eventlog.EventID) (eventlog.Envelope, error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return eventlog.Envelope{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	qtx := b.queries.WithTx(tx)
+	if err = lockTopic(ctx, qtx, topic); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	row, err := qtx.GetStreamEventByID(ctx, backenddb.GetStreamEventByIDParams{
+		Topic:    string(topic),
+		StreamID: string(streamID),
+		ID:       string(eventID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: %w", eventlog.ErrEventNotFound, err)
+		return eventlog.Envelope{}, err
+	}
+	if err != nil {
+		return eventlog.Envelope{}, err
+	}
+	target := envelopeFromRow(row)
+	if err = qtx.DeleteStreamEventsFromVersion(ctx, backenddb.DeleteStreamEventsFromVersionParams{
+		Topic:         string(topic),
+		StreamID:      string(streamID),
+		StreamVersion: target.StreamVersion,
+	}); err != nil {
+		return eventlog.Envelope{}, err
+	}
+
+	sequence, err := qtx.GetNextTopicSequence(ctx, string(topic))
+	if err != nil {
+		return eventlog.Envelope{}, err
+	}
+	streamVersion, err := qtx.GetNextStreamVersion(ctx, backenddb.GetNextStreamVersionParams{
+		Topic:    string(topic),
+		StreamID: string(streamID),
+	})
+	if err != nil {
+		return eventlog.Envelope{}, err
+	}
+
+	replayed := target
+	replayed.ID = eventlog.EventID(uuid.NewString())
+	replayed.Sequence = sequence
+	replayed.StreamVersion = streamVersion
+	replayed.OccurredAt = now()
+
+	if err = insertEnvelope(ctx, qtx, replayed); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	if err = notifyTopic(ctx, qtx, topic); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return eventlog.Envelope{}, err
+	}
+	b.wake()
+	return replayed, nil
+}
+
+func (b *Backend) LoadStream(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, opts eventlog.LoadStreamOptions) ([]eventlog.Envelope, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 500
+	}
+	rows, err := b.queries.LoadStreamEvents(ctx, backenddb.LoadStreamEventsParams{
+		Topic:         string(topic),
+		StreamID:      string(streamID),
+		StreamVersion: opts.AfterVersion,
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scanEnvelopes(rows), nil
+}
+
+func (b *Backend) ReadGlobal(ctx context.Context, topic eventlog.Topic, afterSequence int64, limit int) ([]eventlog.Envelope, error) {
+	if limit <= 0 {
+		limit = 64
+	}
+	for {
+		// Grab the signal before reading so an append that lands between the
+		// read and the wait still wakes us up.
+		signal := b.currentSignal()
+		events, err := b.readAvailable(ctx, topic, afterSequence, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			return events, nil
+		}
+
+		timer := time.NewTimer(b.pollEvery)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-signal:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (b *Backend) LoadCheckpoint(ctx context.Context, topic eventlog.Topic, subscriber eventlog.SubscriberID) (int64, error) {
+	sequence, err := b.queries.GetCheckpoint(ctx, backenddb.GetCheckpointParams{
+		Topic:        string(topic),
+		SubscriberID: string(subscriber),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return sequence, err
+}
+
+func (b *Backend) StoreCheckpoint(ctx context.Context, topic eventlog.Topic, subscriber eventlog.SubscriberID, sequence int64) error {
+	return b.queries.UpsertCheckpoint(ctx, backenddb.UpsertCheckpointParams{
+		Topic:        string(topic),
+		SubscriberID: string(subscriber),
+		LastSequence: sequence,
+		UpdatedAt:    now(),
+	})
+}
+
+func (b *Backend) DeleteCheckpoint(ctx context.Context, topic eventlog.Topic, subscriber eventlog.SubscriberID) error {
+	return b.queries.DeleteCheckpoint(ctx, backenddb.DeleteCheckpointParams{
+		Topic:        string(topic),
+		SubscriberID: string(subscriber),
+	})
+}
+
+func (b *Backend) ListCheckpoints(ctx context.Context, topic eventlog.Topic) ([]eventlog.Checkpoint, error) {
+	rows, err := b.queries.ListCheckpointsForTopic(ctx, string(topic))
+	if err != nil {
+		return nil, err
+	}
+	checkpoints := make([]eventlog.Checkpoint, 0, len(rows))
+	for _, row := range rows {
+		checkpoints = append(checkpoints, eventlog.Checkpoint{
+			Subscriber: eventlog.SubscriberID(row.SubscriberID),
+			Sequence:   row.LastSequence,
+			UpdatedAt:  row.UpdatedAt.UTC(),
+		})
+	}
+	return checkpoints, nil
+}
+
+func (b *Backend) LatestSequence(ctx context.Context, topic eventlog.Topic) (int64, error) {
+	return b.queries.GetLatestTopicSequence(ctx, string(topic))
+}
+
+func (b *Backend) StreamInfo(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID) (eventlog.StreamInfo, error) {
+	row, err := b.queries.GetStreamStats(ctx, backenddb.GetStreamStatsParams{
+		Topic:    string(topic),
+		StreamID: string(streamID),
+	})
+	if err != nil {
+		return eventlog.StreamInfo{}, err
+	}
+	return eventlog.StreamInfo{Events: row.EventCount, LastSequence: row.LastSequence}, nil
+}
+
+func (b *Backend) DeleteStream(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, through int64) (int64, error) {
+	return b.queries.DeleteStreamEventsThroughSequence(ctx, backenddb.DeleteStreamEventsThroughSequenceParams{
+		Topic:    string(topic),
+		StreamID: string(streamID),
+		Sequence: through,
+	})
+}
+
+// MigrationProvider reports the migrations of the eventlog database.
+func (b *Backend) MigrationProvider() (*goose.Provider, error) {
+	return backenddb.NewMigrationProvider(b.db)
+}
+
+func (b *Backend) Close() error {
+	b.cancelListen()
+	<-b.listenDone
+	if !b.ownsDB {
+		return nil
+	}
+	return b.db.Close()
+}
+
+func (b *Backend) readAvailable(ctx context.Context, topic eventlog.Topic, afterSequence int64, limit int) ([]eventlog.Envelope, error) {
+	rows, err := b.queries.ReadGlobalEvents(ctx, backenddb.ReadGlobalEventsParams{
+		Topic:    string(topic),
+		Sequence: afterSequence,
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scanEnvelopes(rows), nil
+}
+
+func (b *Backend) currentSignal() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.signal
+}
+
+func (b *Backend) wake() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	close(b.signal)
+	b.signal = make(chan struct{})
+}
+
+// listen keeps a dedicated connection subscribed to NotifyChannel so readers in
+// this process wake up on appends made by any process sharing the database.
+// Readers fall back to polling while the connection is down.
+func (b *Backend) listen(ctx context.Context) {
+	defer close(b.listenDone)
+	for {
+		_ = b.listenOnce(ctx)
+		timer := time.NewTimer(b.pollEvery)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (b *Backend) listenOnce(ctx context.Context) error {
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("postgres eventlog listener requires the pgx driver, got %T", driverConn)
+		}
+		pgConn := stdlibConn.Conn()
+		if _, err := pgConn.Exec(ctx, "LISTEN "+NotifyChannel); err != nil {
+			return err
+		}
+		// Anything appended while we were disconnected has no notification.
+		b.wake()
+		for {
+			if _, err := pgConn.WaitForNotification(ctx); err != nil {
+				return err
+			}
+			b.wake()
+		}
+	})
+}
+
+func lockTopic(ctx context.Context, qtx *backenddb.Queries, topic eventlog.Topic) error {
+	// Sequences are computed from MAX(sequence); serialize writers per topic so
+	// concurrent appends from several daemons never compute the same value.
+	return qtx.LockTopic(ctx, "droner_eventlog:"+string(topic))
+}
+
+func notifyTopic(ctx context.Context, qtx *backenddb.Queries, topic eventlog.Topic) error {
+	return qtx.NotifyTopic(ctx, backenddb.NotifyTopicParams{Channel: NotifyChannel, Topic: string(topic)})
+}
+
+func insertEnvelope(ctx context.Context, qtx *backenddb.Queries, envelope eventlog.Envelope) error {
+	return qtx.InsertEvent(ctx, backenddb.InsertEventParams{
+		Topic:         string(envelope.Topic),
+		Sequence:      envelope.Sequence,
+		ID:            string(envelope.ID),
+		StreamID:      string(envelope.StreamID),
+		StreamVersion: envelope.StreamVersion,
+		EventType:     string(envelope.Type),
+		SchemaVersion: int32(envelope.SchemaVersion),
+		OccurredAt:    envelope.OccurredAt,
+		CausationID:   string(envelope.CausationID),
+		CorrelationID: envelope.CorrelationID,
+		Payload:       envelope.Payload,
+	})
+}
+
+func envelopeFromRow(row backenddb.EventLog) eventlog.Envelope {
+	return eventlog.Envelope{
+		ID:            eventlog.EventID(row.ID),
+		Topic:         eventlog.Topic(row.Topic),
+		StreamID:      eventlog.StreamID(row.StreamID),
+		StreamVersion: row.StreamVersion,
+		Sequence:      row.Sequence,
+		Type:          eventlog.EventType(row.EventType),
+		SchemaVersion: int(row.SchemaVersion),
+		OccurredAt:    row.OccurredAt.UTC(),
+		CausationID:   eventlog.EventID(row.CausationID),
+		CorrelationID: row.CorrelationID,
+		Payload:       clonePayload(row.Payload),
+	}
+}
+
+func scanEnvelopes(rows []backenddb.EventLog) []eventlog.Envelope {
+	items := make([]eventlog.Envelope, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, envelopeFromRow(row))
+	}
+	return items
+}
+
+// now is truncated to the precision Postgres stores so returned envelopes
+// compare equal to the ones loaded back.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func clonePayload(payload []byte) []byte {
+	if payload == nil {
+		return []byte("null")
+	}
+	return append([]byte(nil), payload...)
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS event_log_causation_idx
+  ON event_log(causation_id);
+CREATE INDEX IF NOT EXISTS event_log_correlation_idx
+  ON event_log(correlation_id, occurred_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS event_log_correlation_idx;
+DROP INDEX IF EXISTS event_log_causation_idx;
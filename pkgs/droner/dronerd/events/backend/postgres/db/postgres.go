@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// MigrationsTable keeps the eventlog migrations apart from the projection
+// migrations when both live in the same Postgres database.
+const MigrationsTable = "droner_eventlog_db_version"
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+func OpenPostgresDB(dsn string) (*sql.DB, error) {
+	conn, err := OpenPostgresDBWithoutMigrations(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := EnsureMigrations(context.Background(), conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func OpenPostgresDBWithoutMigrations(dsn string) (*sql.DB, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, errors.New("postgres dsn is required")
+	}
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	return conn, nil
+}
+
+func NewMigrationProvider(conn *sql.DB) (*goose.Provider, error) {
+	files, err := fs.Sub(migrationFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("open postgres eventlog migrations: %w", err)
+	}
+	store, err := database.NewStore(database.DialectPostgres, MigrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("create postgres eventlog migration store: %w", err)
+	}
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create postgres eventlog migration locker: %w", err)
+	}
+	provider, err := goose.NewProvider("", conn, files, goose.WithStore(store), goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("create postgres eventlog migration provider: %w", err)
+	}
+	return provider, nil
+}
+
+func EnsureMigrations(ctx context.Context, conn *sql.DB) error {
+	provider, err := NewMigrationProvider(conn)
+	if err != nil {
+		return err
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("run postgres eventlog migrations: %w", err)
+	}
+	return nil
+}
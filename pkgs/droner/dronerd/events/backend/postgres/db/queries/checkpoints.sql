@@ -0,0 +1,32 @@
+-- name: GetCheckpoint :one
+SELECT last_sequence
+FROM event_log_checkpoints
+WHERE topic = $1
+  AND subscriber_id = $2;
+
+-- name: UpsertCheckpoint :exec
+INSERT INTO event_log_checkpoints (
+  topic,
+  subscriber_id,
+  last_sequence,
+  updated_at
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4
+)
+ON CONFLICT(topic, subscriber_id) DO UPDATE SET
+  last_sequence = excluded.last_sequence,
+  updated_at = excluded.updated_at;
+
+-- name: ListCheckpointsForTopic :many
+SELECT subscriber_id, last_sequence, updated_at
+FROM event_log_checkpoints
+WHERE topic = $1
+ORDER BY subscriber_id ASC;
+
+-- name: DeleteCheckpoint :exec
+DELETE FROM event_log_checkpoints
+WHERE topic = $1
+  AND subscriber_id = $2;
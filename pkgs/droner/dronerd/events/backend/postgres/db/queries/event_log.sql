@@ -0,0 +1,92 @@
+-- name: LockTopic :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg(lock_key)::text));
+
+-- name: NotifyTopic :exec
+SELECT pg_notify(sqlc.arg(channel)::text, sqlc.arg(topic)::text);
+
+-- name: GetNextTopicSequence :one
+SELECT (GREATEST(
+  (SELECT COALESCE(MAX(e.sequence), 0) FROM event_log e WHERE e.topic = sqlc.arg(topic)),
+  (SELECT COALESCE(MAX(c.last_sequence), 0) FROM event_log_checkpoints c WHERE c.topic = sqlc.arg(topic))
+) + 1)::BIGINT AS next_sequence;
+
+-- name: GetLatestTopicSequence :one
+SELECT COALESCE(MAX(sequence), 0)::BIGINT AS latest_sequence
+FROM event_log
+WHERE topic = $1;
+
+-- name: GetNextStreamVersion :one
+SELECT (COALESCE(MAX(stream_version), 0) + 1)::BIGINT AS next_version
+FROM event_log
+WHERE topic = $1
+  AND stream_id = $2;
+
+-- name: InsertEvent :exec
+INSERT INTO event_log (
+  topic,
+  sequence,
+  id,
+  stream_id,
+  stream_version,
+  event_type,
+  schema_version,
+  occurred_at,
+  causation_id,
+  correlation_id,
+  payload
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  $11
+);
+
+-- name: LoadStreamEvents :many
+SELECT *
+FROM event_log
+WHERE topic = $1
+  AND stream_id = $2
+  AND stream_version > $3
+ORDER BY stream_version ASC
+LIMIT $4;
+
+-- name: GetStreamEventByID :one
+SELECT *
+FROM event_log
+WHERE topic = $1
+  AND stream_id = $2
+  AND id = $3
+LIMIT 1;
+
+-- name: DeleteStreamEventsFromVersion :exec
+DELETE FROM event_log
+WHERE topic = $1
+  AND stream_id = $2
+  AND stream_version >= $3;
+
+-- name: ReadGlobalEvents :many
+SELECT *
+FROM event_log
+WHERE topic = $1
+  AND sequence > $2
+ORDER BY sequence ASC
+LIMIT $3;
+
+-- name: GetStreamStats :one
+SELECT COUNT(*) AS event_count, COALESCE(MAX(sequence), 0)::BIGINT AS last_sequence
+FROM event_log
+WHERE topic = $1
+  AND stream_id = $2;
+
+-- name: DeleteStreamEventsThroughSequence :execrows
+DELETE FROM event_log
+WHERE topic = $1
+  AND stream_id = $2
+  AND sequence <= $3;
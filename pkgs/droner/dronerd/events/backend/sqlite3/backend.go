@@ -10,6 +10,7 @@ import (
 	backenddb "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/sqlite3/db"
 	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
 	"github.com/google/uuid"
+	"github.com/pressly/goose/v3"
 )
 
 type Config struct {
@@ -109,6 +110,10 @@ func (b *Backend) ResetStreamToEvent(ctx context.Context, topic eventlog.Topic,
 		StreamID: string(streamID),
 		ID:       string(eventID),
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w: %w", eventlog.ErrEventNotFound, err)
+		return eventlog.Envelope{}, err
+	}
 	if err != nil {
 		return eventlog.Envelope{}, err
 	}
@@ -210,6 +215,61 @@ func (b *Backend) StoreCheckpoint(ctx context.Context, topic eventlog.Topic, sub
 	})
 }
 
+func (b *Backend) DeleteCheckpoint(ctx context.Context, topic eventlog.Topic, subscriber eventlog.SubscriberID) error {
+	return b.queries.DeleteCheckpoint(ctx, backenddb.DeleteCheckpointParams{
+		Topic:        string(topic),
+		SubscriberID: string(subscriber),
+	})
+}
+
+func (b *Backend) ListCheckpoints(ctx context.Context, topic eventlog.Topic) ([]eventlog.Checkpoint, error) {
+	rows, err := b.queries.ListCheckpointsForTopic(ctx, string(topic))
+	if err != nil {
+		return nil, err
+	}
+	checkpoints := make([]eventlog.Checkpoint, 0, len(rows))
+	for _, row := range rows {
+		checkpoints = append(checkpoints, eventlog.Checkpoint{
+			Subscriber: eventlog.SubscriberID(row.SubscriberID),
+			Sequence:   row.LastSequence,
+			UpdatedAt:  parseTime(row.UpdatedAt),
+		})
+	}
+	return checkpoints, nil
+}
+
+func (b *Backend) LatestSequence(ctx context.Context, topic eventlog.Topic) (int64, error) {
+	next, err := b.queries.GetNextTopicSequence(ctx, string(topic))
+	if err != nil {
+		return 0, err
+	}
+	return next - 1, nil
+}
+
+func (b *Backend) StreamInfo(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID) (eventlog.StreamInfo, error) {
+	row, err := b.queries.GetStreamStats(ctx, backenddb.GetStreamStatsParams{
+		Topic:    string(topic),
+		StreamID: string(streamID),
+	})
+	if err != nil {
+		return eventlog.StreamInfo{}, err
+	}
+	return eventlog.StreamInfo{Events: row.EventCount, LastSequence: row.LastSequence}, nil
+}
+
+func (b *Backend) DeleteStream(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, through int64) (int64, error) {
+	return b.queries.DeleteStreamEventsThroughSequence(ctx, backenddb.DeleteStreamEventsThroughSequenceParams{
+		Topic:    string(topic),
+		StreamID: string(streamID),
+		Sequence: through,
+	})
+}
+
+// MigrationProvider reports the migrations of the eventlog database.
+func (b *Backend) MigrationProvider() (*goose.Provider, error) {
+	return backenddb.NewMigrationProvider(b.db)
+}
+
 func (b *Backend) Close() error {
 	if !b.ownsDB {
 		return nil
@@ -0,0 +1,27 @@
+package sqlite3
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog/eventlogtest"
+)
+
+func TestBackendConformance(t *testing.T) {
+	eventlogtest.RunBackendSuite(t, func(t *testing.T) eventlogtest.Backend {
+		backend, err := New(Config{
+			Path:         filepath.Join(t.TempDir(), "eventlog.db"),
+			PollInterval: 5 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		t.Cleanup(func() {
+			if err := backend.Close(); err != nil {
+				t.Fatalf("backend.Close: %v", err)
+			}
+		})
+		return backend
+	})
+}
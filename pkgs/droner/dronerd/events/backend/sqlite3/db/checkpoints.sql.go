@@ -9,6 +9,22 @@ import (
 	"context"
 )
 
+const deleteCheckpoint = `-- name: DeleteCheckpoint :exec
+DELETE FROM event_log_checkpoints
+WHERE topic = ?
+  AND subscriber_id = ?
+`
+
+type DeleteCheckpointParams struct {
+	Topic        string
+	SubscriberID string
+}
+
+func (q *Queries) DeleteCheckpoint(ctx context.Context, arg DeleteCheckpointParams) error {
+	_, err := q.db.ExecContext(ctx, deleteCheckpoint, arg.Topic, arg.SubscriberID)
+	return err
+}
+
 const getCheckpoint = `-- name: GetCheckpoint :one
 SELECT last_sequence
 FROM event_log_checkpoints
@@ -41,6 +57,42 @@ func (q *Queries) GetMaxCheckpointSequenceForTopic(ctx context.Context, topic st
 	return coalesce, err
 }
 
+const listCheckpointsForTopic = `-- name: ListCheckpointsForTopic :many
+SELECT subscriber_id, last_sequence, updated_at
+FROM event_log_checkpoints
+WHERE topic = ?
+ORDER BY subscriber_id ASC
+`
+
+type ListCheckpointsForTopicRow struct {
+	SubscriberID string
+	LastSequence int64
+	UpdatedAt    string
+}
+
+func (q *Queries) ListCheckpointsForTopic(ctx context.Context, topic string) ([]ListCheckpointsForTopicRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCheckpointsForTopic, topic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCheckpointsForTopicRow
+	for rows.Next() {
+		var i ListCheckpointsForTopicRow
+		if err := rows.Scan(&i.SubscriberID, &i.LastSequence, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upsertCheckpoint = `-- name: UpsertCheckpoint :exec
 INSERT INTO event_log_checkpoints (
   topic,
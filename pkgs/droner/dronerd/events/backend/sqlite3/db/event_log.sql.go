@@ -27,6 +27,27 @@ func (q *Queries) DeleteStreamEventsFromVersion(ctx context.Context, arg DeleteS
 	return err
 }
 
+const deleteStreamEventsThroughSequence = `-- name: DeleteStreamEventsThroughSequence :execrows
+DELETE FROM event_log
+WHERE topic = ?
+  AND stream_id = ?
+  AND sequence <= ?
+`
+
+type DeleteStreamEventsThroughSequenceParams struct {
+	Topic    string
+	StreamID string
+	Sequence int64
+}
+
+func (q *Queries) DeleteStreamEventsThroughSequence(ctx context.Context, arg DeleteStreamEventsThroughSequenceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteStreamEventsThroughSequence, arg.Topic, arg.StreamID, arg.Sequence)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getNextStreamVersion = `-- name: GetNextStreamVersion :one
 SELECT COALESCE(MAX(stream_version), 0) + 1
 FROM event_log
@@ -93,6 +114,30 @@ func (q *Queries) GetStreamEventByID(ctx context.Context, arg GetStreamEventByID
 	return i, err
 }
 
+const getStreamStats = `-- name: GetStreamStats :one
+SELECT COUNT(*) AS event_count, CAST(COALESCE(MAX(sequence), 0) AS INTEGER) AS last_sequence
+FROM event_log
+WHERE topic = ?
+  AND stream_id = ?
+`
+
+type GetStreamStatsParams struct {
+	Topic    string
+	StreamID string
+}
+
+type GetStreamStatsRow struct {
+	EventCount   int64
+	LastSequence int64
+}
+
+func (q *Queries) GetStreamStats(ctx context.Context, arg GetStreamStatsParams) (GetStreamStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getStreamStats, arg.Topic, arg.StreamID)
+	var i GetStreamStatsRow
+	err := row.Scan(&i.EventCount, &i.LastSequence)
+	return i, err
+}
+
 const insertEvent = `-- name: InsertEvent :exec
 INSERT INTO event_log (
   topic,
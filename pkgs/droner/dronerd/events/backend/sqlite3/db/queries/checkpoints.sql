@@ -24,3 +24,14 @@ INSERT INTO event_log_checkpoints (
 ON CONFLICT(topic, subscriber_id) DO UPDATE SET
   last_sequence = excluded.last_sequence,
   updated_at = excluded.updated_at;
+
+-- name: ListCheckpointsForTopic :many
+SELECT subscriber_id, last_sequence, updated_at
+FROM event_log_checkpoints
+WHERE topic = ?
+ORDER BY subscriber_id ASC;
+
+-- name: DeleteCheckpoint :exec
+DELETE FROM event_log_checkpoints
+WHERE topic = ?
+  AND subscriber_id = ?;
@@ -66,3 +66,15 @@ WHERE topic = ?
   AND sequence > ?
 ORDER BY sequence ASC
 LIMIT ?;
+
+-- name: GetStreamStats :one
+SELECT COUNT(*) AS event_count, CAST(COALESCE(MAX(sequence), 0) AS INTEGER) AS last_sequence
+FROM event_log
+WHERE topic = ?
+  AND stream_id = ?;
+
+-- name: DeleteStreamEventsThroughSequence :execrows
+DELETE FROM event_log
+WHERE topic = ?
+  AND stream_id = ?
+  AND sequence <= ?;
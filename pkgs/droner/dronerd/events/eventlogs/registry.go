@@ -4,19 +4,34 @@ import (
 	"context"
 	"fmt"
 
+	postgreseventlog "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/postgres"
 	sqlite3eventlog "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/sqlite3"
 	backenddb "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/sqlite3/db"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestslog"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionslog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/pressly/goose/v3"
 )
 
+type Backend interface {
+	eventlog.Backend
+	eventlog.StreamResetter
+	eventlog.Compactor
+}
+
+// MigrationReporter is implemented by backends whose schema is kept in goose
+// migrations.
+type MigrationReporter interface {
+	MigrationProvider() (*goose.Provider, error)
+}
+
 type Registry struct {
-	backend *sqlite3eventlog.Backend
+	backend Backend
 }
 
 type SessionResetter struct {
-	backend *sqlite3eventlog.Backend
+	backend Backend
 }
 
 func Open(dataDir string) (*Registry, error) {
@@ -24,7 +39,26 @@ func Open(dataDir string) (*Registry, error) {
 	if err != nil {
 		return nil, fmt.Errorf("open eventlog sqlite backend: %w", err)
 	}
-	return &Registry{backend: backend}, nil
+	return New(backend), nil
+}
+
+func OpenStorage(dataDir string, storage conf.StorageConfig) (*Registry, error) {
+	switch storage.Driver {
+	case "", conf.StorageDriverSQLite:
+		return Open(dataDir)
+	case conf.StorageDriverPostgres:
+		backend, err := postgreseventlog.New(postgreseventlog.Config{DSN: storage.Postgres.DSN})
+		if err != nil {
+			return nil, fmt.Errorf("open eventlog postgres backend: %w", err)
+		}
+		return New(backend), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", storage.Driver)
+	}
+}
+
+func New(backend Backend) *Registry {
+	return &Registry{backend: backend}
 }
 
 func (r *Registry) Sessions() (eventlog.EventLog, error) {
@@ -39,6 +73,25 @@ func (r *Registry) SessionResetter() *SessionResetter {
 	return &SessionResetter{backend: r.backend}
 }
 
+// Backend exposes the shared backend for maintenance that spans topics, such
+// as retention.
+func (r *Registry) Backend() Backend {
+	if r == nil {
+		return nil
+	}
+	return r.backend
+}
+
+// MigrationProvider returns the migrations of the backend database, for
+// diagnostics.
+func (r *Registry) MigrationProvider() (*goose.Provider, error) {
+	reporter, ok := r.Backend().(MigrationReporter)
+	if !ok {
+		return nil, fmt.Errorf("eventlog backend has no migrations")
+	}
+	return reporter.MigrationProvider()
+}
+
 func (r *Registry) Close() error {
 	if r == nil || r.backend == nil {
 		return nil
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	coredb "github.com/Oudwins/droner/pkgs/droner/dronerd/db"
+	memoryeventlog "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/memory"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventlogs"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestevents"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
@@ -25,9 +26,9 @@ type Harness struct {
 	SessionsLog     eventlog.EventLog
 	PullRequestsLog eventlog.EventLog
 
-	SessionStore  *sessionevents.SQLiteProjectionStore
-	PRSessions    *pullrequestevents.SQLiteSessionLookupStore
-	PRSnapshots   *pullrequestevents.SQLitePullRequestSnapshotStore
+	SessionStore  *sessionevents.DBProjectionStore
+	PRSessions    *pullrequestevents.DBSessionLookupStore
+	PRSnapshots   *pullrequestevents.DBPullRequestSnapshotStore
 	SessionEvents *sessionevents.System
 	PullRequests  *pullrequestevents.System
 }
@@ -36,6 +37,7 @@ type Options struct {
 	Config       *conf.Config
 	BackendStore *backends.Store
 	Logger       *slog.Logger
+	EventBackend eventlogs.Backend
 	Start        bool
 }
 
@@ -59,11 +61,11 @@ func NewHarness(t testing.TB, opts Options) *Harness {
 		t.Fatalf("OpenSQLiteDB: %v", err)
 	}
 	queries := coredb.New(db)
-	logs, err := eventlogs.Open(dataDir)
-	if err != nil {
-		_ = db.Close()
-		t.Fatalf("eventlogs.Open: %v", err)
+	eventBackend := opts.EventBackend
+	if eventBackend == nil {
+		eventBackend = memoryeventlog.New()
 	}
+	logs := eventlogs.New(eventBackend)
 	sessionsLog, err := logs.Sessions()
 	if err != nil {
 		_ = logs.Close()
@@ -76,9 +78,9 @@ func NewHarness(t testing.TB, opts Options) *Harness {
 		_ = db.Close()
 		t.Fatalf("PullRequests log: %v", err)
 	}
-	sessionStore := sessionevents.NewSQLiteProjectionStore(queries)
-	prSessions := pullrequestevents.NewSQLiteSessionLookupStore(queries)
-	prSnapshots := pullrequestevents.NewSQLitePullRequestSnapshotStore(queries)
+	sessionStore := sessionevents.NewProjectionStore(queries)
+	prSessions := pullrequestevents.NewSessionLookupStore(queries)
+	prSnapshots := pullrequestevents.NewPullRequestSnapshotStore(queries)
 	h := &Harness{
 		DataDir:         dataDir,
 		DB:              db,
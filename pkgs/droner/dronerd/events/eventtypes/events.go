@@ -27,10 +27,35 @@ const (
 	SessionPRCIStateChanged               = eventlog.EventType("session.pr.ci_state_changed")
 	SessionPRClosed                       = eventlog.EventType("session.pr.closed")
 	SessionPRMerged                       = eventlog.EventType("session.pr.merged")
+	SessionPRCIFeedbackSent               = eventlog.EventType("session.pr.ci_feedback.sent")
+	SessionPRReviewCommentsAdded          = eventlog.EventType("session.pr.review_comments.added")
+	SessionPRReviewFeedbackSent           = eventlog.EventType("session.pr.review_feedback.sent")
+	SessionHookFired                      = eventlog.EventType("session.hook.fired")
+	SessionWebhookDelivered               = eventlog.EventType("session.webhook.delivered")
+	SessionWebhookFailed                  = eventlog.EventType("session.webhook.failed")
 )
 
 const (
-	PRObserved = eventlog.EventType("pr.observed")
-	PRClosed   = eventlog.EventType("pr.closed")
-	PRMerged   = eventlog.EventType("pr.merged")
+	PRObserved           = eventlog.EventType("pr.observed")
+	PRClosed             = eventlog.EventType("pr.closed")
+	PRMerged             = eventlog.EventType("pr.merged")
+	PRReviewCommentAdded = eventlog.EventType("pr.review_comment.added")
+	PRHookFired          = eventlog.EventType("pr.hook.fired")
+	PRWebhookDelivered   = eventlog.EventType("pr.webhook.delivered")
+	PRWebhookFailed      = eventlog.EventType("pr.webhook.failed")
 )
+
+// IsRecord reports whether eventType records something droner did in
+// response to another event: a hook run, a webhook delivery or feedback sent
+// to an agent. Hooks and webhooks never react to records, so they cannot
+// trigger each other in a loop.
+func IsRecord(eventType eventlog.EventType) bool {
+	switch eventType {
+	case SessionHookFired, PRHookFired,
+		SessionWebhookDelivered, SessionWebhookFailed, PRWebhookDelivered, PRWebhookFailed,
+		SessionPRCIFeedbackSent, SessionPRReviewFeedbackSent:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,16 @@
+package eventtypes
+
+import (
+	"path"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+// Matches reports whether eventType matches pattern. A "*" in pattern stands
+// for any run of characters, so "session.pr.*" and "session.*.failed" both
+// work. Malformed patterns match nothing.
+func Matches(pattern string, eventType eventlog.EventType) bool {
+	ok, err := path.Match(strings.TrimSpace(pattern), string(eventType))
+	return err == nil && ok
+}
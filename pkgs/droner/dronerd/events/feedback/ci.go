@@ -0,0 +1,154 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+type ciStateChangedPayload struct {
+	PRStreamID string `json:"prStreamId"`
+	PRNumber   int    `json:"prNumber"`
+	CIState    string `json:"ciState"`
+}
+
+type ciFeedbackSentPayload struct {
+	PRStreamID string    `json:"prStreamId"`
+	PRNumber   int       `json:"prNumber"`
+	HeadSHA    string    `json:"headSha"`
+	Attempt    int       `json:"attempt"`
+	Checks     []string  `json:"checks"`
+	SentAt     time.Time `json:"sentAt"`
+}
+
+func (s *System) sendCIFeedback(ctx context.Context, cause eventlog.Envelope) error {
+	var payload ciStateChangedPayload
+	if err := json.Unmarshal(cause.Payload, &payload); err != nil {
+		return err
+	}
+	if payload.CIState != "failing" {
+		return nil
+	}
+	sessionStreamID := string(cause.StreamID)
+	logger := s.logWith(slog.String("stream_id", sessionStreamID), slog.String("pr_stream_id", payload.PRStreamID))
+
+	snapshot, found, err := s.snapshots.Load(ctx, payload.PRStreamID)
+	if err != nil {
+		return err
+	}
+	// Replayed or stale transitions: only the commit that is failing now gets feedback.
+	if !found || snapshot.CI.State != "failing" || strings.TrimSpace(snapshot.HeadSHA) == "" {
+		return nil
+	}
+
+	events, err := eventlog.LoadFullStream(ctx, s.sessionsLog, eventlog.StreamID(sessionStreamID))
+	if err != nil {
+		return err
+	}
+	if causeHandled(events, eventtypes.SessionPRCIFeedbackSent, cause.ID) {
+		return nil
+	}
+	attempts, err := ciFeedbackAttempts(events, payload.PRStreamID, snapshot.HeadSHA)
+	if err != nil {
+		return err
+	}
+	if attempts >= s.config.CI.MaxAttemptsPerCommit {
+		if logger != nil {
+			logger.Info("CI feedback limit reached for head commit", slog.String("head_sha", snapshot.HeadSHA), slog.Int("attempts", attempts))
+		}
+		return nil
+	}
+
+	failures, err := s.fetchFailures(ctx, snapshot)
+	if err != nil {
+		if logger != nil {
+			logger.Warn("Failed to fetch CI output; using status descriptions", slog.String("error", err.Error()))
+		}
+		failures = remote.SnapshotCIFailures(snapshot)
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+
+	message := &messages.Message{
+		Role:  messages.MessageRoleUser,
+		Parts: []messages.MessagePart{messages.NewTextPart(ciFeedbackPrompt(snapshot, failures, s.config.CI.MaxOutputChars))},
+	}
+	if !s.sendAgentMessage(ctx, logger, sessionStreamID, message) {
+		return nil
+	}
+
+	checks := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		checks = append(checks, failure.Name)
+	}
+	return s.appendSessionEvent(ctx, sessionStreamID, eventtypes.SessionPRCIFeedbackSent, ciFeedbackSentPayload{
+		PRStreamID: payload.PRStreamID,
+		PRNumber:   snapshot.Number,
+		HeadSHA:    snapshot.HeadSHA,
+		Attempt:    attempts + 1,
+		Checks:     checks,
+		SentAt:     s.now().UTC(),
+	}, string(cause.ID), sessionStreamID)
+}
+
+// ciFeedbackAttempts counts the CI feedback already sent for headSHA.
+func ciFeedbackAttempts(events []eventlog.Envelope, prStreamID string, headSHA string) (int, error) {
+	attempts := 0
+	for _, evt := range events {
+		if evt.Type != eventtypes.SessionPRCIFeedbackSent {
+			continue
+		}
+		var sent ciFeedbackSentPayload
+		if err := json.Unmarshal(evt.Payload, &sent); err != nil {
+			return 0, err
+		}
+		if sent.PRStreamID == prStreamID && sent.HeadSHA == headSHA {
+			attempts++
+		}
+	}
+	return attempts, nil
+}
+
+func ciFeedbackPrompt(snapshot remote.PullRequestSnapshot, failures []remote.CIFailure, maxOutputChars int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CI is failing on pull request #%d", snapshot.Number)
+	if snapshot.HeadSHA != "" {
+		fmt.Fprintf(&b, " (commit %s)", shortSHA(snapshot.HeadSHA))
+	}
+	b.WriteString(". Investigate the failures below, fix them, and push the fix.\n")
+	for _, failure := range failures {
+		fmt.Fprintf(&b, "\n## %s\n", failure.Name)
+		if failure.TargetURL != "" {
+			fmt.Fprintf(&b, "%s\n", failure.TargetURL)
+		}
+		if summary := strings.TrimSpace(failure.Summary); summary != "" {
+			fmt.Fprintf(&b, "\n%s\n", truncate(summary, maxOutputChars))
+		}
+	}
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+func truncate(value string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(value) <= maxChars {
+		return value
+	}
+	runes := []rune(value)
+	return string(runes[:maxChars]) + "\n[output truncated]"
+}
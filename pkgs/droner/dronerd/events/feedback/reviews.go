@@ -0,0 +1,196 @@
+package feedback
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+type reviewCommentsAddedPayload struct {
+	PRStreamID string  `json:"prStreamId"`
+	PRNumber   int     `json:"prNumber"`
+	CommentIDs []int64 `json:"commentIds"`
+}
+
+type reviewFeedbackSentPayload struct {
+	PRStreamID string    `json:"prStreamId"`
+	PRNumber   int       `json:"prNumber"`
+	CommentIDs []int64   `json:"commentIds"`
+	SentAt     time.Time `json:"sentAt"`
+}
+
+func (s *System) sendReviewFeedback(ctx context.Context, cause eventlog.Envelope) error {
+	var payload reviewCommentsAddedPayload
+	if err := json.Unmarshal(cause.Payload, &payload); err != nil {
+		return err
+	}
+	sessionStreamID := string(cause.StreamID)
+	logger := s.logWith(slog.String("stream_id", sessionStreamID), slog.String("pr_stream_id", payload.PRStreamID))
+
+	snapshot, found, err := s.snapshots.Load(ctx, payload.PRStreamID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	// The snapshot is newer than the event: skip comments resolved since.
+	comments := unresolvedReviewComments(snapshot, payload.CommentIDs)
+	if len(comments) == 0 {
+		return nil
+	}
+
+	// Comments can be announced more than once, e.g. when the snapshot
+	// store is rebuilt, so dedupe on what was forwarded, not on the cause.
+	events, err := eventlog.LoadFullStream(ctx, s.sessionsLog, eventlog.StreamID(sessionStreamID))
+	if err != nil {
+		return err
+	}
+	comments, err = unforwardedReviewComments(events, payload.PRStreamID, comments)
+	if err != nil {
+		return err
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	ref, err := s.sessions.LookupSessionByID(ctx, sessionStreamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	// Completed or deleted sessions have no agent left to act on the review.
+	if !ref.PublicState.IsActive() {
+		if logger != nil {
+			logger.Info("Skipping review feedback for inactive session", slog.String("state", string(ref.PublicState)))
+		}
+		return nil
+	}
+
+	if !s.sendAgentMessage(ctx, logger, sessionStreamID, reviewFeedbackMessage(snapshot, comments, ref.WorktreePath)) {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	return s.appendSessionEvent(ctx, sessionStreamID, eventtypes.SessionPRReviewFeedbackSent, reviewFeedbackSentPayload{
+		PRStreamID: payload.PRStreamID,
+		PRNumber:   snapshot.Number,
+		CommentIDs: ids,
+		SentAt:     s.now().UTC(),
+	}, string(cause.ID), sessionStreamID)
+}
+
+func unresolvedReviewComments(snapshot remote.PullRequestSnapshot, ids []int64) []remote.ReviewComment {
+	wanted := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+	comments := []remote.ReviewComment{}
+	for _, comment := range snapshot.ReviewComments {
+		if _, ok := wanted[comment.ID]; ok && !comment.Resolved {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
+}
+
+// unforwardedReviewComments drops the comments a review feedback event in
+// events already sent to the agent.
+func unforwardedReviewComments(events []eventlog.Envelope, prStreamID string, comments []remote.ReviewComment) ([]remote.ReviewComment, error) {
+	forwarded := map[int64]struct{}{}
+	for _, evt := range events {
+		if evt.Type != eventtypes.SessionPRReviewFeedbackSent {
+			continue
+		}
+		var sent reviewFeedbackSentPayload
+		if err := json.Unmarshal(evt.Payload, &sent); err != nil {
+			return nil, err
+		}
+		if sent.PRStreamID != prStreamID {
+			continue
+		}
+		for _, id := range sent.CommentIDs {
+			forwarded[id] = struct{}{}
+		}
+	}
+	remaining := []remote.ReviewComment{}
+	for _, comment := range comments {
+		if _, ok := forwarded[comment.ID]; !ok {
+			remaining = append(remaining, comment)
+		}
+	}
+	return remaining, nil
+}
+
+// reviewFeedbackMessage lists the comments as text and attaches the lines
+// they refer to as file parts. Files missing from the worktree are left out
+// because the agent could not open them either.
+func reviewFeedbackMessage(snapshot remote.PullRequestSnapshot, comments []remote.ReviewComment, worktreePath string) *messages.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "New review comments on pull request #%d. Address each comment below, then push the changes.\n", snapshot.Number)
+
+	parts := []messages.MessagePart{}
+	attached := map[string]struct{}{}
+	for _, comment := range comments {
+		location := reviewCommentLocation(comment)
+		fmt.Fprintf(&b, "\n## %s", location)
+		if comment.Author != "" {
+			fmt.Fprintf(&b, " (%s)", comment.Author)
+		}
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(comment.Body))
+		if comment.URL != "" {
+			fmt.Fprintf(&b, "%s\n", comment.URL)
+		}
+
+		if _, ok := attached[location]; ok || !worktreeFileExists(worktreePath, comment.Path) {
+			continue
+		}
+		attached[location] = struct{}{}
+		part := messages.NewFilePart(comment.Path)
+		start := comment.StartLine
+		if start == 0 {
+			start = comment.Line
+		}
+		part.File.Source.Text = &messages.FilePartSourceTextData{Start: int64(start), End: int64(comment.Line), Value: location}
+		parts = append(parts, part)
+	}
+	return &messages.Message{
+		Role:  messages.MessageRoleUser,
+		Parts: append([]messages.MessagePart{messages.NewTextPart(b.String())}, parts...),
+	}
+}
+
+func reviewCommentLocation(comment remote.ReviewComment) string {
+	switch {
+	case comment.Line == 0:
+		return comment.Path
+	case comment.StartLine > 0 && comment.StartLine != comment.Line:
+		return fmt.Sprintf("%s:%d-%d", comment.Path, comment.StartLine, comment.Line)
+	default:
+		return fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+	}
+}
+
+func worktreeFileExists(worktreePath string, path string) bool {
+	if strings.TrimSpace(worktreePath) == "" || strings.TrimSpace(path) == "" || filepath.IsAbs(path) || filepath.Clean(path) != path || strings.HasPrefix(path, "..") {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(worktreePath, path))
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,176 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+func reviewCommentsAdded(t *testing.T, log *memoryEventLog, ids ...int64) eventlog.Envelope {
+	t.Helper()
+	payload, err := json.Marshal(reviewCommentsAddedPayload{PRStreamID: "github:owner/repo#7", PRNumber: 7, CommentIDs: ids})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	evt, err := log.Append(context.Background(), eventlog.PendingEvent{StreamID: "session-1", Type: eventtypes.SessionPRReviewCommentsAdded, Payload: payload})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return evt
+}
+
+func reviewedSnapshot() remote.PullRequestSnapshot {
+	snapshot := failingSnapshot("aaa")
+	snapshot.ReviewComments = []remote.ReviewComment{
+		{ID: 1, Author: "alice", Path: "pkg/util.go", StartLine: 4, Line: 8, Body: "Handle the error here.", URL: "https://github.com/owner/repo/pull/7#discussion_r1"},
+		{ID: 2, Author: "bob", Path: "pkg/util.go", Line: 20, Body: "Already fixed", Resolved: true},
+		{ID: 3, Author: "carol", Path: "gone.go", Line: 1, Body: "Delete this file"},
+	}
+	return snapshot
+}
+
+func TestReviewCommentsAreForwardedWithFileParts(t *testing.T) {
+	system, sessionsLog, _, agent := newTestSystem(t, reviewedSnapshot(), 1)
+	worktree := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(worktree, "pkg"), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "pkg", "util.go"), []byte("package pkg\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	agent.ref.WorktreePath = worktree
+	cause := reviewCommentsAdded(t, sessionsLog, 1, 2, 3)
+
+	if err := system.handleSessionEvent(context.Background(), cause); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected one prompt, got %d", len(agent.sent))
+	}
+	message := agent.sent[0]
+	text := messages.ToRawText(&messages.Message{Parts: message.Parts[:1]})
+	for _, want := range []string{"pull request #7", "## pkg/util.go:4-8 (alice)", "Handle the error here.", "## gone.go:1 (carol)"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("prompt missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Already fixed") {
+		t.Fatalf("resolved comment was forwarded:\n%s", text)
+	}
+	if len(message.Parts) != 2 {
+		t.Fatalf("expected one file part for the existing file, got %d parts", len(message.Parts)-1)
+	}
+	file := message.Parts[1].File
+	if file == nil || file.Source == nil || file.Source.Path != "pkg/util.go" || file.Source.Text == nil {
+		t.Fatalf("unexpected file part: %#v", message.Parts[1])
+	}
+	if file.Source.Text.Start != 4 || file.Source.Text.End != 8 || file.Source.Text.Value != "pkg/util.go:4-8" {
+		t.Fatalf("unexpected line range: %#v", file.Source.Text)
+	}
+	if err := messages.MessagePartSchema.Validate(&message.Parts[1]); err != nil {
+		t.Fatalf("file part is invalid: %v", err)
+	}
+
+	var sent []eventlog.Envelope
+	for _, evt := range sessionsLog.events {
+		if evt.Type == eventtypes.SessionPRReviewFeedbackSent {
+			sent = append(sent, evt)
+		}
+	}
+	if len(sent) != 1 || sent[0].CausationID != cause.ID {
+		t.Fatalf("unexpected %s events: %#v", eventtypes.SessionPRReviewFeedbackSent, sent)
+	}
+	var payload reviewFeedbackSentPayload
+	if err := json.Unmarshal(sent[0].Payload, &payload); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(payload.CommentIDs) != 2 || payload.CommentIDs[0] != 1 || payload.CommentIDs[1] != 3 {
+		t.Fatalf("unexpected comment IDs: %#v", payload.CommentIDs)
+	}
+
+	if err := system.handleSessionEvent(context.Background(), cause); err != nil {
+		t.Fatalf("handleSessionEvent redelivery: %v", err)
+	}
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected redelivery to be ignored, got %d prompts", len(agent.sent))
+	}
+}
+
+func TestReviewCommentsAreForwardedOncePerComment(t *testing.T) {
+	system, sessionsLog, snapshots, agent := newTestSystem(t, reviewedSnapshot(), 1)
+	ctx := context.Background()
+
+	if err := system.handleSessionEvent(ctx, reviewCommentsAdded(t, sessionsLog, 1, 3)); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	// A rebuilt snapshot store announces the same comments under a new event.
+	if err := system.handleSessionEvent(ctx, reviewCommentsAdded(t, sessionsLog, 1, 3)); err != nil {
+		t.Fatalf("handleSessionEvent repeat: %v", err)
+	}
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected repeated comments to be skipped, got %d prompts", len(agent.sent))
+	}
+
+	snapshots.snapshot.ReviewComments = append(snapshots.snapshot.ReviewComments, remote.ReviewComment{ID: 4, Author: "dave", Path: "pkg/util.go", Line: 2, Body: "Add a test"})
+	if err := system.handleSessionEvent(ctx, reviewCommentsAdded(t, sessionsLog, 3, 4)); err != nil {
+		t.Fatalf("handleSessionEvent new: %v", err)
+	}
+	if len(agent.sent) != 2 {
+		t.Fatalf("expected a prompt for the new comment, got %d prompts", len(agent.sent))
+	}
+	text := messages.ToRawText(agent.sent[1])
+	if !strings.Contains(text, "Add a test") || strings.Contains(text, "Delete this file") {
+		t.Fatalf("expected only the new comment to be forwarded:\n%s", text)
+	}
+}
+
+func TestResolvedReviewCommentsAreNotForwarded(t *testing.T) {
+	system, sessionsLog, _, agent := newTestSystem(t, reviewedSnapshot(), 1)
+
+	if err := system.handleSessionEvent(context.Background(), reviewCommentsAdded(t, sessionsLog, 2)); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(agent.sent) != 0 {
+		t.Fatalf("expected no prompt for resolved comments, got %d", len(agent.sent))
+	}
+}
+
+func TestReviewCommentsAreNotForwardedToInactiveSessions(t *testing.T) {
+	for _, state := range []sessionevents.PublicState{sessionevents.PublicStateCompleted, sessionevents.PublicStateDeleted} {
+		system, sessionsLog, _, agent := newTestSystem(t, reviewedSnapshot(), 1)
+		agent.ref.PublicState = state
+
+		if err := system.handleSessionEvent(context.Background(), reviewCommentsAdded(t, sessionsLog, 1)); err != nil {
+			t.Fatalf("handleSessionEvent %s: %v", state, err)
+		}
+		if len(agent.sent) != 0 {
+			t.Fatalf("expected no prompt for %s session, got %d", state, len(agent.sent))
+		}
+	}
+}
+
+func TestReviewCommentLocation(t *testing.T) {
+	cases := map[string]struct {
+		comment remote.ReviewComment
+		want    string
+	}{
+		"single line": {comment: remote.ReviewComment{Path: "a.go", Line: 3}, want: "a.go:3"},
+		"range":       {comment: remote.ReviewComment{Path: "a.go", StartLine: 1, Line: 3}, want: "a.go:1-3"},
+		"outdated":    {comment: remote.ReviewComment{Path: "a.go"}, want: "a.go"},
+	}
+	for name, tc := range cases {
+		if got := reviewCommentLocation(tc.comment); got != tc.want {
+			t.Fatalf("%s: reviewCommentLocation() = %q, want %q", name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/memory"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+// Enabling feedback on a daemon with history must not prompt agents about CI
+// runs that failed before it was switched on.
+func TestEnablingFeedbackSkipsExistingEvents(t *testing.T) {
+	backend := memory.New()
+	defer backend.Close()
+	sessionsLog, err := eventlog.New(eventlog.Config{Topic: "sessions"}, backend)
+	if err != nil {
+		t.Fatalf("eventlog.New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	appendFailure := func(streamID string) {
+		payload, err := json.Marshal(ciStateChangedPayload{PRStreamID: "github:owner/repo#7", PRNumber: 7, CIState: "failing"})
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if _, err := sessionsLog.Append(ctx, eventlog.PendingEvent{StreamID: eventlog.StreamID(streamID), Type: eventtypes.SessionPRCIStateChanged, Payload: payload}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	feedbackSent := func(streamID string) int {
+		events, err := eventlog.LoadFullStream(ctx, sessionsLog, eventlog.StreamID(streamID))
+		if err != nil {
+			t.Fatalf("LoadFullStream: %v", err)
+		}
+		sent := 0
+		for _, evt := range events {
+			if evt.Type == eventtypes.SessionPRCIFeedbackSent {
+				sent++
+			}
+		}
+		return sent
+	}
+
+	appendFailure("old-session")
+	system := New(sessionsLog, &memorySnapshotStore{snapshot: failingSnapshot("0123456789abcdef"), found: true}, &recordingAgent{}, conf.FeedbackConfig{
+		CI: conf.CIFeedbackConfig{Enabled: true, MaxAttemptsPerCommit: 1, MaxOutputChars: 200},
+	}, nil)
+	system.fetchFailures = func(context.Context, remote.PullRequestSnapshot) ([]remote.CIFailure, error) {
+		return []remote.CIFailure{{Name: "test", Summary: "TestFoo failed"}}, nil
+	}
+	system.Start(ctx)
+	appendFailure("new-session")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for feedbackSent("new-session") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for feedback on the new failure")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sent := feedbackSent("old-session"); sent != 0 {
+		t.Fatalf("expected no feedback for the failure before feedback was enabled, got %d", sent)
+	}
+}
@@ -0,0 +1,173 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+const (
+	consumerFeedback = "feedback"
+)
+
+// SnapshotStore loads the latest pull request snapshot by PR stream ID.
+type SnapshotStore interface {
+	Load(ctx context.Context, streamID string) (remote.PullRequestSnapshot, bool, error)
+}
+
+// Sessions looks up sessions and sends follow-up prompts to their agents.
+type Sessions interface {
+	LookupSessionByID(ctx context.Context, streamID string) (sessionevents.SessionRef, error)
+	SendAgentMessage(ctx context.Context, streamID string, message *messages.Message) error
+}
+
+type System struct {
+	sessionsLog   eventlog.EventLog
+	snapshots     SnapshotStore
+	sessions      Sessions
+	config        conf.FeedbackConfig
+	logger        *slog.Logger
+	fetchFailures func(ctx context.Context, snapshot remote.PullRequestSnapshot) ([]remote.CIFailure, error)
+	now           func() time.Time
+	startOnce     sync.Once
+}
+
+func New(sessionsLog eventlog.EventLog, snapshots SnapshotStore, sessions Sessions, config conf.FeedbackConfig, logger *slog.Logger) *System {
+	return &System{
+		sessionsLog:   sessionsLog,
+		snapshots:     snapshots,
+		sessions:      sessions,
+		config:        config,
+		logger:        logger,
+		fetchFailures: remote.FetchCIFailures,
+		now:           time.Now,
+	}
+}
+
+// Start subscribes to session events. Only events appended after feedback was
+// first enabled are acted on; while it is disabled the subscriber's checkpoint
+// is dropped so it does not hold back compaction.
+func (s *System) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		if !s.config.CI.Enabled && !s.config.Reviews.Enabled {
+			if err := eventlog.ForgetSubscriber(ctx, s.sessionsLog, consumerFeedback); err != nil && s.logger != nil {
+				s.logger.Warn("Failed to drop feedback checkpoint", "error", err)
+			}
+			return
+		}
+		if err := eventlog.StartAtHead(ctx, s.sessionsLog, consumerFeedback); err != nil {
+			if s.logger != nil {
+				s.logger.Error("Failed to initialize feedback checkpoint", "error", err)
+			}
+			return
+		}
+		go s.runSubscription(ctx, eventlog.Subscription{
+			ID: eventlog.SubscriberID(consumerFeedback),
+			Filter: func(evt eventlog.Envelope) bool {
+				switch evt.Type {
+				case eventtypes.SessionPRCIStateChanged:
+					return s.config.CI.Enabled
+				case eventtypes.SessionPRReviewCommentsAdded:
+					return s.config.Reviews.Enabled
+				default:
+					return false
+				}
+			},
+			Handle: s.handleSessionEvent,
+		})
+	})
+}
+
+func (s *System) Close() error {
+	return nil
+}
+
+func (s *System) runSubscription(ctx context.Context, sub eventlog.Subscription) {
+	if s.sessionsLog == nil {
+		return
+	}
+	for {
+		if err := s.sessionsLog.Subscribe(ctx, sub); err != nil && !errors.Is(err, context.Canceled) {
+			if s.logger != nil {
+				s.logger.Error("feedback subscription failed", "subscriber_id", sub.ID, "error", err)
+			}
+		} else {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func (s *System) handleSessionEvent(ctx context.Context, evt eventlog.Envelope) error {
+	switch evt.Type {
+	case eventtypes.SessionPRCIStateChanged:
+		return s.sendCIFeedback(ctx, evt)
+	case eventtypes.SessionPRReviewCommentsAdded:
+		return s.sendReviewFeedback(ctx, evt)
+	default:
+		return nil
+	}
+}
+
+// sendAgentMessage delivers message and reports whether it reached the agent.
+// Delivery failures are logged, not retried: the agent may be gone for good.
+func (s *System) sendAgentMessage(ctx context.Context, logger *slog.Logger, sessionStreamID string, message *messages.Message) bool {
+	if err := s.sessions.SendAgentMessage(ctx, sessionStreamID, message); err != nil {
+		if logger != nil {
+			logger.Warn("Failed to send feedback to agent", slog.String("error", err.Error()))
+		}
+		return false
+	}
+	return true
+}
+
+// causeHandled reports whether events already hold a sentType event caused by
+// causeID, so redelivered events do not prompt the agent twice.
+func causeHandled(events []eventlog.Envelope, sentType eventlog.EventType, causeID eventlog.EventID) bool {
+	for _, evt := range events {
+		if evt.Type == sentType && evt.CausationID == causeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *System) logWith(args ...any) *slog.Logger {
+	if s.logger == nil {
+		return nil
+	}
+	return s.logger.With(args...)
+}
+
+func (s *System) appendSessionEvent(ctx context.Context, streamID string, eventType eventlog.EventType, payload any, causationID, correlationID string) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = s.sessionsLog.Append(ctx, eventlog.PendingEvent{
+		StreamID:      eventlog.StreamID(streamID),
+		Type:          eventType,
+		SchemaVersion: 1,
+		Payload:       payloadBytes,
+		CausationID:   eventlog.EventID(causationID),
+		CorrelationID: correlationID,
+	})
+	return err
+}
@@ -0,0 +1,287 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+type memoryEventLog struct {
+	events []eventlog.Envelope
+}
+
+func (l *memoryEventLog) Append(_ context.Context, evt eventlog.PendingEvent) (eventlog.Envelope, error) {
+	version := int64(1)
+	for _, existing := range l.events {
+		if existing.StreamID == evt.StreamID {
+			version++
+		}
+	}
+	envelope := eventlog.Envelope{
+		ID:            eventlog.EventID(fmt.Sprintf("event-%d", len(l.events)+1)),
+		StreamID:      evt.StreamID,
+		StreamVersion: version,
+		Type:          evt.Type,
+		Payload:       evt.Payload,
+		CausationID:   evt.CausationID,
+		CorrelationID: evt.CorrelationID,
+	}
+	l.events = append(l.events, envelope)
+	return envelope, nil
+}
+
+// LoadStream pages like the real backends, which return at most 500 events
+// unless a limit is given.
+func (l *memoryEventLog) LoadStream(_ context.Context, streamID eventlog.StreamID, opts eventlog.LoadStreamOptions) ([]eventlog.Envelope, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 500
+	}
+	events := []eventlog.Envelope{}
+	for _, evt := range l.events {
+		if evt.StreamID == streamID && evt.StreamVersion > opts.AfterVersion && len(events) < limit {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+func (l *memoryEventLog) Subscribe(context.Context, eventlog.Subscription) error {
+	return nil
+}
+
+func (l *memoryEventLog) Close() error {
+	return nil
+}
+
+type memorySnapshotStore struct {
+	snapshot remote.PullRequestSnapshot
+	found    bool
+}
+
+func (s *memorySnapshotStore) Load(context.Context, string) (remote.PullRequestSnapshot, bool, error) {
+	return s.snapshot, s.found, nil
+}
+
+type recordingAgent struct {
+	ref       sessionevents.SessionRef
+	streamIDs []string
+	sent      []*messages.Message
+	err       error
+}
+
+func (a *recordingAgent) LookupSessionByID(_ context.Context, streamID string) (sessionevents.SessionRef, error) {
+	ref := a.ref
+	ref.StreamID = streamID
+	return ref, nil
+}
+
+func (a *recordingAgent) SendAgentMessage(_ context.Context, streamID string, message *messages.Message) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.streamIDs = append(a.streamIDs, streamID)
+	a.sent = append(a.sent, message)
+	return nil
+}
+
+func newTestSystem(t *testing.T, snapshot remote.PullRequestSnapshot, maxAttempts int) (*System, *memoryEventLog, *memorySnapshotStore, *recordingAgent) {
+	t.Helper()
+	sessionsLog := &memoryEventLog{}
+	snapshots := &memorySnapshotStore{snapshot: snapshot, found: true}
+	agent := &recordingAgent{ref: sessionevents.SessionRef{PublicState: sessionevents.PublicStateActiveIdle}}
+	system := New(sessionsLog, snapshots, agent, conf.FeedbackConfig{
+		CI:      conf.CIFeedbackConfig{Enabled: true, MaxAttemptsPerCommit: maxAttempts, MaxOutputChars: 200},
+		Reviews: conf.ReviewFeedbackConfig{Enabled: true},
+	}, nil)
+	system.fetchFailures = func(context.Context, remote.PullRequestSnapshot) ([]remote.CIFailure, error) {
+		return []remote.CIFailure{{Name: "test", TargetURL: "https://github.com/owner/repo/runs/1", Summary: "TestFoo failed: want 1, got 2"}}, nil
+	}
+	system.now = func() time.Time { return time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC) }
+	return system, sessionsLog, snapshots, agent
+}
+
+func failingSnapshot(sha string) remote.PullRequestSnapshot {
+	return remote.PullRequestSnapshot{
+		Provider: "github",
+		Number:   7,
+		HeadSHA:  sha,
+		CI:       remote.CIStatusSummary{State: "failing", Statuses: []remote.CIStatusContext{{Name: "test", State: "failing", Description: "exit status 1"}}},
+	}
+}
+
+func ciStateChanged(t *testing.T, log *memoryEventLog, ciState string) eventlog.Envelope {
+	t.Helper()
+	payload, err := json.Marshal(ciStateChangedPayload{PRStreamID: "github:owner/repo#7", PRNumber: 7, CIState: ciState})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	evt, err := log.Append(context.Background(), eventlog.PendingEvent{StreamID: "session-1", Type: eventtypes.SessionPRCIStateChanged, Payload: payload})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return evt
+}
+
+func feedbackEvents(log *memoryEventLog) []eventlog.Envelope {
+	sent := []eventlog.Envelope{}
+	for _, evt := range log.events {
+		if evt.Type == eventtypes.SessionPRCIFeedbackSent {
+			sent = append(sent, evt)
+		}
+	}
+	return sent
+}
+
+func TestCIFailureSendsFeedbackToAgent(t *testing.T) {
+	system, sessionsLog, _, agent := newTestSystem(t, failingSnapshot("0123456789abcdef"), 1)
+	cause := ciStateChanged(t, sessionsLog, "failing")
+
+	if err := system.handleSessionEvent(context.Background(), cause); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+
+	if len(agent.sent) != 1 || agent.streamIDs[0] != "session-1" {
+		t.Fatalf("expected one prompt to session-1, got %#v", agent.streamIDs)
+	}
+	prompt := messages.ToRawText(agent.sent[0])
+	for _, want := range []string{"pull request #7", "0123456789ab", "## test", "https://github.com/owner/repo/runs/1", "TestFoo failed"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+
+	sent := feedbackEvents(sessionsLog)
+	if len(sent) != 1 {
+		t.Fatalf("expected one %s event, got %d", eventtypes.SessionPRCIFeedbackSent, len(sent))
+	}
+	if sent[0].CausationID != cause.ID || sent[0].CorrelationID != "session-1" {
+		t.Fatalf("unexpected causation/correlation: %#v", sent[0])
+	}
+	var payload ciFeedbackSentPayload
+	if err := json.Unmarshal(sent[0].Payload, &payload); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if payload.HeadSHA != "0123456789abcdef" || payload.Attempt != 1 || len(payload.Checks) != 1 || payload.Checks[0] != "test" {
+		t.Fatalf("unexpected payload: %#v", payload)
+	}
+}
+
+func TestCIFeedbackIsCappedPerHeadCommit(t *testing.T) {
+	system, sessionsLog, snapshots, agent := newTestSystem(t, failingSnapshot("aaa"), 2)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := system.handleSessionEvent(ctx, ciStateChanged(t, sessionsLog, "failing")); err != nil {
+			t.Fatalf("handleSessionEvent: %v", err)
+		}
+	}
+	if len(agent.sent) != 2 {
+		t.Fatalf("expected the cap to stop the third prompt, got %d prompts", len(agent.sent))
+	}
+
+	snapshots.snapshot = failingSnapshot("bbb")
+	if err := system.handleSessionEvent(ctx, ciStateChanged(t, sessionsLog, "failing")); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(agent.sent) != 3 {
+		t.Fatalf("expected a new head commit to reset the cap, got %d prompts", len(agent.sent))
+	}
+}
+
+func TestCIFeedbackIgnoresRedeliveredEvent(t *testing.T) {
+	system, sessionsLog, _, agent := newTestSystem(t, failingSnapshot("aaa"), 3)
+	cause := ciStateChanged(t, sessionsLog, "failing")
+
+	for i := 0; i < 2; i++ {
+		if err := system.handleSessionEvent(context.Background(), cause); err != nil {
+			t.Fatalf("handleSessionEvent: %v", err)
+		}
+	}
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected one prompt for a redelivered event, got %d", len(agent.sent))
+	}
+}
+
+func TestCIFeedbackSeesHistoryBeyondOnePage(t *testing.T) {
+	system, sessionsLog, _, agent := newTestSystem(t, failingSnapshot("aaa"), 1)
+	ctx := context.Background()
+	for i := 0; i < 600; i++ {
+		if _, err := sessionsLog.Append(ctx, eventlog.PendingEvent{StreamID: "session-1", Type: eventtypes.SessionPRStateChanged, Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	cause := ciStateChanged(t, sessionsLog, "failing")
+	for i := 0; i < 2; i++ {
+		if err := system.handleSessionEvent(ctx, cause); err != nil {
+			t.Fatalf("handleSessionEvent: %v", err)
+		}
+	}
+	if err := system.handleSessionEvent(ctx, ciStateChanged(t, sessionsLog, "failing")); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected the redelivery and the cap to hold past 500 events, got %d prompts", len(agent.sent))
+	}
+}
+
+func TestCIFeedbackSkipsWhenCIIsNoLongerFailing(t *testing.T) {
+	system, sessionsLog, snapshots, agent := newTestSystem(t, failingSnapshot("aaa"), 1)
+	snapshots.snapshot.CI.State = "passing"
+
+	if err := system.handleSessionEvent(context.Background(), ciStateChanged(t, sessionsLog, "failing")); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if err := system.handleSessionEvent(context.Background(), ciStateChanged(t, sessionsLog, "pending")); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(agent.sent) != 0 || len(feedbackEvents(sessionsLog)) != 0 {
+		t.Fatalf("expected no feedback, got %d prompts", len(agent.sent))
+	}
+}
+
+func TestCIFeedbackFallsBackToStatusDescriptions(t *testing.T) {
+	system, sessionsLog, _, agent := newTestSystem(t, failingSnapshot("aaa"), 1)
+	system.fetchFailures = func(context.Context, remote.PullRequestSnapshot) ([]remote.CIFailure, error) {
+		return nil, errors.New("rate limited")
+	}
+
+	if err := system.handleSessionEvent(context.Background(), ciStateChanged(t, sessionsLog, "failing")); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(agent.sent) != 1 || !strings.Contains(messages.ToRawText(agent.sent[0]), "exit status 1") {
+		t.Fatalf("expected the status description in the prompt, got %#v", agent.sent)
+	}
+}
+
+func TestCIFeedbackDoesNotRecordUndeliveredPrompt(t *testing.T) {
+	system, sessionsLog, _, agent := newTestSystem(t, failingSnapshot("aaa"), 1)
+	agent.err = errors.New("session is not active")
+
+	if err := system.handleSessionEvent(context.Background(), ciStateChanged(t, sessionsLog, "failing")); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(feedbackEvents(sessionsLog)) != 0 {
+		t.Fatalf("expected no %s event when the prompt was not delivered", eventtypes.SessionPRCIFeedbackSent)
+	}
+}
+
+func TestTruncateMarksCutOutput(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Fatalf("truncate() = %q", got)
+	}
+	if got := truncate("0123456789abc", 10); got != "0123456789\n[output truncated]" {
+		t.Fatalf("truncate() = %q", got)
+	}
+}
@@ -0,0 +1,187 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+const defaultNotifyMessage = "{{.Rule}}: {{.Type}}{{if .Branch}} on {{.Branch}}{{end}}"
+
+var errNoSession = errors.New("event is not on a known session stream")
+
+// eventLogError marks action failures that came from the event log, which
+// fireRule returns so the event is redelivered.
+type eventLogError struct {
+	err error
+}
+
+func (e *eventLogError) Error() string {
+	return e.err.Error()
+}
+
+func (s *System) runAction(ctx context.Context, action conf.HookAction, hctx hookContext, cause eventlog.Envelope) error {
+	switch action.Type {
+	case conf.HookActionComplete:
+		if hctx.Session == nil {
+			return errNoSession
+		}
+		if err := s.requestCompletion(ctx, *hctx.Session, cause); err != nil {
+			return &eventLogError{err: err}
+		}
+		return nil
+	case conf.HookActionDelete:
+		if hctx.Session == nil {
+			return errNoSession
+		}
+		if err := s.requestDeletion(ctx, *hctx.Session, cause); err != nil {
+			return &eventLogError{err: err}
+		}
+		return nil
+	case conf.HookActionPrompt:
+		if hctx.Session == nil {
+			return errNoSession
+		}
+		if s.agent == nil {
+			return errors.New("no agent messenger configured")
+		}
+		text, err := renderMessage(action.Message, hctx)
+		if err != nil {
+			return err
+		}
+		return s.agent.SendAgentMessage(ctx, hctx.Stream, &messages.Message{
+			Role:  messages.MessageRoleUser,
+			Parts: []messages.MessagePart{messages.NewTextPart(text)},
+		})
+	case conf.HookActionShell:
+		return runShell(ctx, action, hctx)
+	case conf.HookActionWebhook:
+		return s.postWebhook(ctx, action, hctx)
+	case conf.HookActionNotify:
+		message := action.Message
+		if strings.TrimSpace(message) == "" {
+			message = defaultNotifyMessage
+		}
+		text, err := renderMessage(message, hctx)
+		if err != nil {
+			return err
+		}
+		return s.notify(ctx, "droner", text)
+	default:
+		return fmt.Errorf("unknown hook action %q", action.Type)
+	}
+}
+
+func renderMessage(message string, hctx hookContext) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, hctx); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func actionTimeout(action conf.HookAction) time.Duration {
+	if action.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(action.Timeout) * time.Second
+}
+
+// runShell runs the command with the event exposed as DRONER_* variables
+// rather than templated into the command line, so payload values are never
+// interpreted by the shell.
+func runShell(ctx context.Context, action conf.HookAction, hctx hookContext) error {
+	if strings.TrimSpace(action.Command) == "" {
+		return errors.New("shell action has no command")
+	}
+	ctx, cancel := context.WithTimeout(ctx, actionTimeout(action))
+	defer cancel()
+
+	payload, err := json.Marshal(hctx.Payload)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", action.Command)
+	cmd.Env = append(os.Environ(),
+		"DRONER_RULE="+hctx.Rule,
+		"DRONER_EVENT_TYPE="+hctx.Type,
+		"DRONER_EVENT_ID="+hctx.EventID,
+		"DRONER_STREAM_ID="+hctx.Stream,
+		"DRONER_BRANCH="+hctx.Branch,
+		"DRONER_REPO_PATH="+hctx.RepoPath,
+		"DRONER_WORKTREE_PATH="+hctx.WorktreePath,
+		"DRONER_PAYLOAD="+string(payload),
+	)
+	for _, dir := range []string{hctx.WorktreePath, hctx.RepoPath} {
+		if info, err := os.Stat(dir); dir != "" && err == nil && info.IsDir() {
+			cmd.Dir = dir
+			break
+		}
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (s *System) postWebhook(ctx context.Context, action conf.HookAction, hctx hookContext) error {
+	if action.URL == "" {
+		return errors.New("webhook action has no url")
+	}
+	ctx, cancel := context.WithTimeout(ctx, actionTimeout(action))
+	defer cancel()
+
+	body, err := json.Marshal(hctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, action.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func desktopNotify(ctx context.Context, title string, message string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return exec.CommandContext(ctx, "osascript", "-e", script).Run()
+	case "linux":
+		return exec.CommandContext(ctx, "notify-send", title, message).Run()
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+}
+
+func appleScriptString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
@@ -0,0 +1,359 @@
+package hooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestslog"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionslog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+type hookFiredPayload struct {
+	Rule      string    `json:"rule"`
+	EventType string    `json:"eventType"`
+	Actions   []string  `json:"actions"`
+	Errors    []string  `json:"errors,omitempty"`
+	FiredAt   time.Time `json:"firedAt"`
+}
+
+func (s *System) hasRuleFor(eventType eventlog.EventType) bool {
+	for _, rule := range s.config.Rules {
+		if ruleMatchesType(rule, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleMatchesType never matches records of droner's own work, so a rule on
+// "session.*" triggers neither itself nor on webhook deliveries.
+func ruleMatchesType(rule conf.HookRule, eventType eventlog.EventType) bool {
+	if eventtypes.IsRecord(eventType) {
+		return false
+	}
+	return eventtypes.Matches(rule.Event, eventType)
+}
+
+func isHookFired(eventType eventlog.EventType) bool {
+	return eventType == eventtypes.SessionHookFired || eventType == eventtypes.PRHookFired
+}
+
+func ruleMatches(rule conf.HookRule, evt eventlog.Envelope, payload map[string]any) bool {
+	if !ruleMatchesType(rule, evt.Type) {
+		return false
+	}
+	for _, match := range rule.Match {
+		if payloadField(payload, match.Field) != match.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// payloadField resolves a dotted path in payload and formats the value the
+// way it would be written in droner.json. Missing fields resolve to "".
+func payloadField(payload map[string]any, path string) string {
+	var value any = payload
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return ""
+		}
+		value = object[key]
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	}
+}
+
+func decodePayload(raw []byte) map[string]any {
+	payload := map[string]any{}
+	if len(raw) == 0 {
+		return payload
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return map[string]any{}
+	}
+	return payload
+}
+
+func (s *System) evaluateRules(ctx context.Context, log eventlog.EventLog, evt eventlog.Envelope) error {
+	payload := decodePayload(evt.Payload)
+	for _, rule := range s.config.Rules {
+		if !ruleMatches(rule, evt, payload) {
+			continue
+		}
+		if rule.For > 0 {
+			if err := s.scheduleRule(ctx, log, rule, evt); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.fireRule(ctx, log, rule, evt, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scheduleRule fires rule once its For window has passed since evt occurred.
+// The schedule is stored before the timer is armed so a restart does not
+// lose it. Events replayed after the window already passed fire right away.
+func (s *System) scheduleRule(ctx context.Context, log eventlog.EventLog, rule conf.HookRule, evt eventlog.Envelope) error {
+	fireAt := evt.OccurredAt
+	if fireAt.IsZero() {
+		fireAt = s.now()
+	}
+	fireAt = fireAt.Add(time.Duration(rule.For) * time.Second)
+
+	if s.timerArmed(rule.Name, evt.ID) {
+		return nil
+	}
+	if s.schedules != nil {
+		if err := s.schedules.Save(ctx, Schedule{Rule: rule.Name, Topic: s.topicOf(log), Cause: evt, FireAt: fireAt}); err != nil {
+			return err
+		}
+	}
+	s.armTimer(log, rule, evt, fireAt)
+	return nil
+}
+
+// resumeSchedules arms the delayed rules stored before the last shutdown.
+// Schedules for rules no longer in the config are dropped.
+func (s *System) resumeSchedules(ctx context.Context) {
+	if s.schedules == nil {
+		return
+	}
+	schedules, err := s.schedules.List(ctx)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to load delayed hook rules", slog.String("error", err.Error()))
+		}
+		return
+	}
+	for _, schedule := range schedules {
+		rule, ok := s.ruleNamed(schedule.Rule)
+		log := s.logFor(schedule.Topic)
+		if !ok || rule.For <= 0 || log == nil {
+			s.dropSchedule(ctx, schedule.Rule, schedule.Cause.ID)
+			continue
+		}
+		s.armTimer(log, rule, schedule.Cause, schedule.FireAt)
+	}
+}
+
+func (s *System) timerArmed(ruleName string, causeID eventlog.EventID) bool {
+	s.timersMu.Lock()
+	defer s.timersMu.Unlock()
+	_, ok := s.timers[ruleName+"/"+string(causeID)]
+	return ok
+}
+
+func (s *System) armTimer(log eventlog.EventLog, rule conf.HookRule, evt eventlog.Envelope, fireAt time.Time) {
+	delay := fireAt.Sub(s.now())
+	if delay < 0 {
+		delay = 0
+	}
+	key := rule.Name + "/" + string(evt.ID)
+
+	s.timersMu.Lock()
+	defer s.timersMu.Unlock()
+	if _, ok := s.timers[key]; ok {
+		return
+	}
+	s.timers[key] = time.AfterFunc(delay, func() {
+		s.timersMu.Lock()
+		delete(s.timers, key)
+		s.timersMu.Unlock()
+		if err := s.fireRule(s.runCtx, log, rule, evt, true); err != nil {
+			if s.logger != nil {
+				s.logger.Error("Failed to run delayed hook rule", slog.String("rule", rule.Name), slog.String("event_id", string(evt.ID)), slog.String("error", err.Error()))
+			}
+			return
+		}
+		s.dropSchedule(s.runCtx, rule.Name, evt.ID)
+	})
+}
+
+func (s *System) dropSchedule(ctx context.Context, ruleName string, causeID eventlog.EventID) {
+	if s.schedules == nil {
+		return
+	}
+	if err := s.schedules.Delete(ctx, ruleName, causeID); err != nil && s.logger != nil {
+		s.logger.Warn("Failed to delete delayed hook rule", slog.String("rule", ruleName), slog.String("event_id", string(causeID)), slog.String("error", err.Error()))
+	}
+}
+
+func (s *System) ruleNamed(name string) (conf.HookRule, bool) {
+	for _, rule := range s.config.Rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return conf.HookRule{}, false
+}
+
+func (s *System) topicOf(log eventlog.EventLog) eventlog.Topic {
+	if log == s.prLog {
+		return pullrequestslog.Topic
+	}
+	return sessionslog.Topic
+}
+
+func (s *System) logFor(topic eventlog.Topic) eventlog.EventLog {
+	switch topic {
+	case sessionslog.Topic:
+		return s.sessionsLog
+	case pullrequestslog.Topic:
+		return s.prLog
+	default:
+		return nil
+	}
+}
+
+// fireRule runs the actions of rule for cause and records the run on the
+// cause's stream. The record makes redelivered events a no-op. Action
+// failures are logged and recorded rather than retried; only failures to
+// append events are returned.
+func (s *System) fireRule(ctx context.Context, log eventlog.EventLog, rule conf.HookRule, cause eventlog.Envelope, delayed bool) error {
+	events, err := eventlog.LoadFullStream(ctx, log, cause.StreamID)
+	if err != nil {
+		return err
+	}
+	if ruleFired(events, rule.Name, cause.ID) {
+		return nil
+	}
+	if delayed && streamMovedOn(events, cause.ID) {
+		return nil
+	}
+
+	hctx, err := s.hookContext(ctx, log, rule, cause)
+	if err != nil {
+		return err
+	}
+	logger := s.logWith(slog.String("rule", rule.Name), slog.String("stream_id", string(cause.StreamID)), slog.String("event_type", string(cause.Type)))
+
+	fired := hookFiredPayload{Rule: rule.Name, EventType: string(cause.Type), Actions: []string{}}
+	for _, action := range rule.Actions {
+		fired.Actions = append(fired.Actions, action.Type)
+		if err := s.runAction(ctx, action, hctx, cause); err != nil {
+			var logErr *eventLogError
+			if errors.As(err, &logErr) {
+				return logErr.err
+			}
+			if logger != nil {
+				logger.Warn("Hook action failed", slog.String("action", action.Type), slog.String("error", err.Error()))
+			}
+			fired.Errors = append(fired.Errors, fmt.Sprintf("%s: %v", action.Type, err))
+		}
+	}
+	fired.FiredAt = s.now().UTC()
+
+	firedType := eventtypes.SessionHookFired
+	if log == s.prLog {
+		firedType = eventtypes.PRHookFired
+	}
+	return appendEvent(ctx, log, string(cause.StreamID), firedType, fired, string(cause.ID), string(cause.StreamID))
+}
+
+func ruleFired(events []eventlog.Envelope, ruleName string, causeID eventlog.EventID) bool {
+	for _, evt := range events {
+		if !isHookFired(evt.Type) || evt.CausationID != causeID {
+			continue
+		}
+		var payload hookFiredPayload
+		if err := json.Unmarshal(evt.Payload, &payload); err == nil && payload.Rule == ruleName {
+			return true
+		}
+	}
+	return false
+}
+
+// streamMovedOn reports whether the stream recorded anything after causeID
+// other than records of droner's own work, such as hook runs and webhook
+// deliveries.
+func streamMovedOn(events []eventlog.Envelope, causeID eventlog.EventID) bool {
+	seen := false
+	for _, evt := range events {
+		if evt.ID == causeID {
+			seen = true
+			continue
+		}
+		if seen && !eventtypes.IsRecord(evt.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// hookContext is the data available to action templates, environment
+// variables and webhook bodies.
+type hookContext struct {
+	Rule    string         `json:"rule"`
+	Type    string         `json:"type"`
+	EventID string         `json:"eventId"`
+	Stream  string         `json:"streamId"`
+	Payload map[string]any `json:"payload"`
+	Session *SessionRef    `json:"session,omitempty"`
+
+	// Branch, RepoPath and WorktreePath repeat the session fields so templates
+	// can write {{.Branch}}.
+	Branch       string `json:"-"`
+	RepoPath     string `json:"-"`
+	WorktreePath string `json:"-"`
+}
+
+func (s *System) hookContext(ctx context.Context, log eventlog.EventLog, rule conf.HookRule, cause eventlog.Envelope) (hookContext, error) {
+	hctx := hookContext{
+		Rule:    rule.Name,
+		Type:    string(cause.Type),
+		EventID: string(cause.ID),
+		Stream:  string(cause.StreamID),
+		Payload: decodePayload(cause.Payload),
+	}
+	if log != s.sessionsLog || s.sessions == nil {
+		return hctx, nil
+	}
+	ref, err := s.sessions.LoadByStreamID(ctx, string(cause.StreamID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return hctx, nil
+		}
+		return hookContext{}, err
+	}
+	hctx.Session = &ref
+	hctx.Branch = ref.Branch
+	hctx.RepoPath = ref.RepoPath
+	hctx.WorktreePath = ref.WorktreePath
+	return hctx, nil
+}
+
+func (s *System) logWith(args ...any) *slog.Logger {
+	if s.logger == nil {
+		return nil
+	}
+	return s.logger.With(args...)
+}
@@ -0,0 +1,307 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+type recordingAgent struct {
+	streamIDs []string
+	sent      []*messages.Message
+}
+
+func (a *recordingAgent) SendAgentMessage(_ context.Context, streamID string, message *messages.Message) error {
+	a.streamIDs = append(a.streamIDs, streamID)
+	a.sent = append(a.sent, message)
+	return nil
+}
+
+func newRuleSystem(sessionsLog *memoryEventLog, ref SessionRef, rules ...conf.HookRule) (*System, *recordingAgent, *[]string) {
+	agent := &recordingAgent{}
+	notified := &[]string{}
+	system := New(sessionsLog, &memoryEventLog{}, memorySessionStore{ref: ref}, nil, agent, conf.HooksConfig{Rules: rules}, nil)
+	system.notify = func(_ context.Context, _ string, message string) error {
+		*notified = append(*notified, message)
+		return nil
+	}
+	system.now = func() time.Time { return time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC) }
+	return system, agent, notified
+}
+
+func appendCause(t *testing.T, log *memoryEventLog, eventType eventlog.EventType, payload any) eventlog.Envelope {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	evt, err := log.Append(context.Background(), eventlog.PendingEvent{StreamID: "session-1", Type: eventType, Payload: raw})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return evt
+}
+
+func appendedOfType(log *memoryEventLog, eventType eventlog.EventType) []eventlog.PendingEvent {
+	events := []eventlog.PendingEvent{}
+	for _, evt := range log.appended {
+		if evt.Type == eventType {
+			events = append(events, evt)
+		}
+	}
+	return events
+}
+
+func TestRuleMatchesEventTypeAndPayloadFields(t *testing.T) {
+	rule := conf.HookRule{Name: "ci", Event: "session.pr.*", Match: []conf.HookMatch{{Field: "ciState", Value: "failing"}, {Field: "pr.number", Value: "7"}}}
+	evt := eventlog.Envelope{Type: eventtypes.SessionPRCIStateChanged}
+
+	if !ruleMatches(rule, evt, map[string]any{"ciState": "failing", "pr": map[string]any{"number": float64(7)}}) {
+		t.Fatal("expected rule to match")
+	}
+	if ruleMatches(rule, evt, map[string]any{"ciState": "passing", "pr": map[string]any{"number": float64(7)}}) {
+		t.Fatal("expected a different field value not to match")
+	}
+	if ruleMatches(rule, eventlog.Envelope{Type: eventtypes.SessionReady}, map[string]any{"ciState": "failing"}) {
+		t.Fatal("expected a different event type not to match")
+	}
+	if ruleMatchesType(conf.HookRule{Event: "session.*"}, eventtypes.SessionHookFired) {
+		t.Fatal("expected rules never to match their own records")
+	}
+}
+
+func TestRuleRunsActionsAndRecordsFiring(t *testing.T) {
+	sessionsLog := &memoryEventLog{}
+	system, agent, notified := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1", Branch: "feature", LifecycleState: "session.ready"}, conf.HookRule{
+		Name:  "closed-unmerged",
+		Event: string(eventtypes.SessionPRClosed),
+		Actions: []conf.HookAction{
+			{Type: conf.HookActionPrompt, Message: "PR #{{.Payload.prNumber}} was closed"},
+			{Type: conf.HookActionNotify},
+			{Type: conf.HookActionDelete},
+		},
+	})
+	cause := appendCause(t, sessionsLog, eventtypes.SessionPRClosed, map[string]any{"prNumber": 7})
+
+	for i := 0; i < 2; i++ {
+		if err := system.handleSessionEvent(context.Background(), cause); err != nil {
+			t.Fatalf("handleSessionEvent: %v", err)
+		}
+	}
+
+	if len(agent.sent) != 1 || messages.ToRawText(agent.sent[0]) != "PR #7 was closed" {
+		t.Fatalf("unexpected prompts: %#v", agent.sent)
+	}
+	if len(*notified) != 1 || (*notified)[0] != "closed-unmerged: session.pr.closed on feature" {
+		t.Fatalf("unexpected notifications: %#v", *notified)
+	}
+	deletions := appendedOfType(sessionsLog, eventtypes.SessionDeletionRequested)
+	if len(deletions) != 1 || deletions[0].CausationID != cause.ID {
+		t.Fatalf("expected one deletion request caused by %s, got %#v", cause.ID, deletions)
+	}
+	fired := appendedOfType(sessionsLog, eventtypes.SessionHookFired)
+	if len(fired) != 1 || fired[0].CausationID != cause.ID || fired[0].CorrelationID != "session-1" {
+		t.Fatalf("expected one %s record, got %#v", eventtypes.SessionHookFired, fired)
+	}
+	var payload hookFiredPayload
+	if err := json.Unmarshal(fired[0].Payload, &payload); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if payload.Rule != "closed-unmerged" || len(payload.Actions) != 3 || len(payload.Errors) != 0 {
+		t.Fatalf("unexpected fired payload: %#v", payload)
+	}
+}
+
+func TestRuleRecordsFailedActions(t *testing.T) {
+	sessionsLog := &memoryEventLog{}
+	system, _, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1"}, conf.HookRule{
+		Name:    "notify",
+		Event:   string(eventtypes.SessionReady),
+		Actions: []conf.HookAction{{Type: conf.HookActionNotify}},
+	})
+	system.notify = func(context.Context, string, string) error { return errors.New("no notifier") }
+
+	if err := system.handleSessionEvent(context.Background(), appendCause(t, sessionsLog, eventtypes.SessionReady, map[string]any{})); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	fired := appendedOfType(sessionsLog, eventtypes.SessionHookFired)
+	if len(fired) != 1 || !strings.Contains(string(fired[0].Payload), "no notifier") {
+		t.Fatalf("expected the failure in the fired record, got %#v", fired)
+	}
+}
+
+func TestShellActionExposesEventAsEnvironment(t *testing.T) {
+	worktree := t.TempDir()
+	sessionsLog := &memoryEventLog{}
+	system, _, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1", Branch: "feature", WorktreePath: worktree}, conf.HookRule{
+		Name:    "shell",
+		Event:   string(eventtypes.SessionPRCIStateChanged),
+		Actions: []conf.HookAction{{Type: conf.HookActionShell, Command: `printf '%s %s %s' "$DRONER_BRANCH" "$DRONER_EVENT_TYPE" "$DRONER_PAYLOAD" > out.txt`, Timeout: 5}},
+	})
+
+	if err := system.handleSessionEvent(context.Background(), appendCause(t, sessionsLog, eventtypes.SessionPRCIStateChanged, map[string]any{"ciState": "failing"})); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	out, err := os.ReadFile(filepath.Join(worktree, "out.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(out) != `feature session.pr.ci_state_changed {"ciState":"failing"}` {
+		t.Fatalf("unexpected shell output %q", out)
+	}
+}
+
+func TestWebhookActionPostsEvent(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sessionsLog := &memoryEventLog{}
+	system, _, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1", Branch: "feature"}, conf.HookRule{
+		Name:    "webhook",
+		Event:   string(eventtypes.SessionReady),
+		Actions: []conf.HookAction{{Type: conf.HookActionWebhook, URL: server.URL, Timeout: 5}},
+	})
+
+	if err := system.handleSessionEvent(context.Background(), appendCause(t, sessionsLog, eventtypes.SessionReady, map[string]any{"branch": "feature"})); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	session, _ := body["session"].(map[string]any)
+	if body["rule"] != "webhook" || body["type"] != string(eventtypes.SessionReady) || session["branch"] != "feature" {
+		t.Fatalf("unexpected webhook body: %#v", body)
+	}
+}
+
+func TestDelayedRuleIsDroppedWhenStreamMovesOn(t *testing.T) {
+	sessionsLog := &memoryEventLog{}
+	rule := conf.HookRule{
+		Name:    "idle",
+		Event:   string(eventtypes.SessionAgentIdle),
+		For:     1800,
+		Actions: []conf.HookAction{{Type: conf.HookActionPrompt, Message: "still there?"}},
+	}
+	system, agent, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1"}, rule)
+	idle := appendCause(t, sessionsLog, eventtypes.SessionAgentIdle, map[string]any{})
+	appendCause(t, sessionsLog, eventtypes.SessionAgentBusy, map[string]any{})
+
+	if err := system.fireRule(context.Background(), sessionsLog, rule, idle, true); err != nil {
+		t.Fatalf("fireRule: %v", err)
+	}
+	if len(agent.sent) != 0 {
+		t.Fatalf("expected the busy event to cancel the idle rule, got %d prompts", len(agent.sent))
+	}
+
+	idle = appendCause(t, sessionsLog, eventtypes.SessionAgentIdle, map[string]any{})
+	if err := system.fireRule(context.Background(), sessionsLog, rule, idle, true); err != nil {
+		t.Fatalf("fireRule: %v", err)
+	}
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected the idle rule to fire, got %d prompts", len(agent.sent))
+	}
+}
+
+func TestDelayedRuleSchedulesOncePerEvent(t *testing.T) {
+	sessionsLog := &memoryEventLog{}
+	rule := conf.HookRule{Name: "idle", Event: string(eventtypes.SessionAgentIdle), For: 1800, Actions: []conf.HookAction{{Type: conf.HookActionNotify}}}
+	system, _, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1"}, rule)
+	idle := appendCause(t, sessionsLog, eventtypes.SessionAgentIdle, map[string]any{})
+	idle.OccurredAt = system.now()
+
+	for i := 0; i < 2; i++ {
+		if err := system.handleSessionEvent(context.Background(), idle); err != nil {
+			t.Fatalf("handleSessionEvent: %v", err)
+		}
+	}
+	if len(system.timers) != 1 {
+		t.Fatalf("expected one pending timer, got %d", len(system.timers))
+	}
+	if err := system.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(system.timers) != 0 {
+		t.Fatalf("expected Close to stop pending timers, got %d", len(system.timers))
+	}
+}
+
+func TestRuleRecordsAreFoundPastOnePage(t *testing.T) {
+	sessionsLog := &memoryEventLog{}
+	rule := conf.HookRule{Name: "idle", Event: string(eventtypes.SessionAgentIdle), For: 1800, Actions: []conf.HookAction{{Type: conf.HookActionPrompt, Message: "still there?"}}}
+	system, agent, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1"}, rule)
+	for i := 0; i < 600; i++ {
+		appendCause(t, sessionsLog, eventtypes.SessionPRStateChanged, map[string]any{})
+	}
+
+	idle := appendCause(t, sessionsLog, eventtypes.SessionAgentIdle, map[string]any{})
+	for i := 0; i < 2; i++ {
+		if err := system.fireRule(context.Background(), sessionsLog, rule, idle, true); err != nil {
+			t.Fatalf("fireRule: %v", err)
+		}
+	}
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected the rule to fire once, got %d prompts", len(agent.sent))
+	}
+
+	idle = appendCause(t, sessionsLog, eventtypes.SessionAgentIdle, map[string]any{})
+	appendCause(t, sessionsLog, eventtypes.SessionAgentBusy, map[string]any{})
+	if err := system.fireRule(context.Background(), sessionsLog, rule, idle, true); err != nil {
+		t.Fatalf("fireRule: %v", err)
+	}
+	if len(agent.sent) != 1 {
+		t.Fatalf("expected the busy event to cancel the idle rule, got %d prompts", len(agent.sent))
+	}
+}
+
+func TestDelayedRuleSurvivesRestart(t *testing.T) {
+	sessionsLog := &memoryEventLog{}
+	schedules := &memoryScheduleStore{schedules: map[string]Schedule{}}
+	rule := conf.HookRule{Name: "idle", Event: string(eventtypes.SessionAgentIdle), For: 1800, Actions: []conf.HookAction{{Type: conf.HookActionPrompt, Message: "still there?"}}}
+	before, _, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1"}, rule)
+	before.schedules = schedules
+	idle := appendCause(t, sessionsLog, eventtypes.SessionAgentIdle, map[string]any{})
+	idle.OccurredAt = before.now()
+
+	if err := before.handleSessionEvent(context.Background(), idle); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if err := before.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if schedules.len() != 1 {
+		t.Fatalf("expected the delayed rule to be stored, got %d schedules", schedules.len())
+	}
+
+	after, _, _ := newRuleSystem(sessionsLog, SessionRef{StreamID: "session-1"}, rule)
+	after.schedules = schedules
+	after.now = func() time.Time { return idle.OccurredAt.Add(time.Hour) }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	after.Start(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for schedules.len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if schedules.len() != 0 {
+		t.Fatalf("expected the resumed rule to fire and clear its schedule")
+	}
+	if len(appendedOfType(sessionsLog, eventtypes.SessionHookFired)) != 1 {
+		t.Fatalf("expected one %s event after restart", eventtypes.SessionHookFired)
+	}
+}
@@ -3,33 +3,107 @@ package hooks
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	coredb "github.com/Oudwins/droner/pkgs/droner/dronerd/db"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
 )
 
 type SessionRef struct {
-	Branch         string
-	LifecycleState string
+	StreamID       string `json:"streamId"`
+	Branch         string `json:"branch"`
+	RepoPath       string `json:"repoPath"`
+	WorktreePath   string `json:"worktreePath"`
+	RemoteURL      string `json:"remoteUrl"`
+	LifecycleState string `json:"lifecycleState"`
+	PublicState    string `json:"publicState"`
 }
 
 type SessionLookupStore interface {
 	LoadByStreamID(ctx context.Context, streamID string) (SessionRef, error)
 }
 
-type SQLiteSessionLookupStore struct {
-	queries *coredb.Queries
+type DBSessionLookupStore struct {
+	queries coredb.Querier
 }
 
-func NewSQLiteSessionLookupStore(queries *coredb.Queries) *SQLiteSessionLookupStore {
-	return &SQLiteSessionLookupStore{queries: queries}
+func NewSessionLookupStore(queries coredb.Querier) *DBSessionLookupStore {
+	return &DBSessionLookupStore{queries: queries}
 }
 
-func (s *SQLiteSessionLookupStore) LoadByStreamID(ctx context.Context, streamID string) (SessionRef, error) {
+func (s *DBSessionLookupStore) LoadByStreamID(ctx context.Context, streamID string) (SessionRef, error) {
 	row, err := s.queries.GetSessionProjectionByStreamID(ctx, streamID)
 	if err != nil {
 		return SessionRef{}, err
 	}
-	return SessionRef{Branch: nullStringValue(row.Branch), LifecycleState: row.LifecycleState}, nil
+	return SessionRef{
+		StreamID:       row.StreamID,
+		Branch:         nullStringValue(row.Branch),
+		RepoPath:       row.RepoPath,
+		WorktreePath:   nullStringValue(row.WorktreePath),
+		RemoteURL:      row.RemoteUrl,
+		LifecycleState: row.LifecycleState,
+		PublicState:    row.PublicState,
+	}, nil
+}
+
+// Schedule is a delayed rule waiting for its For window to pass.
+type Schedule struct {
+	Rule   string
+	Topic  eventlog.Topic
+	Cause  eventlog.Envelope
+	FireAt time.Time
+}
+
+// ScheduleStore keeps delayed rules across restarts. Save ignores a rule
+// that is already scheduled for the same cause.
+type ScheduleStore interface {
+	Save(ctx context.Context, schedule Schedule) error
+	Delete(ctx context.Context, rule string, causeID eventlog.EventID) error
+	List(ctx context.Context) ([]Schedule, error)
+}
+
+type DBScheduleStore struct {
+	queries coredb.Querier
+}
+
+func NewScheduleStore(queries coredb.Querier) *DBScheduleStore {
+	return &DBScheduleStore{queries: queries}
+}
+
+func (s *DBScheduleStore) Save(ctx context.Context, schedule Schedule) error {
+	cause, err := json.Marshal(schedule.Cause)
+	if err != nil {
+		return err
+	}
+	return s.queries.InsertHookSchedule(ctx, coredb.InsertHookScheduleParams{
+		Rule:      schedule.Rule,
+		CauseID:   string(schedule.Cause.ID),
+		Topic:     string(schedule.Topic),
+		CauseJson: string(cause),
+		FireAt:    schedule.FireAt.UTC(),
+	})
+}
+
+func (s *DBScheduleStore) Delete(ctx context.Context, rule string, causeID eventlog.EventID) error {
+	return s.queries.DeleteHookSchedule(ctx, coredb.DeleteHookScheduleParams{Rule: rule, CauseID: string(causeID)})
+}
+
+func (s *DBScheduleStore) List(ctx context.Context) ([]Schedule, error) {
+	rows, err := s.queries.ListHookSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]Schedule, 0, len(rows))
+	for _, row := range rows {
+		var cause eventlog.Envelope
+		if err := json.Unmarshal([]byte(row.CauseJson), &cause); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, Schedule{Rule: row.Rule, Topic: eventlog.Topic(row.Topic), Cause: cause, FireAt: row.FireAt})
+	}
+	return schedules, nil
 }
 
 func nullStringValue(value sql.NullString) string {
@@ -5,31 +5,65 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
 )
 
 const (
 	consumerHooks = "hooks"
 )
 
+// Agent sends follow-up prompts to a session's agent.
+type Agent interface {
+	SendAgentMessage(ctx context.Context, streamID string, message *messages.Message) error
+}
+
 type System struct {
 	sessionsLog eventlog.EventLog
 	prLog       eventlog.EventLog
 	sessions    SessionLookupStore
+	schedules   ScheduleStore
+	agent       Agent
+	config      conf.HooksConfig
 	logger      *slog.Logger
+	httpClient  *http.Client
+	notify      func(ctx context.Context, title string, message string) error
+	now         func() time.Time
 	startOnce   sync.Once
+
+	// timers holds delayed rules waiting for their For window to pass. Each
+	// one is also kept in schedules so Start can arm it again after a
+	// restart.
+	timersMu sync.Mutex
+	timers   map[string]*time.Timer
+	runCtx   context.Context
 }
 
 type branchPayload struct {
 	Branch string `json:"branch"`
 }
 
-func New(sessionsLog eventlog.EventLog, prLog eventlog.EventLog, sessions SessionLookupStore, logger *slog.Logger) *System {
-	return &System{sessionsLog: sessionsLog, prLog: prLog, sessions: sessions, logger: logger}
+func New(sessionsLog eventlog.EventLog, prLog eventlog.EventLog, sessions SessionLookupStore, schedules ScheduleStore, agent Agent, config conf.HooksConfig, logger *slog.Logger) *System {
+	return &System{
+		sessionsLog: sessionsLog,
+		prLog:       prLog,
+		sessions:    sessions,
+		schedules:   schedules,
+		agent:       agent,
+		config:      config,
+		logger:      logger,
+		httpClient:  &http.Client{},
+		notify:      desktopNotify,
+		now:         time.Now,
+		timers:      map[string]*time.Timer{},
+		runCtx:      context.Background(),
+	}
 }
 
 func (s *System) Start(ctx context.Context) {
@@ -37,12 +71,23 @@ func (s *System) Start(ctx context.Context) {
 		return
 	}
 	s.startOnce.Do(func() {
+		s.runCtx = ctx
+		s.resumeSchedules(ctx)
 		go s.runSessionSubscription(ctx)
 		go s.runPullRequestSubscription(ctx)
 	})
 }
 
 func (s *System) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.timersMu.Lock()
+	defer s.timersMu.Unlock()
+	for key, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, key)
+	}
 	return nil
 }
 
@@ -50,7 +95,10 @@ func (s *System) runSessionSubscription(ctx context.Context) {
 	s.runSubscription(ctx, s.sessionsLog, eventlog.Subscription{
 		ID: eventlog.SubscriberID(consumerHooks),
 		Filter: func(evt eventlog.Envelope) bool {
-			return evt.Type == eventtypes.SessionPRMerged
+			if evt.Type == eventtypes.SessionPRMerged && s.config.CompleteOnMerge {
+				return true
+			}
+			return s.hasRuleFor(evt.Type)
 		},
 		Handle: s.handleSessionEvent,
 	})
@@ -58,10 +106,9 @@ func (s *System) runSessionSubscription(ctx context.Context) {
 
 func (s *System) runPullRequestSubscription(ctx context.Context) {
 	s.runSubscription(ctx, s.prLog, eventlog.Subscription{
-		ID: eventlog.SubscriberID(consumerHooks),
-		Handle: func(context.Context, eventlog.Envelope) error {
-			return nil
-		},
+		ID:     eventlog.SubscriberID(consumerHooks),
+		Filter: func(evt eventlog.Envelope) bool { return s.hasRuleFor(evt.Type) },
+		Handle: s.handlePullRequestEvent,
 	})
 }
 
@@ -86,12 +133,16 @@ func (s *System) runSubscription(ctx context.Context, log eventlog.EventLog, sub
 }
 
 func (s *System) handleSessionEvent(ctx context.Context, evt eventlog.Envelope) error {
-	switch evt.Type {
-	case eventtypes.SessionPRMerged:
-		return s.completeSession(ctx, evt)
-	default:
-		return nil
+	if evt.Type == eventtypes.SessionPRMerged && s.config.CompleteOnMerge {
+		if err := s.completeSession(ctx, evt); err != nil {
+			return err
+		}
 	}
+	return s.evaluateRules(ctx, s.sessionsLog, evt)
+}
+
+func (s *System) handlePullRequestEvent(ctx context.Context, evt eventlog.Envelope) error {
+	return s.evaluateRules(ctx, s.prLog, evt)
 }
 
 func (s *System) completeSession(ctx context.Context, cause eventlog.Envelope) error {
@@ -99,12 +150,23 @@ func (s *System) completeSession(ctx context.Context, cause eventlog.Envelope) e
 	if err != nil {
 		return err
 	}
+	return s.requestCompletion(ctx, ref, cause)
+}
+
+func (s *System) requestCompletion(ctx context.Context, ref SessionRef, cause eventlog.Envelope) error {
 	if completionAlreadyRequested(ref.LifecycleState) {
 		return nil
 	}
 	return s.appendSessionEvent(ctx, string(cause.StreamID), eventtypes.SessionCompletionRequested, branchPayload{Branch: ref.Branch}, string(cause.ID), string(cause.StreamID))
 }
 
+func (s *System) requestDeletion(ctx context.Context, ref SessionRef, cause eventlog.Envelope) error {
+	if deletionAlreadyRequested(ref.LifecycleState) {
+		return nil
+	}
+	return s.appendSessionEvent(ctx, string(cause.StreamID), eventtypes.SessionDeletionRequested, branchPayload{Branch: ref.Branch}, string(cause.ID), string(cause.StreamID))
+}
+
 func completionAlreadyRequested(lifecycleState string) bool {
 	switch eventlog.EventType(lifecycleState) {
 	case eventtypes.SessionCompletionRequested,
@@ -121,12 +183,27 @@ func completionAlreadyRequested(lifecycleState string) bool {
 	}
 }
 
+func deletionAlreadyRequested(lifecycleState string) bool {
+	switch eventlog.EventType(lifecycleState) {
+	case eventtypes.SessionDeletionRequested,
+		eventtypes.SessionDeletionStarted,
+		eventtypes.SessionDeletionSuccess:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *System) appendSessionEvent(ctx context.Context, streamID string, eventType eventlog.EventType, payload any, causationID, correlationID string) error {
+	return appendEvent(ctx, s.sessionsLog, streamID, eventType, payload, causationID, correlationID)
+}
+
+func appendEvent(ctx context.Context, log eventlog.EventLog, streamID string, eventType eventlog.EventType, payload any, causationID, correlationID string) error {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
-	_, err = s.sessionsLog.Append(ctx, eventlog.PendingEvent{
+	_, err = log.Append(ctx, eventlog.PendingEvent{
 		StreamID:      eventlog.StreamID(streamID),
 		Type:          eventType,
 		SchemaVersion: 1,
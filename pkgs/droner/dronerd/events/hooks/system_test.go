@@ -3,9 +3,12 @@ package hooks
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
 )
 
@@ -20,15 +23,44 @@ func (s memorySessionStore) LoadByStreamID(context.Context, string) (SessionRef,
 
 type memoryEventLog struct {
 	appended []eventlog.PendingEvent
+	events   []eventlog.Envelope
 }
 
 func (l *memoryEventLog) Append(_ context.Context, evt eventlog.PendingEvent) (eventlog.Envelope, error) {
 	l.appended = append(l.appended, evt)
-	return eventlog.Envelope{ID: eventlog.EventID("event-" + string(evt.Type)), StreamID: evt.StreamID, Type: evt.Type}, nil
+	version := int64(1)
+	for _, existing := range l.events {
+		if existing.StreamID == evt.StreamID {
+			version++
+		}
+	}
+	envelope := eventlog.Envelope{
+		ID:            eventlog.EventID(fmt.Sprintf("event-%d", len(l.events)+1)),
+		StreamID:      evt.StreamID,
+		StreamVersion: version,
+		Type:          evt.Type,
+		Payload:       evt.Payload,
+		CausationID:   evt.CausationID,
+		CorrelationID: evt.CorrelationID,
+	}
+	l.events = append(l.events, envelope)
+	return envelope, nil
 }
 
-func (l *memoryEventLog) LoadStream(context.Context, eventlog.StreamID, eventlog.LoadStreamOptions) ([]eventlog.Envelope, error) {
-	return nil, nil
+// LoadStream pages like the real backends, which return at most 500 events
+// unless a limit is given.
+func (l *memoryEventLog) LoadStream(_ context.Context, streamID eventlog.StreamID, opts eventlog.LoadStreamOptions) ([]eventlog.Envelope, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 500
+	}
+	events := []eventlog.Envelope{}
+	for _, evt := range l.events {
+		if evt.StreamID == streamID && evt.StreamVersion > opts.AfterVersion && len(events) < limit {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
 }
 
 func (l *memoryEventLog) Subscribe(context.Context, eventlog.Subscription) error {
@@ -39,10 +71,48 @@ func (l *memoryEventLog) Close() error {
 	return nil
 }
 
+type memoryScheduleStore struct {
+	mu        sync.Mutex
+	schedules map[string]Schedule
+}
+
+func (s *memoryScheduleStore) Save(_ context.Context, schedule Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := schedule.Rule + "/" + string(schedule.Cause.ID)
+	if _, ok := s.schedules[key]; !ok {
+		s.schedules[key] = schedule
+	}
+	return nil
+}
+
+func (s *memoryScheduleStore) Delete(_ context.Context, rule string, causeID eventlog.EventID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.schedules, rule+"/"+string(causeID))
+	return nil
+}
+
+func (s *memoryScheduleStore) List(context.Context) ([]Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedules := []Schedule{}
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func (s *memoryScheduleStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.schedules)
+}
+
 func TestSessionPRMergedRequestsCompletion(t *testing.T) {
 	ctx := context.Background()
 	sessionsLog := &memoryEventLog{}
-	system := New(sessionsLog, nil, memorySessionStore{ref: SessionRef{Branch: "feature", LifecycleState: "session.ready"}}, nil)
+	system := New(sessionsLog, nil, memorySessionStore{ref: SessionRef{Branch: "feature", LifecycleState: "session.ready"}}, nil, nil, conf.HooksConfig{CompleteOnMerge: true}, nil)
 
 	if err := system.handleSessionEvent(ctx, eventlog.Envelope{
 		ID:       eventlog.EventID("cause-1"),
@@ -87,7 +157,7 @@ func TestSessionPRMergedSkipsCompletionWhenAlreadyRequested(t *testing.T) {
 	for _, state := range states {
 		t.Run(string(state), func(t *testing.T) {
 			sessionsLog := &memoryEventLog{}
-			system := New(sessionsLog, nil, memorySessionStore{ref: SessionRef{Branch: "feature", LifecycleState: string(state)}}, nil)
+			system := New(sessionsLog, nil, memorySessionStore{ref: SessionRef{Branch: "feature", LifecycleState: string(state)}}, nil, nil, conf.HooksConfig{CompleteOnMerge: true}, nil)
 
 			if err := system.handleSessionEvent(context.Background(), eventlog.Envelope{
 				ID:       eventlog.EventID("cause-1"),
@@ -102,3 +172,19 @@ func TestSessionPRMergedSkipsCompletionWhenAlreadyRequested(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionPRMergedLeavesSessionOpenWhenCompleteOnMergeIsOff(t *testing.T) {
+	sessionsLog := &memoryEventLog{}
+	system := New(sessionsLog, nil, memorySessionStore{ref: SessionRef{Branch: "feature", LifecycleState: "session.ready"}}, nil, nil, conf.HooksConfig{}, nil)
+
+	if err := system.handleSessionEvent(context.Background(), eventlog.Envelope{
+		ID:       eventlog.EventID("cause-1"),
+		StreamID: eventlog.StreamID("session-1"),
+		Type:     eventtypes.SessionPRMerged,
+	}); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(sessionsLog.appended) != 0 {
+		t.Fatalf("expected no appended events, got %d", len(sessionsLog.appended))
+	}
+}
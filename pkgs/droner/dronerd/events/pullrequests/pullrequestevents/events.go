@@ -27,9 +27,17 @@ type PRFieldChange struct {
 	New   any    `json:"new,omitempty"`
 }
 
+type prReviewCommentAddedPayload struct {
+	StreamID   string               `json:"streamId"`
+	Number     int                  `json:"number"`
+	Comment    remote.ReviewComment `json:"comment"`
+	ObservedAt time.Time            `json:"observedAt"`
+}
+
 type sessionPRLinkedPayload struct {
 	PRStreamID string    `json:"prStreamId"`
 	PRNumber   int       `json:"prNumber"`
+	URL        string    `json:"url,omitempty"`
 	State      string    `json:"state,omitempty"`
 	CIState    string    `json:"ciState,omitempty"`
 	LinkedAt   time.Time `json:"linkedAt"`
@@ -49,6 +57,14 @@ type sessionPRCIStateChangedPayload struct {
 	ChangedAt  time.Time `json:"changedAt"`
 }
 
+type sessionPRReviewCommentsAddedPayload struct {
+	PRStreamID string    `json:"prStreamId"`
+	PRNumber   int       `json:"prNumber"`
+	CommentIDs []int64   `json:"commentIds"`
+	Unresolved int       `json:"unresolved"`
+	ObservedAt time.Time `json:"observedAt"`
+}
+
 func marshalPendingEvent(streamID string, eventType eventlog.EventType, payload any, causationID, correlationID string) (eventlog.PendingEvent, error) {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
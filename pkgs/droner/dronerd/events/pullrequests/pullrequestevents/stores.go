@@ -25,23 +25,23 @@ type PullRequestSnapshotStore interface {
 	Upsert(ctx context.Context, snapshot remote.PullRequestSnapshot, observedAt time.Time) error
 }
 
-type SQLiteSessionLookupStore struct {
-	queries *coredb.Queries
+type DBSessionLookupStore struct {
+	queries coredb.Querier
 }
 
-type SQLitePullRequestSnapshotStore struct {
-	queries *coredb.Queries
+type DBPullRequestSnapshotStore struct {
+	queries coredb.Querier
 }
 
-func NewSQLiteSessionLookupStore(queries *coredb.Queries) *SQLiteSessionLookupStore {
-	return &SQLiteSessionLookupStore{queries: queries}
+func NewSessionLookupStore(queries coredb.Querier) *DBSessionLookupStore {
+	return &DBSessionLookupStore{queries: queries}
 }
 
-func NewSQLitePullRequestSnapshotStore(queries *coredb.Queries) *SQLitePullRequestSnapshotStore {
-	return &SQLitePullRequestSnapshotStore{queries: queries}
+func NewPullRequestSnapshotStore(queries coredb.Querier) *DBPullRequestSnapshotStore {
+	return &DBPullRequestSnapshotStore{queries: queries}
 }
 
-func (s *SQLiteSessionLookupStore) LoadByStreamID(ctx context.Context, streamID string) (SessionRef, error) {
+func (s *DBSessionLookupStore) LoadByStreamID(ctx context.Context, streamID string) (SessionRef, error) {
 	row, err := s.queries.GetSessionProjectionByStreamID(ctx, streamID)
 	if err != nil {
 		return SessionRef{}, err
@@ -49,7 +49,7 @@ func (s *SQLiteSessionLookupStore) LoadByStreamID(ctx context.Context, streamID
 	return SessionRef{RemoteURL: row.RemoteUrl, Branch: nullStringValue(row.Branch)}, nil
 }
 
-func (s *SQLitePullRequestSnapshotStore) Load(ctx context.Context, streamID string) (remote.PullRequestSnapshot, bool, error) {
+func (s *DBPullRequestSnapshotStore) Load(ctx context.Context, streamID string) (remote.PullRequestSnapshot, bool, error) {
 	row, err := s.queries.GetPRLatestSnapshotByStreamID(ctx, streamID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -64,7 +64,24 @@ func (s *SQLitePullRequestSnapshotStore) Load(ctx context.Context, streamID stri
 	return snapshot, true, nil
 }
 
-func (s *SQLitePullRequestSnapshotStore) Upsert(ctx context.Context, snapshot remote.PullRequestSnapshot, observedAt time.Time) error {
+// LoadLatestByRemoteAndBranch loads the most recently observed pull request
+// opened from branch on remoteURL.
+func (s *DBPullRequestSnapshotStore) LoadLatestByRemoteAndBranch(ctx context.Context, remoteURL string, branch string) (remote.PullRequestSnapshot, bool, error) {
+	rows, err := s.queries.ListPRLatestSnapshotsByRemoteAndBranch(ctx, coredb.ListPRLatestSnapshotsByRemoteAndBranchParams{RemoteUrl: remoteURL, HeadRef: branch})
+	if err != nil {
+		return remote.PullRequestSnapshot{}, false, err
+	}
+	if len(rows) == 0 {
+		return remote.PullRequestSnapshot{}, false, nil
+	}
+	var snapshot remote.PullRequestSnapshot
+	if err := json.Unmarshal([]byte(rows[0].SnapshotJson), &snapshot); err != nil {
+		return remote.PullRequestSnapshot{}, false, err
+	}
+	return snapshot, true, nil
+}
+
+func (s *DBPullRequestSnapshotStore) Upsert(ctx context.Context, snapshot remote.PullRequestSnapshot, observedAt time.Time) error {
 	snapshotJSON, err := stableSnapshotJSON(snapshot)
 	if err != nil {
 		return err
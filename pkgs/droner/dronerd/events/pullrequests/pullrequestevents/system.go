@@ -28,6 +28,7 @@ type System struct {
 	snapshots  PullRequestSnapshotStore
 	logger     *slog.Logger
 	remoteSubs *subscriptionState
+	prLocks    *streamLocks
 	startOnce  sync.Once
 }
 
@@ -36,8 +37,44 @@ type subscriptionState struct {
 	subs map[string]string
 }
 
+// streamLocks serializes ingestion per PR stream. The poller, webhook
+// refreshes and LinkCreatedPullRequest can all observe the same PR at once;
+// without the lock each sees no stored snapshot and appends its own
+// initial PRObserved and SessionPRLinked events.
+type streamLocks struct {
+	mu    sync.Mutex
+	locks map[string]*streamLock
+}
+
+type streamLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (l *streamLocks) lock(streamID string) func() {
+	l.mu.Lock()
+	entry, ok := l.locks[streamID]
+	if !ok {
+		entry = &streamLock{}
+		l.locks[streamID] = entry
+	}
+	entry.refs++
+	l.mu.Unlock()
+
+	entry.mu.Lock()
+	return func() {
+		entry.mu.Unlock()
+		l.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(l.locks, streamID)
+		}
+		l.mu.Unlock()
+	}
+}
+
 func New(prLog eventlog.EventLog, sessionLog eventlog.EventLog, sessions SessionLookupStore, snapshots PullRequestSnapshotStore, logger *slog.Logger) *System {
-	return &System{prLog: prLog, sessionLog: sessionLog, sessions: sessions, snapshots: snapshots, logger: logger, remoteSubs: &subscriptionState{subs: map[string]string{}}}
+	return &System{prLog: prLog, sessionLog: sessionLog, sessions: sessions, snapshots: snapshots, logger: logger, remoteSubs: &subscriptionState{subs: map[string]string{}}, prLocks: &streamLocks{locks: map[string]*streamLock{}}}
 }
 
 func (s *System) Start(ctx context.Context) {
@@ -175,17 +212,29 @@ func (s *System) handleRemoteEvent(ctx context.Context, sessionStreamID string,
 	}
 }
 
+// LinkCreatedPullRequest records a pull request droner just opened for a
+// session so it is linked without waiting for the next provider poll.
+func (s *System) LinkCreatedPullRequest(ctx context.Context, sessionStreamID string, snapshot remote.PullRequestSnapshot) (string, error) {
+	if err := s.ingestObserved(ctx, sessionStreamID, snapshot, time.Now().UTC()); err != nil {
+		return "", err
+	}
+	return prStreamID(snapshot), nil
+}
+
 func (s *System) ingestObserved(ctx context.Context, sessionStreamID string, snapshot remote.PullRequestSnapshot, observedAt time.Time) error {
 	streamID := prStreamID(snapshot)
+	unlock := s.prLocks.lock(streamID)
+	defer unlock()
 	oldSnapshot, found, err := s.loadStoredSnapshot(ctx, streamID)
 	if err != nil {
 		return err
 	}
 	changes := []PRFieldChange{}
 	kind := "initial"
+	added := addedReviewComments(oldSnapshot, snapshot)
 	if found {
 		changes = diffPullRequestSnapshots(oldSnapshot, snapshot)
-		if len(changes) == 0 {
+		if len(changes) == 0 && len(added) == 0 {
 			return nil
 		}
 		kind = "delta"
@@ -193,14 +242,47 @@ func (s *System) ingestObserved(ctx context.Context, sessionStreamID string, sna
 	if err := s.snapshots.Upsert(ctx, snapshot, observedAt); err != nil {
 		return err
 	}
-	payload := prObservedPayload{Provider: snapshot.Provider, StreamID: streamID, RemoteURL: snapshot.RemoteURL, RepoOwner: snapshot.RepoOwner, RepoName: snapshot.RepoName, Number: snapshot.Number, ObservedAt: observedAt.UTC(), Kind: kind, Changes: changes}
-	if !found {
-		payload.Snapshot = &snapshot
+	if !found || len(changes) > 0 {
+		payload := prObservedPayload{Provider: snapshot.Provider, StreamID: streamID, RemoteURL: snapshot.RemoteURL, RepoOwner: snapshot.RepoOwner, RepoName: snapshot.RepoName, Number: snapshot.Number, ObservedAt: observedAt.UTC(), Kind: kind, Changes: changes}
+		if !found {
+			payload.Snapshot = &snapshot
+		}
+		if err := s.appendPRLog(ctx, streamID, eventtypes.PRObserved, payload, "", streamID); err != nil {
+			return err
+		}
 	}
-	if err := s.appendPRLog(ctx, streamID, eventtypes.PRObserved, payload, "", streamID); err != nil {
-		return err
+	for _, comment := range added {
+		if err := s.appendPRLog(ctx, streamID, eventtypes.PRReviewCommentAdded, prReviewCommentAddedPayload{StreamID: streamID, Number: snapshot.Number, Comment: comment, ObservedAt: observedAt.UTC()}, "", streamID); err != nil {
+			return err
+		}
+	}
+	return s.appendSessionSummaryEvents(ctx, sessionStreamID, streamID, oldSnapshot, snapshot, found, added, observedAt)
+}
+
+// addedReviewComments returns the comments of newSnapshot that oldSnapshot did
+// not have yet.
+func addedReviewComments(oldSnapshot, newSnapshot remote.PullRequestSnapshot) []remote.ReviewComment {
+	seen := make(map[int64]struct{}, len(oldSnapshot.ReviewComments))
+	for _, comment := range oldSnapshot.ReviewComments {
+		seen[comment.ID] = struct{}{}
+	}
+	added := []remote.ReviewComment{}
+	for _, comment := range newSnapshot.ReviewComments {
+		if _, ok := seen[comment.ID]; !ok {
+			added = append(added, comment)
+		}
+	}
+	return added
+}
+
+func resolvedReviewCommentIDs(snapshot remote.PullRequestSnapshot) []int64 {
+	ids := []int64{}
+	for _, comment := range snapshot.ReviewComments {
+		if comment.Resolved {
+			ids = append(ids, comment.ID)
+		}
 	}
-	return s.appendSessionSummaryEvents(ctx, sessionStreamID, streamID, oldSnapshot, snapshot, found, observedAt)
+	return ids
 }
 
 func (s *System) appendTerminalPREvent(ctx context.Context, event remote.BranchEvent) error {
@@ -208,6 +290,9 @@ func (s *System) appendTerminalPREvent(ctx context.Context, event remote.BranchE
 		return nil
 	}
 	streamID := fmt.Sprintf("github:%s#%d", strings.TrimSuffix(strings.TrimPrefix(event.RemoteURL, "git@github.com:"), ".git"), *event.PRNumber)
+	if event.PRSnapshot != nil {
+		streamID = prStreamID(*event.PRSnapshot)
+	}
 	payload := map[string]any{"remoteUrl": event.RemoteURL, "branch": event.Branch, "prNumber": *event.PRNumber, "observedAt": event.Timestamp.UTC()}
 	eventType := eventtypes.PRClosed
 	if event.Type == remote.PRMerged {
@@ -216,10 +301,10 @@ func (s *System) appendTerminalPREvent(ctx context.Context, event remote.BranchE
 	return s.appendPRLog(ctx, streamID, eventType, payload, "", streamID)
 }
 
-func (s *System) appendSessionSummaryEvents(ctx context.Context, sessionStreamID, prStreamID string, oldSnapshot, newSnapshot remote.PullRequestSnapshot, found bool, observedAt time.Time) error {
+func (s *System) appendSessionSummaryEvents(ctx context.Context, sessionStreamID, prStreamID string, oldSnapshot, newSnapshot remote.PullRequestSnapshot, found bool, addedComments []remote.ReviewComment, observedAt time.Time) error {
 	pending := []eventlog.PendingEvent{}
 	if !found {
-		evt, err := marshalPendingEvent(sessionStreamID, eventtypes.SessionPRLinked, sessionPRLinkedPayload{PRStreamID: prStreamID, PRNumber: newSnapshot.Number, State: sessionPRState(newSnapshot), CIState: newSnapshot.CI.State, LinkedAt: observedAt.UTC()}, "", sessionStreamID)
+		evt, err := marshalPendingEvent(sessionStreamID, eventtypes.SessionPRLinked, sessionPRLinkedPayload{PRStreamID: prStreamID, PRNumber: newSnapshot.Number, URL: newSnapshot.HTMLURL, State: sessionPRState(newSnapshot), CIState: newSnapshot.CI.State, LinkedAt: observedAt.UTC()}, "", sessionStreamID)
 		if err != nil {
 			return err
 		}
@@ -248,6 +333,20 @@ func (s *System) appendSessionSummaryEvents(ctx context.Context, sessionStreamID
 		}
 		pending = append(pending, evt)
 	}
+	if len(addedComments) > 0 {
+		payload := sessionPRReviewCommentsAddedPayload{PRStreamID: prStreamID, PRNumber: newSnapshot.Number, CommentIDs: make([]int64, 0, len(addedComments)), ObservedAt: observedAt.UTC()}
+		for _, comment := range addedComments {
+			payload.CommentIDs = append(payload.CommentIDs, comment.ID)
+			if !comment.Resolved {
+				payload.Unresolved++
+			}
+		}
+		evt, err := marshalPendingEvent(sessionStreamID, eventtypes.SessionPRReviewCommentsAdded, payload, "", sessionStreamID)
+		if err != nil {
+			return err
+		}
+		pending = append(pending, evt)
+	}
 	for _, evt := range pending {
 		if _, err := s.sessionLog.Append(ctx, evt); err != nil {
 			return err
@@ -295,6 +394,11 @@ func diffPullRequestSnapshots(oldSnapshot, newSnapshot remote.PullRequestSnapsho
 	appendChange("requested_reviewers", oldSnapshot.RequestedReviewers, newSnapshot.RequestedReviewers)
 	appendChange("requested_teams", oldSnapshot.RequestedTeams, newSnapshot.RequestedTeams)
 	appendChange("review_summary", oldSnapshot.ReviewSummary, newSnapshot.ReviewSummary)
+	// REST snapshots report every comment as unresolved; only compare
+	// resolution when both sides know it.
+	if oldSnapshot.ResolutionKnown && newSnapshot.ResolutionKnown {
+		appendChange("resolved_review_comments", resolvedReviewCommentIDs(oldSnapshot), resolvedReviewCommentIDs(newSnapshot))
+	}
 	appendChange("ci", oldSnapshot.CI, newSnapshot.CI)
 	appendChange("closed_at", oldSnapshot.ClosedAt, newSnapshot.ClosedAt)
 	appendChange("merged_at", oldSnapshot.MergedAt, newSnapshot.MergedAt)
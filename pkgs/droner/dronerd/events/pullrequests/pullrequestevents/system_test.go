@@ -2,6 +2,8 @@ package pullrequestevents
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
 	"testing"
 	"time"
 
@@ -105,6 +107,94 @@ func TestIngestObservedWritesDeltaAndNamedCIEvent(t *testing.T) {
 	}
 }
 
+func TestLinkCreatedPullRequestSkipsDuplicateLinkOnNextPoll(t *testing.T) {
+	system := newTestSystem(t)
+	ctx := context.Background()
+	snapshot := testSnapshot("pending")
+
+	streamID, err := system.LinkCreatedPullRequest(ctx, "session-1", snapshot)
+	if err != nil {
+		t.Fatalf("LinkCreatedPullRequest: %v", err)
+	}
+	if streamID != "github:owner/repo#42" {
+		t.Fatalf("unexpected PR stream ID: %q", streamID)
+	}
+	if err := system.ingestObserved(ctx, "session-1", testSnapshot("passing"), time.Now().UTC()); err != nil {
+		t.Fatalf("ingestObserved: %v", err)
+	}
+
+	sessionEvents, err := system.sessionLog.LoadStream(ctx, eventlog.StreamID("session-1"), eventlog.LoadStreamOptions{})
+	if err != nil {
+		t.Fatalf("LoadStream session: %v", err)
+	}
+	linked := 0
+	for _, evt := range sessionEvents {
+		if evt.Type == eventtypes.SessionPRLinked {
+			linked++
+		}
+	}
+	if linked != 1 {
+		t.Fatalf("expected one %s event, got %d", eventtypes.SessionPRLinked, linked)
+	}
+}
+
+func TestLinkCreatedPullRequestRacingPollLinksOnce(t *testing.T) {
+	system := newTestSystem(t)
+	system.snapshots = slowSnapshotStore{PullRequestSnapshotStore: system.snapshots, delay: 20 * time.Millisecond}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				_, err := system.LinkCreatedPullRequest(ctx, "session-1", testSnapshot("pending"))
+				errs <- err
+				return
+			}
+			errs <- system.ingestObserved(ctx, "session-1", testSnapshot("pending"), time.Now().UTC())
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("ingest: %v", err)
+		}
+	}
+
+	prEvents, err := system.prLog.LoadStream(ctx, eventlog.StreamID("github:owner/repo#42"), eventlog.LoadStreamOptions{})
+	if err != nil {
+		t.Fatalf("LoadStream pr: %v", err)
+	}
+	sessionEvents, err := system.sessionLog.LoadStream(ctx, eventlog.StreamID("session-1"), eventlog.LoadStreamOptions{})
+	if err != nil {
+		t.Fatalf("LoadStream session: %v", err)
+	}
+	counts := map[eventlog.EventType]int{}
+	for _, evt := range append(prEvents, sessionEvents...) {
+		counts[evt.Type]++
+	}
+	if counts[eventtypes.PRObserved] != 1 || counts[eventtypes.SessionPRLinked] != 1 {
+		t.Fatalf("expected one %s and one %s, got %v", eventtypes.PRObserved, eventtypes.SessionPRLinked, counts)
+	}
+}
+
+// slowSnapshotStore widens the window between loading and upserting a
+// snapshot so concurrent ingests overlap.
+type slowSnapshotStore struct {
+	PullRequestSnapshotStore
+	delay time.Duration
+}
+
+func (s slowSnapshotStore) Load(ctx context.Context, streamID string) (remote.PullRequestSnapshot, bool, error) {
+	snapshot, found, err := s.PullRequestSnapshotStore.Load(ctx, streamID)
+	time.Sleep(s.delay)
+	return snapshot, found, err
+}
+
 func newTestSystem(t *testing.T) *System {
 	t.Helper()
 	dataDir := t.TempDir()
@@ -130,7 +220,7 @@ func newTestSystem(t *testing.T) *System {
 		t.Fatalf("Sessions log: %v", err)
 	}
 	queries := coredb.New(conn)
-	system := New(prLog, sessionLog, NewSQLiteSessionLookupStore(queries), NewSQLitePullRequestSnapshotStore(queries), nil)
+	system := New(prLog, sessionLog, NewSessionLookupStore(queries), NewPullRequestSnapshotStore(queries), nil)
 	t.Cleanup(func() {
 		_ = system.Close()
 		_ = logs.Close()
@@ -158,3 +248,126 @@ func testSnapshot(ciState string) remote.PullRequestSnapshot {
 		UpdatedAt:          time.Date(2026, time.April, 22, 11, 0, 0, 0, time.UTC),
 	}
 }
+
+func TestTerminalEventUsesSnapshotStreamID(t *testing.T) {
+	system := newTestSystem(t)
+	ctx := context.Background()
+	snapshot := testSnapshot("passing")
+	snapshot.Provider = "gitlab"
+	snapshot.RepoOwner = "group/sub"
+	number := snapshot.Number
+	state := "closed"
+
+	event := remote.BranchEvent{Type: remote.PRMerged, RemoteURL: "git@gitlab.com:group/sub/repo.git", Branch: "feature", PRNumber: &number, PRState: &state, PRSnapshot: &snapshot, Timestamp: time.Now()}
+	if err := system.handleRemoteEvent(ctx, "session-1", event); err != nil {
+		t.Fatalf("handleRemoteEvent: %v", err)
+	}
+
+	prEvents, err := system.prLog.LoadStream(ctx, eventlog.StreamID("gitlab:group/sub/repo#42"), eventlog.LoadStreamOptions{})
+	if err != nil {
+		t.Fatalf("LoadStream pr: %v", err)
+	}
+	if len(prEvents) != 1 || prEvents[0].Type != eventtypes.PRMerged {
+		t.Fatalf("unexpected PR events: %#v", prEvents)
+	}
+}
+
+func TestIngestObservedWritesAddedReviewComments(t *testing.T) {
+	system := newTestSystem(t)
+	ctx := context.Background()
+	first := testSnapshot("passing")
+	first.ReviewComments = []remote.ReviewComment{{ID: 1, Author: "alice", Path: "main.go", Line: 3, Body: "nit"}}
+	second := testSnapshot("passing")
+	second.ReviewComments = []remote.ReviewComment{
+		{ID: 1, Author: "alice", Path: "main.go", Line: 3, Body: "nit"},
+		{ID: 2, Author: "bob", Path: "util.go", StartLine: 4, Line: 8, Body: "Handle the error"},
+	}
+
+	if err := system.ingestObserved(ctx, "session-1", first, time.Now().UTC()); err != nil {
+		t.Fatalf("first ingestObserved: %v", err)
+	}
+	if err := system.ingestObserved(ctx, "session-1", second, time.Now().UTC()); err != nil {
+		t.Fatalf("second ingestObserved: %v", err)
+	}
+	if err := system.ingestObserved(ctx, "session-1", second, time.Now().UTC()); err != nil {
+		t.Fatalf("third ingestObserved: %v", err)
+	}
+
+	prEvents, err := system.prLog.LoadStream(ctx, eventlog.StreamID("github:owner/repo#42"), eventlog.LoadStreamOptions{})
+	if err != nil {
+		t.Fatalf("LoadStream pr: %v", err)
+	}
+	want := []eventlog.EventType{eventtypes.PRObserved, eventtypes.PRReviewCommentAdded, eventtypes.PRReviewCommentAdded}
+	if len(prEvents) != len(want) {
+		t.Fatalf("expected %d PR events, got %d", len(want), len(prEvents))
+	}
+	for i, eventType := range want {
+		if prEvents[i].Type != eventType {
+			t.Fatalf("PR event %d = %s, want %s", i, prEvents[i].Type, eventType)
+		}
+	}
+	var added prReviewCommentAddedPayload
+	if err := json.Unmarshal(prEvents[2].Payload, &added); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if added.Comment.ID != 2 || added.Comment.Path != "util.go" || added.Comment.StartLine != 4 {
+		t.Fatalf("unexpected added comment: %#v", added.Comment)
+	}
+
+	sessionEvents, err := system.sessionLog.LoadStream(ctx, eventlog.StreamID("session-1"), eventlog.LoadStreamOptions{})
+	if err != nil {
+		t.Fatalf("LoadStream session: %v", err)
+	}
+	last := sessionEvents[len(sessionEvents)-1]
+	if last.Type != eventtypes.SessionPRReviewCommentsAdded {
+		t.Fatalf("last session event = %s, want %s", last.Type, eventtypes.SessionPRReviewCommentsAdded)
+	}
+	var summary sessionPRReviewCommentsAddedPayload
+	if err := json.Unmarshal(last.Payload, &summary); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(summary.CommentIDs) != 1 || summary.CommentIDs[0] != 2 || summary.Unresolved != 1 {
+		t.Fatalf("unexpected session summary: %#v", summary)
+	}
+}
+
+func TestIngestObservedRecordsResolvedReviewComments(t *testing.T) {
+	system := newTestSystem(t)
+	ctx := context.Background()
+	first := testSnapshot("passing")
+	first.ReviewComments = []remote.ReviewComment{{ID: 1, Path: "main.go", Body: "nit"}}
+	first.ResolutionKnown = true
+	second := testSnapshot("passing")
+	second.ReviewComments = []remote.ReviewComment{{ID: 1, Path: "main.go", Body: "nit", Resolved: true}}
+	second.ResolutionKnown = true
+
+	if err := system.ingestObserved(ctx, "session-1", first, time.Now().UTC()); err != nil {
+		t.Fatalf("first ingestObserved: %v", err)
+	}
+	if err := system.ingestObserved(ctx, "session-1", second, time.Now().UTC()); err != nil {
+		t.Fatalf("second ingestObserved: %v", err)
+	}
+
+	stored, _, err := system.snapshots.Load(ctx, "github:owner/repo#42")
+	if err != nil {
+		t.Fatalf("Load snapshot: %v", err)
+	}
+	if len(stored.ReviewComments) != 1 || !stored.ReviewComments[0].Resolved {
+		t.Fatalf("expected the resolved comment to be stored: %#v", stored.ReviewComments)
+	}
+}
+
+func TestDiffIgnoresResolutionFromRESTSnapshots(t *testing.T) {
+	graphql := testSnapshot("passing")
+	graphql.ReviewComments = []remote.ReviewComment{{ID: 1, Path: "main.go", Body: "nit", Resolved: true}}
+	graphql.ResolutionKnown = true
+	rest := testSnapshot("passing")
+	rest.ReviewComments = []remote.ReviewComment{{ID: 1, Path: "main.go", Body: "nit"}}
+
+	if changes := diffPullRequestSnapshots(graphql, rest); len(changes) != 0 {
+		t.Fatalf("expected no changes between graphql and rest snapshots, got %#v", changes)
+	}
+	if changes := diffPullRequestSnapshots(rest, graphql); len(changes) != 0 {
+		t.Fatalf("expected no changes between rest and graphql snapshots, got %#v", changes)
+	}
+}
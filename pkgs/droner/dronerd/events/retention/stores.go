@@ -0,0 +1,87 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	coredb "github.com/Oudwins/droner/pkgs/droner/dronerd/db"
+)
+
+type SessionRef struct {
+	StreamID  string
+	Branch    string
+	RemoteURL string
+	UpdatedAt time.Time
+}
+
+type PullRequestRef struct {
+	StreamID   string
+	RemoteURL  string
+	HeadRef    string
+	ObservedAt time.Time
+}
+
+type Store interface {
+	ListDeletedSessions(ctx context.Context) ([]SessionRef, error)
+	ListPullRequests(ctx context.Context) ([]PullRequestRef, error)
+	// ListLinkedSessions returns the live sessions on a branch: queued,
+	// active, completing or deleting.
+	ListLinkedSessions(ctx context.Context, remoteURL, branch string) ([]string, error)
+	DeleteSession(ctx context.Context, streamID string) error
+	DeletePullRequest(ctx context.Context, streamID string) error
+}
+
+type DBStore struct {
+	queries coredb.Querier
+}
+
+func NewStore(queries coredb.Querier) *DBStore {
+	return &DBStore{queries: queries}
+}
+
+func (s *DBStore) ListDeletedSessions(ctx context.Context) ([]SessionRef, error) {
+	rows, err := s.queries.ListDeletedSessionProjectionRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]SessionRef, 0, len(rows))
+	for _, row := range rows {
+		refs = append(refs, SessionRef{StreamID: row.StreamID, Branch: nullStringValue(row.Branch), RemoteURL: row.RemoteUrl, UpdatedAt: row.UpdatedAt})
+	}
+	return refs, nil
+}
+
+func (s *DBStore) ListPullRequests(ctx context.Context) ([]PullRequestRef, error) {
+	rows, err := s.queries.ListPRLatestSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]PullRequestRef, 0, len(rows))
+	for _, row := range rows {
+		refs = append(refs, PullRequestRef{StreamID: row.StreamID, RemoteURL: row.RemoteUrl, HeadRef: row.HeadRef, ObservedAt: row.ObservedAt})
+	}
+	return refs, nil
+}
+
+func (s *DBStore) ListLinkedSessions(ctx context.Context, remoteURL, branch string) ([]string, error) {
+	return s.queries.ListLiveSessionProjectionStreamIDsByRemoteAndBranch(ctx, coredb.ListLiveSessionProjectionStreamIDsByRemoteAndBranchParams{
+		RemoteUrl: remoteURL,
+		Branch:    sql.NullString{String: branch, Valid: true},
+	})
+}
+
+func (s *DBStore) DeleteSession(ctx context.Context, streamID string) error {
+	return s.queries.DeleteSessionProjection(ctx, streamID)
+}
+
+func (s *DBStore) DeletePullRequest(ctx context.Context, streamID string) error {
+	return s.queries.DeletePRLatestSnapshot(ctx, streamID)
+}
+
+func nullStringValue(value sql.NullString) string {
+	if !value.Valid {
+		return ""
+	}
+	return value.String
+}
@@ -0,0 +1,52 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	coredb "github.com/Oudwins/droner/pkgs/droner/dronerd/db"
+)
+
+func TestListLinkedSessionsOnlyReturnsLiveSessions(t *testing.T) {
+	conn, err := coredb.OpenSQLiteDB(coredb.DBPath(t.TempDir()))
+	if err != nil {
+		t.Fatalf("OpenSQLiteDB: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	queries := coredb.New(conn)
+	for streamID, state := range map[string]string{
+		"queued":    "queued",
+		"busy":      "active.busy",
+		"completed": "completed",
+		"failed":    "failed",
+		"deleted":   "deleted",
+	} {
+		err := queries.UpsertSessionProjection(context.Background(), coredb.UpsertSessionProjectionParams{
+			StreamID:    streamID,
+			Harness:     "opencode",
+			Branch:      sql.NullString{String: "feature-" + streamID, Valid: true},
+			RepoPath:    "/repo",
+			RemoteUrl:   "git@github.com:acme/app.git",
+			PublicState: state,
+			CreatedAt:   testNow,
+			UpdatedAt:   testNow,
+		})
+		if err != nil {
+			t.Fatalf("UpsertSessionProjection: %v", err)
+		}
+	}
+
+	store := NewStore(queries)
+	for streamID, want := range map[string]bool{"queued": true, "busy": true, "completed": false, "failed": false, "deleted": false} {
+		linked, err := store.ListLinkedSessions(context.Background(), "git@github.com:acme/app.git", "feature-"+streamID)
+		if err != nil {
+			t.Fatalf("ListLinkedSessions: %v", err)
+		}
+		if got := len(linked) == 1; got != want {
+			t.Fatalf("session %s linked = %v (%v), want %v", streamID, got, linked, want)
+		}
+	}
+}
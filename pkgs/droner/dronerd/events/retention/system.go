@@ -0,0 +1,377 @@
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestslog"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionslog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+const (
+	ActionPurge   = "purge"
+	ActionArchive = "archive"
+	ActionSkip    = "skip"
+)
+
+const autoCompactInterval = 24 * time.Hour
+
+type EventStore interface {
+	eventlog.Compactor
+	LoadStream(ctx context.Context, topic eventlog.Topic, streamID eventlog.StreamID, opts eventlog.LoadStreamOptions) ([]eventlog.Envelope, error)
+}
+
+type Options struct {
+	DryRun bool
+	// OlderThan overrides the configured retention window when positive.
+	OlderThan time.Duration
+	// DropSubscribers forgets the checkpoints of subscribers that no longer
+	// run, so they stop pinning the horizon.
+	DropSubscribers []eventlog.SubscriberID
+}
+
+// TopicReport describes a topic. PinnedBy names the subscriber whose
+// checkpoint holds the horizon below the topic head, if any.
+type TopicReport struct {
+	Topic       eventlog.Topic
+	Latest      int64
+	Horizon     int64
+	PinnedBy    eventlog.SubscriberID
+	Checkpoints []eventlog.Checkpoint
+	Dropped     []eventlog.Checkpoint
+}
+
+type StreamReport struct {
+	Topic    eventlog.Topic
+	StreamID string
+	Reason   string
+	Events   int64
+	Action   string
+	Detail   string
+}
+
+type Report struct {
+	DryRun        bool
+	Cutoff        time.Time
+	Topics        []TopicReport
+	Streams       []StreamReport
+	EventsRemoved int64
+}
+
+type System struct {
+	events     EventStore
+	store      Store
+	logger     *slog.Logger
+	config     conf.RetentionConfig
+	archiveDir string
+	now        func() time.Time
+	mu         sync.Mutex
+	startOnce  sync.Once
+}
+
+func New(events EventStore, store Store, logger *slog.Logger, config conf.RetentionConfig, archiveDir string) *System {
+	return &System{events: events, store: store, logger: logger, config: config, archiveDir: archiveDir, now: time.Now}
+}
+
+func (s *System) Start(ctx context.Context) {
+	if s == nil || !s.config.Auto {
+		return
+	}
+	s.startOnce.Do(func() {
+		go s.runAutoCompaction(ctx)
+	})
+}
+
+func (s *System) Close() error {
+	return nil
+}
+
+func (s *System) runAutoCompaction(ctx context.Context) {
+	for {
+		report, err := s.Compact(ctx, Options{})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Error("retention compaction failed", "error", err)
+		} else if err == nil {
+			s.logger.Info("retention compaction finished", "streams", len(report.Streams), "events_removed", report.EventsRemoved)
+			for _, topic := range report.Topics {
+				if topic.PinnedBy != "" {
+					s.logger.Info("retention horizon pinned", "topic", topic.Topic, "subscriber", topic.PinnedBy, "horizon", topic.Horizon, "latest", topic.Latest)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(autoCompactInterval):
+		}
+	}
+}
+
+// Compact removes the streams of sessions that have been deleted for longer
+// than the retention window, together with pull request streams that neither
+// a live session nor a kept deleted session links to. Completed and failed
+// sessions do not keep a pull request stream. Streams holding events some subscriber has not
+// processed yet are skipped and picked up by a later run.
+func (s *System) Compact(ctx context.Context, opts Options) (Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	olderThan := opts.OlderThan
+	if olderThan <= 0 {
+		olderThan = time.Duration(s.config.DeletedSessionDays) * 24 * time.Hour
+	}
+	report := Report{DryRun: opts.DryRun, Cutoff: s.now().UTC().Add(-olderThan)}
+
+	sessionsTopic, err := s.dropSubscribers(ctx, sessionslog.Topic, opts)
+	if err != nil {
+		return Report{}, err
+	}
+	prTopic, err := s.dropSubscribers(ctx, pullrequestslog.Topic, opts)
+	if err != nil {
+		return Report{}, err
+	}
+	report.Topics = []TopicReport{sessionsTopic, prTopic}
+
+	sessions, err := s.store.ListDeletedSessions(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("list deleted sessions: %w", err)
+	}
+	// Deleted sessions whose stream stays keep their pull request stream too.
+	kept := map[string]bool{}
+	for _, ref := range sessions {
+		if !ref.UpdatedAt.Before(report.Cutoff) {
+			kept[branchKey(ref.RemoteURL, ref.Branch)] = true
+			continue
+		}
+		reason := fmt.Sprintf("session %s deleted at %s", ref.Branch, ref.UpdatedAt.UTC().Format(time.RFC3339))
+		stream, err := s.compactStream(ctx, sessionsTopic, ref.StreamID, reason, opts.DryRun, s.store.DeleteSession)
+		if err != nil {
+			return report, err
+		}
+		if stream.Action == ActionSkip {
+			kept[branchKey(ref.RemoteURL, ref.Branch)] = true
+		}
+		report.Streams = append(report.Streams, stream)
+	}
+
+	pullRequests, err := s.store.ListPullRequests(ctx)
+	if err != nil {
+		return report, fmt.Errorf("list pull requests: %w", err)
+	}
+	for _, ref := range pullRequests {
+		if !ref.ObservedAt.Before(report.Cutoff) {
+			continue
+		}
+		linked, err := s.store.ListLinkedSessions(ctx, ref.RemoteURL, ref.HeadRef)
+		if err != nil {
+			return report, fmt.Errorf("list sessions linked to %s: %w", ref.StreamID, err)
+		}
+		if len(linked) > 0 || kept[branchKey(ref.RemoteURL, ref.HeadRef)] {
+			continue
+		}
+		reason := fmt.Sprintf("no live session links to %s", ref.HeadRef)
+		stream, err := s.compactStream(ctx, prTopic, ref.StreamID, reason, opts.DryRun, s.store.DeletePullRequest)
+		if err != nil {
+			return report, err
+		}
+		report.Streams = append(report.Streams, stream)
+	}
+
+	for _, stream := range report.Streams {
+		if stream.Action != ActionSkip {
+			report.EventsRemoved += stream.Events
+		}
+	}
+	return report, nil
+}
+
+// Topics reports the latest sequence and subscriber checkpoints of every
+// topic, which diagnostics uses to show how far each subscriber lags.
+func (s *System) Topics(ctx context.Context) ([]TopicReport, error) {
+	topics := []TopicReport{}
+	for _, topic := range []eventlog.Topic{sessionslog.Topic, pullrequestslog.Topic} {
+		report, err := s.topicReport(ctx, topic)
+		if err != nil {
+			return nil, err
+		}
+		topics = append(topics, report)
+	}
+	return topics, nil
+}
+
+func (s *System) topicReport(ctx context.Context, topic eventlog.Topic) (TopicReport, error) {
+	latest, err := s.events.LatestSequence(ctx, topic)
+	if err != nil {
+		return TopicReport{}, fmt.Errorf("load %s latest sequence: %w", topic, err)
+	}
+	checkpoints, err := s.events.ListCheckpoints(ctx, topic)
+	if err != nil {
+		return TopicReport{}, fmt.Errorf("load %s checkpoints: %w", topic, err)
+	}
+	return newTopicReport(topic, latest, checkpoints), nil
+}
+
+func newTopicReport(topic eventlog.Topic, latest int64, checkpoints []eventlog.Checkpoint) TopicReport {
+	report := TopicReport{Topic: topic, Latest: latest, Horizon: eventlog.CompactionHorizon(checkpoints, latest), Checkpoints: checkpoints}
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Sequence == report.Horizon && report.Horizon < latest {
+			report.PinnedBy = checkpoint.Subscriber
+			break
+		}
+	}
+	return report
+}
+
+// dropSubscribers reports topic as if the checkpoints in opts.DropSubscribers
+// were gone, and removes them unless this is a dry run.
+func (s *System) dropSubscribers(ctx context.Context, topic eventlog.Topic, opts Options) (TopicReport, error) {
+	report, err := s.topicReport(ctx, topic)
+	if err != nil || len(opts.DropSubscribers) == 0 {
+		return report, err
+	}
+	kept := []eventlog.Checkpoint{}
+	dropped := []eventlog.Checkpoint{}
+	for _, checkpoint := range report.Checkpoints {
+		if slices.Contains(opts.DropSubscribers, checkpoint.Subscriber) {
+			dropped = append(dropped, checkpoint)
+		} else {
+			kept = append(kept, checkpoint)
+		}
+	}
+	if !opts.DryRun {
+		for _, checkpoint := range dropped {
+			if err := s.events.DeleteCheckpoint(ctx, topic, checkpoint.Subscriber); err != nil {
+				return TopicReport{}, fmt.Errorf("drop %s checkpoint of %s: %w", topic, checkpoint.Subscriber, err)
+			}
+			s.logger.Info("dropped subscriber checkpoint", "topic", topic, "subscriber", checkpoint.Subscriber, "sequence", checkpoint.Sequence)
+		}
+	}
+	report = newTopicReport(topic, report.Latest, kept)
+	report.Dropped = dropped
+	return report, nil
+}
+
+// compactStream drops the events first and the projection row second, so a
+// failure in between leaves a row that the next run cleans up.
+func (s *System) compactStream(ctx context.Context, topic TopicReport, streamID, reason string, dryRun bool, dropProjection func(context.Context, string) error) (StreamReport, error) {
+	info, err := s.events.StreamInfo(ctx, topic.Topic, eventlog.StreamID(streamID))
+	if err != nil {
+		return StreamReport{}, fmt.Errorf("load %s stream %s: %w", topic.Topic, streamID, err)
+	}
+	stream := StreamReport{Topic: topic.Topic, StreamID: streamID, Reason: reason, Events: info.Events, Action: ActionPurge}
+	if info.Events > 0 && info.LastSequence > topic.Horizon {
+		stream.Action = ActionSkip
+		stream.Detail = fmt.Sprintf("sequence %d has not been processed by every subscriber (horizon %d)", info.LastSequence, topic.Horizon)
+		return stream, nil
+	}
+	if s.config.Archive && info.Events > 0 {
+		stream.Action = ActionArchive
+	}
+	if dryRun {
+		return stream, nil
+	}
+
+	if stream.Action == ActionArchive {
+		path, err := s.archiveStream(ctx, topic.Topic, streamID)
+		if err != nil {
+			return stream, fmt.Errorf("archive %s stream %s: %w", topic.Topic, streamID, err)
+		}
+		stream.Detail = path
+	}
+	removed, err := s.events.DeleteStream(ctx, topic.Topic, eventlog.StreamID(streamID), topic.Horizon)
+	if err != nil {
+		return stream, fmt.Errorf("delete %s stream %s: %w", topic.Topic, streamID, err)
+	}
+	stream.Events = removed
+	if err := dropProjection(ctx, streamID); err != nil {
+		return stream, fmt.Errorf("delete %s projection %s: %w", topic.Topic, streamID, err)
+	}
+	s.logger.Info("compacted stream", "topic", topic.Topic, "stream_id", streamID, "events", removed, "action", stream.Action)
+	return stream, nil
+}
+
+type archivedEvent struct {
+	ID            string          `json:"id"`
+	Topic         string          `json:"topic"`
+	StreamID      string          `json:"streamId"`
+	StreamVersion int64           `json:"streamVersion"`
+	Sequence      int64           `json:"sequence"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	CausationID   string          `json:"causationId,omitempty"`
+	CorrelationID string          `json:"correlationId,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func (s *System) archiveStream(ctx context.Context, topic eventlog.Topic, streamID string) (string, error) {
+	dir := filepath.Join(s.archiveDir, string(topic))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, url.PathEscape(streamID)+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	afterVersion := int64(0)
+	for {
+		events, err := s.events.LoadStream(ctx, topic, eventlog.StreamID(streamID), eventlog.LoadStreamOptions{AfterVersion: afterVersion})
+		if err != nil {
+			return "", err
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, evt := range events {
+			if err := encoder.Encode(newArchivedEvent(evt)); err != nil {
+				return "", err
+			}
+			afterVersion = evt.StreamVersion
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func newArchivedEvent(evt eventlog.Envelope) archivedEvent {
+	payload := json.RawMessage(evt.Payload)
+	if !json.Valid(payload) {
+		quoted, _ := json.Marshal(string(evt.Payload))
+		payload = quoted
+	}
+	return archivedEvent{
+		ID:            string(evt.ID),
+		Topic:         string(evt.Topic),
+		StreamID:      string(evt.StreamID),
+		StreamVersion: evt.StreamVersion,
+		Sequence:      evt.Sequence,
+		Type:          string(evt.Type),
+		SchemaVersion: evt.SchemaVersion,
+		OccurredAt:    evt.OccurredAt,
+		CausationID:   string(evt.CausationID),
+		CorrelationID: evt.CorrelationID,
+		Payload:       payload,
+	}
+}
+
+func branchKey(remoteURL, branch string) string {
+	return remoteURL + "|" + branch
+}
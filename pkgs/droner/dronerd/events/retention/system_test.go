@@ -0,0 +1,312 @@
+package retention
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	memoryeventlog "github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/memory"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestslog"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionslog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+type memoryStore struct {
+	sessions     []SessionRef
+	pullRequests []PullRequestRef
+	linked       map[string][]string
+	deleted      []string
+}
+
+func (s *memoryStore) ListDeletedSessions(context.Context) ([]SessionRef, error) {
+	return s.sessions, nil
+}
+
+func (s *memoryStore) ListPullRequests(context.Context) ([]PullRequestRef, error) {
+	return s.pullRequests, nil
+}
+
+func (s *memoryStore) ListLinkedSessions(_ context.Context, remoteURL, branch string) ([]string, error) {
+	return s.linked[remoteURL+"|"+branch], nil
+}
+
+func (s *memoryStore) DeleteSession(_ context.Context, streamID string) error {
+	s.deleted = append(s.deleted, streamID)
+	return nil
+}
+
+func (s *memoryStore) DeletePullRequest(_ context.Context, streamID string) error {
+	s.deleted = append(s.deleted, streamID)
+	return nil
+}
+
+type fixture struct {
+	backend *memoryeventlog.Backend
+	store   *memoryStore
+	system  *System
+}
+
+func newFixture(t *testing.T, config conf.RetentionConfig) fixture {
+	t.Helper()
+	backend := memoryeventlog.New()
+	t.Cleanup(func() {
+		_ = backend.Close()
+	})
+	store := &memoryStore{linked: map[string][]string{}}
+	system := New(backend, store, slog.New(slog.NewTextHandler(io.Discard, nil)), config, t.TempDir())
+	system.now = func() time.Time { return testNow }
+	return fixture{backend: backend, store: store, system: system}
+}
+
+func (f fixture) append(t *testing.T, topic eventlog.Topic, streamID string, eventType eventlog.EventType) eventlog.Envelope {
+	t.Helper()
+	evt, err := f.backend.Append(context.Background(), topic, eventlog.PendingEvent{
+		StreamID:      eventlog.StreamID(streamID),
+		Type:          eventType,
+		SchemaVersion: 1,
+		Payload:       []byte(`{"branch":"feature"}`),
+	})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return evt
+}
+
+func (f fixture) checkpoint(t *testing.T, topic eventlog.Topic, sequence int64) {
+	t.Helper()
+	if err := f.backend.StoreCheckpoint(context.Background(), topic, "projection", sequence); err != nil {
+		t.Fatalf("StoreCheckpoint: %v", err)
+	}
+}
+
+func (f fixture) streamEvents(t *testing.T, topic eventlog.Topic, streamID string) int64 {
+	t.Helper()
+	info, err := f.backend.StreamInfo(context.Background(), topic, eventlog.StreamID(streamID))
+	if err != nil {
+		t.Fatalf("StreamInfo: %v", err)
+	}
+	return info.Events
+}
+
+func TestCompactRemovesExpiredSessionsAndUnlinkedPullRequests(t *testing.T) {
+	f := newFixture(t, conf.RetentionConfig{DeletedSessionDays: 30})
+	f.append(t, sessionslog.Topic, "old", "session.queued")
+	f.append(t, sessionslog.Topic, "old", "session.deleted")
+	f.append(t, sessionslog.Topic, "recent", "session.deleted")
+	last := f.append(t, sessionslog.Topic, "live", "session.ready")
+	f.checkpoint(t, sessionslog.Topic, last.Sequence)
+	f.append(t, pullrequestslog.Topic, "github:acme/app#1", "pr.observed")
+	prLast := f.append(t, pullrequestslog.Topic, "github:acme/app#2", "pr.observed")
+	f.checkpoint(t, pullrequestslog.Topic, prLast.Sequence)
+
+	f.store.sessions = []SessionRef{
+		{StreamID: "old", Branch: "old-branch", RemoteURL: "git@github.com:acme/app.git", UpdatedAt: testNow.Add(-40 * 24 * time.Hour)},
+		{StreamID: "recent", Branch: "recent-branch", RemoteURL: "git@github.com:acme/app.git", UpdatedAt: testNow.Add(-2 * 24 * time.Hour)},
+	}
+	f.store.pullRequests = []PullRequestRef{
+		{StreamID: "github:acme/app#1", RemoteURL: "git@github.com:acme/app.git", HeadRef: "old-branch", ObservedAt: testNow.Add(-45 * 24 * time.Hour)},
+		{StreamID: "github:acme/app#2", RemoteURL: "git@github.com:acme/app.git", HeadRef: "live-branch", ObservedAt: testNow.Add(-45 * 24 * time.Hour)},
+	}
+	f.store.linked["git@github.com:acme/app.git|live-branch"] = []string{"live"}
+
+	report, err := f.system.Compact(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	if len(report.Streams) != 2 {
+		t.Fatalf("expected two compacted streams, got %+v", report.Streams)
+	}
+	if report.Streams[0].StreamID != "old" || report.Streams[0].Action != ActionPurge || report.Streams[0].Events != 2 {
+		t.Fatalf("unexpected session stream report: %+v", report.Streams[0])
+	}
+	if report.Streams[1].StreamID != "github:acme/app#1" || report.Streams[1].Action != ActionPurge {
+		t.Fatalf("unexpected pull request stream report: %+v", report.Streams[1])
+	}
+	if report.EventsRemoved != 3 {
+		t.Fatalf("expected 3 removed events, got %d", report.EventsRemoved)
+	}
+	if got := f.streamEvents(t, sessionslog.Topic, "old"); got != 0 {
+		t.Fatalf("expected old session stream removed, got %d events", got)
+	}
+	if got := f.streamEvents(t, sessionslog.Topic, "recent"); got != 1 {
+		t.Fatalf("expected recent session stream kept, got %d events", got)
+	}
+	if got := f.streamEvents(t, pullrequestslog.Topic, "github:acme/app#2"); got != 1 {
+		t.Fatalf("expected linked pull request stream kept, got %d events", got)
+	}
+	if len(f.store.deleted) != 2 || f.store.deleted[0] != "old" || f.store.deleted[1] != "github:acme/app#1" {
+		t.Fatalf("unexpected deleted projections: %v", f.store.deleted)
+	}
+}
+
+func TestCompactDryRunLeavesEverythingInPlace(t *testing.T) {
+	f := newFixture(t, conf.RetentionConfig{DeletedSessionDays: 30})
+	last := f.append(t, sessionslog.Topic, "old", "session.deleted")
+	f.checkpoint(t, sessionslog.Topic, last.Sequence)
+	f.append(t, pullrequestslog.Topic, "github:acme/app#1", "pr.observed")
+	f.store.sessions = []SessionRef{{StreamID: "old", Branch: "old-branch", RemoteURL: "remote", UpdatedAt: testNow.Add(-40 * 24 * time.Hour)}}
+	f.store.pullRequests = []PullRequestRef{{StreamID: "github:acme/app#1", RemoteURL: "remote", HeadRef: "old-branch", ObservedAt: testNow.Add(-40 * 24 * time.Hour)}}
+
+	report, err := f.system.Compact(context.Background(), Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	if !report.DryRun || len(report.Streams) != 2 {
+		t.Fatalf("expected dry run to report both streams, got %+v", report)
+	}
+	// The pull request topic has no subscribers, so its only event is the
+	// topic head and has to stay.
+	if report.Streams[1].Action != ActionSkip {
+		t.Fatalf("expected pull request head to be skipped, got %+v", report.Streams[1])
+	}
+	if got := f.streamEvents(t, sessionslog.Topic, "old"); got != 1 {
+		t.Fatalf("expected dry run to keep events, got %d", got)
+	}
+	if len(f.store.deleted) != 0 {
+		t.Fatalf("expected dry run to keep projections, got %v", f.store.deleted)
+	}
+}
+
+func TestCompactSkipsStreamsPastTheCheckpointHorizon(t *testing.T) {
+	f := newFixture(t, conf.RetentionConfig{DeletedSessionDays: 30})
+	queued := f.append(t, sessionslog.Topic, "old", "session.queued")
+	f.append(t, sessionslog.Topic, "old", "session.deleted")
+	f.append(t, sessionslog.Topic, "other", "session.queued")
+	f.checkpoint(t, sessionslog.Topic, queued.Sequence)
+	f.store.sessions = []SessionRef{{StreamID: "old", Branch: "old-branch", RemoteURL: "remote", UpdatedAt: testNow.Add(-40 * 24 * time.Hour)}}
+	f.store.pullRequests = []PullRequestRef{{StreamID: "github:acme/app#1", RemoteURL: "remote", HeadRef: "old-branch", ObservedAt: testNow.Add(-40 * 24 * time.Hour)}}
+
+	report, err := f.system.Compact(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	if len(report.Streams) != 1 || report.Streams[0].Action != ActionSkip {
+		t.Fatalf("expected the session stream to be skipped and its pull request kept, got %+v", report.Streams)
+	}
+	if report.EventsRemoved != 0 {
+		t.Fatalf("expected nothing removed, got %d", report.EventsRemoved)
+	}
+	if got := f.streamEvents(t, sessionslog.Topic, "old"); got != 2 {
+		t.Fatalf("expected skipped stream kept, got %d events", got)
+	}
+}
+
+func TestCompactOlderThanOverridesConfig(t *testing.T) {
+	f := newFixture(t, conf.RetentionConfig{DeletedSessionDays: 30})
+	last := f.append(t, sessionslog.Topic, "old", "session.deleted")
+	f.checkpoint(t, sessionslog.Topic, last.Sequence)
+	f.store.sessions = []SessionRef{{StreamID: "old", UpdatedAt: testNow.Add(-2 * 24 * time.Hour)}}
+
+	report, err := f.system.Compact(context.Background(), Options{OlderThan: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if !report.Cutoff.Equal(testNow.Add(-24 * time.Hour)) {
+		t.Fatalf("unexpected cutoff %s", report.Cutoff)
+	}
+	if len(report.Streams) != 1 || report.Streams[0].Action != ActionPurge {
+		t.Fatalf("expected override to compact the session, got %+v", report.Streams)
+	}
+}
+
+func TestCompactArchivesStreamsBeforeRemoval(t *testing.T) {
+	f := newFixture(t, conf.RetentionConfig{DeletedSessionDays: 30, Archive: true})
+	f.append(t, sessionslog.Topic, "old/stream", "session.queued")
+	last := f.append(t, sessionslog.Topic, "old/stream", "session.deleted")
+	f.checkpoint(t, sessionslog.Topic, last.Sequence)
+	f.store.sessions = []SessionRef{{StreamID: "old/stream", UpdatedAt: testNow.Add(-40 * 24 * time.Hour)}}
+
+	report, err := f.system.Compact(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if len(report.Streams) != 1 || report.Streams[0].Action != ActionArchive {
+		t.Fatalf("expected archived stream, got %+v", report.Streams)
+	}
+
+	path := filepath.Join(f.system.archiveDir, string(sessionslog.Topic), url.PathEscape("old/stream")+".jsonl")
+	if report.Streams[0].Detail != path {
+		t.Fatalf("expected archive path %q, got %q", path, report.Streams[0].Detail)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer file.Close()
+	var archived []archivedEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var evt archivedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil {
+			t.Fatalf("decode archived event: %v", err)
+		}
+		archived = append(archived, evt)
+	}
+	if len(archived) != 2 || archived[0].Type != "session.queued" || archived[1].Type != "session.deleted" {
+		t.Fatalf("unexpected archived events: %+v", archived)
+	}
+	if string(archived[0].Payload) != `{"branch":"feature"}` {
+		t.Fatalf("expected payload preserved, got %s", archived[0].Payload)
+	}
+	if got := f.streamEvents(t, sessionslog.Topic, "old/stream"); got != 0 {
+		t.Fatalf("expected archived stream removed, got %d events", got)
+	}
+}
+
+func TestCompactDropsSubscribersThatPinTheHorizon(t *testing.T) {
+	f := newFixture(t, conf.RetentionConfig{DeletedSessionDays: 30})
+	first := f.append(t, sessionslog.Topic, "old", "session.deleted")
+	last := f.append(t, sessionslog.Topic, "live", "session.ready")
+	f.checkpoint(t, sessionslog.Topic, last.Sequence)
+	if err := f.backend.StoreCheckpoint(context.Background(), sessionslog.Topic, "renamed", first.Sequence-1); err != nil {
+		t.Fatalf("StoreCheckpoint: %v", err)
+	}
+	f.store.sessions = []SessionRef{{StreamID: "old", Branch: "old-branch", RemoteURL: "remote", UpdatedAt: testNow.Add(-40 * 24 * time.Hour)}}
+
+	report, err := f.system.Compact(context.Background(), Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if report.Topics[0].PinnedBy != "renamed" || report.Streams[0].Action != ActionSkip {
+		t.Fatalf("expected the renamed subscriber to pin the horizon, got %+v", report)
+	}
+
+	report, err = f.system.Compact(context.Background(), Options{DryRun: true, DropSubscribers: []eventlog.SubscriberID{"renamed"}})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if report.Topics[0].PinnedBy != "" || len(report.Topics[0].Dropped) != 1 || report.Streams[0].Action != ActionPurge {
+		t.Fatalf("expected a dry run drop to release the horizon, got %+v", report)
+	}
+	if checkpoints, _ := f.backend.ListCheckpoints(context.Background(), sessionslog.Topic); len(checkpoints) != 2 {
+		t.Fatalf("expected a dry run to keep checkpoints, got %+v", checkpoints)
+	}
+
+	report, err = f.system.Compact(context.Background(), Options{DropSubscribers: []eventlog.SubscriberID{"renamed"}})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if report.Streams[0].Action != ActionPurge || f.streamEvents(t, sessionslog.Topic, "old") != 0 {
+		t.Fatalf("expected the old stream compacted once the subscriber was dropped, got %+v", report.Streams)
+	}
+	checkpoints, err := f.backend.ListCheckpoints(context.Background(), sessionslog.Topic)
+	if err != nil {
+		t.Fatalf("ListCheckpoints: %v", err)
+	}
+	if len(checkpoints) != 1 || checkpoints[0].Subscriber != "projection" {
+		t.Fatalf("expected only the projection checkpoint left, got %+v", checkpoints)
+	}
+}
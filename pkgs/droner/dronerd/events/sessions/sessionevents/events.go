@@ -49,9 +49,17 @@ type provisioningPayload struct {
 	Mode   string `json:"mode,omitempty"`
 }
 
+type provisioningSuccessPayload struct {
+	Branch string `json:"branch"`
+	// BaseBranch is the branch the worktree was created from. Restarts leave
+	// it empty and keep the recorded one.
+	BaseBranch string `json:"baseBranch,omitempty"`
+}
+
 type sessionPRLinkedPayload struct {
 	PRStreamID string    `json:"prStreamId"`
 	PRNumber   int       `json:"prNumber"`
+	URL        string    `json:"url,omitempty"`
 	State      string    `json:"state,omitempty"`
 	CIState    string    `json:"ciState,omitempty"`
 	LinkedAt   time.Time `json:"linkedAt"`
@@ -126,6 +134,12 @@ func decodeProvisioningPayload(evt eventlog.Envelope) (provisioningPayload, erro
 	return payload, err
 }
 
+func decodeProvisioningSuccessPayload(evt eventlog.Envelope) (provisioningSuccessPayload, error) {
+	var payload provisioningSuccessPayload
+	err := json.Unmarshal(evt.Payload, &payload)
+	return payload, err
+}
+
 func decodeSessionPRLinkedPayload(evt eventlog.Envelope) (sessionPRLinkedPayload, error) {
 	var payload sessionPRLinkedPayload
 	err := json.Unmarshal(evt.Payload, &payload)
@@ -158,6 +158,7 @@ func (s *System) handleProvisioningStarted(ctx context.Context, evt eventlog.Env
 		return s.appendProvisioningFailure(ctx, evt, fmt.Errorf("failed to resolve backend: %w", err))
 	}
 
+	baseBranch := ""
 	if payload.Mode == provisioningModeRestart {
 		result, hydrateErr := backend.HydrateSession(ctx, coredb.Session{
 			ID:           state.StreamID,
@@ -215,6 +216,9 @@ func (s *System) handleProvisioningStarted(ctx context.Context, evt eventlog.Env
 			MarkReusableWorktreeDeletion: func(candidate backends.ReusableWorktreeCandidate) {
 				cleanupCandidates = append(cleanupCandidates, candidate)
 			},
+			RecordBaseBranch: func(branch string) {
+				baseBranch = branch
+			},
 		}); createErr != nil {
 			return s.appendProvisioningFailure(ctx, evt, createErr)
 		}
@@ -228,12 +232,11 @@ func (s *System) handleProvisioningStarted(ctx context.Context, evt eventlog.Env
 		}
 	}
 
-	for _, eventType := range []eventlog.EventType{eventtypes.SessionEnvironmentProvisioningSuccess, eventtypes.SessionReady} {
-		if _, err := s.appendEvent(ctx, string(evt.StreamID), eventType, requestStepPayload(state.Branch), string(evt.ID), string(evt.StreamID)); err != nil {
-			return err
-		}
+	if _, err := s.appendEvent(ctx, string(evt.StreamID), eventtypes.SessionEnvironmentProvisioningSuccess, provisioningSuccessPayload{Branch: state.Branch, BaseBranch: baseBranch}, string(evt.ID), string(evt.StreamID)); err != nil {
+		return err
 	}
-	return nil
+	_, err = s.appendEvent(ctx, string(evt.StreamID), eventtypes.SessionReady, requestStepPayload(state.Branch), string(evt.ID), string(evt.StreamID))
+	return err
 }
 
 func (s *System) appendProvisioningFailure(ctx context.Context, cause eventlog.Envelope, causeErr error) error {
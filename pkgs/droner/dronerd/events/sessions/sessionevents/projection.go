@@ -17,11 +17,13 @@ type projectionMutation struct {
 	RepoPath       string
 	WorktreePath   string
 	RemoteURL      string
+	BaseBranch     string
 	AgentConfig    string
 	LifecycleState string
 	PublicState    string
 	LastError      string
 	PRNumber       int64
+	PRURL          string
 	PRState        string
 	PRCIState      string
 	PRUpdatedAt    time.Time
@@ -41,7 +43,11 @@ func (s *System) applyProjectionEvent(ctx context.Context, evt eventlog.Envelope
 	if !changed {
 		return nil
 	}
-	return s.upsertProjection(ctx, state.projectionMutation())
+	if err := s.upsertProjection(ctx, state.projectionMutation()); err != nil {
+		return err
+	}
+	s.watchers.publish(state.listItem())
+	return nil
 }
 
 func (s *System) upsertProjection(ctx context.Context, m projectionMutation) error {
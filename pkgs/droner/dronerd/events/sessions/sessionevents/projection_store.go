@@ -26,15 +26,15 @@ type ProjectionStore interface {
 	ListOldest(ctx context.Context, statusesArg string, statusesValue sql.NullString, limit int) ([]ListItem, error)
 }
 
-type SQLiteProjectionStore struct {
-	queries *coredb.Queries
+type DBProjectionStore struct {
+	queries coredb.Querier
 }
 
-func NewSQLiteProjectionStore(queries *coredb.Queries) *SQLiteProjectionStore {
-	return &SQLiteProjectionStore{queries: queries}
+func NewProjectionStore(queries coredb.Querier) *DBProjectionStore {
+	return &DBProjectionStore{queries: queries}
 }
 
-func (s *SQLiteProjectionStore) Upsert(ctx context.Context, m projectionMutation) error {
+func (s *DBProjectionStore) Upsert(ctx context.Context, m projectionMutation) error {
 	return s.queries.UpsertSessionProjection(ctx, coredb.UpsertSessionProjectionParams{
 		StreamID:       m.StreamID,
 		Harness:        m.Harness,
@@ -51,16 +51,18 @@ func (s *SQLiteProjectionStore) Upsert(ctx context.Context, m projectionMutation
 		PrState:        nullableString(m.PRState),
 		PrCiState:      nullableString(m.PRCIState),
 		PrUpdatedAt:    nullableTime(m.PRUpdatedAt),
+		PrUrl:          nullableString(m.PRURL),
+		BaseBranch:     nullableString(m.BaseBranch),
 		CreatedAt:      m.CreatedAt.UTC(),
 		UpdatedAt:      m.UpdatedAt.UTC(),
 	})
 }
 
-func (s *SQLiteProjectionStore) Delete(ctx context.Context, streamID string) error {
+func (s *DBProjectionStore) Delete(ctx context.Context, streamID string) error {
 	return s.queries.DeleteSessionProjection(ctx, streamID)
 }
 
-func (s *SQLiteProjectionStore) LoadStateByStreamID(ctx context.Context, streamID string) (sessionState, error) {
+func (s *DBProjectionStore) LoadStateByStreamID(ctx context.Context, streamID string) (sessionState, error) {
 	row, err := s.queries.GetSessionProjectionByStreamID(ctx, streamID)
 	if err != nil {
 		return sessionState{}, err
@@ -68,7 +70,7 @@ func (s *SQLiteProjectionStore) LoadStateByStreamID(ctx context.Context, streamI
 	return stateFromProjection(row), nil
 }
 
-func (s *SQLiteProjectionStore) LoadCurrentByBranch(ctx context.Context, branch string) (SessionRef, error) {
+func (s *DBProjectionStore) LoadCurrentByBranch(ctx context.Context, branch string) (SessionRef, error) {
 	row, err := s.queries.GetCurrentSessionProjectionByBranch(ctx, nullableString(branch))
 	if err != nil {
 		return SessionRef{}, err
@@ -76,7 +78,7 @@ func (s *SQLiteProjectionStore) LoadCurrentByBranch(ctx context.Context, branch
 	return sessionRefFromRow(row), nil
 }
 
-func (s *SQLiteProjectionStore) LoadBlockedByRepoAndBranch(ctx context.Context, repoPath string, branch string) (SessionRef, error) {
+func (s *DBProjectionStore) LoadBlockedByRepoAndBranch(ctx context.Context, repoPath string, branch string) (SessionRef, error) {
 	row, err := s.queries.GetBlockedSessionProjectionByRepoPathAndBranch(ctx, coredb.GetBlockedSessionProjectionByRepoPathAndBranchParams{RepoPath: repoPath, Branch: nullableString(branch)})
 	if err != nil {
 		return SessionRef{}, err
@@ -84,7 +86,7 @@ func (s *SQLiteProjectionStore) LoadBlockedByRepoAndBranch(ctx context.Context,
 	return sessionRefFromRow(row), nil
 }
 
-func (s *SQLiteProjectionStore) LoadByWorktreePath(ctx context.Context, worktreePath string) (SessionRef, error) {
+func (s *DBProjectionStore) LoadByWorktreePath(ctx context.Context, worktreePath string) (SessionRef, error) {
 	row, err := s.queries.GetSessionProjectionByWorktreePath(ctx, nullableString(worktreePath))
 	if err != nil {
 		return SessionRef{}, err
@@ -92,7 +94,7 @@ func (s *SQLiteProjectionStore) LoadByWorktreePath(ctx context.Context, worktree
 	return sessionRefFromRow(row), nil
 }
 
-func (s *SQLiteProjectionStore) LoadLatestNavigationByBranch(ctx context.Context, branch string) (SessionRef, error) {
+func (s *DBProjectionStore) LoadLatestNavigationByBranch(ctx context.Context, branch string) (SessionRef, error) {
 	row, err := s.queries.GetLatestNavigationSessionProjectionByBranch(ctx, nullableString(branch))
 	if err != nil {
 		return SessionRef{}, err
@@ -100,22 +102,22 @@ func (s *SQLiteProjectionStore) LoadLatestNavigationByBranch(ctx context.Context
 	return sessionRefFromRow(row), nil
 }
 
-func (s *SQLiteProjectionStore) ListActiveRefs(ctx context.Context) ([]SessionRef, error) {
+func (s *DBProjectionStore) ListActiveRefs(ctx context.Context) ([]SessionRef, error) {
 	rows, err := s.queries.ListActiveSessionProjectionRefs(ctx)
 	return sessionRefsFromRows(rows, err)
 }
 
-func (s *SQLiteProjectionStore) ListHydratableRefs(ctx context.Context) ([]SessionRef, error) {
+func (s *DBProjectionStore) ListHydratableRefs(ctx context.Context) ([]SessionRef, error) {
 	rows, err := s.queries.ListHydratableSessionProjectionRefs(ctx)
 	return sessionRefsFromRows(rows, err)
 }
 
-func (s *SQLiteProjectionStore) ListReusableRefs(ctx context.Context, repoPath string, backendID string) ([]SessionRef, error) {
+func (s *DBProjectionStore) ListReusableRefs(ctx context.Context, repoPath string, backendID string) ([]SessionRef, error) {
 	rows, err := s.queries.ListReusableSessionProjectionRefs(ctx, coredb.ListReusableSessionProjectionRefsParams{RepoPath: repoPath, BackendID: backendID})
 	return sessionRefsFromRows(rows, err)
 }
 
-func (s *SQLiteProjectionStore) ListVisible(ctx context.Context) ([]ListItem, error) {
+func (s *DBProjectionStore) ListVisible(ctx context.Context) ([]ListItem, error) {
 	rows, err := s.queries.ListVisibleSessionProjectionItems(ctx)
 	if err != nil {
 		return nil, err
@@ -127,7 +129,7 @@ func (s *SQLiteProjectionStore) ListVisible(ctx context.Context) ([]ListItem, er
 	return items, nil
 }
 
-func (s *SQLiteProjectionStore) ListAll(ctx context.Context) ([]ListItem, error) {
+func (s *DBProjectionStore) ListAll(ctx context.Context) ([]ListItem, error) {
 	rows, err := s.queries.ListAllSessionProjectionItems(ctx)
 	if err != nil {
 		return nil, err
@@ -139,38 +141,38 @@ func (s *SQLiteProjectionStore) ListAll(ctx context.Context) ([]ListItem, error)
 	return items, nil
 }
 
-func (s *SQLiteProjectionStore) ListAfterCursor(ctx context.Context, statusesArg string, statusesValue sql.NullString, cursor string, limit int) ([]ListItem, error) {
+func (s *DBProjectionStore) ListAfterCursor(ctx context.Context, statusesArg string, statusesValue sql.NullString, cursor string, limit int) ([]ListItem, error) {
 	rows, err := s.queries.ListSessionProjectionItemsAfterCursorByStatuses(ctx, coredb.ListSessionProjectionItemsAfterCursorByStatusesParams{Column1: statusesArg, Column2: statusesValue, Column3: cursor, StreamID: cursor, Limit: int64(limit)})
 	if err != nil {
 		return nil, err
 	}
 	items := make([]ListItem, 0, len(rows))
 	for _, row := range rows {
-		items = append(items, newListItem(row.StreamID, row.RepoPath, row.RemoteUrl, nullStringValue(row.Branch), PublicState(row.PublicState)))
+		items = append(items, newListItem(row.StreamID, row.RepoPath, row.RemoteUrl, nullStringValue(row.Branch), PublicState(row.PublicState)).withPullRequest(row.PrNumber, row.PrUrl, row.PrState, row.PrCiState))
 	}
 	return items, nil
 }
 
-func (s *SQLiteProjectionStore) ListBeforeCursor(ctx context.Context, statusesArg string, statusesValue sql.NullString, cursor string, limit int) ([]ListItem, error) {
+func (s *DBProjectionStore) ListBeforeCursor(ctx context.Context, statusesArg string, statusesValue sql.NullString, cursor string, limit int) ([]ListItem, error) {
 	rows, err := s.queries.ListSessionProjectionItemsBeforeCursorByStatuses(ctx, coredb.ListSessionProjectionItemsBeforeCursorByStatusesParams{Column1: statusesArg, Column2: statusesValue, Column3: cursor, StreamID: cursor, Limit: int64(limit)})
 	if err != nil {
 		return nil, err
 	}
 	items := make([]ListItem, 0, len(rows))
 	for _, row := range rows {
-		items = append(items, newListItem(row.StreamID, row.RepoPath, row.RemoteUrl, nullStringValue(row.Branch), PublicState(row.PublicState)))
+		items = append(items, newListItem(row.StreamID, row.RepoPath, row.RemoteUrl, nullStringValue(row.Branch), PublicState(row.PublicState)).withPullRequest(row.PrNumber, row.PrUrl, row.PrState, row.PrCiState))
 	}
 	return items, nil
 }
 
-func (s *SQLiteProjectionStore) ListOldest(ctx context.Context, statusesArg string, statusesValue sql.NullString, limit int) ([]ListItem, error) {
+func (s *DBProjectionStore) ListOldest(ctx context.Context, statusesArg string, statusesValue sql.NullString, limit int) ([]ListItem, error) {
 	rows, err := s.queries.ListSessionProjectionItemsOldestByStatuses(ctx, coredb.ListSessionProjectionItemsOldestByStatusesParams{Column1: statusesArg, Column2: statusesValue, Limit: int64(limit)})
 	if err != nil {
 		return nil, err
 	}
 	items := make([]ListItem, 0, len(rows))
 	for _, row := range rows {
-		items = append(items, newListItem(row.StreamID, row.RepoPath, row.RemoteUrl, nullStringValue(row.Branch), PublicState(row.PublicState)))
+		items = append(items, newListItem(row.StreamID, row.RepoPath, row.RemoteUrl, nullStringValue(row.Branch), PublicState(row.PublicState)).withPullRequest(row.PrNumber, row.PrUrl, row.PrState, row.PrCiState))
 	}
 	return items, nil
 }
@@ -195,6 +197,7 @@ func sessionRefFromRow(row coredb.SessionProjection) SessionRef {
 		RepoPath:       row.RepoPath,
 		WorktreePath:   nullStringValue(row.WorktreePath),
 		RemoteURL:      row.RemoteUrl,
+		BaseBranch:     nullStringValue(row.BaseBranch),
 		LifecycleState: LifecycleState(row.LifecycleState),
 		PublicState:    PublicState(row.PublicState),
 		LastError:      row.LastError,
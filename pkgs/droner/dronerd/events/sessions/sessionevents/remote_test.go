@@ -17,6 +17,7 @@ import (
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/backends"
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
 )
 
 type remoteTestBackend struct {
@@ -28,6 +29,7 @@ type remoteTestBackend struct {
 	hydrateCalls        int
 	completeCalls       int
 	deleteCalls         int
+	sentMessages        []*messages.Message
 }
 
 func (b *remoteTestBackend) ID() conf.BackendID {
@@ -79,6 +81,13 @@ func (b *remoteTestBackend) DeleteSession(ctx context.Context, worktreePath stri
 	return nil
 }
 
+func (b *remoteTestBackend) SendMessage(ctx context.Context, worktreePath string, agentConfig backends.AgentConfig, message *messages.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sentMessages = append(b.sentMessages, message)
+	return nil
+}
+
 func (b *remoteTestBackend) CompleteCalls() int {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -141,7 +150,7 @@ func newRemoteTestSystem(t *testing.T) (*System, *remoteTestBackend, string, con
 		_ = db.Close()
 		t.Fatalf("Sessions log: %v", err)
 	}
-	system := New(sessionsLog, NewSQLiteProjectionStore(queries), eventLogs.SessionResetter(), slog.New(slog.NewJSONHandler(io.Discard, nil)), config, store)
+	system := New(sessionsLog, NewProjectionStore(queries), eventLogs.SessionResetter(), slog.New(slog.NewJSONHandler(io.Discard, nil)), config, store)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	system.Start(ctx)
@@ -310,3 +319,37 @@ func TestCreateSessionRequestsDeletionForReusedCompletedCandidate(t *testing.T)
 		eventtypes.SessionDeletionSuccess,
 	)
 }
+
+func TestSendAgentMessageRequiresActiveSession(t *testing.T) {
+	system, backend, _, _ := newRemoteTestSystem(t)
+
+	if _, err := system.CreateSession(context.Background(), CreateSessionInput{
+		StreamID:        "message-stream",
+		Harness:         conf.HarnessOpenCode,
+		RequestedBranch: "message-branch",
+		BackendID:       conf.BackendLocal,
+		RepoPath:        "/tmp/repo",
+	}); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	waitForPublicState(t, system, "message-branch", PublicStateActiveIdle)
+
+	message := &messages.Message{Role: messages.MessageRoleUser, Parts: []messages.MessagePart{messages.NewTextPart("CI failed")}}
+	if err := system.SendAgentMessage(context.Background(), "message-stream", message); err != nil {
+		t.Fatalf("SendAgentMessage: %v", err)
+	}
+	backend.mu.Lock()
+	sent := len(backend.sentMessages)
+	backend.mu.Unlock()
+	if sent != 1 {
+		t.Fatalf("expected one message sent to the backend, got %d", sent)
+	}
+
+	if _, err := system.RequestCompletion(context.Background(), "message-branch"); err != nil {
+		t.Fatalf("RequestCompletion: %v", err)
+	}
+	waitForPublicState(t, system, "message-branch", PublicStateCompleted)
+	if err := system.SendAgentMessage(context.Background(), "message-stream", message); err == nil {
+		t.Fatal("expected completed sessions to reject agent messages")
+	}
+}
@@ -19,11 +19,13 @@ type sessionState struct {
 	RepoPath        string
 	WorktreePath    string
 	RemoteURL       string
+	BaseBranch      string
 	AgentConfig     string
 	LifecycleState  LifecycleState
 	PublicState     PublicState
 	LastError       string
 	PRNumber        int64
+	PRURL           string
 	PRState         string
 	PRCIState       string
 	PRUpdatedAt     time.Time
@@ -87,6 +89,7 @@ func (s *sessionState) Apply(evt eventlog.Envelope) (bool, error) {
 		s.RepoPath = payload.RepoPath
 		s.WorktreePath = ""
 		s.RemoteURL = payload.RemoteURL
+		s.BaseBranch = ""
 		s.AgentConfig = payload.AgentConfigJSON
 		s.transition(LifecycleStateQueued, PublicStateQueued, "", evt.OccurredAt)
 		if s.CreatedAt.IsZero() {
@@ -118,6 +121,13 @@ func (s *sessionState) Apply(evt eventlog.Envelope) (bool, error) {
 		s.transition(LifecycleStateEnvironmentProvisioningStarted, PublicStateQueued, "", evt.OccurredAt)
 		return true, nil
 	case eventtypes.SessionEnvironmentProvisioningSuccess:
+		payload, err := decodeProvisioningSuccessPayload(evt)
+		if err != nil {
+			return false, err
+		}
+		if payload.BaseBranch != "" {
+			s.BaseBranch = payload.BaseBranch
+		}
 		s.transition(LifecycleStateEnvironmentProvisioningSuccess, PublicStateQueued, "", evt.OccurredAt)
 		return true, nil
 	case eventtypes.SessionReady:
@@ -172,6 +182,7 @@ func (s *sessionState) Apply(evt eventlog.Envelope) (bool, error) {
 			return false, err
 		}
 		s.PRNumber = int64(payload.PRNumber)
+		s.PRURL = payload.URL
 		s.PRState = payload.State
 		s.PRCIState = payload.CIState
 		s.PRUpdatedAt = payload.LinkedAt.UTC()
@@ -261,11 +272,13 @@ func stateFromProjection(row coredb.SessionProjection) sessionState {
 		RepoPath:       row.RepoPath,
 		WorktreePath:   nullStringValue(row.WorktreePath),
 		RemoteURL:      row.RemoteUrl,
+		BaseBranch:     nullStringValue(row.BaseBranch),
 		AgentConfig:    row.AgentConfig,
 		LifecycleState: LifecycleState(row.LifecycleState),
 		PublicState:    PublicState(row.PublicState),
 		LastError:      row.LastError,
 		PRNumber:       nullInt64Value(row.PrNumber),
+		PRURL:          nullStringValue(row.PrUrl),
 		PRState:        nullStringValue(row.PrState),
 		PRCIState:      nullStringValue(row.PrCiState),
 		PRUpdatedAt:    nullTimeValue(row.PrUpdatedAt),
@@ -274,6 +287,33 @@ func stateFromProjection(row coredb.SessionProjection) sessionState {
 	}
 }
 
+func (s sessionState) ref() SessionRef {
+	return SessionRef{
+		StreamID:       s.StreamID,
+		Harness:        s.Harness,
+		Branch:         s.Branch,
+		BackendID:      s.BackendID,
+		RepoPath:       s.RepoPath,
+		WorktreePath:   s.WorktreePath,
+		RemoteURL:      s.RemoteURL,
+		BaseBranch:     s.BaseBranch,
+		LifecycleState: s.LifecycleState,
+		PublicState:    s.PublicState,
+		LastError:      s.LastError,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+func (s sessionState) listItem() ListItem {
+	item := newListItem(s.StreamID, s.RepoPath, s.RemoteURL, s.Branch, s.PublicState)
+	item.PRNumber = s.PRNumber
+	item.PRURL = s.PRURL
+	item.PRState = s.PRState
+	item.PRCIState = s.PRCIState
+	return item
+}
+
 func (s sessionState) projectionMutation() projectionMutation {
 	return projectionMutation{
 		StreamID:       s.StreamID,
@@ -283,11 +323,13 @@ func (s sessionState) projectionMutation() projectionMutation {
 		RepoPath:       s.RepoPath,
 		WorktreePath:   s.WorktreePath,
 		RemoteURL:      s.RemoteURL,
+		BaseBranch:     s.BaseBranch,
 		AgentConfig:    s.AgentConfig,
 		LifecycleState: s.LifecycleState.String(),
 		PublicState:    s.PublicState.String(),
 		LastError:      s.LastError,
 		PRNumber:       s.PRNumber,
+		PRURL:          s.PRURL,
 		PRState:        s.PRState,
 		PRCIState:      s.PRCIState,
 		PRUpdatedAt:    s.PRUpdatedAt,
@@ -16,6 +16,7 @@ import (
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/backends"
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
 )
 
 const (
@@ -38,6 +39,7 @@ type System struct {
 	runAgentEvents func(context.Context, *slog.Logger, conf.OpenCodeConfig, func(context.Context, agentevents.Event) error) error
 
 	startOnce sync.Once
+	watchers  watchHub
 }
 
 type SessionResetter interface {
@@ -69,19 +71,27 @@ type NukeResult struct {
 type ListItem struct {
 	ID        string
 	Repo      string
+	RepoPath  string
 	RemoteURL string
 	Branch    string
 	State     PublicState
+	PRNumber  int64
+	PRURL     string
+	PRState   string
+	PRCIState string
 }
 
 type SessionRef struct {
-	StreamID       string
-	Harness        string
-	Branch         string
-	BackendID      string
-	RepoPath       string
-	WorktreePath   string
-	RemoteURL      string
+	StreamID     string
+	Harness      string
+	Branch       string
+	BackendID    string
+	RepoPath     string
+	WorktreePath string
+	RemoteURL    string
+	// BaseBranch is the branch the worktree was created from; empty for
+	// sessions provisioned before it was recorded.
+	BaseBranch     string
 	LifecycleState LifecycleState
 	PublicState    PublicState
 	LastError      string
@@ -272,6 +282,48 @@ func (s *System) LookupSessionByBranch(ctx context.Context, branch string) (Sess
 	return s.loadCurrentProjectionByBranch(ctx, branch)
 }
 
+func (s *System) LookupSessionByID(ctx context.Context, streamID string) (SessionRef, error) {
+	state, err := s.projections.LoadStateByStreamID(ctx, streamID)
+	if err != nil {
+		return SessionRef{}, err
+	}
+	return state.ref(), nil
+}
+
+// SessionDescription returns the initial prompt of a session as plain text,
+// or "" when it was created without one.
+func (s *System) SessionDescription(ctx context.Context, streamID string) (string, error) {
+	state, err := s.projections.LoadStateByStreamID(ctx, streamID)
+	if err != nil {
+		return "", err
+	}
+	agentConfig, err := s.agentConfigFromJSON(conf.HarnessID(state.Harness), state.AgentConfig)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(agentConfig.ToDescription()), nil
+}
+
+// SendAgentMessage sends a follow-up prompt to the agent of an active session.
+func (s *System) SendAgentMessage(ctx context.Context, streamID string, message *messages.Message) error {
+	state, err := s.projections.LoadStateByStreamID(ctx, streamID)
+	if err != nil {
+		return err
+	}
+	if !state.PublicState.IsActive() {
+		return fmt.Errorf("session %s is not active (status=%s)", streamID, state.PublicState)
+	}
+	agentConfig, err := s.agentConfigFromJSON(conf.HarnessID(state.Harness), state.AgentConfig)
+	if err != nil {
+		return err
+	}
+	backend, err := s.backends.Get(conf.BackendID(state.BackendID))
+	if err != nil {
+		return err
+	}
+	return backend.SendMessage(ctx, state.WorktreePath, agentConfig, message)
+}
+
 func (s *System) LookupBlockedSessionByRepoAndBranch(ctx context.Context, repoPath string, branch string) (SessionRef, error) {
 	return s.loadBlockedProjectionByRepoAndBranch(ctx, repoPath, branch)
 }
@@ -362,7 +414,15 @@ func newListItem(id, repoPath, remoteURL, branch string, state PublicState) List
 	if repo == "." || repo == string(filepath.Separator) {
 		repo = ""
 	}
-	return ListItem{ID: id, Repo: repo, RemoteURL: remoteURL, Branch: branch, State: state}
+	return ListItem{ID: id, Repo: repo, RepoPath: repoPath, RemoteURL: remoteURL, Branch: branch, State: state}
+}
+
+func (item ListItem) withPullRequest(number sql.NullInt64, url sql.NullString, state sql.NullString, ciState sql.NullString) ListItem {
+	item.PRNumber = nullInt64Value(number)
+	item.PRURL = nullStringValue(url)
+	item.PRState = nullStringValue(state)
+	item.PRCIState = nullStringValue(ciState)
+	return item
 }
 
 func (s *System) rebuildProjection(ctx context.Context, streamID string) error {
@@ -373,7 +433,11 @@ func (s *System) rebuildProjection(ctx context.Context, streamID string) error {
 	if err != nil {
 		return err
 	}
-	return s.upsertProjection(ctx, state.projectionMutation())
+	if err := s.upsertProjection(ctx, state.projectionMutation()); err != nil {
+		return err
+	}
+	s.watchers.publish(state.listItem())
+	return nil
 }
 
 func (s *System) runAgentEventBridge(ctx context.Context) {
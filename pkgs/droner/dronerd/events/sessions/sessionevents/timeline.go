@@ -0,0 +1,52 @@
+package sessionevents
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+// TimelineEvent is one event of a session stream. Error and BackendDetails
+// are decoded from the payload of lifecycle failures.
+type TimelineEvent struct {
+	eventlog.Envelope
+	Error          string
+	BackendDetails string
+}
+
+// SessionTimeline loads every event of a session stream in order. It
+// returns sql.ErrNoRows for unknown streams.
+func (s *System) SessionTimeline(ctx context.Context, streamID string) ([]TimelineEvent, error) {
+	events, err := eventlog.LoadFullStream(ctx, s.log, eventlog.StreamID(streamID))
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	timeline := make([]TimelineEvent, 0, len(events))
+	for _, evt := range events {
+		item := TimelineEvent{Envelope: evt}
+		if isFailureEvent(evt.Type) {
+			payload, err := decodeFailedPayload(evt)
+			if err != nil {
+				return nil, err
+			}
+			item.Error = payload.Error
+			item.BackendDetails = payload.BackendDetails
+		}
+		timeline = append(timeline, item)
+	}
+	return timeline, nil
+}
+
+func isFailureEvent(eventType eventlog.EventType) bool {
+	switch eventType {
+	case eventtypes.SessionEnrichmentFailed, eventtypes.SessionEnvironmentProvisioningFailed, eventtypes.SessionCompletionFailed, eventtypes.SessionDeletionFailed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,63 @@
+package sessionevents
+
+import (
+	"context"
+	"sync"
+)
+
+const watchBufferSize = 64
+
+// watchHub fans projection changes out to watchers. Publishing never blocks
+// the projection: a watcher that falls behind by more than watchBufferSize
+// items has its channel closed, so it reconnects and reloads the list
+// instead of silently missing updates.
+type watchHub struct {
+	mu       sync.Mutex
+	watchers map[chan ListItem]struct{}
+}
+
+func (h *watchHub) subscribe() chan ListItem {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.watchers == nil {
+		h.watchers = map[chan ListItem]struct{}{}
+	}
+	ch := make(chan ListItem, watchBufferSize)
+	h.watchers[ch] = struct{}{}
+	return ch
+}
+
+func (h *watchHub) unsubscribe(ch chan ListItem) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.watchers[ch]; !ok {
+		return
+	}
+	delete(h.watchers, ch)
+	close(ch)
+}
+
+func (h *watchHub) publish(item ListItem) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.watchers {
+		select {
+		case ch <- item:
+		default:
+			delete(h.watchers, ch)
+			close(ch)
+		}
+	}
+}
+
+// WatchSessions returns a channel receiving the list item of each session
+// whose projection changes. The channel is closed once ctx ends or the
+// watcher falls too far behind.
+func (s *System) WatchSessions(ctx context.Context) <-chan ListItem {
+	ch := s.watchers.subscribe()
+	go func() {
+		<-ctx.Done()
+		s.watchers.unsubscribe(ch)
+	}()
+	return ch
+}
@@ -0,0 +1,34 @@
+package sessionevents
+
+import "testing"
+
+func TestWatchHubClosesWatcherThatFallsBehind(t *testing.T) {
+	hub := &watchHub{}
+	slow := hub.subscribe()
+	fast := hub.subscribe()
+
+	for i := 0; i <= watchBufferSize; i++ {
+		hub.publish(ListItem{ID: "session-1"})
+		if i < watchBufferSize {
+			<-fast
+		}
+	}
+
+	received := 0
+	for range slow {
+		received++
+	}
+	if received != watchBufferSize {
+		t.Fatalf("expected %d buffered items before the close, got %d", watchBufferSize, received)
+	}
+	select {
+	case _, ok := <-fast:
+		if !ok {
+			t.Fatal("expected the watcher that kept up to stay open")
+		}
+	default:
+		t.Fatal("expected the watcher that kept up to receive the last item")
+	}
+	hub.unsubscribe(slow)
+	hub.unsubscribe(fast)
+}
@@ -0,0 +1,198 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+const (
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+)
+
+// deliveryPayload is the JSON body posted to endpoints. ID is the event ID
+// and stays the same across retries, so receivers can drop duplicates.
+type deliveryPayload struct {
+	ID          string               `json:"id"`
+	Topic       string               `json:"topic"`
+	Type        string               `json:"type"`
+	StreamID    string               `json:"streamId"`
+	OccurredAt  time.Time            `json:"occurredAt"`
+	CausationID string               `json:"causationId,omitempty"`
+	Branch      string               `json:"branch,omitempty"`
+	Repo        deliveryRepo         `json:"repo"`
+	Session     *deliverySession     `json:"session,omitempty"`
+	PullRequest *deliveryPullRequest `json:"pullRequest,omitempty"`
+	Payload     json.RawMessage      `json:"payload"`
+}
+
+type deliveryRepo struct {
+	Path      string `json:"path,omitempty"`
+	RemoteURL string `json:"remoteUrl,omitempty"`
+}
+
+type deliverySession struct {
+	StreamID       string `json:"streamId"`
+	Harness        string `json:"harness"`
+	BackendID      string `json:"backendId"`
+	Branch         string `json:"branch"`
+	RepoPath       string `json:"repoPath"`
+	WorktreePath   string `json:"worktreePath"`
+	RemoteURL      string `json:"remoteUrl"`
+	LifecycleState string `json:"lifecycleState"`
+	PublicState    string `json:"publicState"`
+	LastError      string `json:"lastError,omitempty"`
+}
+
+type deliveryPullRequest struct {
+	StreamID  string `json:"streamId"`
+	Provider  string `json:"provider"`
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	State     string `json:"state"`
+	HeadRef   string `json:"headRef"`
+	HeadSHA   string `json:"headSha"`
+	BaseRef   string `json:"baseRef"`
+	CIState   string `json:"ciState"`
+	RemoteURL string `json:"remoteUrl"`
+}
+
+// deliveryRecord is the payload of the events recording a delivery.
+type deliveryRecord struct {
+	Endpoint    string    `json:"endpoint"`
+	URL         string    `json:"url"`
+	EventType   string    `json:"eventType"`
+	Attempts    int       `json:"attempts"`
+	StatusCode  int       `json:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+func newDeliverySession(ref sessionevents.SessionRef) deliverySession {
+	return deliverySession{
+		StreamID:       ref.StreamID,
+		Harness:        ref.Harness,
+		BackendID:      ref.BackendID,
+		Branch:         ref.Branch,
+		RepoPath:       ref.RepoPath,
+		WorktreePath:   ref.WorktreePath,
+		RemoteURL:      ref.RemoteURL,
+		LifecycleState: string(ref.LifecycleState),
+		PublicState:    string(ref.PublicState),
+		LastError:      ref.LastError,
+	}
+}
+
+func newDeliveryPullRequest(streamID string, snapshot remote.PullRequestSnapshot) deliveryPullRequest {
+	return deliveryPullRequest{
+		StreamID:  streamID,
+		Provider:  snapshot.Provider,
+		Number:    snapshot.Number,
+		Title:     snapshot.Title,
+		URL:       snapshot.HTMLURL,
+		State:     snapshot.State,
+		HeadRef:   snapshot.HeadRef,
+		HeadSHA:   snapshot.HeadSHA,
+		BaseRef:   snapshot.BaseRef,
+		CIState:   snapshot.CI.State,
+		RemoteURL: snapshot.RemoteURL,
+	}
+}
+
+// send posts body to endpoint, retrying network errors, 429 and 5xx responses
+// with exponential backoff. Other 4xx responses are final. It only returns an
+// error when ctx ends, leaving the event for redelivery.
+func (s *System) send(ctx context.Context, endpoint conf.WebhookEndpointConfig, evt eventlog.Envelope, body []byte) (deliveryRecord, error) {
+	record := deliveryRecord{Endpoint: endpoint.Name, URL: endpoint.URL, EventType: string(evt.Type)}
+	secret := endpoint.SigningSecret()
+	backoff := initialBackoff
+
+	for attempt := 1; attempt <= endpoint.MaxAttempts; attempt++ {
+		record.Attempts = attempt
+		statusCode, err := s.post(ctx, endpoint, evt, body, secret)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return deliveryRecord{}, ctxErr
+		}
+		record.StatusCode = statusCode
+		record.Error = ""
+		if err == nil && statusCode >= 200 && statusCode < 300 {
+			break
+		}
+		if err != nil {
+			record.Error = err.Error()
+		} else {
+			record.Error = fmt.Sprintf("unexpected status %d", statusCode)
+		}
+		if !retryable(statusCode) || attempt == endpoint.MaxAttempts {
+			break
+		}
+		if err := s.sleep(ctx, backoff); err != nil {
+			return deliveryRecord{}, err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+	record.CompletedAt = s.now().UTC()
+	return record, nil
+}
+
+// retryable treats a zero status, meaning the request never got a response,
+// as retryable.
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+func (s *System) post(ctx context.Context, endpoint conf.WebhookEndpointConfig, evt eventlog.Envelope, body []byte, secret string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(endpoint.Timeout)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "droner-webhooks")
+	req.Header.Set("X-Droner-Event", string(evt.Type))
+	req.Header.Set("X-Droner-Delivery", string(evt.ID))
+	if secret != "" {
+		req.Header.Set("X-Droner-Signature-256", signature(secret, body))
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// signature uses the same "sha256=<hex>" form as GitHub webhooks.
+func signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package webhooks_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/memory"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/hooks"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/webhooks"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+// A hook rule and a webhook endpoint both on "*" must each react to the
+// cause once and ignore the other's records.
+func TestHooksAndWebhooksDoNotTriggerEachOther(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	backend := memory.New()
+	defer backend.Close()
+	sessionsLog, err := eventlog.New(eventlog.Config{Topic: "sessions"}, backend)
+	if err != nil {
+		t.Fatalf("eventlog.New sessions: %v", err)
+	}
+	prLog, err := eventlog.New(eventlog.Config{Topic: "pullrequests"}, backend)
+	if err != nil {
+		t.Fatalf("eventlog.New pullrequests: %v", err)
+	}
+
+	hookSystem := hooks.New(sessionsLog, prLog, nil, nil, nil, conf.HooksConfig{Rules: []conf.HookRule{{
+		Name:    "everything",
+		Event:   "*",
+		Actions: []conf.HookAction{{Type: conf.HookActionWebhook, URL: server.URL, Timeout: 5}},
+	}}}, nil)
+	webhookSystem := webhooks.New(sessionsLog, prLog, nil, nil, conf.WebhooksConfig{Endpoints: []conf.WebhookEndpointConfig{{
+		Name:        "everything",
+		URL:         server.URL,
+		Events:      []string{"*"},
+		MaxAttempts: 1,
+		Timeout:     5,
+	}}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hookSystem.Start(ctx)
+	webhookSystem.Start(ctx)
+	defer hookSystem.Close()
+	defer webhookSystem.Close()
+
+	if _, err := sessionsLog.Append(ctx, eventlog.PendingEvent{StreamID: "session-1", Type: eventtypes.SessionReady, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	counts := func() map[eventlog.EventType]int {
+		events, err := eventlog.LoadFullStream(ctx, sessionsLog, "session-1")
+		if err != nil {
+			t.Fatalf("LoadFullStream: %v", err)
+		}
+		counts := map[eventlog.EventType]int{}
+		for _, evt := range events {
+			counts[evt.Type]++
+		}
+		return counts
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := counts()
+		if got[eventtypes.SessionHookFired] >= 1 && got[eventtypes.SessionWebhookDelivered] >= 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the hook run and delivery, got %v", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Give a loop time to show up before checking nothing else was added.
+	time.Sleep(200 * time.Millisecond)
+	got := counts()
+	if len(got) != 3 || got[eventtypes.SessionReady] != 1 || got[eventtypes.SessionHookFired] != 1 || got[eventtypes.SessionWebhookDelivered] != 1 {
+		t.Fatalf("expected one hook run and one delivery for the cause, got %v", got)
+	}
+}
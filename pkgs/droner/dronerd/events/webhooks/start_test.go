@@ -0,0 +1,121 @@
+package webhooks_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/backend/memory"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/webhooks"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+func newStartTestLogs(t *testing.T) (*memory.Backend, eventlog.EventLog, eventlog.EventLog) {
+	t.Helper()
+	backend := memory.New()
+	t.Cleanup(func() { _ = backend.Close() })
+	sessionsLog, err := eventlog.New(eventlog.Config{Topic: "sessions"}, backend)
+	if err != nil {
+		t.Fatalf("eventlog.New sessions: %v", err)
+	}
+	prLog, err := eventlog.New(eventlog.Config{Topic: "pullrequests"}, backend)
+	if err != nil {
+		t.Fatalf("eventlog.New pullrequests: %v", err)
+	}
+	return backend, sessionsLog, prLog
+}
+
+// Enabling webhooks on a daemon with history must not replay that history to
+// the new endpoint.
+func TestEnablingWebhooksSkipsExistingEvents(t *testing.T) {
+	var mu sync.Mutex
+	delivered := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		delivered = append(delivered, r.Header.Get("X-Droner-Event"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, sessionsLog, prLog := newStartTestLogs(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, eventType := range []eventlog.EventType{eventtypes.SessionQueued, eventtypes.SessionReady} {
+		if _, err := sessionsLog.Append(ctx, eventlog.PendingEvent{StreamID: "old-session", Type: eventType, Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	system := webhooks.New(sessionsLog, prLog, nil, nil, conf.WebhooksConfig{Endpoints: []conf.WebhookEndpointConfig{{
+		Name:        "everything",
+		URL:         server.URL,
+		Events:      []string{"*"},
+		MaxAttempts: 1,
+		Timeout:     5,
+	}}}, nil)
+	system.Start(ctx)
+	defer system.Close()
+
+	if _, err := sessionsLog.Append(ctx, eventlog.PendingEvent{StreamID: "new-session", Type: eventtypes.SessionQueued, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		events, err := eventlog.LoadFullStream(ctx, sessionsLog, "new-session")
+		if err != nil {
+			t.Fatalf("LoadFullStream: %v", err)
+		}
+		if len(events) == 2 && events[1].Type == eventtypes.SessionWebhookDelivered {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the new event's delivery, got %d events", len(events))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	old, err := eventlog.LoadFullStream(ctx, sessionsLog, "old-session")
+	if err != nil {
+		t.Fatalf("LoadFullStream: %v", err)
+	}
+	if len(old) != 2 {
+		t.Fatalf("expected no delivery records on the old stream, got %d events", len(old))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != 1 {
+		t.Fatalf("expected only the new event to be delivered, got %d deliveries", len(delivered))
+	}
+}
+
+func TestWebhooksWithoutEndpointsDropTheirCheckpoints(t *testing.T) {
+	backend, sessionsLog, prLog := newStartTestLogs(t)
+	ctx := context.Background()
+	if _, err := sessionsLog.Append(ctx, eventlog.PendingEvent{StreamID: "session-1", Type: eventtypes.SessionQueued, Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	for _, topic := range []eventlog.Topic{"sessions", "pullrequests"} {
+		if err := backend.StoreCheckpoint(ctx, topic, "webhooks", 0); err != nil {
+			t.Fatalf("StoreCheckpoint: %v", err)
+		}
+	}
+
+	system := webhooks.New(sessionsLog, prLog, nil, nil, conf.WebhooksConfig{}, nil)
+	system.Start(ctx)
+
+	for _, topic := range []eventlog.Topic{"sessions", "pullrequests"} {
+		checkpoints, err := backend.ListCheckpoints(ctx, topic)
+		if err != nil {
+			t.Fatalf("ListCheckpoints: %v", err)
+		}
+		if len(checkpoints) != 0 {
+			t.Fatalf("%s checkpoints = %#v, want none", topic, checkpoints)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+const (
+	consumerWebhooks = "webhooks"
+
+	topicSessions     = "sessions"
+	topicPullRequests = "pullrequests"
+)
+
+// Sessions looks up the session a session event belongs to.
+type Sessions interface {
+	LookupSessionByID(ctx context.Context, streamID string) (sessionevents.SessionRef, error)
+}
+
+// SnapshotStore loads the latest pull request snapshot by PR stream ID.
+type SnapshotStore interface {
+	Load(ctx context.Context, streamID string) (remote.PullRequestSnapshot, bool, error)
+}
+
+// System posts session and pull request events to the configured endpoints
+// and records each delivery on the event's stream.
+type System struct {
+	sessionsLog eventlog.EventLog
+	prLog       eventlog.EventLog
+	sessions    Sessions
+	snapshots   SnapshotStore
+	config      conf.WebhooksConfig
+	logger      *slog.Logger
+	httpClient  *http.Client
+	sleep       func(ctx context.Context, d time.Duration) error
+	now         func() time.Time
+	startOnce   sync.Once
+}
+
+func New(sessionsLog eventlog.EventLog, prLog eventlog.EventLog, sessions Sessions, snapshots SnapshotStore, config conf.WebhooksConfig, logger *slog.Logger) *System {
+	return &System{
+		sessionsLog: sessionsLog,
+		prLog:       prLog,
+		sessions:    sessions,
+		snapshots:   snapshots,
+		config:      config,
+		logger:      logger,
+		httpClient:  &http.Client{},
+		sleep:       sleepContext,
+		now:         time.Now,
+	}
+}
+
+// Start subscribes to both topics. Endpoints only receive events appended
+// after webhooks were first enabled; while none are configured the
+// subscriber's checkpoints are dropped so they do not hold back compaction.
+func (s *System) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		subscriptions := []struct {
+			log    eventlog.EventLog
+			handle func(context.Context, eventlog.Envelope) error
+		}{
+			{log: s.sessionsLog, handle: s.handleSessionEvent},
+			{log: s.prLog, handle: s.handlePullRequestEvent},
+		}
+		for _, sub := range subscriptions {
+			if len(s.config.Endpoints) == 0 {
+				if err := eventlog.ForgetSubscriber(ctx, sub.log, consumerWebhooks); err != nil && s.logger != nil {
+					s.logger.Warn("Failed to drop webhooks checkpoint", "error", err)
+				}
+				continue
+			}
+			if err := eventlog.StartAtHead(ctx, sub.log, consumerWebhooks); err != nil {
+				if s.logger != nil {
+					s.logger.Error("Failed to initialize webhooks checkpoint", "error", err)
+				}
+				continue
+			}
+			go s.runSubscription(ctx, sub.log, eventlog.Subscription{
+				ID:     eventlog.SubscriberID(consumerWebhooks),
+				Filter: s.wants,
+				Handle: sub.handle,
+			})
+		}
+	})
+}
+
+func (s *System) Close() error {
+	return nil
+}
+
+func (s *System) runSubscription(ctx context.Context, log eventlog.EventLog, sub eventlog.Subscription) {
+	if log == nil {
+		return
+	}
+	for {
+		if err := log.Subscribe(ctx, sub); err != nil && !errors.Is(err, context.Canceled) {
+			if s.logger != nil {
+				s.logger.Error("webhooks subscription failed", "subscriber_id", sub.ID, "error", err)
+			}
+		} else {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+func (s *System) wants(evt eventlog.Envelope) bool {
+	return len(s.endpointsFor(evt.Type)) > 0
+}
+
+// endpointsFor never returns endpoints for records of droner's own work, so
+// a "*" filter delivers neither its own deliveries nor hook runs.
+func (s *System) endpointsFor(eventType eventlog.EventType) []conf.WebhookEndpointConfig {
+	if eventtypes.IsRecord(eventType) {
+		return nil
+	}
+	endpoints := []conf.WebhookEndpointConfig{}
+	for _, endpoint := range s.config.Endpoints {
+		for _, pattern := range endpoint.Events {
+			if eventtypes.Matches(pattern, eventType) {
+				endpoints = append(endpoints, endpoint)
+				break
+			}
+		}
+	}
+	return endpoints
+}
+
+func isDeliveryRecord(eventType eventlog.EventType) bool {
+	switch eventType {
+	case eventtypes.SessionWebhookDelivered, eventtypes.SessionWebhookFailed, eventtypes.PRWebhookDelivered, eventtypes.PRWebhookFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *System) handleSessionEvent(ctx context.Context, evt eventlog.Envelope) error {
+	return s.deliver(ctx, topicSessions, s.sessionsLog, evt)
+}
+
+func (s *System) handlePullRequestEvent(ctx context.Context, evt eventlog.Envelope) error {
+	return s.deliver(ctx, topicPullRequests, s.prLog, evt)
+}
+
+// deliver posts evt to every matching endpoint that has no delivery record
+// for it yet. Endpoints are tried one after another, so a receiver that is
+// down delays later events by up to its retry budget.
+func (s *System) deliver(ctx context.Context, topic string, log eventlog.EventLog, evt eventlog.Envelope) error {
+	endpoints := s.endpointsFor(evt.Type)
+	if len(endpoints) == 0 {
+		return nil
+	}
+	events, err := eventlog.LoadFullStream(ctx, log, evt.StreamID)
+	if err != nil {
+		return err
+	}
+	body, err := s.deliveryBody(ctx, topic, evt)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		if delivered(events, endpoint.Name, evt.ID) {
+			continue
+		}
+		result, err := s.send(ctx, endpoint, evt, body)
+		if err != nil {
+			return err
+		}
+		recordType := eventtypes.SessionWebhookDelivered
+		if topic == topicPullRequests {
+			recordType = eventtypes.PRWebhookDelivered
+		}
+		if result.Error != "" {
+			recordType = eventtypes.SessionWebhookFailed
+			if topic == topicPullRequests {
+				recordType = eventtypes.PRWebhookFailed
+			}
+			if s.logger != nil {
+				s.logger.Warn("Webhook delivery failed",
+					slog.String("endpoint", endpoint.Name),
+					slog.String("event_id", string(evt.ID)),
+					slog.Int("attempts", result.Attempts),
+					slog.String("error", result.Error),
+				)
+			}
+		}
+		if err := appendRecord(ctx, log, evt, recordType, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// delivered reports whether events hold a delivery record for endpoint
+// caused by causeID, successful or not.
+func delivered(events []eventlog.Envelope, endpoint string, causeID eventlog.EventID) bool {
+	for _, evt := range events {
+		if !isDeliveryRecord(evt.Type) || evt.CausationID != causeID {
+			continue
+		}
+		var record deliveryRecord
+		if err := json.Unmarshal(evt.Payload, &record); err == nil && record.Endpoint == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *System) deliveryBody(ctx context.Context, topic string, evt eventlog.Envelope) ([]byte, error) {
+	body := deliveryPayload{
+		ID:          string(evt.ID),
+		Topic:       topic,
+		Type:        string(evt.Type),
+		StreamID:    string(evt.StreamID),
+		OccurredAt:  evt.OccurredAt.UTC(),
+		CausationID: string(evt.CausationID),
+		Payload:     json.RawMessage(evt.Payload),
+	}
+	if len(evt.Payload) == 0 {
+		body.Payload = json.RawMessage("{}")
+	}
+
+	switch topic {
+	case topicSessions:
+		if s.sessions == nil {
+			break
+		}
+		ref, err := s.sessions.LookupSessionByID(ctx, string(evt.StreamID))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if err == nil {
+			session := newDeliverySession(ref)
+			body.Session = &session
+			body.Branch = ref.Branch
+			body.Repo = deliveryRepo{Path: ref.RepoPath, RemoteURL: ref.RemoteURL}
+		}
+	case topicPullRequests:
+		if s.snapshots == nil {
+			break
+		}
+		snapshot, found, err := s.snapshots.Load(ctx, string(evt.StreamID))
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			pr := newDeliveryPullRequest(string(evt.StreamID), snapshot)
+			body.PullRequest = &pr
+			body.Branch = snapshot.HeadRef
+			body.Repo = deliveryRepo{RemoteURL: snapshot.RemoteURL}
+		}
+	}
+	return json.Marshal(body)
+}
+
+func appendRecord(ctx context.Context, log eventlog.EventLog, cause eventlog.Envelope, eventType eventlog.EventType, record deliveryRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = log.Append(ctx, eventlog.PendingEvent{
+		StreamID:      cause.StreamID,
+		Type:          eventType,
+		SchemaVersion: 1,
+		Payload:       payload,
+		CausationID:   cause.ID,
+		CorrelationID: string(cause.StreamID),
+	})
+	return err
+}
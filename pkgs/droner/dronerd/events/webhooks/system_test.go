@@ -0,0 +1,338 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+type memoryEventLog struct {
+	events []eventlog.Envelope
+}
+
+func (l *memoryEventLog) Append(_ context.Context, evt eventlog.PendingEvent) (eventlog.Envelope, error) {
+	version := int64(1)
+	for _, existing := range l.events {
+		if existing.StreamID == evt.StreamID {
+			version++
+		}
+	}
+	envelope := eventlog.Envelope{
+		ID:            eventlog.EventID(fmt.Sprintf("event-%d", len(l.events)+1)),
+		StreamID:      evt.StreamID,
+		StreamVersion: version,
+		Type:          evt.Type,
+		Payload:       evt.Payload,
+		CausationID:   evt.CausationID,
+		CorrelationID: evt.CorrelationID,
+	}
+	l.events = append(l.events, envelope)
+	return envelope, nil
+}
+
+// LoadStream pages like the real backends, which return at most 500 events
+// unless a limit is given.
+func (l *memoryEventLog) LoadStream(_ context.Context, streamID eventlog.StreamID, opts eventlog.LoadStreamOptions) ([]eventlog.Envelope, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 500
+	}
+	events := []eventlog.Envelope{}
+	for _, evt := range l.events {
+		if evt.StreamID == streamID && evt.StreamVersion > opts.AfterVersion && len(events) < limit {
+			events = append(events, evt)
+		}
+	}
+	return events, nil
+}
+
+func (l *memoryEventLog) Subscribe(context.Context, eventlog.Subscription) error {
+	return nil
+}
+
+func (l *memoryEventLog) Close() error {
+	return nil
+}
+
+func (l *memoryEventLog) ofType(eventType eventlog.EventType) []eventlog.Envelope {
+	events := []eventlog.Envelope{}
+	for _, evt := range l.events {
+		if evt.Type == eventType {
+			events = append(events, evt)
+		}
+	}
+	return events
+}
+
+type memorySessions struct {
+	ref sessionevents.SessionRef
+	err error
+}
+
+func (s memorySessions) LookupSessionByID(context.Context, string) (sessionevents.SessionRef, error) {
+	return s.ref, s.err
+}
+
+type memorySnapshotStore struct {
+	snapshot remote.PullRequestSnapshot
+}
+
+func (s memorySnapshotStore) Load(context.Context, string) (remote.PullRequestSnapshot, bool, error) {
+	return s.snapshot, true, nil
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a local HTTP endpoint answering with statuses in order and
+// 204 once they run out.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	r.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+func newTestSystem(t *testing.T, endpoint conf.WebhookEndpointConfig, statuses ...int) (*System, *memoryEventLog, *memoryEventLog, *receiver, *[]time.Duration) {
+	t.Helper()
+	recv := &receiver{statuses: statuses}
+	server := httptest.NewServer(recv)
+	t.Cleanup(server.Close)
+
+	endpoint.URL = server.URL
+	if endpoint.Name == "" {
+		endpoint.Name = "bot"
+	}
+	if len(endpoint.Events) == 0 {
+		endpoint.Events = []string{"session.ready", "session.*.failed", "pr.merged"}
+	}
+	if endpoint.MaxAttempts == 0 {
+		endpoint.MaxAttempts = 3
+	}
+	if endpoint.Timeout == 0 {
+		endpoint.Timeout = 5
+	}
+
+	sessionsLog := &memoryEventLog{}
+	prLog := &memoryEventLog{}
+	sessions := memorySessions{ref: sessionevents.SessionRef{StreamID: "session-1", Branch: "feature", RepoPath: "/repos/app", RemoteURL: "git@github.com:owner/app.git", PublicState: sessionevents.PublicStateActiveIdle}}
+	snapshots := memorySnapshotStore{snapshot: remote.PullRequestSnapshot{Provider: "github", Number: 7, State: "merged", HeadRef: "feature", RemoteURL: "git@github.com:owner/app.git"}}
+	system := New(sessionsLog, prLog, sessions, snapshots, conf.WebhooksConfig{Endpoints: []conf.WebhookEndpointConfig{endpoint}}, nil)
+	slept := &[]time.Duration{}
+	system.sleep = func(_ context.Context, d time.Duration) error {
+		*slept = append(*slept, d)
+		return nil
+	}
+	system.now = func() time.Time { return time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC) }
+	return system, sessionsLog, prLog, recv, slept
+}
+
+func appendEvent(t *testing.T, log *memoryEventLog, streamID string, eventType eventlog.EventType, payload string) eventlog.Envelope {
+	t.Helper()
+	evt, err := log.Append(context.Background(), eventlog.PendingEvent{StreamID: eventlog.StreamID(streamID), Type: eventType, Payload: []byte(payload)})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return evt
+}
+
+func TestSessionEventIsDeliveredWithSessionRef(t *testing.T) {
+	system, sessionsLog, _, recv, _ := newTestSystem(t, conf.WebhookEndpointConfig{})
+	evt := appendEvent(t, sessionsLog, "session-1", eventtypes.SessionReady, `{"branch":"feature"}`)
+
+	if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+
+	if len(recv.requests) != 1 {
+		t.Fatalf("expected one request, got %d", len(recv.requests))
+	}
+	req := recv.requests[0]
+	if req.header.Get("X-Droner-Event") != string(eventtypes.SessionReady) || req.header.Get("X-Droner-Delivery") != string(evt.ID) {
+		t.Fatalf("unexpected headers: %v", req.header)
+	}
+	if req.header.Get("X-Droner-Signature-256") != "" {
+		t.Fatal("expected an unsigned delivery without a secret")
+	}
+	var body deliveryPayload
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if body.Topic != topicSessions || body.Branch != "feature" || body.Repo.Path != "/repos/app" || body.Session == nil || body.Session.PublicState != "active.idle" {
+		t.Fatalf("unexpected body: %s", req.body)
+	}
+	if string(body.Payload) != `{"branch":"feature"}` {
+		t.Fatalf("unexpected event payload: %s", body.Payload)
+	}
+
+	records := sessionsLog.ofType(eventtypes.SessionWebhookDelivered)
+	if len(records) != 1 || records[0].CausationID != evt.ID {
+		t.Fatalf("expected one delivery record caused by %s, got %#v", evt.ID, records)
+	}
+}
+
+func TestDeliveryIsSignedWithSecret(t *testing.T) {
+	system, sessionsLog, _, recv, _ := newTestSystem(t, conf.WebhookEndpointConfig{Secret: "s3cret"})
+	evt := appendEvent(t, sessionsLog, "session-1", eventtypes.SessionReady, `{}`)
+
+	if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	req := recv.requests[0]
+	if !remote.VerifyGitHubWebhookSignature("s3cret", req.body, req.header.Get("X-Droner-Signature-256")) {
+		t.Fatalf("signature %q does not verify", req.header.Get("X-Droner-Signature-256"))
+	}
+}
+
+func TestDeliveryRetriesWithBackoff(t *testing.T) {
+	system, sessionsLog, _, recv, slept := newTestSystem(t, conf.WebhookEndpointConfig{MaxAttempts: 4}, http.StatusBadGateway, http.StatusTooManyRequests)
+	evt := appendEvent(t, sessionsLog, "session-1", eventtypes.SessionEnvironmentProvisioningFailed, `{"error":"boom"}`)
+
+	if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(recv.requests) != 3 {
+		t.Fatalf("expected three attempts, got %d", len(recv.requests))
+	}
+	if len(*slept) != 2 || (*slept)[0] != time.Second || (*slept)[1] != 2*time.Second {
+		t.Fatalf("unexpected backoff: %v", *slept)
+	}
+	var record deliveryRecord
+	records := sessionsLog.ofType(eventtypes.SessionWebhookDelivered)
+	if len(records) != 1 {
+		t.Fatalf("expected a delivered record, got %#v", sessionsLog.events)
+	}
+	if err := json.Unmarshal(records[0].Payload, &record); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if record.Attempts != 3 || record.StatusCode != http.StatusNoContent || record.Error != "" {
+		t.Fatalf("unexpected record: %#v", record)
+	}
+}
+
+func TestDeliveryGivesUpAfterMaxAttempts(t *testing.T) {
+	system, sessionsLog, _, recv, _ := newTestSystem(t, conf.WebhookEndpointConfig{MaxAttempts: 2}, http.StatusInternalServerError, http.StatusInternalServerError)
+	evt := appendEvent(t, sessionsLog, "session-1", eventtypes.SessionReady, `{}`)
+
+	if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(recv.requests) != 2 {
+		t.Fatalf("expected two attempts, got %d", len(recv.requests))
+	}
+	if len(sessionsLog.ofType(eventtypes.SessionWebhookFailed)) != 1 {
+		t.Fatalf("expected a failed record, got %#v", sessionsLog.events)
+	}
+
+	if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(recv.requests) != 2 {
+		t.Fatalf("expected a redelivered event not to be posted again, got %d requests", len(recv.requests))
+	}
+}
+
+func TestDeliveryDoesNotRetryClientErrors(t *testing.T) {
+	system, sessionsLog, _, recv, _ := newTestSystem(t, conf.WebhookEndpointConfig{}, http.StatusUnauthorized)
+	evt := appendEvent(t, sessionsLog, "session-1", eventtypes.SessionReady, `{}`)
+
+	if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(recv.requests) != 1 || len(sessionsLog.ofType(eventtypes.SessionWebhookFailed)) != 1 {
+		t.Fatalf("expected one attempt and a failed record, got %d requests", len(recv.requests))
+	}
+}
+
+func TestPullRequestEventCarriesBranchAndRepo(t *testing.T) {
+	system, _, prLog, recv, _ := newTestSystem(t, conf.WebhookEndpointConfig{})
+	evt := appendEvent(t, prLog, "github:owner/app#7", eventtypes.PRMerged, `{"number":7}`)
+
+	if err := system.handlePullRequestEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handlePullRequestEvent: %v", err)
+	}
+	var body deliveryPayload
+	if err := json.Unmarshal(recv.requests[0].body, &body); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if body.Topic != topicPullRequests || body.Branch != "feature" || body.Repo.RemoteURL != "git@github.com:owner/app.git" || body.PullRequest == nil || body.PullRequest.Number != 7 {
+		t.Fatalf("unexpected body: %s", recv.requests[0].body)
+	}
+	if len(prLog.ofType(eventtypes.PRWebhookDelivered)) != 1 {
+		t.Fatalf("expected the record on the PR stream, got %#v", prLog.events)
+	}
+}
+
+func TestEventFiltersSkipUnmatchedAndRecordEvents(t *testing.T) {
+	system, _, _, _, _ := newTestSystem(t, conf.WebhookEndpointConfig{Events: []string{"*"}})
+
+	for _, record := range []eventlog.EventType{eventtypes.SessionWebhookDelivered, eventtypes.PRWebhookFailed, eventtypes.SessionHookFired, eventtypes.PRHookFired, eventtypes.SessionPRCIFeedbackSent} {
+		if system.wants(eventlog.Envelope{Type: record}) {
+			t.Fatalf("expected %s records never to be delivered", record)
+		}
+	}
+	if !system.wants(eventlog.Envelope{Type: eventtypes.SessionAgentBusy}) {
+		t.Fatal("expected * to match every other event")
+	}
+
+	system, _, _, _, _ = newTestSystem(t, conf.WebhookEndpointConfig{})
+	if system.wants(eventlog.Envelope{Type: eventtypes.SessionAgentBusy}) {
+		t.Fatal("expected unmatched events to be skipped")
+	}
+}
+
+func TestDeliveryRecordIsFoundPastOnePage(t *testing.T) {
+	system, sessionsLog, _, recv, _ := newTestSystem(t, conf.WebhookEndpointConfig{})
+	for i := 0; i < 600; i++ {
+		appendEvent(t, sessionsLog, "session-1", eventtypes.SessionAgentBusy, `{}`)
+	}
+	evt := appendEvent(t, sessionsLog, "session-1", eventtypes.SessionReady, `{}`)
+
+	for i := 0; i < 2; i++ {
+		if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+			t.Fatalf("handleSessionEvent: %v", err)
+		}
+	}
+	if len(recv.requests) != 1 {
+		t.Fatalf("expected the redelivered event to be skipped, got %d requests", len(recv.requests))
+	}
+}
+
+func TestMissingSessionStillDelivers(t *testing.T) {
+	system, sessionsLog, _, recv, _ := newTestSystem(t, conf.WebhookEndpointConfig{})
+	system.sessions = memorySessions{err: sql.ErrNoRows}
+	evt := appendEvent(t, sessionsLog, "session-1", eventtypes.SessionReady, `{}`)
+
+	if err := system.handleSessionEvent(context.Background(), evt); err != nil {
+		t.Fatalf("handleSessionEvent: %v", err)
+	}
+	if len(recv.requests) != 1 {
+		t.Fatalf("expected a delivery without a session ref, got %d", len(recv.requests))
+	}
+}
@@ -10,6 +10,14 @@ type GitHubAuth struct {
 	AccessToken string `json:"access_token" zog:"access_token"`
 }
 
+type GitLabAuth struct {
+	AccessToken string `json:"access_token" zog:"access_token"`
+}
+
+type GiteaAuth struct {
+	AccessToken string `json:"access_token" zog:"access_token"`
+}
+
 type Store struct{}
 
 var lookPath = exec.LookPath
@@ -54,3 +62,61 @@ func ghAuthToken() (string, bool) {
 
 	return token, true
 }
+
+const gitLabPublicHost = "gitlab.com"
+
+// GitLab resolves a token for the GitLab instance at host from the glab CLI
+// configuration for that host. GITLAB_TOKEN applies to gitlab.com only so a
+// public token is never sent to a self-managed instance.
+func (s *Store) GitLab(host string) (*GitLabAuth, bool) {
+	_ = s
+
+	if host == gitLabPublicHost {
+		if token := strings.TrimSpace(os.Getenv("GITLAB_TOKEN")); token != "" {
+			return &GitLabAuth{AccessToken: token}, true
+		}
+	}
+
+	if token, ok := glabAuthToken(host); ok {
+		return &GitLabAuth{AccessToken: token}, true
+	}
+
+	return nil, false
+}
+
+func glabAuthToken(host string) (string, bool) {
+	if _, err := lookPath("glab"); err != nil {
+		return "", false
+	}
+
+	output, err := execCommand("glab", "config", "get", "token", "--host", host).Output()
+	if err != nil {
+		return "", false
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", false
+	}
+
+	return token, true
+}
+
+// Gitea resolves a token for a Gitea or Forgejo instance from the host's own
+// environment variable. Only hosts without a tokenEnv fall back to GITEA_TOKEN
+// and then FORGEJO_TOKEN.
+func (s *Store) Gitea(tokenEnv string) (*GiteaAuth, bool) {
+	_ = s
+
+	names := []string{"GITEA_TOKEN", "FORGEJO_TOKEN"}
+	if tokenEnv != "" {
+		names = []string{tokenEnv}
+	}
+	for _, name := range names {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return &GiteaAuth{AccessToken: token}, true
+		}
+	}
+
+	return nil, false
+}
@@ -152,3 +152,126 @@ func TestGitHubReturnsEmptyWhenGhAuthTokenFails(t *testing.T) {
 		t.Fatalf("expected no github auth, got ok=%v auth=%+v", ok, githubAuth)
 	}
 }
+
+func TestGitLabUsesEnvTokenFirst(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "env-token")
+
+	originalLookPath := lookPath
+	lookPath = func(file string) (string, error) {
+		t.Fatalf("unexpected lookPath call for %q", file)
+		return "", nil
+	}
+	t.Cleanup(func() {
+		lookPath = originalLookPath
+	})
+
+	store, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	gitlabAuth, ok := store.GitLab("gitlab.com")
+	if !ok || gitlabAuth.AccessToken != "env-token" {
+		t.Fatalf("expected env token, got ok=%v auth=%+v", ok, gitlabAuth)
+	}
+}
+
+func TestGitLabFallsBackToGlabConfigToken(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "")
+
+	originalLookPath := lookPath
+	originalExecCommand := execCommand
+	lookPath = func(file string) (string, error) {
+		if file != "glab" {
+			t.Fatalf("lookPath file = %q, want %q", file, "glab")
+		}
+		return "/usr/bin/glab", nil
+	}
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		want := []string{"config", "get", "token", "--host", "gitlab.example.com"}
+		if name != "glab" || len(args) != len(want) {
+			t.Fatalf("command = %q %q, want glab %q", name, args, want)
+		}
+		for i := range want {
+			if args[i] != want[i] {
+				t.Fatalf("command args = %q, want %q", args, want)
+			}
+		}
+		return exec.Command("sh", "-c", "printf 'glab-token\n'")
+	}
+	t.Cleanup(func() {
+		lookPath = originalLookPath
+		execCommand = originalExecCommand
+	})
+
+	store, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	gitlabAuth, ok := store.GitLab("gitlab.example.com")
+	if !ok || gitlabAuth.AccessToken != "glab-token" {
+		t.Fatalf("expected glab token, got ok=%v auth=%+v", ok, gitlabAuth)
+	}
+}
+
+func TestGitLabIgnoresEnvTokenForSelfManagedHosts(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "env-token")
+
+	originalLookPath := lookPath
+	originalExecCommand := execCommand
+	lookPath = func(file string) (string, error) {
+		return "/usr/bin/glab", nil
+	}
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "exit 1")
+	}
+	t.Cleanup(func() {
+		lookPath = originalLookPath
+		execCommand = originalExecCommand
+	})
+
+	store, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	if gitlabAuth, ok := store.GitLab("gitlab.example.com"); ok {
+		t.Fatalf("expected no gitlab auth for self-managed host, got %+v", gitlabAuth)
+	}
+}
+
+func TestGiteaPrefersHostTokenEnv(t *testing.T) {
+	t.Setenv("INTERNAL_FORGEJO_TOKEN", "host-token")
+	t.Setenv("GITEA_TOKEN", "gitea-token")
+	t.Setenv("FORGEJO_TOKEN", "forgejo-token")
+
+	store, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	giteaAuth, ok := store.Gitea("INTERNAL_FORGEJO_TOKEN")
+	if !ok || giteaAuth.AccessToken != "host-token" {
+		t.Fatalf("Gitea(host env) = %#v, %v; want host-token", giteaAuth, ok)
+	}
+	giteaAuth, ok = store.Gitea("")
+	if !ok || giteaAuth.AccessToken != "gitea-token" {
+		t.Fatalf("Gitea(\"\") = %#v, %v; want gitea-token", giteaAuth, ok)
+	}
+
+	if giteaAuth, ok := store.Gitea("UNSET_TOKEN_ENV"); ok {
+		t.Fatalf("Gitea(unset env) = %#v; want no fallback to global tokens", giteaAuth)
+	}
+
+	t.Setenv("GITEA_TOKEN", "")
+	giteaAuth, ok = store.Gitea("")
+	if !ok || giteaAuth.AccessToken != "forgejo-token" {
+		t.Fatalf("Gitea(\"\") = %#v, %v; want forgejo-token", giteaAuth, ok)
+	}
+
+	t.Setenv("FORGEJO_TOKEN", "")
+	if _, ok := store.Gitea(""); ok {
+		t.Fatal("expected no gitea auth without tokens")
+	}
+}
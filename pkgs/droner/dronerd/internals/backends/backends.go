@@ -18,10 +18,14 @@ type Backend interface {
 	// CompleteSession stops the active session runtime (e.g. tmux/opencode) but keeps the worktree/branch for reuse.
 	CompleteSession(ctx context.Context, worktreePath string, sessionID string) error
 	DeleteSession(ctx context.Context, worktreePath string, sessionID string) error
+	// SendMessage prompts the agent already running in worktreePath with a follow-up message.
+	SendMessage(ctx context.Context, worktreePath string, agentConfig AgentConfig, message *messages.Message) error
 }
 
 var ErrUnknownBackend = errors.New("unknown backend")
 
+var ErrNoAgentSession = errors.New("no agent session in worktree")
+
 type Store struct {
 	mu       sync.RWMutex
 	backends map[conf.BackendID]Backend
@@ -61,6 +65,9 @@ type CreateSessionOptions struct {
 	LookupWorktreeSession        func(ctx context.Context, worktreePath string) (*WorktreeSessionRef, error)
 	CurrentStreamID              string
 	MarkReusableWorktreeDeletion func(candidate ReusableWorktreeCandidate)
+	// RecordBaseBranch receives the branch the session's work is based on,
+	// which pull requests and diffs of the session target.
+	RecordBaseBranch func(branch string)
 }
 
 type HydrationResult struct {
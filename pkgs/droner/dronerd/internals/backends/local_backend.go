@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/db"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/repo"
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
 	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
@@ -155,6 +156,9 @@ func (l LocalBackend) CreateSession(ctx context.Context, repoPath string, worktr
 			return err
 		}
 	}
+	if createOpts.RecordBaseBranch != nil {
+		createOpts.RecordBaseBranch(repo.BranchFromRef(branchState.baseRef))
+	}
 	if err := l.runCursorWorktreeSetup(repoPath, worktreePath, sessionID); err != nil {
 		return err
 	}
@@ -210,6 +214,8 @@ func (l LocalBackend) CreateSession(ctx context.Context, repoPath string, worktr
 type localBranchState struct {
 	localExists bool
 	remoteRef   string
+	// baseRef is the ref a new session branch starts from.
+	baseRef string
 }
 
 func (l LocalBackend) prepareSessionWorktree(ctx context.Context, repoPath string, worktreePath string, sessionID string, createOpts CreateSessionOptions, branchState localBranchState, targetWorktreeExisted bool) (bool, *ReusableWorktreeCandidate, error) {
@@ -376,11 +382,7 @@ func (l LocalBackend) createGitWorktree(repoPath string, worktreePath string, br
 
 	baseRef := branchState.remoteRef
 	if baseRef == "" {
-		var err error
-		baseRef, err = l.resolveBaseRef(repoPath)
-		if err != nil {
-			return err
-		}
+		baseRef = branchState.baseRef
 	}
 
 	cmd := execCommand("git", "-C", repoPath, "worktree", "add", "-b", branchName, worktreePath, baseRef)
@@ -407,17 +409,13 @@ func (l LocalBackend) prepareWorktreeForBranch(repoPath string, worktreePath str
 	}
 	baseRef := branchState.remoteRef
 	if baseRef == "" {
-		var err error
-		baseRef, err = l.resolveBaseRef(repoPath)
-		if err != nil {
-			return err
-		}
+		baseRef = branchState.baseRef
 	}
 	return l.checkoutNewBranch(worktreePath, branchName, baseRef)
 }
 
 func (l LocalBackend) resolveBranchState(repoPath string, branchName string) (localBranchState, error) {
-	state := localBranchState{}
+	state := localBranchState{baseRef: repo.BaseRef(repoPath)}
 	localExists, err := l.gitRefExists(repoPath, "refs/heads/"+branchName)
 	if err != nil {
 		return state, err
@@ -851,6 +849,17 @@ func localFileURL(path string) (string, error) {
 	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}).String(), nil
 }
 
+func (l LocalBackend) SendMessage(ctx context.Context, worktreePath string, agentConfig AgentConfig, message *messages.Message) error {
+	opencodeSessionID, err := l.latestOpencodeSessionID(ctx, agentConfig.Opencode, worktreePath)
+	if err != nil {
+		return err
+	}
+	if opencodeSessionID == "" {
+		return ErrNoAgentSession
+	}
+	return l.sendOpencodeMessage(ctx, agentConfig.Opencode, opencodeSessionID, worktreePath, agentConfig.Model, agentConfig.AgentName, message)
+}
+
 func (l LocalBackend) createOpencodeSession(ctx context.Context, config conf.OpenCodeConfig, worktreePath string) (string, error) {
 	return newOpencodeClient(config).CreateSession(ctx, worktreePath)
 }
@@ -921,25 +930,3 @@ func (l LocalBackend) checkoutExistingBranch(worktreePath string, branchName str
 	}
 	return nil
 }
-
-func (l LocalBackend) resolveBaseRef(repoPath string) (string, error) {
-	symbolic := execCommand("git", "-C", repoPath, "symbolic-ref", "refs/remotes/origin/HEAD")
-	if output, err := symbolic.CombinedOutput(); err == nil {
-		ref := strings.TrimSpace(string(output))
-		if ref != "" {
-			return ref, nil
-		}
-	}
-	for _, ref := range []string{
-		"refs/remotes/origin/main",
-		"refs/remotes/origin/master",
-		"refs/heads/main",
-		"refs/heads/master",
-	} {
-		check := execCommand("git", "-C", repoPath, "show-ref", "--verify", "--quiet", ref)
-		if err := check.Run(); err == nil {
-			return ref, nil
-		}
-	}
-	return "HEAD", nil
-}
@@ -0,0 +1,83 @@
+package backends
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	opencode "github.com/sst/opencode-sdk-go"
+	"github.com/sst/opencode-sdk-go/option"
+)
+
+type OpencodeAgent struct {
+	Name        string
+	Description string
+}
+
+// OpencodeModel is identified as "<provider>/<model>", the form session
+// agent configs take.
+type OpencodeModel struct {
+	ID           string
+	Name         string
+	ProviderName string
+}
+
+type OpencodeCatalog struct {
+	Agents []OpencodeAgent
+	Models []OpencodeModel
+}
+
+// LoadOpencodeCatalog asks a running opencode server which agents and models
+// it offers for directory. Subagents are left out since they cannot start a
+// session.
+func LoadOpencodeCatalog(ctx context.Context, config conf.OpenCodeConfig, directory string) (OpencodeCatalog, error) {
+	return newOpencodeClient(config).Catalog(ctx, directory)
+}
+
+func (c *opencodeClient) Catalog(ctx context.Context, directory string) (OpencodeCatalog, error) {
+	agentParams := opencode.AgentListParams{}
+	providerParams := opencode.AppProvidersParams{}
+	if strings.TrimSpace(directory) != "" {
+		agentParams.Directory = opencode.F(directory)
+		providerParams.Directory = opencode.F(directory)
+	}
+	agents, err := c.sdk.Agent.List(ctx, agentParams, option.WithRequestTimeout(timeouts.SecondShort))
+	if err != nil {
+		return OpencodeCatalog{}, err
+	}
+	providers, err := c.sdk.App.Providers(ctx, providerParams, option.WithRequestTimeout(timeouts.SecondShort))
+	if err != nil {
+		return OpencodeCatalog{}, err
+	}
+
+	catalog := OpencodeCatalog{}
+	if agents != nil {
+		for _, agent := range *agents {
+			if agent.Mode == opencode.AgentModeSubagent {
+				continue
+			}
+			catalog.Agents = append(catalog.Agents, OpencodeAgent{Name: agent.Name, Description: agent.Description})
+		}
+	}
+	if providers != nil {
+		for _, provider := range providers.Providers {
+			providerName := provider.Name
+			if providerName == "" {
+				providerName = provider.ID
+			}
+			for id, model := range provider.Models {
+				name := model.Name
+				if name == "" {
+					name = id
+				}
+				catalog.Models = append(catalog.Models, OpencodeModel{ID: provider.ID + "/" + id, Name: name, ProviderName: providerName})
+			}
+		}
+	}
+	sort.Slice(catalog.Models, func(i int, j int) bool {
+		return catalog.Models[i].ID < catalog.Models[j].ID
+	})
+	return catalog, nil
+}
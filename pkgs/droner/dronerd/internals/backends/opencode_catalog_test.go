@@ -0,0 +1,57 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadOpencodeCatalogListsPrimaryAgentsAndModels(t *testing.T) {
+	repoDir := t.TempDir()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/agent", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("directory"); got != repoDir {
+			t.Fatalf("directory query = %q, want %q", got, repoDir)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"name": "build", "mode": "primary", "builtIn": true, "options": map[string]any{}, "permission": map[string]any{}, "tools": map[string]any{}},
+			{"name": "general", "mode": "subagent", "builtIn": true, "options": map[string]any{}, "permission": map[string]any{}, "tools": map[string]any{}},
+			{"name": "review", "mode": "all", "description": "Reviews changes", "builtIn": false, "options": map[string]any{}, "permission": map[string]any{}, "tools": map[string]any{}},
+		})
+	})
+	mux.HandleFunc("/config/providers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"default": map[string]string{"openai": "gpt-5.5"},
+			"providers": []map[string]any{{
+				"id":   "openai",
+				"name": "OpenAI",
+				"env":  []string{"OPENAI_API_KEY"},
+				"models": map[string]any{
+					"gpt-5.5":      map[string]any{"id": "gpt-5.5", "name": "GPT-5.5"},
+					"gpt-5.5-mini": map[string]any{"id": "gpt-5.5-mini", "name": "GPT-5.5 mini"},
+				},
+			}},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	catalog, err := LoadOpencodeCatalog(context.Background(), opencodeConfigFromServer(t, srv), repoDir)
+	if err != nil {
+		t.Fatalf("LoadOpencodeCatalog: %v", err)
+	}
+	if len(catalog.Agents) != 2 || catalog.Agents[0].Name != "build" || catalog.Agents[1].Name != "review" || catalog.Agents[1].Description != "Reviews changes" {
+		t.Fatalf("agents = %#v, want build and review", catalog.Agents)
+	}
+	want := []OpencodeModel{
+		{ID: "openai/gpt-5.5", Name: "GPT-5.5", ProviderName: "OpenAI"},
+		{ID: "openai/gpt-5.5-mini", Name: "GPT-5.5 mini", ProviderName: "OpenAI"},
+	}
+	if len(catalog.Models) != len(want) || catalog.Models[0] != want[0] || catalog.Models[1] != want[1] {
+		t.Fatalf("models = %#v, want %#v", catalog.Models, want)
+	}
+}
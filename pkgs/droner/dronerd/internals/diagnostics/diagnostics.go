@@ -0,0 +1,126 @@
+// Package diagnostics checks the tools and settings sessions depend on, so
+// setup problems show up with a fix instead of as failed sessions.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/auth"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+)
+
+const (
+	CheckTmux           = "tmux"
+	CheckOpencode       = "opencode"
+	CheckOpencodeServer = "opencode server"
+	CheckGitHubToken    = "github token"
+	CheckWorktreeDir    = "worktree dir"
+)
+
+var lookPath = exec.LookPath
+
+var hasGitHubToken = func() bool {
+	store, err := auth.Default()
+	if err != nil {
+		return false
+	}
+	_, ok := store.GitHub()
+	return ok
+}
+
+// EnvironmentChecks runs the checks that only depend on the machine and the
+// config, in the order they are printed.
+func EnvironmentChecks(ctx context.Context, config *conf.Config) []schemas.DiagnosticCheck {
+	opencode := checkBinary(CheckOpencode, "opencode", "Install opencode (https://opencode.ai/docs) and make sure it is on the PATH the droner daemon starts with")
+	return []schemas.DiagnosticCheck{
+		checkBinary(CheckTmux, "tmux", "Install tmux (e.g. `brew install tmux` or `apt install tmux`) and make sure it is on the PATH the droner daemon starts with"),
+		opencode,
+		checkOpencodeServer(ctx, config.Sessions.Harness.Providers.OpenCode, opencode.Status == schemas.DiagnosticStatusOK),
+		checkGitHubToken(),
+		checkWorktreeDir(config.Sessions.Backends.Local.WorktreeDir),
+	}
+}
+
+func checkBinary(name string, binary string, fix string) schemas.DiagnosticCheck {
+	path, err := lookPath(binary)
+	if err != nil {
+		return schemas.DiagnosticCheck{Name: name, Status: schemas.DiagnosticStatusFail, Detail: binary + " not found on PATH", Fix: fix}
+	}
+	return schemas.DiagnosticCheck{Name: name, Status: schemas.DiagnosticStatusOK, Detail: path}
+}
+
+// checkOpencodeServer only warns when the server is down: the local backend
+// starts it for the next session as long as the binary is installed.
+func checkOpencodeServer(ctx context.Context, config conf.OpenCodeConfig, installed bool) schemas.DiagnosticCheck {
+	address := fmt.Sprintf("%s:%d", config.Hostname, config.Port)
+	check := schemas.DiagnosticCheck{Name: CheckOpencodeServer}
+
+	ctx, cancel := context.WithTimeout(ctx, timeouts.SecondShort)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+"/global/health", nil)
+	if err == nil {
+		var resp *http.Response
+		resp, err = http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				check.Status = schemas.DiagnosticStatusOK
+				check.Detail = "healthy at " + address
+				return check
+			}
+			err = fmt.Errorf("health check returned %s", resp.Status)
+		}
+	}
+
+	check.Detail = fmt.Sprintf("unreachable at %s: %v", address, err)
+	if installed {
+		check.Status = schemas.DiagnosticStatusWarn
+		check.Fix = fmt.Sprintf("droner starts it with the next session. To check it by hand run `opencode serve --hostname %s --port %d`, or point sessions.harness.providers.opencode.hostname/port in droner.json at a running server", config.Hostname, config.Port)
+		return check
+	}
+	check.Status = schemas.DiagnosticStatusFail
+	check.Fix = "Install opencode first; droner starts the server with the next session"
+	return check
+}
+
+// checkGitHubToken warns because only sessions on GitHub remotes need it.
+func checkGitHubToken() schemas.DiagnosticCheck {
+	if hasGitHubToken() {
+		return schemas.DiagnosticCheck{Name: CheckGitHubToken, Status: schemas.DiagnosticStatusOK, Detail: "found in GITHUB_TOKEN or gh auth"}
+	}
+	return schemas.DiagnosticCheck{
+		Name:   CheckGitHubToken,
+		Status: schemas.DiagnosticStatusWarn,
+		Detail: "no GITHUB_TOKEN and no `gh auth token`",
+		Fix:    "Export GITHUB_TOKEN for the droner daemon or run `gh auth login`; pull requests and CI feedback on GitHub need it",
+	}
+}
+
+func checkWorktreeDir(dir string) schemas.DiagnosticCheck {
+	check := schemas.DiagnosticCheck{Name: CheckWorktreeDir, Detail: dir}
+	fix := fmt.Sprintf("Make %s writable, or set sessions.backends.local.worktreeDir in droner.json to a writable directory", dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		check.Status = schemas.DiagnosticStatusFail
+		check.Detail = fmt.Sprintf("cannot create %s: %v", dir, err)
+		check.Fix = fix
+		return check
+	}
+	probe, err := os.CreateTemp(dir, ".droner-doctor-*")
+	if err != nil {
+		check.Status = schemas.DiagnosticStatusFail
+		check.Detail = fmt.Sprintf("cannot write to %s: %v", dir, err)
+		check.Fix = fix
+		return check
+	}
+	probe.Close()
+	_ = os.Remove(filepath.Clean(probe.Name()))
+	check.Status = schemas.DiagnosticStatusOK
+	return check
+}
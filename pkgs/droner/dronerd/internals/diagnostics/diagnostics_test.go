@@ -0,0 +1,121 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+func stubEnvironment(t *testing.T, binaries map[string]bool, githubToken bool) {
+	t.Helper()
+	origLookPath := lookPath
+	origGitHubToken := hasGitHubToken
+	lookPath = func(file string) (string, error) {
+		if binaries[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+	hasGitHubToken = func() bool { return githubToken }
+	t.Cleanup(func() {
+		lookPath = origLookPath
+		hasGitHubToken = origGitHubToken
+	})
+}
+
+func testConfig(t *testing.T, opencodeAddr string) *conf.Config {
+	t.Helper()
+	host, portValue, err := net.SplitHostPort(opencodeAddr)
+	if err != nil {
+		t.Fatalf("split %q: %v", opencodeAddr, err)
+	}
+	port, err := strconv.Atoi(portValue)
+	if err != nil {
+		t.Fatalf("port %q: %v", portValue, err)
+	}
+	config := &conf.Config{}
+	config.Sessions.Harness.Providers.OpenCode = conf.OpenCodeConfig{Hostname: host, Port: port}
+	config.Sessions.Backends.Local.WorktreeDir = filepath.Join(t.TempDir(), "worktrees")
+	return config
+}
+
+func checksByName(checks []schemas.DiagnosticCheck) map[string]schemas.DiagnosticCheck {
+	byName := map[string]schemas.DiagnosticCheck{}
+	for _, check := range checks {
+		byName[check.Name] = check
+	}
+	return byName
+}
+
+func TestEnvironmentChecksPassWithHealthyOpencode(t *testing.T) {
+	stubEnvironment(t, map[string]bool{"tmux": true, "opencode": true}, true)
+	opencode := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/global/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer opencode.Close()
+
+	checks := EnvironmentChecks(context.Background(), testConfig(t, opencode.Listener.Addr().String()))
+	if len(checks) != 5 {
+		t.Fatalf("checks = %#v", checks)
+	}
+	for _, check := range checks {
+		if check.Status != schemas.DiagnosticStatusOK || check.Fix != "" {
+			t.Fatalf("expected %s to pass, got %#v", check.Name, check)
+		}
+	}
+}
+
+func TestEnvironmentChecksExplainMissingTools(t *testing.T) {
+	stubEnvironment(t, map[string]bool{"opencode": true}, false)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	config := testConfig(t, addr)
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// A file in the way makes the worktree dir impossible to create.
+	config.Sessions.Backends.Local.WorktreeDir = filepath.Join(blocker, "worktrees")
+
+	checks := checksByName(EnvironmentChecks(context.Background(), config))
+	want := map[string]schemas.DiagnosticStatus{
+		CheckTmux:           schemas.DiagnosticStatusFail,
+		CheckOpencode:       schemas.DiagnosticStatusOK,
+		CheckOpencodeServer: schemas.DiagnosticStatusWarn,
+		CheckGitHubToken:    schemas.DiagnosticStatusWarn,
+		CheckWorktreeDir:    schemas.DiagnosticStatusFail,
+	}
+	for name, status := range want {
+		check := checks[name]
+		if check.Status != status {
+			t.Fatalf("%s status = %q, want %q (%#v)", name, check.Status, status, check)
+		}
+		if status != schemas.DiagnosticStatusOK && check.Fix == "" {
+			t.Fatalf("%s has no fix: %#v", name, check)
+		}
+	}
+
+	stubEnvironment(t, map[string]bool{}, true)
+	checks = checksByName(EnvironmentChecks(context.Background(), config))
+	if checks[CheckOpencodeServer].Status != schemas.DiagnosticStatusFail {
+		t.Fatalf("expected the opencode server to fail without the binary, got %#v", checks[CheckOpencodeServer])
+	}
+}
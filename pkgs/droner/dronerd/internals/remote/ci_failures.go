@@ -0,0 +1,77 @@
+package remote
+
+import (
+	"context"
+	"strings"
+)
+
+// CIFailure is one failing check on a pull request head commit.
+type CIFailure struct {
+	Name      string
+	TargetURL string
+	// Summary is the check's own output when the provider exposes it, or its
+	// status description otherwise.
+	Summary string
+}
+
+// ciFailureFetcher is implemented by providers that expose check output.
+type ciFailureFetcher interface {
+	fetchCIFailures(ctx context.Context, key subscriptionKey, snapshot PullRequestSnapshot) ([]CIFailure, error)
+}
+
+// FetchCIFailures returns the failing checks for the head commit of snapshot.
+// Providers that cannot fetch check output fall back to the status
+// descriptions already recorded on the snapshot.
+func FetchCIFailures(ctx context.Context, snapshot PullRequestSnapshot) ([]CIFailure, error) {
+	key := subscriptionKey{remoteURL: strings.TrimSpace(snapshot.RemoteURL), branch: strings.TrimSpace(snapshot.HeadRef)}
+	p, ok := getRegistry().providerForKey(key)
+	if !ok || strings.TrimSpace(snapshot.HeadSHA) == "" {
+		return SnapshotCIFailures(snapshot), nil
+	}
+	fetcher, ok := p.(ciFailureFetcher)
+	if !ok {
+		return SnapshotCIFailures(snapshot), nil
+	}
+	if err := p.ensureAuth(key); err != nil {
+		return nil, err
+	}
+	return fetcher.fetchCIFailures(ctx, key, snapshot)
+}
+
+// SnapshotCIFailures lists the failing statuses recorded on snapshot, with their
+// descriptions as summaries.
+func SnapshotCIFailures(snapshot PullRequestSnapshot) []CIFailure {
+	failures := []CIFailure{}
+	for _, status := range snapshot.CI.Statuses {
+		if status.State != "failing" {
+			continue
+		}
+		failures = append(failures, CIFailure{Name: status.Name, TargetURL: status.TargetURL, Summary: status.Description})
+	}
+	return failures
+}
+
+// githubCIFailures keeps the failing statuses and check runs of ci, preferring
+// check run output over the one-line status description.
+func githubCIFailures(ci GitHubCIStatusResult) []CIFailure {
+	failures := []CIFailure{}
+	for _, status := range ci.CombinedStatus.Statuses {
+		if normalizeCIState(status.State) != "failing" {
+			continue
+		}
+		failures = append(failures, CIFailure{Name: strings.TrimSpace(status.Context), TargetURL: status.TargetURL, Summary: strings.TrimSpace(status.Description)})
+	}
+	for _, check := range ci.CheckRuns {
+		if check.Conclusion == nil || normalizeCIState(*check.Conclusion) != "failing" {
+			continue
+		}
+		parts := []string{}
+		for _, part := range []string{check.Output.Title, check.Output.Summary, check.Output.Text} {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		failures = append(failures, CIFailure{Name: strings.TrimSpace(check.Name), TargetURL: check.HTMLURL, Summary: strings.Join(parts, "\n\n")})
+	}
+	return failures
+}
@@ -0,0 +1,429 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/auth"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+)
+
+const (
+	giteaPullsPageSize = 50
+	giteaMaxPullPages  = 3
+)
+
+type GiteaBranchData struct {
+	BranchExists bool
+	PullRequest  *GiteaPullRequest
+}
+
+type GiteaPullRequest struct {
+	Number             int                 `json:"number"`
+	State              string              `json:"state"`
+	Title              string              `json:"title"`
+	HTMLURL            string              `json:"html_url"`
+	Draft              bool                `json:"draft"`
+	Merged             bool                `json:"merged"`
+	Mergeable          bool                `json:"mergeable"`
+	Head               GiteaPRBranch       `json:"head"`
+	Base               GiteaPRBranch       `json:"base"`
+	RequestedReviewers []GiteaUser         `json:"requested_reviewers"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	ClosedAt           *time.Time          `json:"closed_at"`
+	MergedAt           *time.Time          `json:"merged_at"`
+	Reviews            []GiteaReview       `json:"-"`
+	Status             GiteaCombinedStatus `json:"-"`
+}
+
+type GiteaPRBranch struct {
+	Ref  string           `json:"ref"`
+	SHA  string           `json:"sha"`
+	Repo *GiteaRepository `json:"repo"`
+}
+
+type GiteaRepository struct {
+	FullName string `json:"full_name"`
+}
+
+type GiteaUser struct {
+	Login string `json:"login"`
+}
+
+type GiteaReview struct {
+	User      GiteaUser `json:"user"`
+	State     string    `json:"state"`
+	Dismissed bool      `json:"dismissed"`
+}
+
+type GiteaCombinedStatus struct {
+	State    string              `json:"state"`
+	Statuses []GiteaCommitStatus `json:"statuses"`
+}
+
+type GiteaCommitStatus struct {
+	Context     string `json:"context"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
+}
+
+type GiteaSDK interface {
+	IsAuthenticated(host string) bool
+	EnsureAuth(host string) error
+	GetBranchData(ctx context.Context, remoteURL string, branch string) (GiteaBranchData, error)
+}
+
+type liveGiteaSDK struct {
+	mu           sync.Mutex
+	hosts        map[string]conf.GiteaHostConfig
+	tokens       map[string]string
+	resolveToken func(host conf.GiteaHostConfig) (string, bool)
+	httpClient   *http.Client
+}
+
+func newLiveGiteaSDK(hosts []conf.GiteaHostConfig) *liveGiteaSDK {
+	s := &liveGiteaSDK{
+		hosts:        make(map[string]conf.GiteaHostConfig, len(hosts)),
+		tokens:       map[string]string{},
+		resolveToken: resolveGiteaToken,
+		httpClient:   &http.Client{Timeout: timeouts.SecondDefault},
+	}
+	for _, host := range hosts {
+		s.hosts[host.Host] = host
+	}
+	return s
+}
+
+func (s *liveGiteaSDK) token(host string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token, ok := s.tokens[host]; ok {
+		return token
+	}
+	hostConfig, ok := s.hosts[host]
+	if !ok {
+		return ""
+	}
+	token, _ := s.resolveToken(hostConfig)
+	s.tokens[host] = token
+	return token
+}
+
+func (s *liveGiteaSDK) IsAuthenticated(host string) bool {
+	return s.token(host) != ""
+}
+
+// EnsureAuth resolves the token again when it was missing, like the GitLab SDK.
+func (s *liveGiteaSDK) EnsureAuth(host string) error {
+	s.mu.Lock()
+	if s.tokens[host] == "" {
+		delete(s.tokens, host)
+	}
+	s.mu.Unlock()
+	if !s.IsAuthenticated(host) {
+		return sdk.ErrAuthRequired
+	}
+	return nil
+}
+
+func (s *liveGiteaSDK) apiBaseURL(host string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hostConfig, ok := s.hosts[host]
+	if !ok {
+		return "", fmt.Errorf("gitea host is not configured: %s", host)
+	}
+	return hostConfig.BaseURL + "/api/v1", nil
+}
+
+func (s *liveGiteaSDK) GetBranchData(ctx context.Context, remoteURL string, branch string) (GiteaBranchData, error) {
+	host, owner, repo, err := parseGiteaRemoteURL(remoteURL)
+	if err != nil {
+		return GiteaBranchData{}, err
+	}
+	if err := s.EnsureAuth(host); err != nil {
+		return GiteaBranchData{}, err
+	}
+	baseURL, err := s.apiBaseURL(host)
+	if err != nil {
+		return GiteaBranchData{}, err
+	}
+	repoURL := baseURL + "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+
+	branchExists, err := s.fetchBranchExists(ctx, host, repoURL, branch)
+	if err != nil {
+		return GiteaBranchData{}, err
+	}
+	pullRequest, err := s.fetchPullRequestForBranch(ctx, host, repoURL, owner+"/"+repo, branch)
+	if err != nil {
+		return GiteaBranchData{}, err
+	}
+	return GiteaBranchData{BranchExists: branchExists, PullRequest: pullRequest}, nil
+}
+
+func (s *liveGiteaSDK) fetchBranchExists(ctx context.Context, host string, repoURL string, branch string) (bool, error) {
+	status, _, err := s.doGET(ctx, host, repoURL+"/branches/"+url.PathEscape(branch))
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected gitea status checking branch: %d", status)
+	}
+}
+
+// fetchPullRequestForBranch pages through recently updated pull requests
+// because the Gitea API cannot filter the list by head branch.
+func (s *liveGiteaSDK) fetchPullRequestForBranch(ctx context.Context, host string, repoURL string, fullName string, branch string) (*GiteaPullRequest, error) {
+	var matches []GiteaPullRequest
+	for page := 1; page <= giteaMaxPullPages; page++ {
+		q := url.Values{}
+		q.Set("state", "all")
+		q.Set("sort", "recentupdate")
+		q.Set("limit", strconv.Itoa(giteaPullsPageSize))
+		q.Set("page", strconv.Itoa(page))
+
+		var pulls []GiteaPullRequest
+		if err := s.getJSON(ctx, host, repoURL+"/pulls?"+q.Encode(), "listing pull requests", &pulls); err != nil {
+			return nil, err
+		}
+		for _, pull := range pulls {
+			if pull.Head.Ref != branch {
+				continue
+			}
+			if pull.Head.Repo != nil && !strings.EqualFold(pull.Head.Repo.FullName, fullName) {
+				continue
+			}
+			matches = append(matches, pull)
+		}
+		if len(matches) > 0 || len(pulls) < giteaPullsPageSize {
+			break
+		}
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	newest := newestGiteaPullRequest(matches)
+
+	pullURL := fmt.Sprintf("%s/pulls/%d", repoURL, newest.Number)
+	if err := s.getJSON(ctx, host, pullURL+"/reviews", "listing pull reviews", &newest.Reviews); err != nil {
+		return nil, err
+	}
+	if newest.Head.SHA != "" {
+		statusURL := repoURL + "/commits/" + url.PathEscape(newest.Head.SHA) + "/status"
+		if err := s.getJSON(ctx, host, statusURL, "fetching commit status", &newest.Status); err != nil {
+			return nil, err
+		}
+	}
+	return &newest, nil
+}
+
+func newestGiteaPullRequest(pulls []GiteaPullRequest) GiteaPullRequest {
+	newest := pulls[0]
+	for _, pull := range pulls[1:] {
+		if pull.UpdatedAt.After(newest.UpdatedAt) || (pull.UpdatedAt.Equal(newest.UpdatedAt) && pull.Number > newest.Number) {
+			newest = pull
+		}
+	}
+	return newest
+}
+
+func normalizeGiteaPullRequest(remoteURL string, owner string, repo string, pull *GiteaPullRequest) *PullRequestSnapshot {
+	if pull == nil {
+		return nil
+	}
+
+	reviewers := make([]string, 0, len(pull.RequestedReviewers))
+	for _, reviewer := range pull.RequestedReviewers {
+		if login := strings.TrimSpace(reviewer.Login); login != "" {
+			reviewers = append(reviewers, login)
+		}
+	}
+	sort.Strings(reviewers)
+
+	state := "open"
+	if strings.EqualFold(strings.TrimSpace(pull.State), "closed") {
+		state = "closed"
+	}
+	mergedAt := pull.MergedAt
+	if pull.Merged && mergedAt == nil {
+		mergedAt = pull.ClosedAt
+		if mergedAt == nil {
+			mergedAt = &pull.UpdatedAt
+		}
+	}
+
+	// Mergeability is only computed while the pull request is open.
+	var mergeable *bool
+	mergeableState := ""
+	if state == "open" {
+		mergeable = &pull.Mergeable
+		mergeableState = "conflict"
+		if pull.Mergeable {
+			mergeableState = "mergeable"
+		}
+	}
+
+	return &PullRequestSnapshot{
+		Provider:           "gitea",
+		RemoteURL:          remoteURL,
+		RepoOwner:          owner,
+		RepoName:           repo,
+		Number:             pull.Number,
+		State:              state,
+		Title:              pull.Title,
+		HTMLURL:            pull.HTMLURL,
+		Draft:              pull.Draft || isGiteaWorkInProgress(pull.Title),
+		HeadRef:            pull.Head.Ref,
+		HeadSHA:            pull.Head.SHA,
+		BaseRef:            pull.Base.Ref,
+		Mergeable:          mergeable,
+		MergeableState:     mergeableState,
+		RequestedReviewers: reviewers,
+		RequestedTeams:     []string{},
+		ReviewSummary:      summarizeGiteaReviews(pull.Reviews),
+		CI:                 summarizeGiteaStatus(pull.Status),
+		CreatedAt:          pull.CreatedAt.UTC(),
+		UpdatedAt:          pull.UpdatedAt.UTC(),
+		ClosedAt:           utcTimePtr(pull.ClosedAt),
+		MergedAt:           utcTimePtr(mergedAt),
+	}
+}
+
+// isGiteaWorkInProgress matches Gitea's default work-in-progress title
+// prefixes, which older instances use instead of a draft flag.
+func isGiteaWorkInProgress(title string) bool {
+	title = strings.ToUpper(strings.TrimSpace(title))
+	return strings.HasPrefix(title, "WIP:") || strings.HasPrefix(title, "[WIP]")
+}
+
+func summarizeGiteaReviews(reviews []GiteaReview) ReviewSummary {
+	approved := map[string]struct{}{}
+	changesRequested := map[string]struct{}{}
+	commented := map[string]struct{}{}
+	for _, review := range reviews {
+		login := strings.TrimSpace(review.User.Login)
+		if login == "" || review.Dismissed {
+			continue
+		}
+		switch strings.ToUpper(strings.TrimSpace(review.State)) {
+		case "APPROVED":
+			approved[login] = struct{}{}
+		case "REQUEST_CHANGES":
+			changesRequested[login] = struct{}{}
+		case "COMMENT":
+			commented[login] = struct{}{}
+		}
+	}
+	return ReviewSummary{Approved: sortedKeys(approved), ChangesRequested: sortedKeys(changesRequested), Commented: sortedKeys(commented)}
+}
+
+func summarizeGiteaStatus(combined GiteaCombinedStatus) CIStatusSummary {
+	statuses := make([]CIStatusContext, 0, len(combined.Statuses))
+	for _, status := range combined.Statuses {
+		name := strings.TrimSpace(status.Context)
+		if name == "" {
+			continue
+		}
+		statuses = append(statuses, CIStatusContext{Name: name, State: normalizeGiteaCIState(status.Status), Description: status.Description, TargetURL: status.TargetURL})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	if len(statuses) == 0 {
+		return CIStatusSummary{State: normalizeGiteaCIState(combined.State), Statuses: statuses}
+	}
+	return CIStatusSummary{State: aggregateCIState(statuses, ""), Statuses: statuses}
+}
+
+func normalizeGiteaCIState(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "success", "warning":
+		return "passing"
+	case "failure", "error":
+		return "failing"
+	case "pending":
+		return "pending"
+	default:
+		return "unknown"
+	}
+}
+
+func (s *liveGiteaSDK) getJSON(ctx context.Context, host string, requestURL string, action string, target any) error {
+	status, body, err := s.doGET(ctx, host, requestURL)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("unexpected gitea status %s: %d", action, status)
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to parse gitea response %s: %w", action, err)
+	}
+	return nil
+}
+
+func (s *liveGiteaSDK) doGET(ctx context.Context, host string, requestURL string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "droner")
+	req.Header.Set("Authorization", "token "+s.token(host))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+func resolveGiteaToken(host conf.GiteaHostConfig) (string, bool) {
+	store, err := auth.Default()
+	if err != nil {
+		return "", false
+	}
+	if giteaAuth, ok := store.Gitea(host.TokenEnv); ok {
+		if token := strings.TrimSpace(giteaAuth.AccessToken); token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// parseGiteaRemoteURL splits a remote into host, owner and repository. Gitea
+// has no nested groups, so deeper paths are rejected.
+func parseGiteaRemoteURL(remoteURL string) (string, string, string, error) {
+	host, projectPath, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	owner, repo, ok := strings.Cut(projectPath, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", "", errors.New("remote URL is not an owner/repository path: " + remoteURL)
+	}
+	return host, owner, repo, nil
+}
@@ -0,0 +1,63 @@
+package remote
+
+import (
+	"context"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+)
+
+type giteaBranchSource struct {
+	giteaSDK GiteaSDK
+	hosts    map[string]struct{}
+}
+
+func newGiteaProvider(handler BranchEventHandler) remoteProvider {
+	config := conf.GetConfig().Providers.Gitea
+	hosts := make([]string, 0, len(config.Hosts))
+	for _, host := range config.Hosts {
+		hosts = append(hosts, host.Host)
+	}
+	return newPollingProvider(newGiteaBranchSource(newLiveGiteaSDK(config.Hosts), hosts), handler, conf.PollDuration(config.PollInterval), giteaProviderLogger())
+}
+
+func newGiteaBranchSource(gt GiteaSDK, hosts []string) *giteaBranchSource {
+	source := &giteaBranchSource{giteaSDK: gt, hosts: make(map[string]struct{}, len(hosts))}
+	for _, host := range hosts {
+		source.hosts[host] = struct{}{}
+	}
+	return source
+}
+
+func (s *giteaBranchSource) isValidURL(remoteURL string) bool {
+	host, _, _, err := parseGiteaRemoteURL(remoteURL)
+	if err != nil {
+		return false
+	}
+	_, ok := s.hosts[host]
+	return ok
+}
+
+func (s *giteaBranchSource) isAuthenticated(remoteURL string) bool {
+	host, _, _, err := parseGiteaRemoteURL(remoteURL)
+	return err == nil && s.giteaSDK.IsAuthenticated(host)
+}
+
+func (s *giteaBranchSource) ensureAuth(remoteURL string) error {
+	host, _, _, err := parseGiteaRemoteURL(remoteURL)
+	if err != nil {
+		return err
+	}
+	return s.giteaSDK.EnsureAuth(host)
+}
+
+func (s *giteaBranchSource) getBranchSnapshot(ctx context.Context, remoteURL string, branch string) (branchSnapshot, error) {
+	_, owner, repo, err := parseGiteaRemoteURL(remoteURL)
+	if err != nil {
+		return branchSnapshot{}, err
+	}
+	data, err := s.giteaSDK.GetBranchData(ctx, remoteURL, branch)
+	if err != nil {
+		return branchSnapshot{}, err
+	}
+	return branchSnapshot{BranchExists: data.BranchExists, PullRequest: normalizeGiteaPullRequest(remoteURL, owner, repo, data.PullRequest)}, nil
+}
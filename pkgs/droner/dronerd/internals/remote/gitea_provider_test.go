@@ -0,0 +1,93 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeGiteaSDK struct {
+	mu            sync.Mutex
+	authenticated map[string]bool
+	branchData    map[subscriptionKey][]GiteaBranchData
+}
+
+func newFakeGiteaSDK() *fakeGiteaSDK {
+	return &fakeGiteaSDK{authenticated: map[string]bool{}, branchData: make(map[subscriptionKey][]GiteaBranchData)}
+}
+
+func (s *fakeGiteaSDK) IsAuthenticated(host string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authenticated[host]
+}
+
+func (s *fakeGiteaSDK) EnsureAuth(host string) error {
+	if !s.IsAuthenticated(host) {
+		return ErrUnsupportedRemote
+	}
+	return nil
+}
+
+func (s *fakeGiteaSDK) GetBranchData(ctx context.Context, remoteURL string, branch string) (GiteaBranchData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := subscriptionKey{remoteURL: remoteURL, branch: branch}
+	responses := s.branchData[key]
+	if len(responses) == 0 {
+		return GiteaBranchData{}, nil
+	}
+	response := responses[0]
+	s.branchData[key] = responses[1:]
+	return response, nil
+}
+
+func TestGiteaProviderOnlyAcceptsConfiguredHosts(t *testing.T) {
+	provider := newPollingProvider(newGiteaBranchSource(newFakeGiteaSDK(), []string{"code.example.com"}), nil, time.Hour, giteaProviderLogger())
+	defer provider.close()
+
+	for remoteURL, want := range map[string]bool{
+		"git@code.example.com:team/service.git":  true,
+		"https://code.example.com/team/service":  true,
+		"https://code.example.com/team/sub/repo": false,
+		"git@gitea.com:team/service.git":         false,
+		"git@github.com:owner/repo.git":          false,
+	} {
+		if got := provider.isValidKey(subscriptionKey{remoteURL: remoteURL}); got != want {
+			t.Fatalf("isValidKey(%q) = %v, want %v", remoteURL, got, want)
+		}
+	}
+}
+
+func TestGiteaProviderEmitsMergeEvents(t *testing.T) {
+	gt := newFakeGiteaSDK()
+	gt.authenticated["code.example.com"] = true
+	received := make(chan BranchEvent, 4)
+	provider := newPollingProvider(newGiteaBranchSource(gt, []string{"code.example.com"}), func(event BranchEvent) { received <- event }, time.Hour, giteaProviderLogger())
+	defer provider.close()
+
+	key := subscriptionKey{remoteURL: "git@code.example.com:team/service.git", branch: "feature"}
+	mergedAt := time.Date(2026, time.March, 24, 12, 0, 0, 0, time.UTC)
+	gt.branchData[key] = []GiteaBranchData{
+		{BranchExists: true, PullRequest: &GiteaPullRequest{Number: 4, State: "open"}},
+		{BranchExists: true, PullRequest: &GiteaPullRequest{Number: 4, State: "closed", Merged: true, MergedAt: &mergedAt}},
+	}
+	provider.subscribe(key)
+
+	if err := provider.pollNext(context.Background()); err != nil {
+		t.Fatalf("first pollNext: %v", err)
+	}
+	if event := expectEvent(t, received); event.Type != PRObserved || event.PRSnapshot.Provider != "gitea" {
+		t.Fatalf("first event = %#v, want gitea PRObserved", event)
+	}
+	if err := provider.pollNext(context.Background()); err != nil {
+		t.Fatalf("second pollNext: %v", err)
+	}
+	for _, eventType := range []BranchEventType{PRObserved, PRClosed, PRMerged} {
+		if event := expectEvent(t, received); event.Type != eventType || event.PRSnapshot == nil {
+			t.Fatalf("event = %#v, want %s with snapshot", event, eventType)
+		}
+	}
+	expectNoEvent(t, received)
+}
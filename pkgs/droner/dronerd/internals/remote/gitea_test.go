@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+)
+
+func TestParseGiteaRemoteURL(t *testing.T) {
+	host, owner, repo, err := parseGiteaRemoteURL("git@Code.Example.com:team/service.git")
+	if err != nil {
+		t.Fatalf("parseGiteaRemoteURL: %v", err)
+	}
+	if host != "code.example.com" || owner != "team" || repo != "service" {
+		t.Fatalf("parseGiteaRemoteURL = %s %s %s, want code.example.com team service", host, owner, repo)
+	}
+	if _, _, _, err := parseGiteaRemoteURL("https://code.example.com/team/sub/service.git"); err == nil {
+		t.Fatal("expected error for nested repository path")
+	}
+}
+
+func TestLiveGiteaSDKGetBranchData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token forgejo-token" {
+			t.Fatalf("authorization = %q, want token header", got)
+		}
+		switch r.URL.EscapedPath() {
+		case "/forgejo/api/v1/repos/team/service/branches/feature%2Fone":
+			_, _ = w.Write([]byte(`{"name":"feature/one"}`))
+		case "/forgejo/api/v1/repos/team/service/pulls":
+			if got := r.URL.Query().Get("state"); got != "all" {
+				t.Fatalf("state = %q, want all", got)
+			}
+			_, _ = w.Write([]byte(`[
+				{"number":9,"head":{"ref":"other"},"updated_at":"2026-03-25T12:00:00Z"},
+				{"number":8,"head":{"ref":"feature/one","repo":{"full_name":"someone/service"}},"updated_at":"2026-03-25T12:00:00Z"},
+				{"number":4,"state":"open","title":"WIP: ship it","html_url":"https://code.example.com/team/service/pulls/4","mergeable":true,"head":{"ref":"feature/one","sha":"abc","repo":{"full_name":"team/service"}},"base":{"ref":"main"},"requested_reviewers":[{"login":"carol"},{"login":"bob"}],"created_at":"2026-03-20T12:00:00Z","updated_at":"2026-03-24T12:00:00Z"}
+			]`))
+		case "/forgejo/api/v1/repos/team/service/pulls/4/reviews":
+			_, _ = w.Write([]byte(`[{"user":{"login":"alice"},"state":"APPROVED"},{"user":{"login":"bob"},"state":"REQUEST_CHANGES"},{"user":{"login":"dave"},"state":"REQUEST_CHANGES","dismissed":true},{"user":{"login":"erin"},"state":"COMMENT"}]`))
+		case "/forgejo/api/v1/repos/team/service/commits/abc/status":
+			_, _ = w.Write([]byte(`{"state":"pending","statuses":[{"context":"ci/test","status":"success","target_url":"https://ci/1"},{"context":"ci/build","status":"pending"}]}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	gt := newLiveGiteaSDK([]conf.GiteaHostConfig{{Host: "code.example.com", BaseURL: server.URL + "/forgejo", TokenEnv: "SERVICE_TOKEN"}})
+	gt.resolveToken = func(host conf.GiteaHostConfig) (string, bool) {
+		if host.TokenEnv != "SERVICE_TOKEN" {
+			t.Fatalf("token env = %q, want SERVICE_TOKEN", host.TokenEnv)
+		}
+		return "forgejo-token", true
+	}
+
+	remoteURL := "git@code.example.com:team/service.git"
+	data, err := gt.GetBranchData(context.Background(), remoteURL, "feature/one")
+	if err != nil {
+		t.Fatalf("GetBranchData: %v", err)
+	}
+	if !data.BranchExists || data.PullRequest == nil || data.PullRequest.Number != 4 {
+		t.Fatalf("unexpected branch data: %#v", data)
+	}
+
+	snapshot := normalizeGiteaPullRequest(remoteURL, "team", "service", data.PullRequest)
+	if snapshot.Provider != "gitea" || snapshot.RepoOwner != "team" || snapshot.RepoName != "service" || snapshot.Number != 4 {
+		t.Fatalf("unexpected snapshot identity: %#v", snapshot)
+	}
+	if snapshot.State != "open" || !snapshot.Draft || snapshot.Mergeable == nil || !*snapshot.Mergeable || snapshot.MergeableState != "mergeable" {
+		t.Fatalf("unexpected snapshot state: %#v", snapshot)
+	}
+	if len(snapshot.RequestedReviewers) != 2 || snapshot.RequestedReviewers[0] != "bob" {
+		t.Fatalf("requested reviewers = %#v, want sorted reviewers", snapshot.RequestedReviewers)
+	}
+	reviews := snapshot.ReviewSummary
+	if len(reviews.Approved) != 1 || len(reviews.ChangesRequested) != 1 || reviews.ChangesRequested[0] != "bob" || len(reviews.Commented) != 1 {
+		t.Fatalf("unexpected review summary: %#v", reviews)
+	}
+	if snapshot.CI.State != "pending" || len(snapshot.CI.Statuses) != 2 || snapshot.CI.Statuses[0].Name != "ci/build" {
+		t.Fatalf("unexpected CI summary: %#v", snapshot.CI)
+	}
+}
+
+func TestNormalizeGiteaMergedPullRequest(t *testing.T) {
+	pull := &GiteaPullRequest{Number: 3, State: "closed", Merged: true, Mergeable: true}
+	pull.ClosedAt = &pull.UpdatedAt
+
+	snapshot := normalizeGiteaPullRequest("git@code.example.com:team/service.git", "team", "service", pull)
+	if snapshot.State != "closed" || snapshot.MergedAt == nil {
+		t.Fatalf("unexpected merged snapshot: %#v", snapshot)
+	}
+	if snapshot.Mergeable != nil || snapshot.MergeableState != "" {
+		t.Fatalf("closed snapshot mergeable = %v %q, want unset", snapshot.Mergeable, snapshot.MergeableState)
+	}
+	if snapshot.CI.State != "unknown" {
+		t.Fatalf("CI state = %q, want unknown without statuses", snapshot.CI.State)
+	}
+}
+
+func TestLiveGiteaSDKRejectsUnconfiguredHost(t *testing.T) {
+	gt := newLiveGiteaSDK(nil)
+	gt.resolveToken = func(conf.GiteaHostConfig) (string, bool) {
+		t.Fatal("unexpected token lookup for unconfigured host")
+		return "", false
+	}
+	if gt.IsAuthenticated("code.example.com") {
+		t.Fatal("expected unconfigured host to be unauthenticated")
+	}
+}
@@ -1,6 +1,7 @@
 package remote
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -24,23 +25,27 @@ type GitHubBranchData struct {
 }
 
 type GitHubPullRequest struct {
-	Number             int                  `json:"number"`
-	State              string               `json:"state"`
-	MergedAt           *time.Time           `json:"merged_at,omitempty"`
-	ClosedAt           *time.Time           `json:"closed_at,omitempty"`
-	CreatedAt          time.Time            `json:"created_at"`
-	UpdatedAt          time.Time            `json:"updated_at"`
-	Title              string               `json:"title"`
-	HTMLURL            string               `json:"html_url"`
-	Draft              bool                 `json:"draft"`
-	Mergeable          *bool                `json:"mergeable"`
-	MergeableState     string               `json:"mergeable_state"`
-	RequestedReviewers []GitHubUser         `json:"requested_reviewers"`
-	RequestedTeams     []GitHubTeam         `json:"requested_teams"`
-	Head               GitHubBranchRef      `json:"head"`
-	Base               GitHubBranchRef      `json:"base"`
-	Reviews            []GitHubReview       `json:"-"`
-	CI                 GitHubCIStatusResult `json:"-"`
+	Number             int                   `json:"number"`
+	State              string                `json:"state"`
+	MergedAt           *time.Time            `json:"merged_at,omitempty"`
+	ClosedAt           *time.Time            `json:"closed_at,omitempty"`
+	CreatedAt          time.Time             `json:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at"`
+	Title              string                `json:"title"`
+	HTMLURL            string                `json:"html_url"`
+	Draft              bool                  `json:"draft"`
+	Mergeable          *bool                 `json:"mergeable"`
+	MergeableState     string                `json:"mergeable_state"`
+	RequestedReviewers []GitHubUser          `json:"requested_reviewers"`
+	RequestedTeams     []GitHubTeam          `json:"requested_teams"`
+	Head               GitHubBranchRef       `json:"head"`
+	Base               GitHubBranchRef       `json:"base"`
+	Reviews            []GitHubReview        `json:"-"`
+	ReviewComments     []GitHubReviewComment `json:"-"`
+	CI                 GitHubCIStatusResult  `json:"-"`
+	// ResolutionKnown is set when ReviewComments came from GraphQL review
+	// threads and so carry their resolved state.
+	ResolutionKnown bool `json:"-"`
 }
 
 type GitHubBranchRef struct {
@@ -61,6 +66,19 @@ type GitHubReview struct {
 	State string     `json:"state"`
 }
 
+type GitHubReviewComment struct {
+	ID        int64      `json:"id"`
+	User      GitHubUser `json:"user"`
+	Path      string     `json:"path"`
+	Line      *int       `json:"line"`
+	StartLine *int       `json:"start_line"`
+	Body      string     `json:"body"`
+	HTMLURL   string     `json:"html_url"`
+	CreatedAt time.Time  `json:"created_at"`
+	// Resolved is only known through GraphQL review threads; REST leaves it false.
+	Resolved bool `json:"-"`
+}
+
 type GitHubCombinedStatus struct {
 	State    string                `json:"state"`
 	Statuses []GitHubStatusContext `json:"statuses"`
@@ -78,10 +96,17 @@ type GitHubCheckRunsResponse struct {
 }
 
 type GitHubCheckRun struct {
-	Name       string  `json:"name"`
-	Status     string  `json:"status"`
-	Conclusion *string `json:"conclusion"`
-	HTMLURL    string  `json:"html_url"`
+	Name       string               `json:"name"`
+	Status     string               `json:"status"`
+	Conclusion *string              `json:"conclusion"`
+	HTMLURL    string               `json:"html_url"`
+	Output     GitHubCheckRunOutput `json:"output"`
+}
+
+type GitHubCheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Text    string `json:"text"`
 }
 
 type GitHubCIStatusResult struct {
@@ -93,14 +118,31 @@ type GitHubSDK interface {
 	IsAuthenticated() bool
 	EnsureAuth() error
 	GetBranchData(ctx context.Context, remoteURL string, branch string) (GitHubBranchData, error)
+	// GetBranchDataBatch fetches several branches of one repository in a
+	// single GraphQL round trip, keyed by branch name.
+	GetBranchDataBatch(ctx context.Context, owner string, repo string, branches []string) (map[string]GitHubBranchData, error)
+	CreatePullRequest(ctx context.Context, owner string, repo string, request GitHubCreatePullRequest) (GitHubPullRequest, error)
+	// GetCIStatus fetches commit statuses and check runs, including their
+	// output, for one commit.
+	GetCIStatus(ctx context.Context, owner string, repo string, sha string) (GitHubCIStatusResult, error)
 	SetAuthToken(token string)
+	RateLimit() GitHubRateLimit
+}
+
+type GitHubCreatePullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body,omitempty"`
+	Draft bool   `json:"draft"`
 }
 
 type liveGitHubSDK struct {
-	token      string
-	mu         sync.RWMutex
-	apiBaseURL string
-	httpClient *http.Client
+	token       string
+	mu          sync.RWMutex
+	apiBaseURL  string
+	httpClient  *http.Client
+	rateLimiter *githubRateLimiter
 }
 
 func newLiveGitHubSDK() *liveGitHubSDK {
@@ -109,12 +151,17 @@ func newLiveGitHubSDK() *liveGitHubSDK {
 		token = resolvedToken
 	}
 	return &liveGitHubSDK{
-		token:      token,
-		apiBaseURL: "https://api.github.com",
-		httpClient: &http.Client{Timeout: timeouts.SecondDefault},
+		token:       token,
+		apiBaseURL:  "https://api.github.com",
+		httpClient:  &http.Client{Timeout: timeouts.SecondDefault},
+		rateLimiter: newGitHubRateLimiter(),
 	}
 }
 
+func (s *liveGitHubSDK) RateLimit() GitHubRateLimit {
+	return s.rateLimiter.snapshot()
+}
+
 func (s *liveGitHubSDK) SetAuthToken(token string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -211,6 +258,10 @@ func (s *liveGitHubSDK) fetchPullRequestForBranch(ctx context.Context, remoteURL
 	if err != nil {
 		return nil, err
 	}
+	detail.ReviewComments, err = s.fetchPullRequestReviewComments(ctx, owner, repo, detail.Number)
+	if err != nil {
+		return nil, err
+	}
 	if strings.TrimSpace(detail.Head.SHA) != "" {
 		detail.CI, err = s.fetchCIStatus(ctx, owner, repo, detail.Head.SHA)
 		if err != nil {
@@ -268,6 +319,8 @@ func normalizeGitHubPullRequest(remoteURL, owner, repo string, pull *GitHubPullR
 		RequestedReviewers: reviewers,
 		RequestedTeams:     teams,
 		ReviewSummary:      summarizeReviews(pull.Reviews),
+		ReviewComments:     normalizeGitHubReviewComments(pull.ReviewComments),
+		ResolutionKnown:    pull.ResolutionKnown,
 		CI:                 summarizeCI(pull.CI),
 		CreatedAt:          pull.CreatedAt.UTC(),
 		UpdatedAt:          pull.UpdatedAt.UTC(),
@@ -297,6 +350,33 @@ func summarizeReviews(reviews []GitHubReview) ReviewSummary {
 	return ReviewSummary{Approved: sortedKeys(approved), ChangesRequested: sortedKeys(changesRequested), Commented: sortedKeys(commented)}
 }
 
+func normalizeGitHubReviewComments(comments []GitHubReviewComment) []ReviewComment {
+	if len(comments) == 0 {
+		return nil
+	}
+	normalized := make([]ReviewComment, 0, len(comments))
+	for _, comment := range comments {
+		item := ReviewComment{
+			ID:        comment.ID,
+			Author:    strings.TrimSpace(comment.User.Login),
+			Path:      comment.Path,
+			Body:      comment.Body,
+			URL:       comment.HTMLURL,
+			Resolved:  comment.Resolved,
+			CreatedAt: comment.CreatedAt.UTC(),
+		}
+		if comment.Line != nil {
+			item.Line = *comment.Line
+		}
+		if comment.StartLine != nil {
+			item.StartLine = *comment.StartLine
+		}
+		normalized = append(normalized, item)
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i].ID < normalized[j].ID })
+	return normalized
+}
+
 func summarizeCI(ci GitHubCIStatusResult) CIStatusSummary {
 	statuses := make([]CIStatusContext, 0, len(ci.CombinedStatus.Statuses)+len(ci.CheckRuns))
 	for _, status := range ci.CombinedStatus.Statuses {
@@ -405,6 +485,38 @@ func (s *liveGitHubSDK) fetchPullRequestReviews(ctx context.Context, owner strin
 	return reviews, nil
 }
 
+// fetchPullRequestReviewComments follows the Link header through every page
+// so long reviews are not silently truncated.
+func (s *liveGitHubSDK) fetchPullRequestReviewComments(ctx context.Context, owner string, repo string, number int) ([]GitHubReviewComment, error) {
+	q := url.Values{}
+	q.Set("per_page", "100")
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/comments?%s", s.apiBaseURL, owner, repo, number, q.Encode())
+	comments := []GitHubReviewComment{}
+	for requestURL != "" {
+		status, body, next, err := s.doGETPage(ctx, requestURL)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("unexpected github status listing pull review comments: %d", status)
+		}
+		var page []GitHubReviewComment
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse github pull review comments response: %w", err)
+		}
+		comments = append(comments, page...)
+		requestURL = next
+	}
+	return comments, nil
+}
+
+func (s *liveGitHubSDK) GetCIStatus(ctx context.Context, owner string, repo string, sha string) (GitHubCIStatusResult, error) {
+	if err := s.EnsureAuth(); err != nil {
+		return GitHubCIStatusResult{}, err
+	}
+	return s.fetchCIStatus(ctx, owner, repo, sha)
+}
+
 func (s *liveGitHubSDK) fetchCIStatus(ctx context.Context, owner string, repo string, sha string) (GitHubCIStatusResult, error) {
 	statusURL := fmt.Sprintf("%s/repos/%s/%s/commits/%s/status", s.apiBaseURL, owner, repo, url.PathEscape(sha))
 	status, body, err := s.doGET(ctx, statusURL)
@@ -434,10 +546,80 @@ func (s *liveGitHubSDK) fetchCIStatus(ctx context.Context, owner string, repo st
 	return GitHubCIStatusResult{CombinedStatus: combined, CheckRuns: checks.CheckRuns}, nil
 }
 
+func (s *liveGitHubSDK) CreatePullRequest(ctx context.Context, owner string, repo string, request GitHubCreatePullRequest) (GitHubPullRequest, error) {
+	if err := s.EnsureAuth(); err != nil {
+		return GitHubPullRequest{}, err
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return GitHubPullRequest{}, err
+	}
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/pulls", s.apiBaseURL, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(payload))
+	if err != nil {
+		return GitHubPullRequest{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "droner")
+	s.mu.RLock()
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(s.token))
+	s.mu.RUnlock()
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return GitHubPullRequest{}, err
+	}
+	defer resp.Body.Close()
+	if err := s.rateLimiter.observe(resp); err != nil {
+		return GitHubPullRequest{}, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GitHubPullRequest{}, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return GitHubPullRequest{}, fmt.Errorf("unexpected github status creating pull: %d: %s", resp.StatusCode, githubErrorMessage(body))
+	}
+	var pull GitHubPullRequest
+	if err := json.Unmarshal(body, &pull); err != nil {
+		return GitHubPullRequest{}, fmt.Errorf("failed to parse github pull response: %w", err)
+	}
+	return pull, nil
+}
+
+// githubErrorMessage flattens a REST error body such as the 422 returned when
+// a pull request already exists for the branch.
+func githubErrorMessage(body []byte) string {
+	var parsed struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil || parsed.Message == "" {
+		return strings.TrimSpace(string(body))
+	}
+	messages := []string{parsed.Message}
+	for _, detail := range parsed.Errors {
+		if detail.Message != "" {
+			messages = append(messages, detail.Message)
+		}
+	}
+	return strings.Join(messages, ": ")
+}
+
 func (s *liveGitHubSDK) doGET(ctx context.Context, requestURL string) (int, []byte, error) {
+	status, body, _, err := s.doGETPage(ctx, requestURL)
+	return status, body, err
+}
+
+// doGETPage is doGET for list endpoints: it also returns the rel="next" URL
+// from the Link header, or "" on the last page.
+func (s *liveGitHubSDK) doGETPage(ctx context.Context, requestURL string) (int, []byte, string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, "", err
 	}
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("User-Agent", "droner")
@@ -445,19 +627,61 @@ func (s *liveGitHubSDK) doGET(ctx context.Context, requestURL string) (int, []by
 	s.mu.RLock()
 	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(s.token))
 	s.mu.RUnlock()
+	if etag, ok := s.rateLimiter.etag(requestURL); ok {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, "", err
 	}
 	defer resp.Body.Close()
 
+	if err := s.rateLimiter.observe(resp); err != nil {
+		state := s.rateLimiter.snapshot()
+		githubProviderLogger().Debug("github rate limited",
+			"url", requestURL,
+			"status", resp.StatusCode,
+			"backoff_until", state.BackoffUntil,
+			"reason", state.BackoffReason,
+		)
+		return 0, nil, "", err
+	}
+
+	// A 304 replays the body cached with the ETag so callers never see it.
+	if resp.StatusCode == http.StatusNotModified {
+		if body, next, ok := s.rateLimiter.cachedBody(requestURL); ok {
+			return http.StatusOK, body, next, nil
+		}
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, "", err
 	}
+	next := githubNextPageURL(resp.Header.Get("Link"))
+	if resp.StatusCode == http.StatusOK {
+		s.rateLimiter.storeETag(requestURL, resp.Header.Get("ETag"), body, next)
+	}
+
+	return resp.StatusCode, body, next, nil
+}
 
-	return resp.StatusCode, body, nil
+// githubNextPageURL extracts the rel="next" target from a Link header such as
+// `<https://api.github.com/...&page=2>; rel="next", <...>; rel="last"`.
+func githubNextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+	return ""
 }
 
 func resolveGitHubToken() (string, error) {
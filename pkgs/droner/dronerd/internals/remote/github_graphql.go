@@ -0,0 +1,547 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// githubGraphQLBatchSize caps the branches per query to keep its cost low.
+const githubGraphQLBatchSize = 20
+
+// githubGraphQLPageSize is used when following up on review threads and
+// comments that did not fit in the batch query.
+const githubGraphQLPageSize = 100
+
+var ErrGitHubGraphQLUnavailable = errors.New("github graphql unavailable")
+
+const githubGraphQLReviewCommentFragment = `fragment ReviewCommentFields on PullRequestReviewComment {
+  databaseId
+  author { login }
+  body
+  url
+  createdAt
+}`
+
+const githubGraphQLReviewThreadFragment = `fragment ReviewThreadFields on PullRequestReviewThread {
+  id
+  isResolved
+  path
+  line
+  startLine
+  comments(first: 20) {
+    pageInfo { hasNextPage endCursor }
+    nodes { ...ReviewCommentFields }
+  }
+}`
+
+const githubGraphQLPullRequestFragment = `fragment PullRequestFields on PullRequest {
+  id
+  number
+  state
+  title
+  url
+  isDraft
+  mergeable
+  mergeStateStatus
+  createdAt
+  updatedAt
+  closedAt
+  mergedAt
+  headRefName
+  headRefOid
+  baseRefName
+  baseRefOid
+  headRepositoryOwner { login }
+  reviewRequests(first: 50) {
+    nodes {
+      requestedReviewer {
+        ... on User { login }
+        ... on Team { slug }
+      }
+    }
+  }
+  reviews(first: 100) {
+    nodes {
+      state
+      author { login }
+    }
+  }
+  reviewThreads(first: 50) {
+    pageInfo { hasNextPage endCursor }
+    nodes { ...ReviewThreadFields }
+  }
+  commits(last: 1) {
+    nodes {
+      commit {
+        statusCheckRollup {
+          contexts(first: 100) {
+            nodes {
+              __typename
+              ... on StatusContext { context state description targetUrl }
+              ... on CheckRun { name status conclusion detailsUrl }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type githubGraphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type githubGraphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type githubGraphQLPullRequests struct {
+	Nodes []githubGraphQLPullRequest `json:"nodes"`
+}
+
+type githubGraphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type githubGraphQLReviewThreads struct {
+	PageInfo githubGraphQLPageInfo       `json:"pageInfo"`
+	Nodes    []githubGraphQLReviewThread `json:"nodes"`
+}
+
+type githubGraphQLReviewThread struct {
+	ID         string                      `json:"id"`
+	IsResolved bool                        `json:"isResolved"`
+	Path       string                      `json:"path"`
+	Line       *int                        `json:"line"`
+	StartLine  *int                        `json:"startLine"`
+	Comments   githubGraphQLReviewComments `json:"comments"`
+}
+
+type githubGraphQLReviewComments struct {
+	PageInfo githubGraphQLPageInfo        `json:"pageInfo"`
+	Nodes    []githubGraphQLReviewComment `json:"nodes"`
+}
+
+type githubGraphQLReviewComment struct {
+	DatabaseID int64 `json:"databaseId"`
+	Author     *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type githubGraphQLPullRequest struct {
+	ID                  string     `json:"id"`
+	Number              int        `json:"number"`
+	State               string     `json:"state"`
+	Title               string     `json:"title"`
+	URL                 string     `json:"url"`
+	IsDraft             bool       `json:"isDraft"`
+	Mergeable           string     `json:"mergeable"`
+	MergeStateStatus    string     `json:"mergeStateStatus"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+	ClosedAt            *time.Time `json:"closedAt"`
+	MergedAt            *time.Time `json:"mergedAt"`
+	HeadRefName         string     `json:"headRefName"`
+	HeadRefOid          string     `json:"headRefOid"`
+	BaseRefName         string     `json:"baseRefName"`
+	BaseRefOid          string     `json:"baseRefOid"`
+	HeadRepositoryOwner *struct {
+		Login string `json:"login"`
+	} `json:"headRepositoryOwner"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Login string `json:"login"`
+				Slug  string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Reviews struct {
+		Nodes []struct {
+			State  string `json:"state"`
+			Author *struct {
+				Login string `json:"login"`
+			} `json:"author"`
+		} `json:"nodes"`
+	} `json:"reviews"`
+	ReviewThreads githubGraphQLReviewThreads `json:"reviewThreads"`
+	Commits       struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					Contexts struct {
+						Nodes []githubGraphQLCheckContext `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// githubGraphQLCheckContext is either a StatusContext or a CheckRun.
+type githubGraphQLCheckContext struct {
+	Typename    string  `json:"__typename"`
+	Context     string  `json:"context"`
+	State       string  `json:"state"`
+	Description string  `json:"description"`
+	TargetURL   string  `json:"targetUrl"`
+	Name        string  `json:"name"`
+	Status      string  `json:"status"`
+	Conclusion  *string `json:"conclusion"`
+	DetailsURL  string  `json:"detailsUrl"`
+}
+
+// buildGitHubBranchesQuery aliases a ref lookup and a pull request search per
+// branch so one query covers every branch of a repository.
+func buildGitHubBranchesQuery(owner string, repo string, branches []string) githubGraphQLRequest {
+	variables := map[string]any{"owner": owner, "repo": repo}
+	params := []string{"$owner: String!", "$repo: String!"}
+	var fields strings.Builder
+	for i, branch := range branches {
+		params = append(params, fmt.Sprintf("$ref%d: String!", i), fmt.Sprintf("$head%d: String!", i))
+		variables[fmt.Sprintf("ref%d", i)] = "refs/heads/" + branch
+		variables[fmt.Sprintf("head%d", i)] = branch
+		fmt.Fprintf(&fields, "    ref%d: ref(qualifiedName: $ref%d) { name }\n", i, i)
+		fmt.Fprintf(&fields, "    pulls%d: pullRequests(headRefName: $head%d, first: 10, states: [OPEN, CLOSED, MERGED], orderBy: {field: UPDATED_AT, direction: DESC}) { nodes { ...PullRequestFields } }\n", i, i)
+	}
+	query := fmt.Sprintf("query(%s) {\n  repository(owner: $owner, name: $repo) {\n%s  }\n}\n%s\n%s\n%s", strings.Join(params, ", "), fields.String(), githubGraphQLPullRequestFragment, githubGraphQLReviewThreadFragment, githubGraphQLReviewCommentFragment)
+	return githubGraphQLRequest{Query: query, Variables: variables}
+}
+
+func buildGitHubReviewThreadsPageQuery(pullRequestID string, after string) githubGraphQLRequest {
+	query := fmt.Sprintf(`query($id: ID!, $after: String) {
+  node(id: $id) {
+    ... on PullRequest {
+      reviewThreads(first: %d, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes { ...ReviewThreadFields }
+      }
+    }
+  }
+}
+%s
+%s`, githubGraphQLPageSize, githubGraphQLReviewThreadFragment, githubGraphQLReviewCommentFragment)
+	return githubGraphQLRequest{Query: query, Variables: map[string]any{"id": pullRequestID, "after": after}}
+}
+
+func buildGitHubReviewCommentsPageQuery(threadID string, after string) githubGraphQLRequest {
+	query := fmt.Sprintf(`query($id: ID!, $after: String) {
+  node(id: $id) {
+    ... on PullRequestReviewThread {
+      comments(first: %d, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes { ...ReviewCommentFields }
+      }
+    }
+  }
+}
+%s`, githubGraphQLPageSize, githubGraphQLReviewCommentFragment)
+	return githubGraphQLRequest{Query: query, Variables: map[string]any{"id": threadID, "after": after}}
+}
+
+// GetBranchDataBatch fetches the branch state and newest pull request of
+// several branches in one repository through GraphQL.
+func (s *liveGitHubSDK) GetBranchDataBatch(ctx context.Context, owner string, repo string, branches []string) (map[string]GitHubBranchData, error) {
+	if err := s.EnsureAuth(); err != nil {
+		return nil, err
+	}
+	results := make(map[string]GitHubBranchData, len(branches))
+	for start := 0; start < len(branches); start += githubGraphQLBatchSize {
+		chunk := branches[start:min(start+githubGraphQLBatchSize, len(branches))]
+		repository, err := s.queryGraphQL(ctx, buildGitHubBranchesQuery(owner, repo, chunk))
+		if err != nil {
+			return nil, err
+		}
+		for i, branch := range chunk {
+			data, node, err := parseGitHubGraphQLBranch(repository, i, owner)
+			if err != nil {
+				return nil, err
+			}
+			if node != nil && node.hasMoreReviewComments() {
+				if err := s.loadRemainingReviewThreads(ctx, node); err != nil {
+					return nil, err
+				}
+				pull := node.toPullRequest()
+				data.PullRequest = &pull
+			}
+			results[branch] = data
+		}
+	}
+	return results, nil
+}
+
+// loadRemainingReviewThreads pages through the review threads and comments
+// the batch query cut off, so long reviews are not silently truncated.
+func (s *liveGitHubSDK) loadRemainingReviewThreads(ctx context.Context, pull *githubGraphQLPullRequest) error {
+	for pull.ReviewThreads.PageInfo.HasNextPage {
+		var data struct {
+			Node *struct {
+				ReviewThreads githubGraphQLReviewThreads `json:"reviewThreads"`
+			} `json:"node"`
+		}
+		if err := s.postGraphQL(ctx, buildGitHubReviewThreadsPageQuery(pull.ID, pull.ReviewThreads.PageInfo.EndCursor), &data); err != nil {
+			return err
+		}
+		if data.Node == nil {
+			return fmt.Errorf("%w: pull request %d not found", ErrGitHubGraphQLUnavailable, pull.Number)
+		}
+		pull.ReviewThreads.Nodes = append(pull.ReviewThreads.Nodes, data.Node.ReviewThreads.Nodes...)
+		pull.ReviewThreads.PageInfo = data.Node.ReviewThreads.PageInfo
+	}
+	for i := range pull.ReviewThreads.Nodes {
+		thread := &pull.ReviewThreads.Nodes[i]
+		for thread.Comments.PageInfo.HasNextPage {
+			var data struct {
+				Node *struct {
+					Comments githubGraphQLReviewComments `json:"comments"`
+				} `json:"node"`
+			}
+			if err := s.postGraphQL(ctx, buildGitHubReviewCommentsPageQuery(thread.ID, thread.Comments.PageInfo.EndCursor), &data); err != nil {
+				return err
+			}
+			if data.Node == nil {
+				return fmt.Errorf("%w: review thread %s not found", ErrGitHubGraphQLUnavailable, thread.ID)
+			}
+			thread.Comments.Nodes = append(thread.Comments.Nodes, data.Node.Comments.Nodes...)
+			thread.Comments.PageInfo = data.Node.Comments.PageInfo
+		}
+	}
+	return nil
+}
+
+func (s *liveGitHubSDK) queryGraphQL(ctx context.Context, request githubGraphQLRequest) (map[string]json.RawMessage, error) {
+	var data struct {
+		Repository map[string]json.RawMessage `json:"repository"`
+	}
+	if err := s.postGraphQL(ctx, request, &data); err != nil {
+		return nil, err
+	}
+	if data.Repository == nil {
+		return nil, fmt.Errorf("%w: repository not found", ErrGitHubGraphQLUnavailable)
+	}
+	return data.Repository, nil
+}
+
+func (s *liveGitHubSDK) postGraphQL(ctx context.Context, request githubGraphQLRequest, data any) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiBaseURL+"/graphql", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "droner")
+	s.mu.RLock()
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(s.token))
+	s.mu.RUnlock()
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := s.rateLimiter.observe(resp); err != nil {
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status %d", ErrGitHubGraphQLUnavailable, resp.StatusCode)
+	}
+
+	var parsed githubGraphQLResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return fmt.Errorf("failed to parse github graphql response: %w", err)
+	}
+	if len(parsed.Errors) > 0 {
+		return fmt.Errorf("%w: %s", ErrGitHubGraphQLUnavailable, parsed.Errors[0].Message)
+	}
+	if err := json.Unmarshal(parsed.Data, data); err != nil {
+		return fmt.Errorf("failed to parse github graphql data: %w", err)
+	}
+	return nil
+}
+
+// parseGitHubGraphQLBranch also returns the node of the pull request it picked
+// so the caller can page through what the batch query cut off.
+func parseGitHubGraphQLBranch(repository map[string]json.RawMessage, index int, owner string) (GitHubBranchData, *githubGraphQLPullRequest, error) {
+	var data GitHubBranchData
+	var ref *struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(repository[fmt.Sprintf("ref%d", index)], &ref); err != nil {
+		return GitHubBranchData{}, nil, fmt.Errorf("failed to parse github graphql ref: %w", err)
+	}
+	data.BranchExists = ref != nil
+
+	var pulls githubGraphQLPullRequests
+	if err := json.Unmarshal(repository[fmt.Sprintf("pulls%d", index)], &pulls); err != nil {
+		return GitHubBranchData{}, nil, fmt.Errorf("failed to parse github graphql pull requests: %w", err)
+	}
+	// Like the REST head=owner:branch filter, ignore pull requests from forks.
+	candidates := make([]GitHubPullRequest, 0, len(pulls.Nodes))
+	nodes := make(map[int]*githubGraphQLPullRequest, len(pulls.Nodes))
+	for i, node := range pulls.Nodes {
+		if node.HeadRepositoryOwner == nil || !strings.EqualFold(node.HeadRepositoryOwner.Login, owner) {
+			continue
+		}
+		candidates = append(candidates, node.toPullRequest())
+		nodes[node.Number] = &pulls.Nodes[i]
+	}
+	if len(candidates) == 0 {
+		return data, nil, nil
+	}
+	pull := newestPullRequest(candidates)
+	data.PullRequest = &pull
+	return data, nodes[pull.Number], nil
+}
+
+func (p githubGraphQLPullRequest) hasMoreReviewComments() bool {
+	if p.ReviewThreads.PageInfo.HasNextPage {
+		return true
+	}
+	for _, thread := range p.ReviewThreads.Nodes {
+		if thread.Comments.PageInfo.HasNextPage {
+			return true
+		}
+	}
+	return false
+}
+
+// toPullRequest maps GraphQL enums onto the REST shape so both paths share
+// normalizeGitHubPullRequest.
+func (p githubGraphQLPullRequest) toPullRequest() GitHubPullRequest {
+	pull := GitHubPullRequest{
+		Number:         p.Number,
+		State:          "open",
+		MergedAt:       p.MergedAt,
+		ClosedAt:       p.ClosedAt,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+		Title:          p.Title,
+		HTMLURL:        p.URL,
+		Draft:          p.IsDraft,
+		MergeableState: strings.ToLower(p.MergeStateStatus),
+		Head:           GitHubBranchRef{Ref: p.HeadRefName, SHA: p.HeadRefOid},
+		Base:           GitHubBranchRef{Ref: p.BaseRefName, SHA: p.BaseRefOid},
+	}
+	if p.State != "OPEN" {
+		pull.State = "closed"
+	}
+	switch p.Mergeable {
+	case "MERGEABLE":
+		mergeable := true
+		pull.Mergeable = &mergeable
+	case "CONFLICTING":
+		mergeable := false
+		pull.Mergeable = &mergeable
+	}
+
+	for _, request := range p.ReviewRequests.Nodes {
+		if request.RequestedReviewer.Login != "" {
+			pull.RequestedReviewers = append(pull.RequestedReviewers, GitHubUser{Login: request.RequestedReviewer.Login})
+		}
+		if request.RequestedReviewer.Slug != "" {
+			pull.RequestedTeams = append(pull.RequestedTeams, GitHubTeam{Slug: request.RequestedReviewer.Slug})
+		}
+	}
+	for _, review := range p.Reviews.Nodes {
+		if review.Author != nil {
+			pull.Reviews = append(pull.Reviews, GitHubReview{User: GitHubUser{Login: review.Author.Login}, State: review.State})
+		}
+	}
+
+	pull.ResolutionKnown = true
+	for _, thread := range p.ReviewThreads.Nodes {
+		for _, comment := range thread.Comments.Nodes {
+			reviewComment := GitHubReviewComment{
+				ID:        comment.DatabaseID,
+				Path:      thread.Path,
+				Line:      thread.Line,
+				StartLine: thread.StartLine,
+				Body:      comment.Body,
+				HTMLURL:   comment.URL,
+				CreatedAt: comment.CreatedAt,
+				Resolved:  thread.IsResolved,
+			}
+			if comment.Author != nil {
+				reviewComment.User = GitHubUser{Login: comment.Author.Login}
+			}
+			pull.ReviewComments = append(pull.ReviewComments, reviewComment)
+		}
+	}
+
+	for _, commit := range p.Commits.Nodes {
+		rollup := commit.Commit.StatusCheckRollup
+		if rollup == nil {
+			continue
+		}
+		for _, context := range rollup.Contexts.Nodes {
+			switch context.Typename {
+			case "StatusContext":
+				pull.CI.CombinedStatus.Statuses = append(pull.CI.CombinedStatus.Statuses, GitHubStatusContext{
+					Context:     context.Context,
+					State:       strings.ToLower(context.State),
+					Description: context.Description,
+					TargetURL:   context.TargetURL,
+				})
+			case "CheckRun":
+				var conclusion *string
+				if context.Conclusion != nil {
+					lowered := strings.ToLower(*context.Conclusion)
+					conclusion = &lowered
+				}
+				pull.CI.CheckRuns = append(pull.CI.CheckRuns, GitHubCheckRun{
+					Name:       context.Name,
+					Status:     strings.ToLower(context.Status),
+					Conclusion: conclusion,
+					HTMLURL:    context.DetailsURL,
+				})
+			}
+		}
+	}
+	// The rollup state also covers check runs; REST's combined status only
+	// covers status contexts, so derive it the same way here.
+	pull.CI.CombinedStatus.State = githubCombinedStatusState(pull.CI.CombinedStatus.Statuses)
+	return pull
+}
+
+// githubCombinedStatusState computes the state REST's combined status
+// endpoint reports: pending without statuses or while any is pending, failure
+// as soon as one failed or errored.
+func githubCombinedStatusState(statuses []GitHubStatusContext) string {
+	if len(statuses) == 0 {
+		return "pending"
+	}
+	state := "success"
+	for _, status := range statuses {
+		switch status.State {
+		case "error", "failure":
+			return "failure"
+		case "pending":
+			state = "pending"
+		}
+	}
+	return state
+}
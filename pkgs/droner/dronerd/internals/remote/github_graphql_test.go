@@ -0,0 +1,198 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const graphQLBranchesResponse = `{
+  "data": {
+    "repository": {
+      "ref0": {"name": "feature"},
+      "pulls0": {"nodes": [
+        {"number": 3, "state": "OPEN", "headRepositoryOwner": {"login": "someone"}, "updatedAt": "2026-03-25T12:00:00Z"},
+        {
+          "number": 7, "state": "MERGED", "title": "Ship it", "url": "https://github.com/owner/repo/pull/7",
+          "isDraft": false, "mergeable": "UNKNOWN", "mergeStateStatus": "UNKNOWN",
+          "createdAt": "2026-03-20T12:00:00Z", "updatedAt": "2026-03-24T12:00:00Z",
+          "closedAt": "2026-03-24T12:00:00Z", "mergedAt": "2026-03-24T12:00:00Z",
+          "headRefName": "feature", "headRefOid": "abc", "baseRefName": "main", "baseRefOid": "def",
+          "headRepositoryOwner": {"login": "Owner"},
+          "reviewRequests": {"nodes": [{"requestedReviewer": {"login": "bob"}}, {"requestedReviewer": {"slug": "core"}}]},
+          "reviews": {"nodes": [{"state": "APPROVED", "author": {"login": "alice"}}, {"state": "COMMENTED", "author": null}]},
+          "reviewThreads": {"nodes": [{"isResolved": true, "path": "main.go", "line": 12, "startLine": null, "comments": {"nodes": [
+            {"databaseId": 11, "author": {"login": "alice"}, "body": "Rename this", "url": "https://github.com/owner/repo/pull/7#discussion_r11", "createdAt": "2026-03-21T12:00:00Z"}
+          ]}}]},
+          "commits": {"nodes": [{"commit": {"statusCheckRollup": {"state": "FAILURE", "contexts": {"nodes": [
+            {"__typename": "StatusContext", "context": "ci/build", "state": "SUCCESS", "description": "ok", "targetUrl": "https://ci/1"},
+            {"__typename": "CheckRun", "name": "test", "status": "COMPLETED", "conclusion": "FAILURE", "detailsUrl": "https://github.com/runs/1"}
+          ]}}}}]}
+        }
+      ]},
+      "ref1": null,
+      "pulls1": {"nodes": []}
+    }
+  }
+}`
+
+func TestLiveGitHubSDKGetBranchDataBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var request githubGraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if request.Variables["owner"] != "owner" || request.Variables["ref0"] != "refs/heads/feature" || request.Variables["head1"] != "gone" {
+			t.Fatalf("unexpected variables: %#v", request.Variables)
+		}
+		if !strings.Contains(request.Query, "pulls1: pullRequests(headRefName: $head1") {
+			t.Fatalf("query does not alias the second branch:\n%s", request.Query)
+		}
+		_, _ = w.Write([]byte(graphQLBranchesResponse))
+	}))
+	defer server.Close()
+
+	githubSDK := newLiveGitHubSDK()
+	githubSDK.apiBaseURL = server.URL
+	githubSDK.SetAuthToken("test-token")
+
+	results, err := githubSDK.GetBranchDataBatch(context.Background(), "owner", "repo", []string{"feature", "gone"})
+	if err != nil {
+		t.Fatalf("GetBranchDataBatch: %v", err)
+	}
+	if gone := results["gone"]; gone.BranchExists || gone.PullRequest != nil {
+		t.Fatalf("unexpected data for missing branch: %#v", gone)
+	}
+	feature := results["feature"]
+	if !feature.BranchExists || feature.PullRequest == nil || feature.PullRequest.Number != 7 {
+		t.Fatalf("expected PR #7 from the repository owner, got %#v", feature)
+	}
+	// The rollup says FAILURE because of the check run; like REST, the
+	// combined status only covers status contexts.
+	if state := feature.PullRequest.CI.CombinedStatus.State; state != "success" {
+		t.Fatalf("combined status = %q, want success", state)
+	}
+
+	mergedAt := time.Date(2026, time.March, 24, 12, 0, 0, 0, time.UTC)
+	conclusion := "failure"
+	line := 12
+	rest := &GitHubPullRequest{
+		Number:             7,
+		State:              "closed",
+		MergedAt:           &mergedAt,
+		ClosedAt:           &mergedAt,
+		CreatedAt:          time.Date(2026, time.March, 20, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:          mergedAt,
+		Title:              "Ship it",
+		HTMLURL:            "https://github.com/owner/repo/pull/7",
+		MergeableState:     "unknown",
+		RequestedReviewers: []GitHubUser{{Login: "bob"}},
+		RequestedTeams:     []GitHubTeam{{Slug: "core"}},
+		Head:               GitHubBranchRef{Ref: "feature", SHA: "abc"},
+		Base:               GitHubBranchRef{Ref: "main", SHA: "def"},
+		Reviews:            []GitHubReview{{User: GitHubUser{Login: "alice"}, State: "APPROVED"}},
+		ReviewComments: []GitHubReviewComment{{
+			ID:        11,
+			User:      GitHubUser{Login: "alice"},
+			Path:      "main.go",
+			Line:      &line,
+			Body:      "Rename this",
+			HTMLURL:   "https://github.com/owner/repo/pull/7#discussion_r11",
+			CreatedAt: time.Date(2026, time.March, 21, 12, 0, 0, 0, time.UTC),
+			Resolved:  true,
+		}},
+		ResolutionKnown: true,
+		CI: GitHubCIStatusResult{
+			CombinedStatus: GitHubCombinedStatus{State: "success", Statuses: []GitHubStatusContext{{Context: "ci/build", State: "success", Description: "ok", TargetURL: "https://ci/1"}}},
+			CheckRuns:      []GitHubCheckRun{{Name: "test", Status: "completed", Conclusion: &conclusion, HTMLURL: "https://github.com/runs/1"}},
+		},
+	}
+	remoteURL := "git@github.com:owner/repo.git"
+	got := normalizeGitHubPullRequest(remoteURL, "owner", "repo", feature.PullRequest)
+	want := normalizeGitHubPullRequest(remoteURL, "owner", "repo", rest)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("graphql snapshot differs from rest snapshot:\n got %#v\nwant %#v", got, want)
+	}
+}
+
+func TestLiveGitHubSDKGetBranchDataBatchPagesReviewThreads(t *testing.T) {
+	var requests []githubGraphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request githubGraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		requests = append(requests, request)
+		switch {
+		case strings.Contains(request.Query, "repository(owner:"):
+			_, _ = w.Write([]byte(`{"data": {"repository": {
+  "ref0": {"name": "feature"},
+  "pulls0": {"nodes": [{
+    "id": "PR_7", "number": 7, "state": "OPEN", "headRefName": "feature", "headRepositoryOwner": {"login": "owner"},
+    "reviewThreads": {"pageInfo": {"hasNextPage": true, "endCursor": "threads-1"}, "nodes": [
+      {"id": "T_1", "path": "a.go", "comments": {"pageInfo": {"hasNextPage": false}, "nodes": [{"databaseId": 1, "body": "first"}]}}
+    ]}
+  }]}
+}}}`))
+		case strings.Contains(request.Query, "reviewThreads(first:"):
+			if request.Variables["id"] != "PR_7" || request.Variables["after"] != "threads-1" {
+				t.Fatalf("unexpected thread page variables: %#v", request.Variables)
+			}
+			_, _ = w.Write([]byte(`{"data": {"node": {"reviewThreads": {"pageInfo": {"hasNextPage": false}, "nodes": [
+  {"id": "T_2", "path": "b.go", "comments": {"pageInfo": {"hasNextPage": true, "endCursor": "comments-1"}, "nodes": [{"databaseId": 2, "body": "second"}]}}
+]}}}}`))
+		case strings.Contains(request.Query, "comments(first:"):
+			if request.Variables["id"] != "T_2" || request.Variables["after"] != "comments-1" {
+				t.Fatalf("unexpected comment page variables: %#v", request.Variables)
+			}
+			_, _ = w.Write([]byte(`{"data": {"node": {"comments": {"pageInfo": {"hasNextPage": false}, "nodes": [{"databaseId": 3, "body": "third"}]}}}}`))
+		default:
+			t.Fatalf("unexpected query:\n%s", request.Query)
+		}
+	}))
+	defer server.Close()
+
+	githubSDK := newLiveGitHubSDK()
+	githubSDK.apiBaseURL = server.URL
+	githubSDK.SetAuthToken("test-token")
+
+	results, err := githubSDK.GetBranchDataBatch(context.Background(), "owner", "repo", []string{"feature"})
+	if err != nil {
+		t.Fatalf("GetBranchDataBatch: %v", err)
+	}
+	pull := results["feature"].PullRequest
+	if pull == nil {
+		t.Fatalf("expected a pull request, got %#v", results["feature"])
+	}
+	ids := []int64{}
+	for _, comment := range pull.ReviewComments {
+		ids = append(ids, comment.ID)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3}) || len(requests) != 3 {
+		t.Fatalf("review comment ids = %v after %d requests, want [1 2 3] after 3", ids, len(requests))
+	}
+}
+
+func TestLiveGitHubSDKGetBranchDataBatchReportsGraphQLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"repository":null},"errors":[{"message":"Could not resolve to a Repository"}]}`))
+	}))
+	defer server.Close()
+
+	githubSDK := newLiveGitHubSDK()
+	githubSDK.apiBaseURL = server.URL
+	githubSDK.SetAuthToken("test-token")
+
+	if _, err := githubSDK.GetBranchDataBatch(context.Background(), "owner", "missing", []string{"feature"}); !errors.Is(err, ErrGitHubGraphQLUnavailable) {
+		t.Fatalf("GetBranchDataBatch error = %v, want ErrGitHubGraphQLUnavailable", err)
+	}
+}
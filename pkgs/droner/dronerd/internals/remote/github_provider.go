@@ -2,24 +2,35 @@ package remote
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/env"
 )
 
+const githubRefreshQueueSize = 64
+
 type roundRobinGitHubProvider struct {
 	githubSDK     GitHubSDK
 	pollIntervalD time.Duration
 	eventHandler  BranchEventHandler
 	stop          context.CancelFunc
+	// refresh carries subscriptions a webhook asked to poll out of turn.
+	refresh chan subscriptionKey
 
 	mu            sync.Mutex
 	state         map[subscriptionKey]githubBranchState
 	subscriptions map[subscriptionKey]struct{}
 	order         []subscriptionKey
 	nextIndex     int
+	pending       map[subscriptionKey]struct{}
+	// batched holds keys refreshed alongside another key of their repository,
+	// which the round robin skips once so each tick polls a new repository.
+	batched map[subscriptionKey]struct{}
 }
 
 type githubBranchState struct {
@@ -28,7 +39,13 @@ type githubBranchState struct {
 }
 
 func newGithubProvider(handler BranchEventHandler) remoteProvider {
-	return newGithubProviderDetailed(newLiveGitHubSDK(), handler, time.Duration(conf.GetConfig().Providers.Github.PollInterval))
+	config := conf.GetConfig().Providers.Github
+	interval := conf.PollDuration(config.PollInterval)
+	// With webhooks delivering changes, polling only reconciles missed deliveries.
+	if env.Get().GITHUB_WEBHOOK_SECRET != "" {
+		interval = conf.PollDuration(config.Webhook.ReconcileInterval)
+	}
+	return newGithubProviderDetailed(newLiveGitHubSDK(), handler, interval)
 }
 
 func newGithubProviderDetailed(gh GitHubSDK, handler BranchEventHandler, interval time.Duration) *roundRobinGitHubProvider {
@@ -38,6 +55,9 @@ func newGithubProviderDetailed(gh GitHubSDK, handler BranchEventHandler, interva
 		pollIntervalD: interval,
 		state:         make(map[subscriptionKey]githubBranchState),
 		subscriptions: make(map[subscriptionKey]struct{}),
+		pending:       make(map[subscriptionKey]struct{}),
+		batched:       make(map[subscriptionKey]struct{}),
+		refresh:       make(chan subscriptionKey, githubRefreshQueueSize),
 		stop:          cancel,
 		eventHandler:  handler,
 	}
@@ -50,7 +70,7 @@ func (p *roundRobinGitHubProvider) isValidKey(key subscriptionKey) bool {
 	return isGitHubURL(key.remoteURL)
 }
 
-func (p *roundRobinGitHubProvider) ensureAuth() error {
+func (p *roundRobinGitHubProvider) ensureAuth(key subscriptionKey) error {
 	return p.githubSDK.EnsureAuth()
 }
 
@@ -88,6 +108,8 @@ func (p *roundRobinGitHubProvider) unsubscribe(key subscriptionKey) {
 
 	delete(p.subscriptions, key)
 	delete(p.state, key)
+	delete(p.pending, key)
+	delete(p.batched, key)
 
 	// TODO this is nasty maybe we can do something different
 	for i, candidate := range p.order {
@@ -126,10 +148,76 @@ func (p *roundRobinGitHubProvider) run(ctx context.Context) {
 			return
 		case <-ticker.C:
 			_ = p.pollNext(ctx)
+		case key := <-p.refresh:
+			_ = p.pollQueued(ctx, key)
 		}
 	}
 }
 
+// queueWebhookRefresh schedules every subscription matching target for an
+// immediate poll. Keys already waiting are not queued twice, and a full queue
+// drops the refresh because the reconciliation poll will catch up.
+func (p *roundRobinGitHubProvider) queueWebhookRefresh(target githubWebhookTarget) int {
+	logger := githubProviderLogger()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	queued := 0
+	for _, key := range p.order {
+		headSHA := ""
+		if pull := p.state[key].data.PullRequest; pull != nil {
+			headSHA = pull.Head.SHA
+		}
+		if !target.matches(key, headSHA) {
+			continue
+		}
+		if _, waiting := p.pending[key]; waiting {
+			queued++
+			continue
+		}
+		select {
+		case p.refresh <- key:
+			p.pending[key] = struct{}{}
+			queued++
+		default:
+			logger.Debug("webhook refresh dropped queue full",
+				"remote_url", key.remoteURL,
+				"branch", key.branch,
+			)
+		}
+	}
+	return queued
+}
+
+func (p *roundRobinGitHubProvider) pollQueued(ctx context.Context, key subscriptionKey) error {
+	p.mu.Lock()
+	_, waiting := p.pending[key]
+	delete(p.pending, key)
+	delete(p.batched, key)
+	p.mu.Unlock()
+	if !waiting {
+		return nil
+	}
+	if !p.githubSDK.IsAuthenticated() {
+		githubProviderLogger().Debug("webhook refresh skipped unauthenticated")
+		return nil
+	}
+	// The reconciliation poll picks the key up again once the backoff ends.
+	if rateLimit := p.githubSDK.RateLimit(); rateLimit.BackingOff(time.Now()) {
+		githubProviderLogger().Debug("webhook refresh skipped rate limited",
+			"remote_url", key.remoteURL,
+			"branch", key.branch,
+			"backoff_until", rateLimit.BackoffUntil,
+		)
+		return nil
+	}
+	githubProviderLogger().Debug("webhook refresh starting",
+		"remote_url", key.remoteURL,
+		"branch", key.branch,
+	)
+	return p.pollRepository(ctx, key)
+}
+
 func (p *roundRobinGitHubProvider) pollNext(ctx context.Context) error {
 	logger := githubProviderLogger()
 	if !p.githubSDK.IsAuthenticated() {
@@ -137,6 +225,15 @@ func (p *roundRobinGitHubProvider) pollNext(ctx context.Context) error {
 		return nil
 	}
 
+	if rateLimit := p.githubSDK.RateLimit(); rateLimit.BackingOff(time.Now()) {
+		logger.Debug("poll skipped rate limited",
+			"backoff_until", rateLimit.BackoffUntil,
+			"reason", rateLimit.BackoffReason,
+			"remaining", rateLimit.Remaining,
+		)
+		return nil
+	}
+
 	key, ok := p.nextSubscription()
 	if !ok {
 		logger.Debug("poll skipped no subscriptions")
@@ -146,7 +243,7 @@ func (p *roundRobinGitHubProvider) pollNext(ctx context.Context) error {
 		"remote_url", key.remoteURL,
 		"branch", key.branch,
 	)
-	return p.pollSubscription(ctx, key)
+	return p.pollRepository(ctx, key)
 }
 
 func (p *roundRobinGitHubProvider) nextSubscription() (subscriptionKey, bool) {
@@ -155,14 +252,113 @@ func (p *roundRobinGitHubProvider) nextSubscription() (subscriptionKey, bool) {
 	if len(p.order) == 0 {
 		return subscriptionKey{}, false
 	}
-	if p.nextIndex >= len(p.order) {
-		p.nextIndex = 0
+	for range p.order {
+		if p.nextIndex >= len(p.order) {
+			p.nextIndex = 0
+		}
+		key := p.order[p.nextIndex]
+		p.nextIndex = (p.nextIndex + 1) % len(p.order)
+		if _, skip := p.batched[key]; skip {
+			delete(p.batched, key)
+			continue
+		}
+		return key, true
 	}
+	// Every key was batched; start a new round with the next one.
 	key := p.order[p.nextIndex]
 	p.nextIndex = (p.nextIndex + 1) % len(p.order)
 	return key, true
 }
 
+// repositoryKeys returns key followed by every other subscription on the same
+// GitHub repository.
+func (p *roundRobinGitHubProvider) repositoryKeys(key subscriptionKey, owner string, repo string) []subscriptionKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := []subscriptionKey{key}
+	for _, candidate := range p.order {
+		if candidate == key {
+			continue
+		}
+		candidateOwner, candidateRepo, err := parseGitHubURL(candidate.remoteURL)
+		if err == nil && strings.EqualFold(candidateOwner, owner) && strings.EqualFold(candidateRepo, repo) {
+			keys = append(keys, candidate)
+		}
+	}
+	return keys
+}
+
+// pollRepository refreshes key and the other subscriptions on its repository
+// with one batched GraphQL query, falling back to polling key over REST.
+func (p *roundRobinGitHubProvider) pollRepository(ctx context.Context, key subscriptionKey) error {
+	logger := githubProviderLogger()
+	owner, repo, err := parseGitHubURL(key.remoteURL)
+	if err != nil {
+		return p.pollSubscription(ctx, key)
+	}
+	keys := p.repositoryKeys(key, owner, repo)
+	branches := make([]string, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, candidate := range keys {
+		if _, exists := seen[candidate.branch]; !exists {
+			seen[candidate.branch] = struct{}{}
+			branches = append(branches, candidate.branch)
+		}
+	}
+
+	results, err := p.githubSDK.GetBranchDataBatch(ctx, owner, repo, branches)
+	if err != nil {
+		if errors.Is(err, ErrGitHubRateLimited) {
+			return err
+		}
+		logger.Debug("batch poll failed falling back to rest",
+			"owner", owner,
+			"repo", repo,
+			"branch_count", len(branches),
+			"error", err,
+		)
+		return p.pollSubscription(ctx, key)
+	}
+	logger.Debug("batch poll completed",
+		"owner", owner,
+		"repo", repo,
+		"branch_count", len(branches),
+		"rate_limit_remaining", p.githubSDK.RateLimit().Remaining,
+	)
+
+	p.mu.Lock()
+	for _, candidate := range keys[1:] {
+		if _, subscribed := p.subscriptions[candidate]; subscribed {
+			p.batched[candidate] = struct{}{}
+		}
+	}
+	p.mu.Unlock()
+	for _, candidate := range keys {
+		p.emit(p.storeBranchData(candidate, results[candidate.branch]))
+	}
+	return nil
+}
+
+func (p *roundRobinGitHubProvider) emit(events []BranchEvent) {
+	logger := githubProviderLogger()
+	handler := p.currentEventHandler()
+	if handler == nil {
+		logger.Debug("poll has no handler",
+			"event_count", len(events),
+		)
+		return
+	}
+	for _, event := range events {
+		logger.Debug("emitting event",
+			"event_type", event.Type,
+			"remote_url", event.RemoteURL,
+			"branch", event.Branch,
+			"pr_number", event.PRNumber,
+		)
+		handler(event)
+	}
+}
+
 func (p *roundRobinGitHubProvider) pollSubscription(ctx context.Context, key subscriptionKey) error {
 	logger := githubProviderLogger()
 	if !isGitHubURL(key.remoteURL) {
@@ -182,33 +378,18 @@ func (p *roundRobinGitHubProvider) pollSubscription(ctx context.Context, key sub
 		)
 		return err
 	}
+	rateLimit := p.githubSDK.RateLimit()
 	logger.Debug("poll completed",
 		"remote_url", key.remoteURL,
 		"branch", key.branch,
 		"branch_exists", branchData.BranchExists,
 		"has_pull_request", branchData.PullRequest != nil,
+		"rate_limit_remaining", rateLimit.Remaining,
+		"rate_limit_reset", rateLimit.ResetAt,
+		"not_modified", rateLimit.NotModified,
 	)
 
-	events := p.storeBranchData(key, branchData)
-	handler := p.currentEventHandler()
-	if handler == nil {
-		logger.Debug("poll has no handler",
-			"remote_url", key.remoteURL,
-			"branch", key.branch,
-			"event_count", len(events),
-		)
-		return nil
-	}
-
-	for _, event := range events {
-		logger.Debug("emitting event",
-			"event_type", event.Type,
-			"remote_url", event.RemoteURL,
-			"branch", event.Branch,
-			"pr_number", event.PRNumber,
-		)
-		handler(event)
-	}
+	p.emit(p.storeBranchData(key, branchData))
 	return nil
 }
 
@@ -251,7 +432,7 @@ func diffGitHubBranchState(key subscriptionKey, previous GitHubBranchData, curre
 	if currentPR != nil {
 		previousSnapshot := normalizeGitHubPullRequestForKey(key, previousPR)
 		currentSnapshot := normalizeGitHubPullRequestForKey(key, currentPR)
-		if !reflect.DeepEqual(previousSnapshot, currentSnapshot) {
+		if !sameGitHubPullRequestSnapshot(previousSnapshot, currentSnapshot) {
 			prState := currentPR.State
 			number := currentPR.Number
 			events = append(events, BranchEvent{Type: PRObserved, RemoteURL: key.remoteURL, Branch: key.branch, PRNumber: &number, PRState: &prState, PRSnapshot: currentSnapshot, Timestamp: now})
@@ -273,6 +454,30 @@ func diffGitHubBranchState(key subscriptionKey, previous GitHubBranchData, curre
 	return events
 }
 
+// sameGitHubPullRequestSnapshot compares two snapshots, ignoring review
+// comment resolution when only one side came from GraphQL. Polls fall back to
+// REST and back again, and REST reports every comment as unresolved.
+func sameGitHubPullRequestSnapshot(previous *PullRequestSnapshot, current *PullRequestSnapshot) bool {
+	if previous != nil && current != nil && previous.ResolutionKnown != current.ResolutionKnown {
+		previous = withoutReviewCommentResolution(*previous)
+		current = withoutReviewCommentResolution(*current)
+	}
+	return reflect.DeepEqual(previous, current)
+}
+
+func withoutReviewCommentResolution(snapshot PullRequestSnapshot) *PullRequestSnapshot {
+	snapshot.ResolutionKnown = false
+	if snapshot.ReviewComments != nil {
+		comments := make([]ReviewComment, len(snapshot.ReviewComments))
+		for i, comment := range snapshot.ReviewComments {
+			comment.Resolved = false
+			comments[i] = comment
+		}
+		snapshot.ReviewComments = comments
+	}
+	return &snapshot
+}
+
 func initialGitHubBranchEvents(key subscriptionKey, current GitHubBranchData) []BranchEvent {
 	events := make([]BranchEvent, 0, 3)
 	now := time.Now()
@@ -299,3 +504,49 @@ func normalizeGitHubPullRequestForKey(key subscriptionKey, pull *GitHubPullReque
 	owner, repo, _ := parseGitHubURL(key.remoteURL)
 	return normalizeGitHubPullRequest(key.remoteURL, owner, repo, pull)
 }
+
+func (p *roundRobinGitHubProvider) createPullRequest(ctx context.Context, key subscriptionKey, input CreatePullRequestInput) (PullRequestSnapshot, error) {
+	owner, repo, err := parseGitHubURL(key.remoteURL)
+	if err != nil {
+		return PullRequestSnapshot{}, err
+	}
+	pull, err := p.githubSDK.CreatePullRequest(ctx, owner, repo, GitHubCreatePullRequest{
+		Title: input.Title,
+		Head:  key.branch,
+		Base:  input.Base,
+		Body:  input.Body,
+		Draft: input.Draft,
+	})
+	if err != nil {
+		return PullRequestSnapshot{}, err
+	}
+	// Checks have not reported on a pull request that was just opened.
+	if pull.CI.CombinedStatus.State == "" {
+		pull.CI.CombinedStatus.State = "pending"
+	}
+	// Poll out of turn so reviews and checks follow without a full interval.
+	p.queueWebhookRefresh(githubWebhookTarget{Owner: owner, Repo: repo, Branches: []string{key.branch}})
+	return *normalizeGitHubPullRequest(key.remoteURL, owner, repo, &pull), nil
+}
+
+func (p *roundRobinGitHubProvider) fetchCIFailures(ctx context.Context, key subscriptionKey, snapshot PullRequestSnapshot) ([]CIFailure, error) {
+	owner, repo, err := parseGitHubURL(key.remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	ci, err := p.githubSDK.GetCIStatus(ctx, owner, repo, snapshot.HeadSHA)
+	if err != nil {
+		return nil, err
+	}
+	return githubCIFailures(ci), nil
+}
+
+// GitHubRateLimitStatus returns the quota state of the GitHub provider.
+func GitHubRateLimitStatus() GitHubRateLimit {
+	for _, p := range getRegistry().providers {
+		if github, ok := p.(*roundRobinGitHubProvider); ok {
+			return github.githubSDK.RateLimit()
+		}
+	}
+	return GitHubRateLimit{}
+}
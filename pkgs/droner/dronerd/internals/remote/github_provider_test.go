@@ -2,6 +2,7 @@ package remote
 
 import (
 	"context"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -38,6 +39,15 @@ type fakeGitHubSDK struct {
 	branchData    map[subscriptionKey][]GitHubBranchData
 	branchCalls   []subscriptionKey
 	branchDataErr error
+	rateLimit     GitHubRateLimit
+	// graphQL enables GetBranchDataBatch; without it polls fall back to REST.
+	graphQL    bool
+	batchCalls [][]string
+	created    []GitHubCreatePullRequest
+	createPR   GitHubPullRequest
+	createErr  error
+	ci         GitHubCIStatusResult
+	ciCalls    []string
 }
 
 func newFakeGitHubSDK() *fakeGitHubSDK {
@@ -63,6 +73,47 @@ func (s *fakeGitHubSDK) SetAuthToken(token string) {
 	s.authenticated = strings.TrimSpace(token) != ""
 }
 
+func (s *fakeGitHubSDK) GetBranchDataBatch(ctx context.Context, owner string, repo string, branches []string) (map[string]GitHubBranchData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.graphQL {
+		return nil, ErrGitHubGraphQLUnavailable
+	}
+	s.batchCalls = append(s.batchCalls, append([]string(nil), branches...))
+	results := make(map[string]GitHubBranchData, len(branches))
+	for _, branch := range branches {
+		key := subscriptionKey{remoteURL: "git@github.com:" + owner + "/" + repo + ".git", branch: branch}
+		responses := s.branchData[key]
+		if len(responses) == 0 {
+			results[branch] = GitHubBranchData{}
+			continue
+		}
+		results[branch] = responses[0]
+		s.branchData[key] = responses[1:]
+	}
+	return results, nil
+}
+
+func (s *fakeGitHubSDK) CreatePullRequest(ctx context.Context, owner string, repo string, request GitHubCreatePullRequest) (GitHubPullRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.created = append(s.created, request)
+	return s.createPR, s.createErr
+}
+
+func (s *fakeGitHubSDK) GetCIStatus(ctx context.Context, owner string, repo string, sha string) (GitHubCIStatusResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ciCalls = append(s.ciCalls, sha)
+	return s.ci, nil
+}
+
+func (s *fakeGitHubSDK) RateLimit() GitHubRateLimit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rateLimit
+}
+
 func (s *fakeGitHubSDK) GetBranchData(ctx context.Context, remoteURL string, branch string) (GitHubBranchData, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -157,6 +208,41 @@ func TestRoundRobinGitHubProviderEmitsTerminalEvents(t *testing.T) {
 	}
 }
 
+func TestRoundRobinGitHubProviderIgnoresResolutionLostToRESTFallback(t *testing.T) {
+	githubSDK := newFakeGitHubSDK()
+	githubSDK.SetAuthToken("token")
+	handler := func(e BranchEvent) {}
+	provider := newGithubProviderDetailed(githubSDK, handler, time.Hour)
+	defer provider.close()
+
+	key := subscriptionKey{remoteURL: "git@github.com:org/repo.git", branch: "feature"}
+	comment := GitHubReviewComment{ID: 11, User: GitHubUser{Login: "alice"}, Path: "main.go", Body: "nit"}
+	resolved := comment
+	resolved.Resolved = true
+	githubSDK.branchData[key] = []GitHubBranchData{
+		{BranchExists: true, PullRequest: &GitHubPullRequest{Number: 7, State: "open", ReviewComments: []GitHubReviewComment{resolved}, ResolutionKnown: true}},
+		{BranchExists: true, PullRequest: &GitHubPullRequest{Number: 7, State: "open", ReviewComments: []GitHubReviewComment{comment}}},
+		{BranchExists: true, PullRequest: &GitHubPullRequest{Number: 7, State: "open", ReviewComments: []GitHubReviewComment{resolved}, ResolutionKnown: true}},
+	}
+
+	received := make(chan BranchEvent, 3)
+	provider.eventHandler = func(event BranchEvent) {
+		received <- event
+	}
+	provider.subscribe(key)
+
+	for i := 0; i < 3; i++ {
+		if err := provider.pollNext(context.Background()); err != nil {
+			t.Fatalf("pollNext %d: %v", i, err)
+		}
+	}
+
+	if event := expectEvent(t, received); event.Type != PRObserved {
+		t.Fatalf("expected initial PRObserved, got %s", event.Type)
+	}
+	expectNoEvent(t, received)
+}
+
 func TestRoundRobinGitHubProviderEmitsTerminalEventsFromInitialState(t *testing.T) {
 	githubSDK := newFakeGitHubSDK()
 	githubSDK.SetAuthToken("token")
@@ -251,7 +337,7 @@ func TestRoundRobinGitHubProviderDelegatesEnsureAuth(t *testing.T) {
 	provider := newGithubProviderDetailed(githubSDK, handler, time.Hour)
 	defer provider.close()
 
-	if err := provider.ensureAuth(); err != nil {
+	if err := provider.ensureAuth(subscriptionKey{}); err != nil {
 		t.Fatalf("ensureAuth: %v", err)
 	}
 
@@ -261,3 +347,155 @@ func TestRoundRobinGitHubProviderDelegatesEnsureAuth(t *testing.T) {
 		t.Fatalf("unexpected ensure auth calls: %#v", githubSDK.ensureCalls)
 	}
 }
+
+func TestRoundRobinGitHubProviderCreatesPullRequest(t *testing.T) {
+	githubSDK := newFakeGitHubSDK()
+	githubSDK.SetAuthToken("token")
+	githubSDK.createPR = GitHubPullRequest{Number: 9, State: "open", Title: "Add feature", Head: GitHubBranchRef{Ref: "feature", SHA: "abc"}, Base: GitHubBranchRef{Ref: "main"}}
+	provider := &roundRobinGitHubProvider{
+		githubSDK: githubSDK,
+		state:     make(map[subscriptionKey]githubBranchState),
+		pending:   make(map[subscriptionKey]struct{}),
+		refresh:   make(chan subscriptionKey, githubRefreshQueueSize),
+	}
+	key := subscriptionKey{remoteURL: "git@github.com:org/repo.git", branch: "feature"}
+	provider.order = []subscriptionKey{key}
+
+	snapshot, err := provider.createPullRequest(context.Background(), key, CreatePullRequestInput{Branch: "feature", Base: "main", Title: "Add feature", Body: "details", Draft: true})
+	if err != nil {
+		t.Fatalf("createPullRequest: %v", err)
+	}
+	if snapshot.Provider != "github" || snapshot.RepoOwner != "org" || snapshot.RepoName != "repo" || snapshot.Number != 9 {
+		t.Fatalf("unexpected snapshot: %#v", snapshot)
+	}
+	if snapshot.CI.State != "pending" {
+		t.Fatalf("expected pending CI for a new pull request, got %q", snapshot.CI.State)
+	}
+	want := GitHubCreatePullRequest{Title: "Add feature", Head: "feature", Base: "main", Body: "details", Draft: true}
+	if len(githubSDK.created) != 1 || githubSDK.created[0] != want {
+		t.Fatalf("unexpected create requests: %#v", githubSDK.created)
+	}
+	select {
+	case queued := <-provider.refresh:
+		if queued != key {
+			t.Fatalf("unexpected refresh key: %#v", queued)
+		}
+	default:
+		t.Fatalf("expected a refresh to be queued after creating the pull request")
+	}
+}
+
+func TestRoundRobinGitHubProviderFetchesCIFailureOutput(t *testing.T) {
+	failure := "failure"
+	success := "success"
+	githubSDK := newFakeGitHubSDK()
+	githubSDK.ci = GitHubCIStatusResult{
+		CombinedStatus: GitHubCombinedStatus{State: "failure", Statuses: []GitHubStatusContext{
+			{Context: "lint", State: "error", Description: "2 lint errors", TargetURL: "https://ci.example/lint"},
+			{Context: "deploy", State: "success"},
+		}},
+		CheckRuns: []GitHubCheckRun{
+			{Name: "test", Status: "completed", Conclusion: &failure, HTMLURL: "https://github.com/org/repo/runs/1", Output: GitHubCheckRunOutput{Title: "1 test failed", Summary: "TestFoo", Text: "  "}},
+			{Name: "build", Status: "completed", Conclusion: &success},
+			{Name: "e2e", Status: "in_progress"},
+		},
+	}
+	provider := &roundRobinGitHubProvider{githubSDK: githubSDK}
+	key := subscriptionKey{remoteURL: "git@github.com:org/repo.git", branch: "feature"}
+
+	failures, err := provider.fetchCIFailures(context.Background(), key, PullRequestSnapshot{HeadSHA: "abc"})
+	if err != nil {
+		t.Fatalf("fetchCIFailures: %v", err)
+	}
+	want := []CIFailure{
+		{Name: "lint", TargetURL: "https://ci.example/lint", Summary: "2 lint errors"},
+		{Name: "test", TargetURL: "https://github.com/org/repo/runs/1", Summary: "1 test failed\n\nTestFoo"},
+	}
+	if !reflect.DeepEqual(failures, want) {
+		t.Fatalf("failures = %#v, want %#v", failures, want)
+	}
+	if len(githubSDK.ciCalls) != 1 || githubSDK.ciCalls[0] != "abc" {
+		t.Fatalf("unexpected CI calls: %#v", githubSDK.ciCalls)
+	}
+}
+
+func TestFetchCIFailuresFallsBackToSnapshotStatuses(t *testing.T) {
+	failures, err := FetchCIFailures(context.Background(), PullRequestSnapshot{
+		RemoteURL: "https://example.com/owner/repo.git",
+		HeadRef:   "feature",
+		CI: CIStatusSummary{State: "failing", Statuses: []CIStatusContext{
+			{Name: "build", State: "passing"},
+			{Name: "test", State: "failing", Description: "exit status 1", TargetURL: "https://ci.example/1"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("FetchCIFailures: %v", err)
+	}
+	if len(failures) != 1 || failures[0] != (CIFailure{Name: "test", TargetURL: "https://ci.example/1", Summary: "exit status 1"}) {
+		t.Fatalf("unexpected failures: %#v", failures)
+	}
+}
+
+func TestRoundRobinGitHubProviderSkipsPollsWhileRateLimited(t *testing.T) {
+	githubSDK := newFakeGitHubSDK()
+	githubSDK.SetAuthToken("token")
+	githubSDK.rateLimit = GitHubRateLimit{Remaining: 12, BackoffUntil: time.Now().Add(time.Hour), BackoffReason: "low remaining quota"}
+	provider := newGithubProviderDetailed(githubSDK, nil, time.Hour)
+	defer provider.close()
+
+	provider.subscribe(subscriptionKey{remoteURL: "git@github.com:org/repo.git", branch: "feature"})
+	if err := provider.pollNext(context.Background()); err != nil {
+		t.Fatalf("pollNext: %v", err)
+	}
+
+	githubSDK.mu.Lock()
+	defer githubSDK.mu.Unlock()
+	if len(githubSDK.branchCalls) != 0 {
+		t.Fatalf("expected no SDK calls while backing off, got %#v", githubSDK.branchCalls)
+	}
+}
+
+func TestRoundRobinGitHubProviderBatchesSubscriptionsPerRepository(t *testing.T) {
+	githubSDK := newFakeGitHubSDK()
+	githubSDK.SetAuthToken("token")
+	githubSDK.graphQL = true
+	received := make(chan BranchEvent, 4)
+	provider := newGithubProviderDetailed(githubSDK, func(event BranchEvent) { received <- event }, time.Hour)
+	defer provider.close()
+
+	first := subscriptionKey{remoteURL: "git@github.com:org/repo.git", branch: "one"}
+	other := subscriptionKey{remoteURL: "git@github.com:org/other.git", branch: "three"}
+	second := subscriptionKey{remoteURL: "git@github.com:org/repo.git", branch: "two"}
+	openPR := GitHubBranchData{BranchExists: true, PullRequest: &GitHubPullRequest{Number: 9, State: "open"}}
+	githubSDK.branchData[second] = []GitHubBranchData{openPR, openPR}
+	provider.subscribe(first)
+	provider.subscribe(other)
+	provider.subscribe(second)
+
+	for i := 0; i < 3; i++ {
+		if err := provider.pollNext(context.Background()); err != nil {
+			t.Fatalf("pollNext %d: %v", i, err)
+		}
+	}
+
+	event := expectEvent(t, received)
+	if event.Type != PRObserved || event.Branch != "two" || event.PRSnapshot == nil {
+		t.Fatalf("unexpected event: %#v", event)
+	}
+	expectNoEvent(t, received)
+
+	githubSDK.mu.Lock()
+	defer githubSDK.mu.Unlock()
+	if len(githubSDK.branchCalls) != 0 {
+		t.Fatalf("expected no REST polls, got %#v", githubSDK.branchCalls)
+	}
+	want := [][]string{{"one", "two"}, {"three"}, {"one", "two"}}
+	if len(githubSDK.batchCalls) != len(want) {
+		t.Fatalf("batch calls = %#v, want %#v", githubSDK.batchCalls, want)
+	}
+	for i := range want {
+		if strings.Join(githubSDK.batchCalls[i], ",") != strings.Join(want[i], ",") {
+			t.Fatalf("batch call %d = %#v, want %#v", i, githubSDK.batchCalls[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package remote
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// githubLowQuotaRemaining pauses polling until the window resets so the
+	// quota shared with gh is not exhausted by droner alone.
+	githubLowQuotaRemaining = 100
+	// githubSecondaryBackoff is used when a secondary rate limit response has
+	// no Retry-After header.
+	githubSecondaryBackoff = time.Minute
+	githubETagCacheSize    = 1024
+)
+
+var ErrGitHubRateLimited = errors.New("github rate limit exceeded")
+
+// GitHubRateLimit is the quota state last reported by the GitHub API.
+type GitHubRateLimit struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Used      int
+	ResetAt   time.Time
+	UpdatedAt time.Time
+	// BackoffUntil is set while polling is paused.
+	BackoffUntil  time.Time
+	BackoffReason string
+	Requests      int64
+	// NotModified counts conditional requests answered with 304, which do
+	// not count against the quota.
+	NotModified int64
+}
+
+// BackingOff reports whether polling should wait at now.
+func (r GitHubRateLimit) BackingOff(now time.Time) bool {
+	return now.Before(r.BackoffUntil)
+}
+
+type githubETagEntry struct {
+	etag string
+	body []byte
+	next string
+}
+
+// githubRateLimiter records quota headers and caches ETags per URL so repeat
+// polls can be sent as conditional requests.
+type githubRateLimiter struct {
+	mu    sync.Mutex
+	state GitHubRateLimit
+	etags map[string]githubETagEntry
+	now   func() time.Time
+}
+
+func newGitHubRateLimiter() *githubRateLimiter {
+	return &githubRateLimiter{etags: make(map[string]githubETagEntry), now: time.Now}
+}
+
+func (l *githubRateLimiter) snapshot() GitHubRateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
+
+func (l *githubRateLimiter) etag(requestURL string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.etags[requestURL]
+	return entry.etag, ok
+}
+
+// cachedBody returns the body and next page URL stored with the ETag for a
+// 304 response.
+func (l *githubRateLimiter) cachedBody(requestURL string) ([]byte, string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.etags[requestURL]
+	return entry.body, entry.next, ok
+}
+
+func (l *githubRateLimiter) storeETag(requestURL string, etag string, body []byte, next string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if etag == "" {
+		delete(l.etags, requestURL)
+		return
+	}
+	if _, exists := l.etags[requestURL]; !exists && len(l.etags) >= githubETagCacheSize {
+		for evict := range l.etags {
+			delete(l.etags, evict)
+			break
+		}
+	}
+	l.etags[requestURL] = githubETagEntry{etag: etag, body: body, next: next}
+}
+
+// observe records the quota headers of a response and returns
+// ErrGitHubRateLimited when the response was rejected by a rate limit.
+func (l *githubRateLimiter) observe(resp *http.Response) error {
+	now := l.now()
+	header := resp.Header
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state.Requests++
+	if resp.StatusCode == http.StatusNotModified {
+		l.state.NotModified++
+	}
+	if limit, ok := headerInt(header, "X-RateLimit-Limit"); ok {
+		l.state.Limit = limit
+		l.state.Remaining, _ = headerInt(header, "X-RateLimit-Remaining")
+		l.state.Used, _ = headerInt(header, "X-RateLimit-Used")
+		l.state.Resource = header.Get("X-RateLimit-Resource")
+		if reset, ok := headerInt(header, "X-RateLimit-Reset"); ok {
+			l.state.ResetAt = time.Unix(int64(reset), 0).UTC()
+		}
+		l.state.UpdatedAt = now.UTC()
+	}
+
+	limited := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden && (header.Get("Retry-After") != "" || header.Get("X-RateLimit-Remaining") == "0")
+	switch {
+	case limited && header.Get("Retry-After") != "":
+		retryAfter, _ := headerInt(header, "Retry-After")
+		l.backoff(now.Add(time.Duration(retryAfter)*time.Second), "secondary rate limit")
+	case limited && header.Get("X-RateLimit-Remaining") == "0" && l.state.ResetAt.After(now):
+		l.backoff(l.state.ResetAt, "rate limit exhausted")
+	case limited:
+		l.backoff(now.Add(githubSecondaryBackoff), "secondary rate limit")
+	case l.state.Limit > 0 && l.state.Remaining <= githubLowQuotaRemaining && l.state.ResetAt.After(now):
+		l.backoff(l.state.ResetAt, "low remaining quota")
+	}
+	if limited {
+		return ErrGitHubRateLimited
+	}
+	return nil
+}
+
+func (l *githubRateLimiter) backoff(until time.Time, reason string) {
+	until = until.UTC()
+	if until.After(l.state.BackoffUntil) {
+		l.state.BackoffUntil = until
+		l.state.BackoffReason = reason
+	}
+}
+
+func headerInt(header http.Header, key string) (int, bool) {
+	value, err := strconv.Atoi(strings.TrimSpace(header.Get(key)))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
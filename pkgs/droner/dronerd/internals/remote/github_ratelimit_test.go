@@ -0,0 +1,115 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLiveGitHubSDKSendsConditionalRequests(t *testing.T) {
+	reset := time.Now().Add(30 * time.Minute).Unix()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(5000-requests))
+		w.Header().Set("X-RateLimit-Used", strconv.Itoa(requests))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"number":7}`))
+	}))
+	defer server.Close()
+
+	githubSDK := newLiveGitHubSDK()
+	githubSDK.apiBaseURL = server.URL
+	githubSDK.SetAuthToken("token")
+
+	for i := 0; i < 2; i++ {
+		status, body, err := githubSDK.doGET(context.Background(), server.URL+"/repos/org/repo/pulls/7")
+		if err != nil {
+			t.Fatalf("doGET %d: %v", i, err)
+		}
+		if status != http.StatusOK || string(body) != `{"number":7}` {
+			t.Fatalf("doGET %d = %d %s, want cached body", i, status, body)
+		}
+	}
+
+	rateLimit := githubSDK.RateLimit()
+	if rateLimit.Limit != 5000 || rateLimit.Remaining != 4998 || rateLimit.Resource != "core" {
+		t.Fatalf("unexpected rate limit: %#v", rateLimit)
+	}
+	if rateLimit.Requests != 2 || rateLimit.NotModified != 1 {
+		t.Fatalf("requests = %d not modified = %d, want 2 and 1", rateLimit.Requests, rateLimit.NotModified)
+	}
+	if rateLimit.ResetAt.Unix() != reset || rateLimit.BackingOff(time.Now()) {
+		t.Fatalf("unexpected reset or backoff: %#v", rateLimit)
+	}
+}
+
+func TestGitHubRateLimiterBacksOff(t *testing.T) {
+	now := time.Date(2026, time.March, 24, 12, 0, 0, 0, time.UTC)
+	reset := now.Add(20 * time.Minute)
+	response := func(status int, headers map[string]string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		for key, value := range headers {
+			resp.Header.Set(key, value)
+		}
+		return resp
+	}
+
+	limiter := newGitHubRateLimiter()
+	limiter.now = func() time.Time { return now }
+	lowQuota := response(http.StatusOK, map[string]string{
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Remaining": "50",
+		"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+	})
+	if err := limiter.observe(lowQuota); err != nil {
+		t.Fatalf("observe low quota: %v", err)
+	}
+	if state := limiter.snapshot(); !state.BackoffUntil.Equal(reset) || state.BackoffReason != "low remaining quota" {
+		t.Fatalf("low quota backoff = %#v, want until reset", state)
+	}
+
+	limiter = newGitHubRateLimiter()
+	limiter.now = func() time.Time { return now }
+	secondary := response(http.StatusForbidden, map[string]string{"Retry-After": "90"})
+	if err := limiter.observe(secondary); !errors.Is(err, ErrGitHubRateLimited) {
+		t.Fatalf("observe secondary = %v, want ErrGitHubRateLimited", err)
+	}
+	if state := limiter.snapshot(); !state.BackoffUntil.Equal(now.Add(90*time.Second)) || state.BackoffReason != "secondary rate limit" {
+		t.Fatalf("secondary backoff = %#v, want Retry-After", state)
+	}
+	if !limiter.snapshot().BackingOff(now.Add(time.Minute)) || limiter.snapshot().BackingOff(now.Add(2*time.Minute)) {
+		t.Fatal("expected backoff to end after Retry-After")
+	}
+
+	limiter = newGitHubRateLimiter()
+	limiter.now = func() time.Time { return now }
+	exhausted := response(http.StatusForbidden, map[string]string{
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+	})
+	if err := limiter.observe(exhausted); !errors.Is(err, ErrGitHubRateLimited) {
+		t.Fatalf("observe exhausted = %v, want ErrGitHubRateLimited", err)
+	}
+	if state := limiter.snapshot(); !state.BackoffUntil.Equal(reset) || state.BackoffReason != "rate limit exhausted" {
+		t.Fatalf("exhausted backoff = %#v, want until reset", state)
+	}
+
+	limiter = newGitHubRateLimiter()
+	limiter.now = func() time.Time { return now }
+	if err := limiter.observe(response(http.StatusForbidden, nil)); err != nil {
+		t.Fatalf("plain 403 = %v, want no rate limit error", err)
+	}
+}
@@ -2,8 +2,10 @@ package remote
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -57,6 +59,8 @@ func TestLiveGitHubSDKGetBranchData(t *testing.T) {
 			_, _ = w.Write([]byte(`{"number":42,"state":"closed","merged_at":"2026-03-24T12:00:00Z","updated_at":"2026-03-24T12:00:00Z","title":"Ship it","html_url":"https://github.com/owner/repo/pull/42","head":{"ref":"feature","sha":"abc"},"base":{"ref":"main"}}`))
 		case r.URL.Path == "/repos/owner/repo/pulls/42/reviews":
 			_, _ = w.Write([]byte(`[]`))
+		case r.URL.Path == "/repos/owner/repo/pulls/42/comments":
+			_, _ = w.Write([]byte(`[{"id":9,"user":{"login":"octocat"},"path":"main.go","line":12,"start_line":10,"body":"Handle the error","html_url":"https://github.com/owner/repo/pull/42#discussion_r9","created_at":"2026-03-24T11:00:00Z"}]`))
 		case r.URL.Path == "/repos/owner/repo/commits/abc/status":
 			_, _ = w.Write([]byte(`{"state":"success","statuses":[]}`))
 		case r.URL.Path == "/repos/owner/repo/commits/abc/check-runs":
@@ -93,6 +97,97 @@ func TestLiveGitHubSDKGetBranchData(t *testing.T) {
 	if data.PullRequest.MergedAt == nil || !data.PullRequest.MergedAt.Equal(mergedAt) {
 		t.Fatalf("unexpected merged time: %v", data.PullRequest.MergedAt)
 	}
+	comments := normalizeGitHubReviewComments(data.PullRequest.ReviewComments)
+	if len(comments) != 1 || comments[0].ID != 9 || comments[0].Author != "octocat" || comments[0].Path != "main.go" || comments[0].StartLine != 10 || comments[0].Line != 12 || comments[0].Resolved {
+		t.Fatalf("unexpected review comments: %#v", comments)
+	}
+}
+
+func TestLiveGitHubSDKPagesReviewComments(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls/42/comments" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		// Unchanged pages come back as 304s and must still link onwards.
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"page-`+r.URL.Query().Get("page")+`"`)
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<`+server.URL+`/repos/owner/repo/pulls/42/comments?per_page=100&page=2>; rel="next", <`+server.URL+`/repos/owner/repo/pulls/42/comments?per_page=100&page=2>; rel="last"`)
+			_, _ = w.Write([]byte(`[{"id":1,"body":"first"}]`))
+		case "2":
+			w.Header().Set("Link", `<`+server.URL+`/repos/owner/repo/pulls/42/comments?per_page=100>; rel="prev", <`+server.URL+`/repos/owner/repo/pulls/42/comments?per_page=100>; rel="first"`)
+			_, _ = w.Write([]byte(`[{"id":2,"body":"second"}]`))
+		default:
+			t.Fatalf("unexpected page: %q", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	githubSDK := newLiveGitHubSDK()
+	githubSDK.apiBaseURL = server.URL
+	githubSDK.SetAuthToken("test-token")
+
+	for _, attempt := range []string{"fresh", "cached"} {
+		comments, err := githubSDK.fetchPullRequestReviewComments(context.Background(), "owner", "repo", 42)
+		if err != nil {
+			t.Fatalf("fetchPullRequestReviewComments %s: %v", attempt, err)
+		}
+		if len(comments) != 2 || comments[0].ID != 1 || comments[1].ID != 2 {
+			t.Fatalf("expected both pages of comments on %s fetch, got %#v", attempt, comments)
+		}
+	}
+}
+
+func TestLiveGitHubSDKCreatePullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/pulls" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var request GitHubCreatePullRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if request.Head != "feature" || request.Base != "main" || request.Title != "Ship it" || !request.Draft {
+			t.Fatalf("unexpected create request: %#v", request)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":7,"state":"open","draft":true,"title":"Ship it","html_url":"https://github.com/owner/repo/pull/7","head":{"ref":"feature","sha":"abc"},"base":{"ref":"main"}}`))
+	}))
+	defer server.Close()
+
+	githubSDK := newLiveGitHubSDK()
+	githubSDK.apiBaseURL = server.URL
+	githubSDK.SetAuthToken("test-token")
+
+	pull, err := githubSDK.CreatePullRequest(context.Background(), "owner", "repo", GitHubCreatePullRequest{Title: "Ship it", Head: "feature", Base: "main", Draft: true})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if pull.Number != 7 || !pull.Draft || pull.Head.Ref != "feature" {
+		t.Fatalf("unexpected pull: %#v", pull)
+	}
+}
+
+func TestLiveGitHubSDKCreatePullRequestReportsValidationErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"Validation Failed","errors":[{"message":"A pull request already exists for owner:feature."}]}`))
+	}))
+	defer server.Close()
+
+	githubSDK := newLiveGitHubSDK()
+	githubSDK.apiBaseURL = server.URL
+	githubSDK.SetAuthToken("test-token")
+
+	_, err := githubSDK.CreatePullRequest(context.Background(), "owner", "repo", GitHubCreatePullRequest{Title: "Ship it", Head: "feature", Base: "main"})
+	if err == nil || !strings.Contains(err.Error(), "A pull request already exists") {
+		t.Fatalf("expected validation message, got %v", err)
+	}
 }
 
 func TestLiveGitHubSDKAuthState(t *testing.T) {
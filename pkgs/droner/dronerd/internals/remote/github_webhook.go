@@ -0,0 +1,143 @@
+package remote
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// githubWebhookTarget names the subscriptions a delivery affects: every
+// subscription on the repository whose branch is listed, or whose last seen
+// pull request head matches HeadSHA.
+type githubWebhookTarget struct {
+	Owner    string
+	Repo     string
+	Branches []string
+	HeadSHA  string
+}
+
+type githubWebhookRepository struct {
+	FullName string `json:"full_name"`
+}
+
+type githubWebhookPullRequest struct {
+	Head struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+type githubWebhookPayload struct {
+	Repository  githubWebhookRepository   `json:"repository"`
+	PullRequest *githubWebhookPullRequest `json:"pull_request"`
+	CheckRun    *struct {
+		HeadSHA      string                     `json:"head_sha"`
+		PullRequests []githubWebhookPullRequest `json:"pull_requests"`
+		CheckSuite   struct {
+			HeadBranch string `json:"head_branch"`
+		} `json:"check_suite"`
+	} `json:"check_run"`
+	// status
+	SHA      string `json:"sha"`
+	Branches []struct {
+		Name string `json:"name"`
+	} `json:"branches"`
+	// push
+	Ref   string `json:"ref"`
+	After string `json:"after"`
+}
+
+// VerifyGitHubWebhookSignature checks the X-Hub-Signature-256 header against
+// the HMAC-SHA256 of body.
+func VerifyGitHubWebhookSignature(secret string, body []byte, signature string) bool {
+	digest, ok := strings.CutPrefix(strings.TrimSpace(signature), "sha256=")
+	if !ok || secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// parseGitHubWebhook extracts the refresh target from a delivery. It reports
+// false for event types that never change a subscription, such as ping.
+func parseGitHubWebhook(eventType string, body []byte) (githubWebhookTarget, bool, error) {
+	switch eventType {
+	case "pull_request", "pull_request_review", "check_run", "status", "push":
+	default:
+		return githubWebhookTarget{}, false, nil
+	}
+
+	var payload githubWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return githubWebhookTarget{}, false, fmt.Errorf("failed to parse github %s webhook: %w", eventType, err)
+	}
+	owner, repo, ok := strings.Cut(payload.Repository.FullName, "/")
+	if !ok || owner == "" || repo == "" {
+		return githubWebhookTarget{}, false, fmt.Errorf("github %s webhook has no repository", eventType)
+	}
+	target := githubWebhookTarget{Owner: owner, Repo: repo}
+
+	switch eventType {
+	case "pull_request", "pull_request_review":
+		if payload.PullRequest != nil {
+			target.Branches = append(target.Branches, payload.PullRequest.Head.Ref)
+			target.HeadSHA = payload.PullRequest.Head.SHA
+		}
+	case "check_run":
+		if payload.CheckRun != nil {
+			target.HeadSHA = payload.CheckRun.HeadSHA
+			target.Branches = append(target.Branches, payload.CheckRun.CheckSuite.HeadBranch)
+			for _, pull := range payload.CheckRun.PullRequests {
+				target.Branches = append(target.Branches, pull.Head.Ref)
+			}
+		}
+	case "status":
+		target.HeadSHA = payload.SHA
+		for _, branch := range payload.Branches {
+			target.Branches = append(target.Branches, branch.Name)
+		}
+	case "push":
+		if branch, ok := strings.CutPrefix(payload.Ref, "refs/heads/"); ok {
+			target.Branches = append(target.Branches, branch)
+		}
+		target.HeadSHA = payload.After
+	}
+	return target, true, nil
+}
+
+func (t githubWebhookTarget) matches(key subscriptionKey, headSHA string) bool {
+	owner, repo, err := parseGitHubURL(key.remoteURL)
+	if err != nil || !strings.EqualFold(owner, t.Owner) || !strings.EqualFold(repo, t.Repo) {
+		return false
+	}
+	for _, branch := range t.Branches {
+		if branch != "" && branch == key.branch {
+			return true
+		}
+	}
+	return t.HeadSHA != "" && t.HeadSHA == headSHA
+}
+
+// HandleGitHubWebhook queues an immediate refresh of every subscription a
+// verified delivery affects and reports how many were queued.
+func HandleGitHubWebhook(eventType string, body []byte) (int, error) {
+	target, ok, err := parseGitHubWebhook(eventType, body)
+	if err != nil || !ok {
+		return 0, err
+	}
+	refreshed := 0
+	for _, p := range getRegistry().providers {
+		if github, ok := p.(*roundRobinGitHubProvider); ok {
+			refreshed += github.queueWebhookRefresh(target)
+		}
+	}
+	return refreshed, nil
+}
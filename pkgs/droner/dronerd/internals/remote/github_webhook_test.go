@@ -0,0 +1,155 @@
+package remote
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const recordedHeadSHA = "6dcb09b5b57875f334f61aebed695e2e4193db5e"
+
+func readWebhookFixture(t *testing.T, eventType string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "github_webhooks", eventType+".json"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return body
+}
+
+func signGitHubWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyGitHubWebhookSignature(t *testing.T) {
+	body := readWebhookFixture(t, "push")
+	signature := signGitHubWebhook("secret", body)
+
+	if !VerifyGitHubWebhookSignature("secret", body, signature) {
+		t.Fatal("expected valid signature")
+	}
+	if VerifyGitHubWebhookSignature("other", body, signature) {
+		t.Fatal("expected signature with another secret to fail")
+	}
+	if VerifyGitHubWebhookSignature("secret", append(body, ' '), signature) {
+		t.Fatal("expected signature over modified body to fail")
+	}
+	if VerifyGitHubWebhookSignature("secret", body, "sha1=abc") {
+		t.Fatal("expected non sha256 signature to fail")
+	}
+	if VerifyGitHubWebhookSignature("", body, signature) {
+		t.Fatal("expected empty secret to fail")
+	}
+}
+
+func TestParseGitHubWebhookRecordedPayloads(t *testing.T) {
+	key := subscriptionKey{remoteURL: "git@github.com:Oudwins/droner.git", branch: "feature/webhooks"}
+	for _, tc := range []struct {
+		eventType string
+		headSHA   string
+	}{
+		{eventType: "pull_request"},
+		{eventType: "pull_request_review"},
+		{eventType: "check_run"},
+		{eventType: "status", headSHA: recordedHeadSHA},
+		{eventType: "push"},
+	} {
+		target, ok, err := parseGitHubWebhook(tc.eventType, readWebhookFixture(t, tc.eventType))
+		if err != nil || !ok {
+			t.Fatalf("parseGitHubWebhook(%s) = %v, %v", tc.eventType, ok, err)
+		}
+		if target.Owner != "Oudwins" || target.Repo != "droner" {
+			t.Fatalf("%s target repo = %s/%s", tc.eventType, target.Owner, target.Repo)
+		}
+		if !target.matches(key, tc.headSHA) {
+			t.Fatalf("%s target %#v does not match subscription", tc.eventType, target)
+		}
+		other := subscriptionKey{remoteURL: "git@github.com:Oudwins/other.git", branch: "feature/webhooks"}
+		if target.matches(other, recordedHeadSHA) {
+			t.Fatalf("%s target matched another repository", tc.eventType)
+		}
+	}
+
+	if _, ok, err := parseGitHubWebhook("ping", []byte(`{"zen":"hi"}`)); ok || err != nil {
+		t.Fatalf("ping = %v, %v; want ignored", ok, err)
+	}
+	if _, _, err := parseGitHubWebhook("push", []byte(`{`)); err == nil {
+		t.Fatal("expected malformed payload to fail")
+	}
+}
+
+func TestRoundRobinGitHubProviderRefreshesOnWebhook(t *testing.T) {
+	githubSDK := newFakeGitHubSDK()
+	githubSDK.SetAuthToken("token")
+	received := make(chan BranchEvent, 4)
+	provider := newGithubProviderDetailed(githubSDK, func(event BranchEvent) { received <- event }, time.Hour)
+	defer provider.close()
+
+	matching := subscriptionKey{remoteURL: "git@github.com:Oudwins/droner.git", branch: "feature/webhooks"}
+	other := subscriptionKey{remoteURL: "git@github.com:Oudwins/droner.git", branch: "other"}
+	githubSDK.branchData[matching] = []GitHubBranchData{{BranchExists: true, PullRequest: &GitHubPullRequest{Number: 42, State: "open"}}}
+	provider.subscribe(matching)
+	provider.subscribe(other)
+
+	target, _, err := parseGitHubWebhook("pull_request", readWebhookFixture(t, "pull_request"))
+	if err != nil {
+		t.Fatalf("parseGitHubWebhook: %v", err)
+	}
+	if queued := provider.queueWebhookRefresh(target); queued != 1 {
+		t.Fatalf("queued = %d, want 1", queued)
+	}
+
+	select {
+	case event := <-received:
+		if event.Type != PRObserved || event.Branch != matching.branch {
+			t.Fatalf("unexpected event: %#v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected webhook refresh to poll the subscription")
+	}
+
+	githubSDK.mu.Lock()
+	defer githubSDK.mu.Unlock()
+	if len(githubSDK.branchCalls) != 1 || githubSDK.branchCalls[0] != matching {
+		t.Fatalf("unexpected poll calls: %#v", githubSDK.branchCalls)
+	}
+}
+
+func TestRoundRobinGitHubProviderCoalescesQueuedRefreshes(t *testing.T) {
+	githubSDK := newFakeGitHubSDK()
+	githubSDK.SetAuthToken("token")
+	// Built without the run loop so queued refreshes stay observable.
+	provider := &roundRobinGitHubProvider{
+		githubSDK:     githubSDK,
+		state:         make(map[subscriptionKey]githubBranchState),
+		subscriptions: make(map[subscriptionKey]struct{}),
+		pending:       make(map[subscriptionKey]struct{}),
+		refresh:       make(chan subscriptionKey, githubRefreshQueueSize),
+	}
+
+	key := subscriptionKey{remoteURL: "git@github.com:Oudwins/droner.git", branch: "feature/webhooks"}
+	provider.subscribe(key)
+	target := githubWebhookTarget{Owner: "oudwins", Repo: "DRONER", Branches: []string{key.branch}}
+	provider.queueWebhookRefresh(target)
+	provider.queueWebhookRefresh(target)
+	if len(provider.refresh) != 1 {
+		t.Fatalf("refresh queue length = %d, want 1", len(provider.refresh))
+	}
+
+	provider.unsubscribe(key)
+	if err := provider.pollQueued(context.Background(), <-provider.refresh); err != nil {
+		t.Fatalf("pollQueued: %v", err)
+	}
+	githubSDK.mu.Lock()
+	defer githubSDK.mu.Unlock()
+	if len(githubSDK.branchCalls) != 0 {
+		t.Fatalf("expected unsubscribed refresh to be skipped, got %#v", githubSDK.branchCalls)
+	}
+}
@@ -0,0 +1,431 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/auth"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+)
+
+const gitLabDefaultHost = "gitlab.com"
+
+type GitLabBranchData struct {
+	BranchExists bool
+	MergeRequest *GitLabMergeRequest
+}
+
+type GitLabMergeRequest struct {
+	IID                 int                 `json:"iid"`
+	State               string              `json:"state"`
+	Title               string              `json:"title"`
+	WebURL              string              `json:"web_url"`
+	Draft               bool                `json:"draft"`
+	SourceBranch        string              `json:"source_branch"`
+	TargetBranch        string              `json:"target_branch"`
+	SHA                 string              `json:"sha"`
+	MergeStatus         string              `json:"merge_status"`
+	DetailedMergeStatus string              `json:"detailed_merge_status"`
+	Reviewers           []GitLabUser        `json:"reviewers"`
+	HeadPipeline        *GitLabPipeline     `json:"head_pipeline"`
+	CreatedAt           time.Time           `json:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at"`
+	ClosedAt            *time.Time          `json:"closed_at"`
+	MergedAt            *time.Time          `json:"merged_at"`
+	Approvals           GitLabApprovals     `json:"-"`
+	ReviewerStates      []GitLabReviewer    `json:"-"`
+	Jobs                []GitLabPipelineJob `json:"-"`
+}
+
+type GitLabUser struct {
+	Username string `json:"username"`
+}
+
+type GitLabPipeline struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	WebURL string `json:"web_url"`
+}
+
+type GitLabApprovals struct {
+	ApprovedBy []struct {
+		User GitLabUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+type GitLabReviewer struct {
+	User  GitLabUser `json:"user"`
+	State string     `json:"state"`
+}
+
+type GitLabPipelineJob struct {
+	Name         string `json:"name"`
+	Stage        string `json:"stage"`
+	Status       string `json:"status"`
+	AllowFailure bool   `json:"allow_failure"`
+	WebURL       string `json:"web_url"`
+}
+
+type GitLabSDK interface {
+	IsAuthenticated(host string) bool
+	EnsureAuth(host string) error
+	GetBranchData(ctx context.Context, remoteURL string, branch string) (GitLabBranchData, error)
+}
+
+type liveGitLabSDK struct {
+	mu           sync.Mutex
+	tokens       map[string]string
+	resolveToken func(host string) (string, bool)
+	apiBaseURL   func(host string) string
+	httpClient   *http.Client
+}
+
+func newLiveGitLabSDK() *liveGitLabSDK {
+	return &liveGitLabSDK{
+		tokens:       map[string]string{},
+		resolveToken: resolveGitLabToken,
+		apiBaseURL: func(host string) string {
+			return "https://" + host + "/api/v4"
+		},
+		httpClient: &http.Client{Timeout: timeouts.SecondDefault},
+	}
+}
+
+func (s *liveGitLabSDK) token(host string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token, ok := s.tokens[host]; ok {
+		return token
+	}
+	token, _ := s.resolveToken(host)
+	s.tokens[host] = token
+	return token
+}
+
+func (s *liveGitLabSDK) IsAuthenticated(host string) bool {
+	return s.token(host) != ""
+}
+
+// EnsureAuth resolves the token again when it was missing, so fixing GITLAB_TOKEN
+// or logging in with glab does not require a restart.
+func (s *liveGitLabSDK) EnsureAuth(host string) error {
+	s.mu.Lock()
+	if s.tokens[host] == "" {
+		delete(s.tokens, host)
+	}
+	s.mu.Unlock()
+	if !s.IsAuthenticated(host) {
+		return sdk.ErrAuthRequired
+	}
+	return nil
+}
+
+func (s *liveGitLabSDK) GetBranchData(ctx context.Context, remoteURL string, branch string) (GitLabBranchData, error) {
+	host, projectPath, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		return GitLabBranchData{}, err
+	}
+	if err := s.EnsureAuth(host); err != nil {
+		return GitLabBranchData{}, err
+	}
+	project := s.apiBaseURL(host) + "/projects/" + url.PathEscape(projectPath)
+
+	branchExists, err := s.fetchBranchExists(ctx, host, project, branch)
+	if err != nil {
+		return GitLabBranchData{}, err
+	}
+	mergeRequest, err := s.fetchMergeRequestForBranch(ctx, host, project, branch)
+	if err != nil {
+		return GitLabBranchData{}, err
+	}
+	return GitLabBranchData{BranchExists: branchExists, MergeRequest: mergeRequest}, nil
+}
+
+func (s *liveGitLabSDK) fetchBranchExists(ctx context.Context, host string, project string, branch string) (bool, error) {
+	status, _, err := s.doGET(ctx, host, project+"/repository/branches/"+url.PathEscape(branch))
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected gitlab status checking branch: %d", status)
+	}
+}
+
+func (s *liveGitLabSDK) fetchMergeRequestForBranch(ctx context.Context, host string, project string, branch string) (*GitLabMergeRequest, error) {
+	q := url.Values{}
+	q.Set("source_branch", branch)
+	q.Set("state", "all")
+	q.Set("order_by", "updated_at")
+	q.Set("per_page", "100")
+
+	var mergeRequests []GitLabMergeRequest
+	if err := s.getJSON(ctx, host, project+"/merge_requests?"+q.Encode(), "listing merge requests", &mergeRequests); err != nil {
+		return nil, err
+	}
+	if len(mergeRequests) == 0 {
+		return nil, nil
+	}
+	newest := newestMergeRequest(mergeRequests)
+
+	var detail GitLabMergeRequest
+	mergeRequestURL := fmt.Sprintf("%s/merge_requests/%d", project, newest.IID)
+	if err := s.getJSON(ctx, host, mergeRequestURL, "fetching merge request", &detail); err != nil {
+		return nil, err
+	}
+	if err := s.getJSON(ctx, host, mergeRequestURL+"/approvals", "fetching merge request approvals", &detail.Approvals); err != nil {
+		return nil, err
+	}
+	// Reviewer states only exist on newer GitLab versions.
+	status, body, err := s.doGET(ctx, host, mergeRequestURL+"/reviewers")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusOK {
+		if err := json.Unmarshal(body, &detail.ReviewerStates); err != nil {
+			return nil, fmt.Errorf("failed to parse gitlab reviewers response: %w", err)
+		}
+	}
+	if detail.HeadPipeline != nil && detail.HeadPipeline.ID != 0 {
+		jobsURL := fmt.Sprintf("%s/pipelines/%d/jobs?per_page=100", project, detail.HeadPipeline.ID)
+		if err := s.getJSON(ctx, host, jobsURL, "listing pipeline jobs", &detail.Jobs); err != nil {
+			return nil, err
+		}
+	}
+	return &detail, nil
+}
+
+func newestMergeRequest(mergeRequests []GitLabMergeRequest) GitLabMergeRequest {
+	newest := mergeRequests[0]
+	for _, mergeRequest := range mergeRequests[1:] {
+		if mergeRequest.UpdatedAt.After(newest.UpdatedAt) || (mergeRequest.UpdatedAt.Equal(newest.UpdatedAt) && mergeRequest.IID > newest.IID) {
+			newest = mergeRequest
+		}
+	}
+	return newest
+}
+
+func normalizeGitLabMergeRequest(remoteURL string, projectPath string, mergeRequest *GitLabMergeRequest) *PullRequestSnapshot {
+	if mergeRequest == nil {
+		return nil
+	}
+	owner, repo := path.Split(projectPath)
+
+	reviewers := make([]string, 0, len(mergeRequest.Reviewers))
+	for _, reviewer := range mergeRequest.Reviewers {
+		if username := strings.TrimSpace(reviewer.Username); username != "" {
+			reviewers = append(reviewers, username)
+		}
+	}
+	sort.Strings(reviewers)
+
+	state := "open"
+	mergedAt := mergeRequest.MergedAt
+	switch strings.ToLower(strings.TrimSpace(mergeRequest.State)) {
+	case "closed":
+		state = "closed"
+	case "merged":
+		state = "closed"
+		if mergedAt == nil {
+			mergedAt = &mergeRequest.UpdatedAt
+		}
+	}
+	mergeableState := mergeRequest.DetailedMergeStatus
+	if mergeableState == "" {
+		mergeableState = mergeRequest.MergeStatus
+	}
+
+	return &PullRequestSnapshot{
+		Provider:           "gitlab",
+		RemoteURL:          remoteURL,
+		RepoOwner:          strings.TrimSuffix(owner, "/"),
+		RepoName:           repo,
+		Number:             mergeRequest.IID,
+		State:              state,
+		Title:              mergeRequest.Title,
+		HTMLURL:            mergeRequest.WebURL,
+		Draft:              mergeRequest.Draft,
+		HeadRef:            mergeRequest.SourceBranch,
+		HeadSHA:            mergeRequest.SHA,
+		BaseRef:            mergeRequest.TargetBranch,
+		Mergeable:          gitLabMergeable(mergeableState),
+		MergeableState:     mergeableState,
+		RequestedReviewers: reviewers,
+		RequestedTeams:     []string{},
+		ReviewSummary:      summarizeGitLabReviews(mergeRequest.Approvals, mergeRequest.ReviewerStates),
+		CI:                 summarizeGitLabPipeline(mergeRequest.HeadPipeline, mergeRequest.Jobs),
+		CreatedAt:          mergeRequest.CreatedAt.UTC(),
+		UpdatedAt:          mergeRequest.UpdatedAt.UTC(),
+		ClosedAt:           utcTimePtr(mergeRequest.ClosedAt),
+		MergedAt:           utcTimePtr(mergedAt),
+	}
+}
+
+func gitLabMergeable(status string) *bool {
+	var mergeable bool
+	switch status {
+	case "mergeable", "can_be_merged":
+		mergeable = true
+	case "conflict", "need_rebase", "broken_status", "cannot_be_merged":
+		mergeable = false
+	default:
+		return nil
+	}
+	return &mergeable
+}
+
+func summarizeGitLabReviews(approvals GitLabApprovals, reviewers []GitLabReviewer) ReviewSummary {
+	approved := map[string]struct{}{}
+	changesRequested := map[string]struct{}{}
+	commented := map[string]struct{}{}
+	for _, approval := range approvals.ApprovedBy {
+		if username := strings.TrimSpace(approval.User.Username); username != "" {
+			approved[username] = struct{}{}
+		}
+	}
+	for _, reviewer := range reviewers {
+		username := strings.TrimSpace(reviewer.User.Username)
+		if username == "" {
+			continue
+		}
+		switch reviewer.State {
+		case "requested_changes":
+			changesRequested[username] = struct{}{}
+		case "reviewed":
+			commented[username] = struct{}{}
+		}
+	}
+	return ReviewSummary{Approved: sortedKeys(approved), ChangesRequested: sortedKeys(changesRequested), Commented: sortedKeys(commented)}
+}
+
+func summarizeGitLabPipeline(pipeline *GitLabPipeline, jobs []GitLabPipelineJob) CIStatusSummary {
+	statuses := make([]CIStatusContext, 0, len(jobs))
+	for _, job := range jobs {
+		name := strings.TrimSpace(job.Name)
+		if name == "" {
+			continue
+		}
+		state := normalizeGitLabCIState(job.Status)
+		description := job.Stage
+		if state == "failing" && job.AllowFailure {
+			state = "passing"
+			description = job.Stage + " (allowed to fail)"
+		}
+		statuses = append(statuses, CIStatusContext{Name: name, State: state, Description: description, TargetURL: job.WebURL})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	pipelineState := ""
+	if pipeline != nil {
+		pipelineState = pipeline.Status
+	}
+	if len(statuses) == 0 {
+		return CIStatusSummary{State: normalizeGitLabCIState(pipelineState), Statuses: statuses}
+	}
+	return CIStatusSummary{State: aggregateCIState(statuses, ""), Statuses: statuses}
+}
+
+func normalizeGitLabCIState(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "success", "skipped", "manual":
+		return "passing"
+	case "failed", "canceled":
+		return "failing"
+	case "created", "pending", "running", "preparing", "waiting_for_resource", "scheduled":
+		return "pending"
+	default:
+		return "unknown"
+	}
+}
+
+func (s *liveGitLabSDK) getJSON(ctx context.Context, host string, requestURL string, action string, target any) error {
+	status, body, err := s.doGET(ctx, host, requestURL)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("unexpected gitlab status %s: %d", action, status)
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to parse gitlab response %s: %w", action, err)
+	}
+	return nil
+}
+
+func (s *liveGitLabSDK) doGET(ctx context.Context, host string, requestURL string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "droner")
+	req.Header.Set("Authorization", "Bearer "+s.token(host))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+func resolveGitLabToken(host string) (string, bool) {
+	store, err := auth.Default()
+	if err != nil {
+		return "", false
+	}
+	if gitlabAuth, ok := store.GitLab(host); ok {
+		if token := strings.TrimSpace(gitlabAuth.AccessToken); token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// parseRemoteURL splits an SSH or HTTP(S) git remote into its host and
+// project path, without the .git suffix.
+func parseRemoteURL(remoteURL string) (string, string, error) {
+	trimmed := strings.TrimSpace(remoteURL)
+	var host, projectPath string
+	if !strings.Contains(trimmed, "://") {
+		at := strings.Index(trimmed, "@")
+		colon := strings.Index(trimmed, ":")
+		if at < 0 || colon < at {
+			return "", "", fmt.Errorf("invalid remote URL: %s", remoteURL)
+		}
+		host = trimmed[at+1 : colon]
+		projectPath = trimmed[colon+1:]
+	} else {
+		parsed, err := url.Parse(trimmed)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid remote URL: %s", remoteURL)
+		}
+		host = parsed.Hostname()
+		projectPath = parsed.Path
+	}
+	projectPath = strings.TrimSuffix(strings.Trim(projectPath, "/"), ".git")
+	if host == "" || !strings.Contains(projectPath, "/") {
+		return "", "", errors.New("remote URL has no owner/repository path: " + remoteURL)
+	}
+	return strings.ToLower(host), projectPath, nil
+}
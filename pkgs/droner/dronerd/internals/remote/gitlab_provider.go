@@ -0,0 +1,59 @@
+package remote
+
+import (
+	"context"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+)
+
+type gitLabBranchSource struct {
+	gitlabSDK GitLabSDK
+	hosts     map[string]struct{}
+}
+
+func newGitlabProvider(handler BranchEventHandler) remoteProvider {
+	config := conf.GetConfig().Providers.GitLab
+	return newPollingProvider(newGitLabBranchSource(newLiveGitLabSDK(), config.Hosts), handler, conf.PollDuration(config.PollInterval), gitlabProviderLogger())
+}
+
+func newGitLabBranchSource(gl GitLabSDK, hosts []string) *gitLabBranchSource {
+	source := &gitLabBranchSource{gitlabSDK: gl, hosts: map[string]struct{}{gitLabDefaultHost: {}}}
+	for _, host := range hosts {
+		source.hosts[host] = struct{}{}
+	}
+	return source
+}
+
+func (s *gitLabBranchSource) isValidURL(remoteURL string) bool {
+	host, _, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		return false
+	}
+	_, ok := s.hosts[host]
+	return ok
+}
+
+func (s *gitLabBranchSource) isAuthenticated(remoteURL string) bool {
+	host, _, err := parseRemoteURL(remoteURL)
+	return err == nil && s.gitlabSDK.IsAuthenticated(host)
+}
+
+func (s *gitLabBranchSource) ensureAuth(remoteURL string) error {
+	host, _, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		return err
+	}
+	return s.gitlabSDK.EnsureAuth(host)
+}
+
+func (s *gitLabBranchSource) getBranchSnapshot(ctx context.Context, remoteURL string, branch string) (branchSnapshot, error) {
+	_, projectPath, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		return branchSnapshot{}, err
+	}
+	data, err := s.gitlabSDK.GetBranchData(ctx, remoteURL, branch)
+	if err != nil {
+		return branchSnapshot{}, err
+	}
+	return branchSnapshot{BranchExists: data.BranchExists, PullRequest: normalizeGitLabMergeRequest(remoteURL, projectPath, data.MergeRequest)}, nil
+}
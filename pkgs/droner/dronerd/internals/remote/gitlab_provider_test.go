@@ -0,0 +1,122 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeGitLabSDK struct {
+	mu            sync.Mutex
+	authenticated map[string]bool
+	branchData    map[subscriptionKey][]GitLabBranchData
+	branchCalls   []subscriptionKey
+}
+
+func newFakeGitLabSDK() *fakeGitLabSDK {
+	return &fakeGitLabSDK{authenticated: map[string]bool{}, branchData: make(map[subscriptionKey][]GitLabBranchData)}
+}
+
+func (s *fakeGitLabSDK) IsAuthenticated(host string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authenticated[host]
+}
+
+func (s *fakeGitLabSDK) EnsureAuth(host string) error {
+	if !s.IsAuthenticated(host) {
+		return ErrUnsupportedRemote
+	}
+	return nil
+}
+
+func (s *fakeGitLabSDK) GetBranchData(ctx context.Context, remoteURL string, branch string) (GitLabBranchData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := subscriptionKey{remoteURL: remoteURL, branch: branch}
+	s.branchCalls = append(s.branchCalls, key)
+	responses := s.branchData[key]
+	if len(responses) == 0 {
+		return GitLabBranchData{}, nil
+	}
+	response := responses[0]
+	s.branchData[key] = responses[1:]
+	return response, nil
+}
+
+func TestGitLabProviderAcceptsConfiguredHosts(t *testing.T) {
+	provider := newPollingProvider(newGitLabBranchSource(newFakeGitLabSDK(), []string{"git.internal"}), nil, time.Hour, gitlabProviderLogger())
+	defer provider.close()
+
+	for remoteURL, want := range map[string]bool{
+		"git@gitlab.com:group/repo.git":       true,
+		"https://git.internal/group/repo.git": true,
+		"git@github.com:owner/repo.git":       false,
+		"https://gitlab.example.com/a/b.git":  false,
+	} {
+		if got := provider.isValidKey(subscriptionKey{remoteURL: remoteURL}); got != want {
+			t.Fatalf("isValidKey(%q) = %v, want %v", remoteURL, got, want)
+		}
+	}
+}
+
+func TestGitLabProviderEmitsTerminalEventsWithSnapshots(t *testing.T) {
+	gl := newFakeGitLabSDK()
+	gl.authenticated["gitlab.com"] = true
+	received := make(chan BranchEvent, 4)
+	provider := newPollingProvider(newGitLabBranchSource(gl, nil), func(event BranchEvent) { received <- event }, time.Hour, gitlabProviderLogger())
+	defer provider.close()
+
+	key := subscriptionKey{remoteURL: "git@gitlab.com:group/repo.git", branch: "feature"}
+	mergedAt := time.Date(2026, time.March, 24, 12, 0, 0, 0, time.UTC)
+	gl.branchData[key] = []GitLabBranchData{
+		{BranchExists: true, MergeRequest: &GitLabMergeRequest{IID: 7, State: "opened"}},
+		{BranchExists: false, MergeRequest: &GitLabMergeRequest{IID: 7, State: "merged", MergedAt: &mergedAt}},
+	}
+	provider.subscribe(key)
+
+	if err := provider.pollNext(context.Background()); err != nil {
+		t.Fatalf("first pollNext: %v", err)
+	}
+	event := expectEvent(t, received)
+	if event.Type != PRObserved || event.PRSnapshot == nil || event.PRSnapshot.Provider != "gitlab" || event.PRSnapshot.State != "open" {
+		t.Fatalf("first event = %#v, want open gitlab snapshot", event)
+	}
+	expectNoEvent(t, received)
+
+	if err := provider.pollNext(context.Background()); err != nil {
+		t.Fatalf("second pollNext: %v", err)
+	}
+	want := []BranchEventType{BranchDeleted, PRObserved, PRClosed, PRMerged}
+	for _, eventType := range want {
+		event := expectEvent(t, received)
+		if event.Type != eventType {
+			t.Fatalf("event = %s, want %s", event.Type, eventType)
+		}
+		if eventType != BranchDeleted && (event.PRSnapshot == nil || *event.PRNumber != 7) {
+			t.Fatalf("%s event = %#v, want snapshot for !7", eventType, event)
+		}
+	}
+}
+
+func TestGitLabProviderSkipsUnauthenticatedHosts(t *testing.T) {
+	gl := newFakeGitLabSDK()
+	provider := newPollingProvider(newGitLabBranchSource(gl, nil), nil, time.Hour, gitlabProviderLogger())
+	defer provider.close()
+
+	key := subscriptionKey{remoteURL: "git@gitlab.com:group/repo.git", branch: "feature"}
+	if err := provider.ensureAuth(key); err == nil {
+		t.Fatal("expected ensureAuth to fail without a token")
+	}
+	provider.subscribe(key)
+	if err := provider.pollNext(context.Background()); err != nil {
+		t.Fatalf("pollNext: %v", err)
+	}
+
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+	if len(gl.branchCalls) != 0 {
+		t.Fatalf("expected no SDK calls, got %#v", gl.branchCalls)
+	}
+}
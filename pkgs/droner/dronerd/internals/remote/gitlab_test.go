@@ -0,0 +1,149 @@
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	cases := []struct {
+		remoteURL string
+		host      string
+		path      string
+	}{
+		{"git@gitlab.com:group/repo.git", "gitlab.com", "group/repo"},
+		{"https://gitlab.com/group/sub/repo.git", "gitlab.com", "group/sub/repo"},
+		{"ssh://git@GitLab.Example.com:2222/group/repo.git", "gitlab.example.com", "group/repo"},
+	}
+	for _, tc := range cases {
+		host, projectPath, err := parseRemoteURL(tc.remoteURL)
+		if err != nil {
+			t.Fatalf("parseRemoteURL(%q): %v", tc.remoteURL, err)
+		}
+		if host != tc.host || projectPath != tc.path {
+			t.Fatalf("parseRemoteURL(%q) = %s %s, want %s %s", tc.remoteURL, host, projectPath, tc.host, tc.path)
+		}
+	}
+	if _, _, err := parseRemoteURL("git@gitlab.com:repo"); err == nil {
+		t.Fatal("expected error for remote without owner")
+	}
+}
+
+func TestLiveGitLabSDKGetBranchData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer gl-token" {
+			t.Fatalf("authorization = %q, want bearer token", got)
+		}
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fsub%2Frepo/repository/branches/feature%2Fone":
+			_, _ = w.Write([]byte(`{"name":"feature/one"}`))
+		case "/api/v4/projects/group%2Fsub%2Frepo/merge_requests":
+			if got := r.URL.Query().Get("source_branch"); got != "feature/one" {
+				t.Fatalf("source_branch = %q, want feature/one", got)
+			}
+			_, _ = w.Write([]byte(`[{"iid":3,"updated_at":"2026-03-20T12:00:00Z"},{"iid":5,"updated_at":"2026-03-24T12:00:00Z"}]`))
+		case "/api/v4/projects/group%2Fsub%2Frepo/merge_requests/5":
+			_, _ = w.Write([]byte(`{"iid":5,"state":"merged","title":"Ship it","web_url":"https://gitlab.com/group/sub/repo/-/merge_requests/5","draft":false,"source_branch":"feature/one","target_branch":"main","sha":"abc","detailed_merge_status":"mergeable","reviewers":[{"username":"bob"},{"username":"alice"}],"head_pipeline":{"id":77,"status":"failed"},"created_at":"2026-03-20T12:00:00Z","updated_at":"2026-03-24T12:00:00Z","merged_at":"2026-03-24T12:00:00Z"}`))
+		case "/api/v4/projects/group%2Fsub%2Frepo/merge_requests/5/approvals":
+			_, _ = w.Write([]byte(`{"approved_by":[{"user":{"username":"alice"}}]}`))
+		case "/api/v4/projects/group%2Fsub%2Frepo/merge_requests/5/reviewers":
+			_, _ = w.Write([]byte(`[{"user":{"username":"bob"},"state":"requested_changes"}]`))
+		case "/api/v4/projects/group%2Fsub%2Frepo/pipelines/77/jobs":
+			_, _ = w.Write([]byte(`[{"name":"test","stage":"test","status":"failed","web_url":"https://gitlab.com/jobs/1"},{"name":"lint","stage":"test","status":"failed","allow_failure":true},{"name":"build","stage":"build","status":"success"}]`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	gl := newLiveGitLabSDK()
+	gl.resolveToken = func(host string) (string, bool) {
+		if host != "gitlab.com" {
+			t.Fatalf("token host = %q, want gitlab.com", host)
+		}
+		return "gl-token", true
+	}
+	gl.apiBaseURL = func(string) string { return server.URL + "/api/v4" }
+
+	remoteURL := "git@gitlab.com:group/sub/repo.git"
+	data, err := gl.GetBranchData(context.Background(), remoteURL, "feature/one")
+	if err != nil {
+		t.Fatalf("GetBranchData: %v", err)
+	}
+	if !data.BranchExists || data.MergeRequest == nil || data.MergeRequest.IID != 5 {
+		t.Fatalf("unexpected branch data: %#v", data)
+	}
+
+	snapshot := normalizeGitLabMergeRequest(remoteURL, "group/sub/repo", data.MergeRequest)
+	if snapshot.Provider != "gitlab" || snapshot.RepoOwner != "group/sub" || snapshot.RepoName != "repo" || snapshot.Number != 5 {
+		t.Fatalf("unexpected snapshot identity: %#v", snapshot)
+	}
+	if snapshot.State != "closed" || snapshot.MergedAt == nil || snapshot.Mergeable == nil || !*snapshot.Mergeable {
+		t.Fatalf("unexpected snapshot state: %#v", snapshot)
+	}
+	if len(snapshot.RequestedReviewers) != 2 || snapshot.RequestedReviewers[0] != "alice" {
+		t.Fatalf("requested reviewers = %#v, want sorted reviewers", snapshot.RequestedReviewers)
+	}
+	if len(snapshot.ReviewSummary.Approved) != 1 || snapshot.ReviewSummary.Approved[0] != "alice" {
+		t.Fatalf("approved = %#v, want alice", snapshot.ReviewSummary.Approved)
+	}
+	if len(snapshot.ReviewSummary.ChangesRequested) != 1 || snapshot.ReviewSummary.ChangesRequested[0] != "bob" {
+		t.Fatalf("changes requested = %#v, want bob", snapshot.ReviewSummary.ChangesRequested)
+	}
+	if snapshot.CI.State != "failing" || len(snapshot.CI.Statuses) != 3 {
+		t.Fatalf("unexpected CI summary: %#v", snapshot.CI)
+	}
+	if lint := snapshot.CI.Statuses[1]; lint.Name != "lint" || lint.State != "passing" {
+		t.Fatalf("lint status = %#v, want allowed failure to pass", lint)
+	}
+}
+
+func TestLiveGitLabSDKMissingBranchAndNoMergeRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Frepo/repository/branches/feature":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v4/projects/group%2Frepo/merge_requests":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	gl := newLiveGitLabSDK()
+	gl.resolveToken = func(string) (string, bool) { return "gl-token", true }
+	gl.apiBaseURL = func(string) string { return server.URL + "/api/v4" }
+
+	data, err := gl.GetBranchData(context.Background(), "https://gitlab.com/group/repo.git", "feature")
+	if err != nil {
+		t.Fatalf("GetBranchData: %v", err)
+	}
+	if data.BranchExists || data.MergeRequest != nil {
+		t.Fatalf("unexpected branch data: %#v", data)
+	}
+}
+
+func TestLiveGitLabSDKEnsureAuthRetriesMissingToken(t *testing.T) {
+	gl := newLiveGitLabSDK()
+	calls := 0
+	gl.resolveToken = func(string) (string, bool) {
+		calls++
+		return "", false
+	}
+	if err := gl.EnsureAuth("gitlab.com"); err == nil {
+		t.Fatal("expected auth error without token")
+	}
+	if gl.IsAuthenticated("gitlab.com") {
+		t.Fatal("expected cached missing token")
+	}
+	gl.resolveToken = func(string) (string, bool) { return "late-token", true }
+	if err := gl.EnsureAuth("gitlab.com"); err != nil {
+		t.Fatalf("EnsureAuth after login: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("resolve calls = %d, want 1 before login", calls)
+	}
+}
@@ -9,3 +9,11 @@ func remoteLogger() *slog.Logger {
 func githubProviderLogger() *slog.Logger {
 	return slog.Default().With("component", "github_provider")
 }
+
+func gitlabProviderLogger() *slog.Logger {
+	return slog.Default().With("component", "gitlab_provider")
+}
+
+func giteaProviderLogger() *slog.Logger {
+	return slog.Default().With("component", "gitea_provider")
+}
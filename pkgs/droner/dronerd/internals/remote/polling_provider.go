@@ -0,0 +1,260 @@
+package remote
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// branchSnapshot is the provider-neutral state of a branch and its newest
+// pull or merge request.
+type branchSnapshot struct {
+	BranchExists bool
+	PullRequest  *PullRequestSnapshot
+}
+
+// branchSource fetches branch state from one provider's API.
+type branchSource interface {
+	isValidURL(remoteURL string) bool
+	isAuthenticated(remoteURL string) bool
+	ensureAuth(remoteURL string) error
+	getBranchSnapshot(ctx context.Context, remoteURL string, branch string) (branchSnapshot, error)
+}
+
+// pollingProvider polls one subscription per tick in round-robin order, like
+// roundRobinGitHubProvider, for providers that normalize straight into
+// PullRequestSnapshot.
+type pollingProvider struct {
+	source        branchSource
+	pollIntervalD time.Duration
+	eventHandler  BranchEventHandler
+	logger        *slog.Logger
+	stop          context.CancelFunc
+
+	mu            sync.Mutex
+	state         map[subscriptionKey]branchSnapshot
+	subscriptions map[subscriptionKey]struct{}
+	order         []subscriptionKey
+	nextIndex     int
+}
+
+func newPollingProvider(source branchSource, handler BranchEventHandler, interval time.Duration, logger *slog.Logger) *pollingProvider {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &pollingProvider{
+		source:        source,
+		pollIntervalD: interval,
+		eventHandler:  handler,
+		logger:        logger,
+		stop:          cancel,
+		state:         make(map[subscriptionKey]branchSnapshot),
+		subscriptions: make(map[subscriptionKey]struct{}),
+	}
+	go p.run(ctx)
+	return p
+}
+
+func (p *pollingProvider) isValidKey(key subscriptionKey) bool {
+	return p.source.isValidURL(key.remoteURL)
+}
+
+func (p *pollingProvider) ensureAuth(key subscriptionKey) error {
+	return p.source.ensureAuth(key.remoteURL)
+}
+
+func (p *pollingProvider) subscribe(key subscriptionKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exists := p.subscriptions[key]; exists {
+		p.logger.Debug("subscription already active",
+			"remote_url", key.remoteURL,
+			"branch", key.branch,
+		)
+		return
+	}
+	p.subscriptions[key] = struct{}{}
+	p.order = append(p.order, key)
+	p.logger.Debug("subscription registered",
+		"remote_url", key.remoteURL,
+		"branch", key.branch,
+		"subscription_count", len(p.order),
+	)
+}
+
+func (p *pollingProvider) unsubscribe(key subscriptionKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exists := p.subscriptions[key]; !exists {
+		return
+	}
+	delete(p.subscriptions, key)
+	delete(p.state, key)
+	for i, candidate := range p.order {
+		if candidate != key {
+			continue
+		}
+		p.order = append(p.order[:i], p.order[i+1:]...)
+		if i < p.nextIndex {
+			p.nextIndex--
+		}
+		if p.nextIndex >= len(p.order) {
+			p.nextIndex = 0
+		}
+		break
+	}
+	p.logger.Debug("subscription removed",
+		"remote_url", key.remoteURL,
+		"branch", key.branch,
+		"subscription_count", len(p.order),
+	)
+}
+
+func (p *pollingProvider) close() {
+	if p.stop != nil {
+		p.stop()
+	}
+}
+
+func (p *pollingProvider) run(ctx context.Context) {
+	ticker := time.NewTicker(p.pollIntervalD)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = p.pollNext(ctx)
+		}
+	}
+}
+
+func (p *pollingProvider) pollNext(ctx context.Context) error {
+	key, ok := p.nextSubscription()
+	if !ok {
+		return nil
+	}
+	if !p.source.isAuthenticated(key.remoteURL) {
+		p.logger.Debug("poll skipped unauthenticated",
+			"remote_url", key.remoteURL,
+			"branch", key.branch,
+		)
+		return nil
+	}
+
+	current, err := p.source.getBranchSnapshot(ctx, key.remoteURL, key.branch)
+	if err != nil {
+		p.logger.Debug("poll failed",
+			"remote_url", key.remoteURL,
+			"branch", key.branch,
+			"error", err,
+		)
+		return err
+	}
+	p.logger.Debug("poll completed",
+		"remote_url", key.remoteURL,
+		"branch", key.branch,
+		"branch_exists", current.BranchExists,
+		"has_pull_request", current.PullRequest != nil,
+	)
+
+	events := p.storeBranchSnapshot(key, current)
+	handler := p.currentEventHandler()
+	if handler == nil {
+		return nil
+	}
+	for _, event := range events {
+		handler(event)
+	}
+	return nil
+}
+
+func (p *pollingProvider) nextSubscription() (subscriptionKey, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.order) == 0 {
+		return subscriptionKey{}, false
+	}
+	if p.nextIndex >= len(p.order) {
+		p.nextIndex = 0
+	}
+	key := p.order[p.nextIndex]
+	p.nextIndex = (p.nextIndex + 1) % len(p.order)
+	return key, true
+}
+
+func (p *pollingProvider) storeBranchSnapshot(key subscriptionKey, current branchSnapshot) []BranchEvent {
+	p.mu.Lock()
+	if _, exists := p.subscriptions[key]; !exists {
+		p.mu.Unlock()
+		return nil
+	}
+	previous, initialized := p.state[key]
+	p.state[key] = current
+	p.mu.Unlock()
+
+	if !initialized {
+		return initialBranchSnapshotEvents(key, current)
+	}
+	return diffBranchSnapshots(key, previous, current)
+}
+
+func (p *pollingProvider) currentEventHandler() BranchEventHandler {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.eventHandler
+}
+
+// Terminal events carry the snapshot so consumers can derive the pull request
+// stream without parsing provider-specific remote URLs.
+func diffBranchSnapshots(key subscriptionKey, previous branchSnapshot, current branchSnapshot) []BranchEvent {
+	events := make([]BranchEvent, 0, 3)
+	now := time.Now()
+
+	if previous.BranchExists && !current.BranchExists {
+		events = append(events, BranchEvent{Type: BranchDeleted, RemoteURL: key.remoteURL, Branch: key.branch, Timestamp: now})
+	}
+
+	previousPR := previous.PullRequest
+	currentPR := current.PullRequest
+	if currentPR == nil {
+		return events
+	}
+	if !reflect.DeepEqual(previousPR, currentPR) {
+		events = append(events, newSnapshotEvent(PRObserved, key, currentPR, now))
+	}
+	if previousPR != nil {
+		if previousPR.State == "open" && currentPR.State == "closed" {
+			events = append(events, newSnapshotEvent(PRClosed, key, currentPR, now))
+		}
+		if previousPR.MergedAt == nil && currentPR.MergedAt != nil {
+			events = append(events, newSnapshotEvent(PRMerged, key, currentPR, now))
+		}
+	}
+	return events
+}
+
+func initialBranchSnapshotEvents(key subscriptionKey, current branchSnapshot) []BranchEvent {
+	events := make([]BranchEvent, 0, 3)
+	now := time.Now()
+
+	currentPR := current.PullRequest
+	if currentPR == nil {
+		return events
+	}
+	events = append(events, newSnapshotEvent(PRObserved, key, currentPR, now))
+	if currentPR.State == "closed" {
+		events = append(events, newSnapshotEvent(PRClosed, key, currentPR, now))
+	}
+	if currentPR.MergedAt != nil {
+		events = append(events, newSnapshotEvent(PRMerged, key, currentPR, now))
+	}
+	return events
+}
+
+func newSnapshotEvent(eventType BranchEventType, key subscriptionKey, snapshot *PullRequestSnapshot, now time.Time) BranchEvent {
+	prState := snapshot.State
+	number := snapshot.Number
+	return BranchEvent{Type: eventType, RemoteURL: key.remoteURL, Branch: key.branch, PRNumber: &number, PRState: &prState, PRSnapshot: snapshot, Timestamp: now}
+}
@@ -6,5 +6,5 @@ type remoteProvider interface {
 	subscribe(key subscriptionKey)
 	unsubscribe(key subscriptionKey)
 	close()
-	ensureAuth() error
+	ensureAuth(key subscriptionKey) error
 }
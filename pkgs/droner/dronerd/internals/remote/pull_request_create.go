@@ -0,0 +1,39 @@
+package remote
+
+import (
+	"context"
+	"strings"
+)
+
+// CreatePullRequestInput describes a pull request opened from a pushed branch.
+type CreatePullRequestInput struct {
+	Branch string
+	Base   string
+	Title  string
+	Body   string
+	Draft  bool
+}
+
+// pullRequestCreator is implemented by providers that can open pull requests.
+type pullRequestCreator interface {
+	createPullRequest(ctx context.Context, key subscriptionKey, input CreatePullRequestInput) (PullRequestSnapshot, error)
+}
+
+// CreatePullRequest opens a pull request for input.Branch on the provider of
+// remoteURL and returns its first snapshot. Providers that cannot open pull
+// requests yet report ErrUnsupportedRemote.
+func CreatePullRequest(ctx context.Context, remoteURL string, input CreatePullRequestInput) (PullRequestSnapshot, error) {
+	key := subscriptionKey{remoteURL: strings.TrimSpace(remoteURL), branch: strings.TrimSpace(input.Branch)}
+	p, ok := getRegistry().providerForKey(key)
+	if !ok {
+		return PullRequestSnapshot{}, ErrUnsupportedRemote
+	}
+	creator, ok := p.(pullRequestCreator)
+	if !ok {
+		return PullRequestSnapshot{}, ErrUnsupportedRemote
+	}
+	if err := p.ensureAuth(key); err != nil {
+		return PullRequestSnapshot{}, err
+	}
+	return creator.createPullRequest(ctx, key, input)
+}
@@ -51,7 +51,7 @@ func (p *fakeProvider) close() {
 	p.closed = true
 }
 
-func (p *fakeProvider) ensureAuth() error {
+func (p *fakeProvider) ensureAuth(key subscriptionKey) error {
 	p.mu.Lock()
 	p.ensureCall++
 	p.mu.Unlock()
@@ -39,10 +39,14 @@ type PullRequestSnapshot struct {
 	RequestedTeams     []string        `json:"requestedTeams"`
 	ReviewSummary      ReviewSummary   `json:"reviewSummary"`
 	CI                 CIStatusSummary `json:"ci"`
+	ReviewComments     []ReviewComment `json:"reviewComments,omitempty"`
 	CreatedAt          time.Time       `json:"createdAt"`
 	UpdatedAt          time.Time       `json:"updatedAt"`
 	ClosedAt           *time.Time      `json:"closedAt,omitempty"`
 	MergedAt           *time.Time      `json:"mergedAt,omitempty"`
+	// ResolutionKnown is set when the provider reports which review comments
+	// are resolved. Without it every comment reads as unresolved.
+	ResolutionKnown bool `json:"resolutionKnown,omitempty"`
 }
 
 type ReviewSummary struct {
@@ -51,6 +55,21 @@ type ReviewSummary struct {
 	Commented        []string `json:"commented"`
 }
 
+// ReviewComment is an inline review comment on a pull request diff.
+type ReviewComment struct {
+	ID     int64  `json:"id"`
+	Author string `json:"author"`
+	Path   string `json:"path"`
+	// Line is the last line the comment refers to; StartLine is set for
+	// multi-line comments. Both are 0 when the comment is outdated.
+	Line      int       `json:"line,omitempty"`
+	StartLine int       `json:"startLine,omitempty"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url,omitempty"`
+	Resolved  bool      `json:"resolved"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type CIStatusSummary struct {
 	State    string            `json:"state"`
 	Statuses []CIStatusContext `json:"statuses"`
@@ -106,7 +125,7 @@ func getRegistry() *registry {
 			subscriptions: make(map[subscriptionKey]*subscription),
 			providers:     []remoteProvider{},
 		}
-		globalRegistry.providers = append(globalRegistry.providers, newGithubProvider(globalRegistry.dispatch))
+		globalRegistry.providers = append(globalRegistry.providers, newGithubProvider(globalRegistry.dispatch), newGitlabProvider(globalRegistry.dispatch), newGiteaProvider(globalRegistry.dispatch))
 	})
 	return globalRegistry
 }
@@ -121,7 +140,7 @@ func (r *registry) subscribe(ctx context.Context, remoteURL string, branchName s
 	)
 
 	if p, ok := r.providerForKey(key); ok {
-		if err := p.ensureAuth(); err != nil {
+		if err := p.ensureAuth(key); err != nil {
 			logger.Debug("subscribe failed auth",
 				"remote_url", remoteURL,
 				"branch", branchName,
@@ -234,7 +253,7 @@ func EnsureAuth(ctx context.Context, remoteURL string) error {
 	_ = ctx
 	key := subscriptionKey{remoteURL: remoteURL}
 	if p, ok := getRegistry().providerForKey(key); ok {
-		return p.ensureAuth()
+		return p.ensureAuth(key)
 	}
 	return ErrUnsupportedRemote
 }
@@ -0,0 +1,217 @@
+package repo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	FileStatusAdded     = "added"
+	FileStatusModified  = "modified"
+	FileStatusDeleted   = "deleted"
+	FileStatusRenamed   = "renamed"
+	FileStatusUntracked = "untracked"
+)
+
+// FileDiff is the change to one file. Patch is the unified diff starting at
+// its "diff --git" line.
+type FileDiff struct {
+	Path       string
+	OldPath    string
+	Status     string
+	Insertions int
+	Deletions  int
+	Binary     bool
+	Patch      string
+}
+
+// WorktreeDiff splits the changes of a worktree the way they reach a pull
+// request: commits since the merge base with BaseRef, the index, and the
+// working tree including untracked files.
+type WorktreeDiff struct {
+	BaseRef   string
+	MergeBase string
+	Committed []FileDiff
+	Staged    []FileDiff
+	Unstaged  []FileDiff
+}
+
+// ErrBaseNotFound is returned by DiffWorktree when the base branch does not
+// name a commit in the worktree's repository.
+var ErrBaseNotFound = errors.New("base branch not found")
+
+// DiffWorktree diffs worktreePath against baseBranch, preferring the remote
+// tracking branch so commits merged upstream do not show up as changes.
+func DiffWorktree(worktreePath string, baseBranch string) (WorktreeDiff, error) {
+	baseRef := baseBranch
+	remoteRef := "refs/remotes/origin/" + baseBranch
+	if err := execCommand("git", "-C", worktreePath, "show-ref", "--verify", "--quiet", remoteRef).Run(); err == nil {
+		baseRef = "origin/" + baseBranch
+	}
+	// Resolve the base once, with option parsing ended, so only the commit id
+	// reaches the commands below.
+	baseCommit, err := gitOutput(worktreePath, "rev-parse", "--verify", "--quiet", "--end-of-options", baseRef+"^{commit}")
+	if err != nil {
+		return WorktreeDiff{}, fmt.Errorf("%w: %s", ErrBaseNotFound, baseBranch)
+	}
+	mergeBase, err := gitOutput(worktreePath, "merge-base", "HEAD", strings.TrimSpace(baseCommit))
+	if err != nil {
+		return WorktreeDiff{}, err
+	}
+	diff := WorktreeDiff{BaseRef: baseRef, MergeBase: strings.TrimSpace(mergeBase)}
+
+	committed, err := gitOutput(worktreePath, "diff", "-M", "--no-color", "--no-ext-diff", diff.MergeBase, "HEAD")
+	if err != nil {
+		return WorktreeDiff{}, err
+	}
+	diff.Committed = ParseUnifiedDiff(committed)
+
+	staged, err := gitOutput(worktreePath, "diff", "-M", "--no-color", "--no-ext-diff", "--cached")
+	if err != nil {
+		return WorktreeDiff{}, err
+	}
+	diff.Staged = ParseUnifiedDiff(staged)
+
+	unstaged, err := gitOutput(worktreePath, "diff", "-M", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return WorktreeDiff{}, err
+	}
+	diff.Unstaged = ParseUnifiedDiff(unstaged)
+
+	untracked, err := untrackedDiffs(worktreePath)
+	if err != nil {
+		return WorktreeDiff{}, err
+	}
+	diff.Unstaged = append(diff.Unstaged, untracked...)
+	return diff, nil
+}
+
+func untrackedDiffs(worktreePath string) ([]FileDiff, error) {
+	output, err := gitOutput(worktreePath, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	files := []FileDiff{}
+	for _, path := range strings.Split(output, "\x00") {
+		if path == "" {
+			continue
+		}
+		// --no-index exits with 1 whenever the files differ, which they
+		// always do against /dev/null.
+		cmd := execCommand("git", "-C", worktreePath, "diff", "--no-color", "--no-ext-diff", "--no-index", "--", "/dev/null", path)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+				return nil, fmt.Errorf("git diff %s failed: %s", path, commandMessage(stderr.String(), err))
+			}
+		}
+		for _, file := range ParseUnifiedDiff(stdout.String()) {
+			file.Path = path
+			file.Status = FileStatusUntracked
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// ParseUnifiedDiff splits git diff output into files and counts their added
+// and removed lines.
+func ParseUnifiedDiff(output string) []FileDiff {
+	files := []FileDiff{}
+	var current *FileDiff
+	var patch strings.Builder
+	inHunk := false
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Patch = patch.String()
+		files = append(files, *current)
+		current = nil
+		patch.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if line == "" {
+			continue
+		}
+		text := strings.TrimRight(line, "\n")
+		if strings.HasPrefix(text, "diff --git ") {
+			flush()
+			current = &FileDiff{Status: FileStatusModified}
+			current.OldPath, current.Path = pathsFromDiffHeader(strings.TrimPrefix(text, "diff --git "))
+			inHunk = false
+		}
+		if current == nil {
+			continue
+		}
+		patch.WriteString(line)
+
+		switch {
+		case strings.HasPrefix(text, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(text, "+"):
+			current.Insertions++
+		case inHunk && strings.HasPrefix(text, "-"):
+			current.Deletions++
+		case inHunk:
+		case strings.HasPrefix(text, "new file mode"):
+			current.Status = FileStatusAdded
+		case strings.HasPrefix(text, "deleted file mode"):
+			current.Status = FileStatusDeleted
+		case strings.HasPrefix(text, "rename from "):
+			current.Status = FileStatusRenamed
+			current.OldPath = strings.TrimPrefix(text, "rename from ")
+		case strings.HasPrefix(text, "rename to "):
+			current.Path = strings.TrimPrefix(text, "rename to ")
+		case strings.HasPrefix(text, "Binary files ") || text == "GIT binary patch":
+			current.Binary = true
+		case strings.HasPrefix(text, "--- a/"):
+			current.OldPath = strings.TrimPrefix(text, "--- a/")
+		case strings.HasPrefix(text, "+++ b/"):
+			current.Path = strings.TrimPrefix(text, "+++ b/")
+		}
+	}
+	flush()
+
+	for i := range files {
+		if files[i].Status != FileStatusRenamed && files[i].OldPath == files[i].Path {
+			files[i].OldPath = ""
+		}
+	}
+	return files
+}
+
+// pathsFromDiffHeader reads "a/<old> b/<new>". The header is ambiguous when
+// paths contain " b/", so the ---/+++ lines override it where present.
+func pathsFromDiffHeader(header string) (string, string) {
+	oldPath, newPath, ok := strings.Cut(header, " b/")
+	if !ok {
+		return "", header
+	}
+	return strings.TrimPrefix(oldPath, "a/"), newPath
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := execCommand("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %s", args[0], commandMessage(stderr.String(), err))
+	}
+	return stdout.String(), nil
+}
+
+func commandMessage(stderr string, err error) string {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return msg
+	}
+	return err.Error()
+}
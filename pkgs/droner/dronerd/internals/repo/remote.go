@@ -1,7 +1,10 @@
 package repo
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 )
 
@@ -24,3 +27,57 @@ func GetRemoteURL(repoPath string) (string, error) {
 
 	return strings.TrimSpace(string(output)), nil
 }
+
+// PushBranch pushes branch from worktreePath to `origin` and sets it as the
+// upstream. Git never prompts for credentials, so a push that needs them fails
+// instead of waiting until ctx expires.
+func PushBranch(ctx context.Context, worktreePath string, branch string) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", worktreePath, "push", "--set-upstream", "origin", branch)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("git push origin %s: %w", branch, ctxErr)
+		}
+		msg := strings.TrimSpace(string(output))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("git push origin %s failed: %s", branch, msg)
+	}
+	return nil
+}
+
+// BaseRef returns the ref new session branches of repoPath start from: the
+// target of `origin/HEAD`, falling back to main or master and finally HEAD.
+func BaseRef(repoPath string) string {
+	symbolic := execCommand("git", "-C", repoPath, "symbolic-ref", "refs/remotes/origin/HEAD")
+	if output, err := symbolic.CombinedOutput(); err == nil {
+		if ref := strings.TrimSpace(string(output)); ref != "" {
+			return ref
+		}
+	}
+	for _, ref := range []string{
+		"refs/remotes/origin/main",
+		"refs/remotes/origin/master",
+		"refs/heads/main",
+		"refs/heads/master",
+	} {
+		check := execCommand("git", "-C", repoPath, "show-ref", "--verify", "--quiet", ref)
+		if err := check.Run(); err == nil {
+			return ref
+		}
+	}
+	return "HEAD"
+}
+
+// BranchFromRef returns the branch name behind a local or origin ref such as
+// one returned by BaseRef, or "" when ref does not name a branch.
+func BranchFromRef(ref string) string {
+	for _, prefix := range []string{"refs/remotes/origin/", "refs/heads/"} {
+		if branch, ok := strings.CutPrefix(ref, prefix); ok {
+			return branch
+		}
+	}
+	return ""
+}
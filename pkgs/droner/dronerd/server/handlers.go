@@ -385,23 +385,33 @@ func navigationBranchFromTmuxSession(tmuxSession string) string {
 func renderSessionListResponse(w http.ResponseWriter, r *http.Request, items []sessionevents.ListItem) {
 	responseItems := make([]schemas.SessionListItem, 0, len(items))
 	for _, item := range items {
-		tmuxSession := ""
-		if item.Repo != "" && item.Branch != "" {
-			tmuxSession = item.Repo + "#" + item.Branch
-		}
-
-		responseItems = append(responseItems, schemas.SessionListItem{
-			ID:          item.ID,
-			Repo:        item.Repo,
-			RemoteURL:   item.RemoteURL,
-			TmuxSession: tmuxSession,
-			Branch:      optionalBranch(item.Branch),
-			State:       schemas.SessionPublicState(item.State),
-		})
+		responseItems = append(responseItems, sessionListItem(item))
 	}
 	RenderJSON(w, r, schemas.SessionListResponse{Sessions: responseItems})
 }
 
+func sessionListItem(item sessionevents.ListItem) schemas.SessionListItem {
+	tmuxSession := ""
+	if item.Repo != "" && item.Branch != "" {
+		tmuxSession = item.Repo + "#" + item.Branch
+	}
+
+	responseItem := schemas.SessionListItem{
+		ID:          item.ID,
+		Repo:        item.Repo,
+		RepoPath:    item.RepoPath,
+		RemoteURL:   item.RemoteURL,
+		TmuxSession: tmuxSession,
+		Branch:      optionalBranch(item.Branch),
+		State:       schemas.SessionPublicState(item.State),
+		PRNumber:    item.PRNumber,
+		PRURL:       item.PRURL,
+		PRState:     item.PRState,
+		PRCIState:   item.PRCIState,
+	}
+	return responseItem
+}
+
 func optionalBranch(value string) *schemas.SBranch {
 	if strings.TrimSpace(value) == "" {
 		return nil
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/retention"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+
+	z "github.com/Oudwins/zog"
+)
+
+func (s *Server) HandlerAdminCompact(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	var payload schemas.AdminCompactRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		logger.Info("Json decoding failed", slog.String("err", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeInvalidJson, "Invalid json", nil), Render.Status(http.StatusBadRequest))
+		return
+	}
+
+	errs := schemas.AdminCompactSchema.Validate(&payload)
+	if errs != nil {
+		flattened := z.Issues.FlattenAndCollect(errs)
+		logger.Info("Schema validation failed", slog.Any("errors", flattened))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, "Schema validation failed", flattened), Render.Status(http.StatusBadRequest))
+		return
+	}
+
+	dropSubscribers := make([]eventlog.SubscriberID, 0, len(payload.DropSubscribers))
+	for _, subscriber := range payload.DropSubscribers {
+		dropSubscribers = append(dropSubscribers, eventlog.SubscriberID(subscriber))
+	}
+	report, err := s.retention.Compact(r.Context(), retention.Options{
+		DryRun:          payload.DryRun,
+		OlderThan:       time.Duration(payload.OlderThanDays) * 24 * time.Hour,
+		DropSubscribers: dropSubscribers,
+	})
+	if err != nil {
+		logger.Error("Failed to compact event log", slog.Bool("dry_run", payload.DryRun), slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, "Failed to compact event log", nil), Render.Status(http.StatusInternalServerError))
+		return
+	}
+
+	RenderJSON(w, r, compactResponse(report), Render.Status(http.StatusOK))
+}
+
+func compactResponse(report retention.Report) schemas.AdminCompactResponse {
+	res := schemas.AdminCompactResponse{
+		DryRun:        report.DryRun,
+		Cutoff:        report.Cutoff,
+		Topics:        make([]schemas.AdminCompactTopic, 0, len(report.Topics)),
+		Streams:       make([]schemas.AdminCompactStream, 0, len(report.Streams)),
+		EventsRemoved: report.EventsRemoved,
+	}
+	for _, topic := range report.Topics {
+		res.Topics = append(res.Topics, schemas.AdminCompactTopic{
+			Topic:       string(topic.Topic),
+			Latest:      topic.Latest,
+			Horizon:     topic.Horizon,
+			PinnedBy:    string(topic.PinnedBy),
+			Checkpoints: compactCheckpoints(topic.Checkpoints),
+			Dropped:     compactCheckpoints(topic.Dropped),
+		})
+	}
+	for _, stream := range report.Streams {
+		res.Streams = append(res.Streams, schemas.AdminCompactStream{
+			Topic:    string(stream.Topic),
+			StreamID: stream.StreamID,
+			Reason:   stream.Reason,
+			Events:   stream.Events,
+			Action:   stream.Action,
+			Detail:   stream.Detail,
+		})
+	}
+	return res
+}
+
+func compactCheckpoints(checkpoints []eventlog.Checkpoint) []schemas.AdminCompactCheckpoint {
+	out := make([]schemas.AdminCompactCheckpoint, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		out = append(out, schemas.AdminCompactCheckpoint{
+			Subscriber: string(checkpoint.Subscriber),
+			Sequence:   checkpoint.Sequence,
+			UpdatedAt:  checkpoint.UpdatedAt,
+		})
+	}
+	return out
+}
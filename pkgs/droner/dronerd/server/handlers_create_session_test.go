@@ -29,6 +29,10 @@ import (
 	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
 )
 
+// testSessionBaseBranch is the base branch createSessionBackend records for
+// every session.
+const testSessionBaseBranch = "develop"
+
 type createSessionBackend struct {
 	worktreeRoot string
 }
@@ -42,6 +46,9 @@ func (b *createSessionBackend) WorktreePath(repoPath string, sessionID string) (
 }
 
 func (b *createSessionBackend) CreateSession(ctx context.Context, repoPath string, worktreePath string, sessionID string, agentConfig backends.AgentConfig, opts ...backends.CreateSessionOptions) error {
+	if len(opts) > 0 && opts[0].RecordBaseBranch != nil {
+		opts[0].RecordBaseBranch(testSessionBaseBranch)
+	}
 	return nil
 }
 
@@ -57,6 +64,10 @@ func (b *createSessionBackend) DeleteSession(ctx context.Context, worktreePath s
 	return nil
 }
 
+func (b *createSessionBackend) SendMessage(ctx context.Context, worktreePath string, agentConfig backends.AgentConfig, message *messages.Message) error {
+	return nil
+}
+
 func newEventSourcedCreateSessionTestServer(t *testing.T) (*Server, *db.Queries, string, string) {
 	t.Helper()
 
@@ -112,7 +123,7 @@ func newEventSourcedCreateSessionTestServer(t *testing.T) (*Server, *db.Queries,
 		DB:           projectionConn,
 		Queries:      projectionQueries,
 		EventLogs:    eventLogs,
-		Sessions:     sessionevents.NewSQLiteProjectionStore(projectionQueries),
+		Sessions:     sessionevents.NewProjectionStore(projectionQueries),
 		BackendStore: store,
 	}}
 
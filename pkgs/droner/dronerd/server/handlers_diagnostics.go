@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+
+	coredb "github.com/Oudwins/droner/pkgs/droner/dronerd/db"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/diagnostics"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/pressly/goose/v3"
+)
+
+// subscriberLagWarning is how many events a subscriber may be behind before
+// diagnostics flags it. Subscribers normally keep up within a few events.
+const subscriberLagWarning = 100
+
+func (s *Server) HandlerDiagnostics(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	response := schemas.DiagnosticsResponse{
+		Version:     s.Base.Config.Version,
+		Checks:      diagnostics.EnvironmentChecks(r.Context(), s.Base.Config),
+		Databases:   s.databaseDiagnostics(r.Context(), logger),
+		Subscribers: []schemas.DiagnosticSubscriber{},
+	}
+
+	if s.retention != nil {
+		topics, err := s.retention.Topics(r.Context())
+		if err != nil {
+			logger.Error("Failed to load subscriber checkpoints", slog.String("error", err.Error()))
+			RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, "Failed to load subscriber checkpoints", nil), Render.Status(http.StatusInternalServerError))
+			return
+		}
+		for _, topic := range topics {
+			for _, checkpoint := range topic.Checkpoints {
+				response.Subscribers = append(response.Subscribers, s.subscriberDiagnostic(topic.Topic, topic.Latest, checkpoint))
+			}
+		}
+	}
+
+	RenderJSON(w, r, response, Render.Status(http.StatusOK))
+}
+
+func (s *Server) databaseDiagnostics(ctx context.Context, logger *slog.Logger) []schemas.DiagnosticDatabase {
+	driver := s.Base.Config.Storage.Driver
+	if driver == "" {
+		driver = conf.StorageDriverSQLite
+	}
+	projection := func() (*goose.Provider, error) {
+		if driver == conf.StorageDriverPostgres {
+			return coredb.NewPostgresMigrationProvider(s.Base.DB)
+		}
+		return coredb.NewMigrationProvider(s.Base.DB)
+	}
+	return []schemas.DiagnosticDatabase{
+		s.databaseDiagnostic(ctx, logger, "projections", driver, projection),
+		s.databaseDiagnostic(ctx, logger, "eventlog", driver, s.Base.EventLogs.MigrationProvider),
+	}
+}
+
+func (s *Server) databaseDiagnostic(ctx context.Context, logger *slog.Logger, name string, driver conf.StorageDriver, provider func() (*goose.Provider, error)) schemas.DiagnosticDatabase {
+	database := schemas.DiagnosticDatabase{Name: name, Driver: driver.String()}
+	migrations, err := provider()
+	if err == nil {
+		sources := migrations.ListSources()
+		if len(sources) > 0 {
+			database.Latest = sources[len(sources)-1].Version
+		}
+		database.Version, err = migrations.GetDBVersion(ctx)
+	}
+	switch {
+	case err != nil:
+		logger.Error("Failed to read migration status", slog.String("database", name), slog.String("error", err.Error()))
+		database.Status = schemas.DiagnosticStatusFail
+		database.Error = err.Error()
+		database.Fix = s.databaseFix(driver)
+	case database.Version < database.Latest:
+		database.Status = schemas.DiagnosticStatusFail
+		database.Fix = "Restart the daemon (`droner serve --detach` after stopping it) so it applies the pending migrations, and check log.txt if they fail"
+	case database.Version > database.Latest:
+		database.Status = schemas.DiagnosticStatusFail
+		database.Fix = fmt.Sprintf("The database was migrated by a newer droner (version %d, this build knows %d); upgrade droner", database.Version, database.Latest)
+	default:
+		database.Status = schemas.DiagnosticStatusOK
+	}
+	return database
+}
+
+func (s *Server) databaseFix(driver conf.StorageDriver) string {
+	if driver == conf.StorageDriverPostgres {
+		return "Check that storage.postgres.dsn in droner.json points at a reachable database the droner user can read"
+	}
+	return fmt.Sprintf("Check that %s is readable and not corrupted", filepath.Dir(coredb.DBPath(s.Base.Env.DATA_DIR)))
+}
+
+func (s *Server) subscriberDiagnostic(topic eventlog.Topic, latest int64, checkpoint eventlog.Checkpoint) schemas.DiagnosticSubscriber {
+	diagnostic := schemas.DiagnosticSubscriber{
+		Topic:      string(topic),
+		Subscriber: string(checkpoint.Subscriber),
+		Status:     schemas.DiagnosticStatusOK,
+		Sequence:   checkpoint.Sequence,
+		Latest:     latest,
+		Lag:        max(latest-checkpoint.Sequence, 0),
+		UpdatedAt:  checkpoint.UpdatedAt,
+	}
+	if diagnostic.Lag > subscriberLagWarning {
+		diagnostic.Status = schemas.DiagnosticStatusWarn
+		diagnostic.Fix = fmt.Sprintf("Look for %s errors in %s; restarting the daemon resumes it from its checkpoint. If %s no longer runs, drop it with `droner admin compact --drop-subscriber %s`", checkpoint.Subscriber, filepath.Join(s.Base.Env.DATA_DIR, "log.txt"), checkpoint.Subscriber, checkpoint.Subscriber)
+	}
+	return diagnostic
+}
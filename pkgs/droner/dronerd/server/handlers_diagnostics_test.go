@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/core"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/retention"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/env"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+func TestHandlerDiagnosticsReportsMigrationsAndSubscribers(t *testing.T) {
+	server, _, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	server.retention = retention.New(server.Base.EventLogs.Backend(), nil, server.Base.Logger, conf.RetentionConfig{}, filepath.Join(server.Base.Env.DATA_DIR, "archive"))
+	createEventSourcedSession(t, server, repoDir, "doctor")
+	waitForSessionState(t, server, "doctor", sessionevents.PublicStateActiveIdle)
+
+	req := httptest.NewRequest(http.MethodGet, "/diagnostics", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("diagnostics status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+
+	var response schemas.DiagnosticsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if len(response.Checks) == 0 {
+		t.Fatal("expected environment checks")
+	}
+	if len(response.Databases) != 2 {
+		t.Fatalf("databases = %#v", response.Databases)
+	}
+	for _, database := range response.Databases {
+		if database.Status != schemas.DiagnosticStatusOK || database.Driver != "sqlite" || database.Latest == 0 || database.Version != database.Latest {
+			t.Fatalf("unexpected %s database: %#v", database.Name, database)
+		}
+	}
+	if len(response.Subscribers) == 0 {
+		t.Fatal("expected subscriber checkpoints")
+	}
+	for _, subscriber := range response.Subscribers {
+		if subscriber.Topic == "" || subscriber.Subscriber == "" || subscriber.Lag != subscriber.Latest-subscriber.Sequence {
+			t.Fatalf("unexpected subscriber: %#v", subscriber)
+		}
+	}
+}
+
+func TestSubscriberDiagnosticWarnsOnLag(t *testing.T) {
+	server := &Server{Base: &core.BaseServer{Env: &env.EnvStruct{DATA_DIR: "/data"}}}
+
+	caughtUp := server.subscriberDiagnostic("sessions", 40, eventlog.Checkpoint{Subscriber: "projection", Sequence: 40})
+	if caughtUp.Status != schemas.DiagnosticStatusOK || caughtUp.Lag != 0 || caughtUp.Fix != "" {
+		t.Fatalf("caught up subscriber = %#v", caughtUp)
+	}
+	behind := server.subscriberDiagnostic("sessions", 500, eventlog.Checkpoint{Subscriber: "hooks", Sequence: 12})
+	if behind.Status != schemas.DiagnosticStatusWarn || behind.Lag != 488 || behind.Fix == "" {
+		t.Fatalf("lagging subscriber = %#v", behind)
+	}
+}
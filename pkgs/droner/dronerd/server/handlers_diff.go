@@ -0,0 +1,113 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/repo"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/zog/zhttp"
+	"github.com/go-chi/chi/v5"
+
+	z "github.com/Oudwins/zog"
+)
+
+func (s *Server) HandlerSessionDiff(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	var q schemas.SessionDiffQuery
+	if errs := schemas.SessionDiffQuerySchema.Parse(zhttp.Request(r), &q); errs != nil {
+		flattened := z.Issues.FlattenAndCollect(errs)
+		logger.Info("Query validation failed", slog.Any("errors", flattened))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, "Query validation failed", flattened), Render.Status(http.StatusBadRequest))
+		return
+	}
+
+	streamID := strings.TrimSpace(chi.URLParam(r, "id"))
+	logger = logger.With(slog.String("stream_id", streamID))
+	ref, err := s.events.LookupSessionByID(r.Context(), streamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeNotFound, "Session not found", nil), Render.Status(http.StatusNotFound))
+			return
+		}
+		logger.Error("Failed to load session", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, "Failed to load session", nil), Render.Status(http.StatusInternalServerError))
+		return
+	}
+	// Completing a session keeps its worktree, deleting it does not.
+	if (!ref.PublicState.IsActive() && ref.PublicState != sessionevents.PublicStateCompleted) || strings.TrimSpace(ref.WorktreePath) == "" {
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, fmt.Sprintf("Session has no worktree (status=%s)", ref.PublicState), nil), Render.Status(http.StatusConflict))
+		return
+	}
+
+	base := q.Base
+	if base == "" {
+		base, err = sessionBaseBranch(ref)
+		if err != nil {
+			logger.Error("Failed to resolve base branch", slog.String("error", err.Error()))
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, err.Error(), nil), Render.Status(http.StatusBadRequest))
+			return
+		}
+	}
+	diff, err := repo.DiffWorktree(ref.WorktreePath, base)
+	if errors.Is(err, repo.ErrBaseNotFound) {
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, err.Error(), nil), Render.Status(http.StatusBadRequest))
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to diff session worktree", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, err.Error(), nil), Render.Status(http.StatusInternalServerError))
+		return
+	}
+
+	response := schemas.SessionDiffResponse{
+		ID:        streamID,
+		Branch:    schemas.SBranch(ref.Branch),
+		BaseRef:   diff.BaseRef,
+		MergeBase: diff.MergeBase,
+		Committed: sessionDiffFiles(diff.Committed, q.Patch),
+		Staged:    sessionDiffFiles(diff.Staged, q.Patch),
+		Unstaged:  sessionDiffFiles(diff.Unstaged, q.Patch),
+	}
+	response.Summary = sessionDiffSummary(response.Committed, response.Staged, response.Unstaged)
+	RenderJSON(w, r, response)
+}
+
+func sessionDiffFiles(files []repo.FileDiff, withPatch bool) []schemas.SessionDiffFile {
+	out := make([]schemas.SessionDiffFile, 0, len(files))
+	for _, file := range files {
+		item := schemas.SessionDiffFile{
+			Path:       file.Path,
+			OldPath:    file.OldPath,
+			Status:     file.Status,
+			Insertions: file.Insertions,
+			Deletions:  file.Deletions,
+			Binary:     file.Binary,
+		}
+		if withPatch {
+			item.Patch = file.Patch
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// sessionDiffSummary counts a file changed in several sections once, while
+// its lines count in each section they were changed in.
+func sessionDiffSummary(sections ...[]schemas.SessionDiffFile) schemas.SessionDiffSummary {
+	summary := schemas.SessionDiffSummary{}
+	paths := map[string]struct{}{}
+	for _, files := range sections {
+		for _, file := range files {
+			paths[file.Path] = struct{}{}
+			summary.Insertions += file.Insertions
+			summary.Deletions += file.Deletions
+		}
+	}
+	summary.Files = len(paths)
+	return summary
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=droner", "-c", "user.email=droner@example.com"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, output)
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile %s: %v", path, err)
+	}
+}
+
+func TestHandlerSessionDiffSeparatesCommittedStagedAndUnstaged(t *testing.T) {
+	server, _, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	writeTestFile(t, filepath.Join(repoDir, "main.go"), "package main\n\nfunc main() {}\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "initial")
+
+	created := createEventSourcedSession(t, server, repoDir, "diffed")
+	ref := waitForSessionState(t, server, "diffed", sessionevents.PublicStateActiveIdle)
+	runGit(t, repoDir, "worktree", "add", "-b", "diffed", ref.WorktreePath)
+
+	worktree := ref.WorktreePath
+	writeTestFile(t, filepath.Join(worktree, "main.go"), "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")
+	runGit(t, worktree, "commit", "-am", "say hi")
+	writeTestFile(t, filepath.Join(worktree, "staged.go"), "package main\n")
+	runGit(t, worktree, "add", "staged.go")
+	writeTestFile(t, filepath.Join(worktree, "main.go"), "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n")
+	writeTestFile(t, filepath.Join(worktree, "notes.txt"), "todo\n")
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions/"+created.ID+"/diff?base=main", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("diff status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+
+	var response schemas.SessionDiffResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if response.BaseRef != "main" || response.MergeBase == "" {
+		t.Fatalf("base = %q merge base = %q", response.BaseRef, response.MergeBase)
+	}
+	if len(response.Committed) != 1 || response.Committed[0].Path != "main.go" || response.Committed[0].Insertions != 3 || response.Committed[0].Deletions != 1 {
+		t.Fatalf("committed = %#v", response.Committed)
+	}
+	if !strings.Contains(response.Committed[0].Patch, "+\tprintln(\"hi\")") {
+		t.Fatalf("committed patch = %q", response.Committed[0].Patch)
+	}
+	if len(response.Staged) != 1 || response.Staged[0].Path != "staged.go" || response.Staged[0].Status != "added" {
+		t.Fatalf("staged = %#v", response.Staged)
+	}
+	if len(response.Unstaged) != 2 || response.Unstaged[0].Path != "main.go" || response.Unstaged[1].Path != "notes.txt" || response.Unstaged[1].Status != "untracked" {
+		t.Fatalf("unstaged = %#v", response.Unstaged)
+	}
+	if want := (schemas.SessionDiffSummary{Files: 3, Insertions: 6, Deletions: 2}); response.Summary != want {
+		t.Fatalf("summary = %#v, want %#v", response.Summary, want)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/sessions/"+created.ID+"/diff?base=main&patch=false", nil)
+	rec = httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	var summaryOnly schemas.SessionDiffResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &summaryOnly); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if summaryOnly.Summary != response.Summary || summaryOnly.Committed[0].Patch != "" {
+		t.Fatalf("patch=false response = %#v, want the summary without patches", summaryOnly)
+	}
+}
+
+func TestHandlerSessionDiffDefaultsToRecordedBaseBranch(t *testing.T) {
+	server, _, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	writeTestFile(t, filepath.Join(repoDir, "main.go"), "package main\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "initial")
+	runGit(t, repoDir, "branch", testSessionBaseBranch)
+	writeTestFile(t, filepath.Join(repoDir, "main.go"), "package main\n\nfunc main() {}\n")
+	runGit(t, repoDir, "commit", "-am", "only on the default branch")
+
+	created := createEventSourcedSession(t, server, repoDir, "from-develop")
+	ref := waitForSessionState(t, server, "from-develop", sessionevents.PublicStateActiveIdle)
+	if ref.BaseBranch != testSessionBaseBranch {
+		t.Fatalf("recorded base branch = %q, want %q", ref.BaseBranch, testSessionBaseBranch)
+	}
+	runGit(t, repoDir, "worktree", "add", "-b", "from-develop", ref.WorktreePath, testSessionBaseBranch)
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions/"+created.ID+"/diff", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("diff status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+	var response schemas.SessionDiffResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if response.BaseRef != testSessionBaseBranch || len(response.Committed) != 0 {
+		t.Fatalf("base = %q committed = %#v, want an empty diff against %s", response.BaseRef, response.Committed, testSessionBaseBranch)
+	}
+}
+
+func TestHandlerSessionDiffRejectsUnsafeOrUnknownBase(t *testing.T) {
+	server, _, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	writeTestFile(t, filepath.Join(repoDir, "main.go"), "package main\n")
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "initial")
+
+	created := createEventSourcedSession(t, server, repoDir, "based")
+	ref := waitForSessionState(t, server, "based", sessionevents.PublicStateActiveIdle)
+	runGit(t, repoDir, "worktree", "add", "-b", "based", ref.WorktreePath)
+
+	output := filepath.Join(t.TempDir(), "written")
+	for _, base := range []string{"--output=" + output, "-main", "main..based", "missing"} {
+		req := httptest.NewRequest(http.MethodGet, "/sessions/"+created.ID+"/diff?base="+url.QueryEscape(base), nil)
+		rec := httptest.NewRecorder()
+		server.Router().ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("base %q: status = %d, want %d; body=%s", base, rec.Code, http.StatusBadRequest, rec.Body.String())
+		}
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Fatalf("expected no file written through the base parameter, stat err = %v", err)
+	}
+}
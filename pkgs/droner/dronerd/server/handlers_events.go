@@ -0,0 +1,46 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *Server) HandlerSessionEvents(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	streamID := strings.TrimSpace(chi.URLParam(r, "id"))
+	logger = logger.With(slog.String("stream_id", streamID))
+	timeline, err := s.events.SessionTimeline(r.Context(), streamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeNotFound, "Session not found", nil), Render.Status(http.StatusNotFound))
+			return
+		}
+		logger.Error("Failed to load session events", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, "Failed to load session events", nil), Render.Status(http.StatusInternalServerError))
+		return
+	}
+
+	response := schemas.SessionEventsResponse{ID: streamID, Events: make([]schemas.SessionEvent, 0, len(timeline))}
+	// Deleted sessions keep their stream, so the branch is best effort.
+	if ref, err := s.events.LookupSessionByID(r.Context(), streamID); err == nil {
+		response.Branch = schemas.SBranch(ref.Branch)
+	}
+	for _, evt := range timeline {
+		response.Events = append(response.Events, schemas.SessionEvent{
+			ID:             string(evt.ID),
+			Type:           string(evt.Type),
+			Version:        evt.StreamVersion,
+			OccurredAt:     evt.OccurredAt,
+			CausationID:    string(evt.CausationID),
+			Payload:        evt.Payload,
+			Error:          evt.Error,
+			BackendDetails: evt.BackendDetails,
+		})
+	}
+	RenderJSON(w, r, response)
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+func TestHandlerSessionEventsReturnsStreamInOrder(t *testing.T) {
+	server, _, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	created := createEventSourcedSession(t, server, repoDir, "timeline")
+	waitForSessionState(t, server, "timeline", sessionevents.PublicStateActiveIdle)
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions/"+created.ID+"/events", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("events status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+
+	var response schemas.SessionEventsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if response.ID != created.ID || response.Branch.String() != "timeline" {
+		t.Fatalf("response id = %q branch = %q", response.ID, response.Branch)
+	}
+	if len(response.Events) == 0 || response.Events[0].Type != "session.queued" {
+		t.Fatalf("events = %#v, want session.queued first", response.Events)
+	}
+	ready := false
+	for i, evt := range response.Events {
+		if evt.Version != int64(i+1) {
+			t.Fatalf("event %d has version %d", i, evt.Version)
+		}
+		if evt.OccurredAt.IsZero() || len(evt.Payload) == 0 {
+			t.Fatalf("event %d is missing its time or payload: %#v", i, evt)
+		}
+		ready = ready || evt.Type == "session.ready"
+	}
+	if !ready {
+		t.Fatalf("events = %#v, want session.ready", response.Events)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/sessions/missing/events", nil)
+	rec = httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing session status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandlerSessionEventsReturnsStreamsLongerThanOnePage(t *testing.T) {
+	server, _, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	created := createEventSourcedSession(t, server, repoDir, "long-timeline")
+	waitForSessionState(t, server, "long-timeline", sessionevents.PublicStateActiveIdle)
+
+	sessionsLog, err := server.Base.EventLogs.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions log: %v", err)
+	}
+	for i := 0; i < 600; i++ {
+		if _, err := sessionsLog.Append(context.Background(), eventlog.PendingEvent{StreamID: eventlog.StreamID(created.ID), Type: "session.note", Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	full, err := eventlog.LoadFullStream(context.Background(), sessionsLog, eventlog.StreamID(created.ID))
+	if err != nil {
+		t.Fatalf("LoadFullStream: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions/"+created.ID+"/events", nil)
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("events status = %d; body=%s", rec.Code, rec.Body.String())
+	}
+	var response schemas.SessionEventsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if len(response.Events) != len(full) || len(full) <= 600 {
+		t.Fatalf("listed %d events, want all %d", len(response.Events), len(full))
+	}
+	if last := response.Events[len(response.Events)-1]; last.Version != int64(len(full)) {
+		t.Fatalf("last event version = %d, want %d", last.Version, len(full))
+	}
+}
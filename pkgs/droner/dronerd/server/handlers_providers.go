@@ -0,0 +1,68 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/backends"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+func (s *Server) HandlerGitHubRateLimit(_ *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	RenderJSON(w, r, gitHubRateLimitResponse(remote.GitHubRateLimitStatus(), time.Now()), Render.Status(http.StatusOK))
+}
+
+// HandlerOpencodeCatalog proxies the agents and models of the opencode server
+// sessions run against. The server only runs once a session started it, so
+// clients treat 502 as "nothing to discover yet".
+func (s *Server) HandlerOpencodeCatalog(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	directory := strings.TrimSpace(r.URL.Query().Get("directory"))
+	catalog, err := backends.LoadOpencodeCatalog(r.Context(), s.Base.Config.Sessions.Harness.Providers.OpenCode, directory)
+	if err != nil {
+		logger.Info("Failed to load opencode catalog", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, "opencode server unavailable: "+err.Error(), nil), Render.Status(http.StatusBadGateway))
+		return
+	}
+	response := schemas.OpencodeCatalogResponse{
+		Agents: make([]schemas.OpencodeAgent, 0, len(catalog.Agents)),
+		Models: make([]schemas.OpencodeModel, 0, len(catalog.Models)),
+	}
+	for _, agent := range catalog.Agents {
+		response.Agents = append(response.Agents, schemas.OpencodeAgent{Name: agent.Name, Description: agent.Description})
+	}
+	for _, model := range catalog.Models {
+		response.Models = append(response.Models, schemas.OpencodeModel{ID: model.ID, Name: model.Name, ProviderName: model.ProviderName})
+	}
+	RenderJSON(w, r, response)
+}
+
+func gitHubRateLimitResponse(rateLimit remote.GitHubRateLimit, now time.Time) schemas.GitHubRateLimitResponse {
+	response := schemas.GitHubRateLimitResponse{
+		Resource:      rateLimit.Resource,
+		Limit:         rateLimit.Limit,
+		Remaining:     rateLimit.Remaining,
+		Used:          rateLimit.Used,
+		ResetAt:       optionalTime(rateLimit.ResetAt),
+		UpdatedAt:     optionalTime(rateLimit.UpdatedAt),
+		BackingOff:    rateLimit.BackingOff(now),
+		BackoffReason: rateLimit.BackoffReason,
+		Requests:      rateLimit.Requests,
+		NotModified:   rateLimit.NotModified,
+	}
+	if response.BackingOff {
+		response.BackoffUntil = optionalTime(rateLimit.BackoffUntil)
+	} else {
+		response.BackoffReason = ""
+	}
+	return response
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/repo"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	"github.com/go-chi/chi/v5"
+
+	z "github.com/Oudwins/zog"
+)
+
+const (
+	// maxPullRequestTitleLength keeps titles derived from a prompt readable.
+	maxPullRequestTitleLength = 72
+	// pushSessionBranchTimeout leaves part of the client's request timeout for
+	// opening the pull request after the push.
+	pushSessionBranchTimeout = 20 * time.Second
+)
+
+// Swapped in tests so opening a pull request needs neither a remote nor a
+// provider.
+var (
+	pushSessionBranch       = repo.PushBranch
+	createRemotePullRequest = remote.CreatePullRequest
+)
+
+func (s *Server) HandlerCreateSessionPullRequest(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	var payload schemas.SessionPullRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		logger.Info("Json decoding failed", slog.String("err", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeInvalidJson, "Invalid json", nil), Render.Status(http.StatusBadRequest))
+		return
+	}
+
+	errs := schemas.SessionPullRequestSchema.Validate(&payload)
+	if errs != nil {
+		flattened := z.Issues.FlattenAndCollect(errs)
+		logger.Info("Schema validation failed", slog.Any("errors", flattened))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, "Schema validation failed", flattened), Render.Status(http.StatusBadRequest))
+		return
+	}
+
+	streamID := strings.TrimSpace(chi.URLParam(r, "id"))
+	logger = logger.With(slog.String("stream_id", streamID))
+	ref, err := s.events.LookupSessionByID(r.Context(), streamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeNotFound, "Session not found", nil), Render.Status(http.StatusNotFound))
+			return
+		}
+		logger.Error("Failed to load session", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, "Failed to load session", nil), Render.Status(http.StatusInternalServerError))
+		return
+	}
+	if !ref.PublicState.IsActive() && ref.PublicState != sessionevents.PublicStateCompleted {
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, fmt.Sprintf("Session is not active (status=%s)", ref.PublicState), nil), Render.Status(http.StatusConflict))
+		return
+	}
+	if strings.TrimSpace(ref.RemoteURL) == "" {
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, "Session repo has no origin remote", nil), Render.Status(http.StatusBadRequest))
+		return
+	}
+
+	description, err := s.events.SessionDescription(r.Context(), streamID)
+	if err != nil {
+		logger.Warn("Failed to load session prompt; continuing without it", slog.String("error", err.Error()))
+		description = ""
+	}
+	title := payload.Title
+	if title == "" {
+		title = pullRequestTitle(description, ref.Branch)
+	}
+	body := payload.Body
+	if body == "" && payload.BodyFromAgent {
+		body = description
+	}
+	base := payload.Base
+	if base == "" {
+		base, err = sessionBaseBranch(ref)
+		if err != nil {
+			logger.Error("Failed to resolve base branch", slog.String("error", err.Error()))
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, err.Error(), nil), Render.Status(http.StatusBadRequest))
+			return
+		}
+	}
+
+	pushCtx, cancelPush := context.WithTimeout(r.Context(), pushSessionBranchTimeout)
+	err = pushSessionBranch(pushCtx, ref.WorktreePath, ref.Branch)
+	cancelPush()
+	if err != nil {
+		logger.Error("Failed to push session branch", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, err.Error(), nil), Render.Status(http.StatusInternalServerError))
+		return
+	}
+
+	snapshot, err := createRemotePullRequest(r.Context(), ref.RemoteURL, remote.CreatePullRequestInput{
+		Branch: ref.Branch,
+		Base:   base,
+		Title:  title,
+		Body:   body,
+		Draft:  payload.Draft,
+	})
+	if err != nil {
+		logger.Error("Failed to create pull request", slog.String("error", err.Error()))
+		switch {
+		case errors.Is(err, remote.ErrUnsupportedRemote):
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, "Remote provider cannot open pull requests", nil), Render.Status(http.StatusBadRequest))
+		case errors.Is(err, sdk.ErrAuthRequired):
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeUnauthorized, "Remote provider is not authenticated", nil), Render.Status(http.StatusUnauthorized))
+		default:
+			RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, err.Error(), nil), Render.Status(http.StatusBadGateway))
+		}
+		return
+	}
+
+	prStreamID, err := s.prs.LinkCreatedPullRequest(r.Context(), streamID, snapshot)
+	if err != nil {
+		// The pull request exists; the next poll links it instead.
+		logger.Error("Failed to link created pull request", slog.Int("pr_number", snapshot.Number), slog.String("error", err.Error()))
+	}
+
+	RenderJSON(w, r, schemas.SessionPullRequestResponse{
+		ID:         streamID,
+		Branch:     schemas.SBranch(ref.Branch),
+		Number:     snapshot.Number,
+		URL:        snapshot.HTMLURL,
+		Title:      snapshot.Title,
+		BaseRef:    snapshot.BaseRef,
+		Draft:      snapshot.Draft,
+		PRStreamID: prStreamID,
+	}, Render.Status(http.StatusCreated))
+}
+
+// sessionBaseBranch returns the branch the session's worktree was created
+// from. Sessions provisioned before it was recorded fall back to the branch a
+// new session of the repo would start from.
+func sessionBaseBranch(ref sessionevents.SessionRef) (string, error) {
+	if ref.BaseBranch != "" {
+		return ref.BaseBranch, nil
+	}
+	if branch := repo.BranchFromRef(repo.BaseRef(ref.RepoPath)); branch != "" {
+		return branch, nil
+	}
+	return "", fmt.Errorf("failed to resolve the base branch of %s; pass one explicitly", ref.Branch)
+}
+
+// pullRequestTitle uses the first line of the session prompt, falling back to
+// the branch name.
+func pullRequestTitle(description string, branch string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return branch
+	}
+	if utf8.RuneCountInString(title) > maxPullRequestTitleLength {
+		runes := []rune(title)
+		title = strings.TrimSpace(string(runes[:maxPullRequestTitleLength-3])) + "..."
+	}
+	return title
+}
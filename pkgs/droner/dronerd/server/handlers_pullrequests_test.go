@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/eventtypes"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+type pullRequestCreateFunc func(ctx context.Context, remoteURL string, input remote.CreatePullRequestInput) (remote.PullRequestSnapshot, error)
+
+func newPullRequestTestServer(t *testing.T, create pullRequestCreateFunc) (*Server, string, *[]string) {
+	t.Helper()
+	server, queries, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	cmd := exec.Command("git", "-C", repoDir, "remote", "add", "origin", "git@github.com:owner/repo.git")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git remote add failed: %v: %s", err, output)
+	}
+	sessionsLog, err := server.Base.EventLogs.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions log: %v", err)
+	}
+	prLog, err := server.Base.EventLogs.PullRequests()
+	if err != nil {
+		t.Fatalf("PullRequests log: %v", err)
+	}
+	server.prs = pullrequestevents.New(prLog, sessionsLog, pullrequestevents.NewSessionLookupStore(queries), pullrequestevents.NewPullRequestSnapshotStore(queries), server.Base.Logger)
+
+	pushed := []string{}
+	previousPush, previousCreate := pushSessionBranch, createRemotePullRequest
+	pushSessionBranch = func(ctx context.Context, worktreePath string, branch string) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("push of %s has no deadline", branch)
+		}
+		pushed = append(pushed, branch)
+		return nil
+	}
+	createRemotePullRequest = create
+	t.Cleanup(func() {
+		pushSessionBranch, createRemotePullRequest = previousPush, previousCreate
+	})
+	return server, repoDir, &pushed
+}
+
+func openSessionPullRequest(server *Server, id string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/sessions/"+id+"/pr", bytesReader([]byte(body)))
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerCreateSessionPullRequestPushesAndLinksPullRequest(t *testing.T) {
+	var received remote.CreatePullRequestInput
+	server, repoDir, pushed := newPullRequestTestServer(t, func(ctx context.Context, remoteURL string, input remote.CreatePullRequestInput) (remote.PullRequestSnapshot, error) {
+		received = input
+		return remote.PullRequestSnapshot{
+			Provider:  "github",
+			RemoteURL: remoteURL,
+			RepoOwner: "owner",
+			RepoName:  "repo",
+			Number:    12,
+			State:     "open",
+			Title:     input.Title,
+			HTMLURL:   "https://github.com/owner/repo/pull/12",
+			Draft:     input.Draft,
+			HeadRef:   input.Branch,
+			BaseRef:   input.Base,
+			CI:        remote.CIStatusSummary{State: "pending"},
+		}, nil
+	})
+	created := createEventSourcedSession(t, server, repoDir, "pr-branch")
+	waitForSessionState(t, server, "pr-branch", sessionevents.PublicStateActiveIdle)
+
+	rec := openSessionPullRequest(server, created.ID, `{"title":"Open it","draft":true}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body=%s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	var response schemas.SessionPullRequestResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if response.Number != 12 || response.PRStreamID != "github:owner/repo#12" || !response.Draft || response.BaseRef != testSessionBaseBranch {
+		t.Fatalf("unexpected response: %#v", response)
+	}
+	if len(*pushed) != 1 || (*pushed)[0] != "pr-branch" {
+		t.Fatalf("unexpected pushes: %#v", *pushed)
+	}
+	if received.Branch != "pr-branch" || received.Base != testSessionBaseBranch || received.Title != "Open it" || !received.Draft {
+		t.Fatalf("unexpected create input: %#v", received)
+	}
+
+	sessionsLog, err := server.Base.EventLogs.Sessions()
+	if err != nil {
+		t.Fatalf("Sessions log: %v", err)
+	}
+	events, err := sessionsLog.LoadStream(context.Background(), eventlog.StreamID(created.ID), eventlog.LoadStreamOptions{})
+	if err != nil {
+		t.Fatalf("load session events: %v", err)
+	}
+	linked := false
+	for _, evt := range events {
+		linked = linked || evt.Type == eventtypes.SessionPRLinked
+	}
+	if !linked {
+		t.Fatalf("expected %s on the session stream", eventtypes.SessionPRLinked)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		listed := listSessions(t, server, "/sessions?limit=10")
+		if len(listed.Sessions) == 1 && listed.Sessions[0].PRURL == "https://github.com/owner/repo/pull/12" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the listed session to carry the pull request URL, got %#v", listed.Sessions)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestHandlerCreateSessionPullRequestDefaultsTitleToBranch(t *testing.T) {
+	var received remote.CreatePullRequestInput
+	server, repoDir, _ := newPullRequestTestServer(t, func(ctx context.Context, remoteURL string, input remote.CreatePullRequestInput) (remote.PullRequestSnapshot, error) {
+		received = input
+		return remote.PullRequestSnapshot{Provider: "github", RepoOwner: "owner", RepoName: "repo", Number: 3, State: "open"}, nil
+	})
+	created := createEventSourcedSession(t, server, repoDir, "no-prompt")
+	waitForSessionState(t, server, "no-prompt", sessionevents.PublicStateActiveIdle)
+
+	rec := openSessionPullRequest(server, created.ID, `{"bodyFromAgent":true}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body=%s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	if received.Title != "no-prompt" || received.Body != "" {
+		t.Fatalf("unexpected defaults: %#v", received)
+	}
+}
+
+func TestHandlerCreateSessionPullRequestRejectsUnsupportedRemote(t *testing.T) {
+	server, repoDir, _ := newPullRequestTestServer(t, func(ctx context.Context, remoteURL string, input remote.CreatePullRequestInput) (remote.PullRequestSnapshot, error) {
+		return remote.PullRequestSnapshot{}, remote.ErrUnsupportedRemote
+	})
+	created := createEventSourcedSession(t, server, repoDir, "unsupported")
+	waitForSessionState(t, server, "unsupported", sessionevents.PublicStateActiveIdle)
+
+	rec := openSessionPullRequest(server, created.ID, `{}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d; body=%s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+}
+
+func TestHandlerCreateSessionPullRequestUnknownSession(t *testing.T) {
+	server, _, _ := newPullRequestTestServer(t, nil)
+
+	rec := openSessionPullRequest(server, "missing", `{}`)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d; body=%s", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
+
+func TestPullRequestTitle(t *testing.T) {
+	long := "Refactor the session projection store so every backend shares one query path"
+	cases := map[string]struct {
+		description string
+		want        string
+	}{
+		"first line":   {description: "Fix the flaky test\n\nIt races the poller.", want: "Fix the flaky test"},
+		"empty":        {description: "  ", want: "feature"},
+		"truncated":    {description: long, want: long[:69] + "..."},
+		"command text": {description: "/review the diff", want: "/review the diff"},
+	}
+	for name, tc := range cases {
+		if got := pullRequestTitle(tc.description, "feature"); got != tc.want {
+			t.Fatalf("%s: pullRequestTitle() = %q, want %q", name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+const watchKeepAliveInterval = 15 * time.Second
+
+// HandlerWatchSessions streams session list items as server-sent events
+// whenever a session projection changes. A "ready" event is sent first, once
+// the stream is subscribed. The stream ends when the client falls too far
+// behind; clients reconnect and list sessions again after the next "ready".
+func (s *Server) HandlerWatchSessions(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		RenderJSON(w, r, JsonResponseError(JsonResponseErroCodeInternal, "Streaming is not supported", nil), Render.Status(http.StatusInternalServerError))
+		return
+	}
+	updates := s.events.WatchSessions(r.Context())
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeWatchEvent(w, schemas.SessionWatchEvent{Type: schemas.SessionWatchEventReady}); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(watchKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case item, ok := <-updates:
+			if !ok {
+				return
+			}
+			session := sessionListItem(item)
+			if err := writeWatchEvent(w, schemas.SessionWatchEvent{Type: schemas.SessionWatchEventSession, Session: &session}); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeWatchEvent(w http.ResponseWriter, evt schemas.SessionWatchEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Type, data)
+	return err
+}
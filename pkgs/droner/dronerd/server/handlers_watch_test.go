@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+)
+
+func TestHandlerWatchSessionsStreamsProjectionChanges(t *testing.T) {
+	server, _, repoDir, _ := newEventSourcedCreateSessionTestServer(t)
+	httpServer := httptest.NewServer(server.Router())
+	t.Cleanup(httpServer.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := sdk.NewClient(sdk.WithBaseURL(httpServer.URL))
+	ready := make(chan struct{})
+	sessions := make(chan schemas.SessionListItem, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.WatchSessions(ctx, func(evt schemas.SessionWatchEvent) {
+			switch evt.Type {
+			case schemas.SessionWatchEventReady:
+				close(ready)
+			case schemas.SessionWatchEventSession:
+				sessions <- *evt.Session
+			}
+		})
+	}()
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		t.Fatal("watch stream never became ready")
+	}
+	createEventSourcedSession(t, server, repoDir, "watched")
+
+	for {
+		select {
+		case item := <-sessions:
+			if item.State != schemas.SessionPublicStateActiveIdle {
+				continue
+			}
+			if item.Branch == nil || item.Branch.String() != "watched" {
+				t.Fatalf("watched item branch = %v, want watched", item.Branch)
+			}
+			if item.RepoPath != repoDir || item.TmuxSession != "repo#watched" {
+				t.Fatalf("watched item = %#v, want repo path %q", item, repoDir)
+			}
+			cancel()
+			if err := <-done; err != nil {
+				t.Fatalf("WatchSessions: %v", err)
+			}
+			return
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the session to become active.idle")
+		}
+	}
+}
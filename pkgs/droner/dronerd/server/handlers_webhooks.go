@@ -0,0 +1,53 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+// GitHub caps webhook payloads at 25MB.
+const maxGitHubWebhookBytes = 25 << 20
+
+func (s *Server) HandlerGitHubWebhook(logger *slog.Logger, w http.ResponseWriter, r *http.Request) {
+	secret := s.Base.Env.GITHUB_WEBHOOK_SECRET
+	if secret == "" {
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeNotFound, "GitHub webhooks are not enabled", nil), Render.Status(http.StatusNotFound))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxGitHubWebhookBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeValidationFailed, "Webhook payload too large", nil), Render.Status(http.StatusRequestEntityTooLarge))
+			return
+		}
+		logger.Info("Failed to read webhook body", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeInvalidJson, "Failed to read webhook body", nil), Render.Status(http.StatusBadRequest))
+		return
+	}
+
+	eventType := r.Header.Get("X-GitHub-Event")
+	delivery := r.Header.Get("X-GitHub-Delivery")
+	logger = logger.With(slog.String("github_event", eventType), slog.String("github_delivery", delivery))
+	if !remote.VerifyGitHubWebhookSignature(secret, body, r.Header.Get("X-Hub-Signature-256")) {
+		logger.Warn("Rejected GitHub webhook with invalid signature")
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeUnauthorized, "Invalid webhook signature", nil), Render.Status(http.StatusUnauthorized))
+		return
+	}
+
+	refreshed, err := remote.HandleGitHubWebhook(eventType, body)
+	if err != nil {
+		logger.Info("Failed to handle GitHub webhook", slog.String("error", err.Error()))
+		RenderJSON(w, r, JsonResponseError(JsonResponseErrorCodeInvalidJson, "Invalid webhook payload", nil), Render.Status(http.StatusBadRequest))
+		return
+	}
+	logger.Debug("GitHub webhook handled", slog.Int("refreshed", refreshed))
+
+	RenderJSON(w, r, schemas.GitHubWebhookResponse{Event: eventType, Delivery: delivery, Refreshed: refreshed}, Render.Status(http.StatusAccepted))
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/core"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/remote"
+	"github.com/Oudwins/droner/pkgs/droner/internals/env"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+func newWebhookTestServer(secret string) *Server {
+	return &Server{Base: &core.BaseServer{
+		Env:    &env.EnvStruct{GITHUB_WEBHOOK_SECRET: secret},
+		Logger: slog.New(slog.NewJSONHandler(io.Discard, nil)),
+	}}
+}
+
+func postGitHubWebhook(server *Server, eventType string, body []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", eventType)
+	req.Header.Set("X-GitHub-Delivery", "delivery-1")
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	rec := httptest.NewRecorder()
+	server.Router().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerGitHubWebhookDisabledWithoutSecret(t *testing.T) {
+	rec := postGitHubWebhook(newWebhookTestServer(""), "ping", []byte(`{}`), "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandlerGitHubWebhookRejectsInvalidSignature(t *testing.T) {
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+	rec := postGitHubWebhook(newWebhookTestServer("secret"), "ping", body, "sha256=deadbeef")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandlerGitHubWebhookAcceptsSignedPing(t *testing.T) {
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	rec := postGitHubWebhook(newWebhookTestServer("secret"), "ping", body, signature)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body.String())
+	}
+	var response schemas.GitHubWebhookResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Event != "ping" || response.Delivery != "delivery-1" || response.Refreshed != 0 {
+		t.Fatalf("unexpected response: %#v", response)
+	}
+}
+
+func TestGitHubRateLimitResponseHidesExpiredBackoff(t *testing.T) {
+	now := time.Date(2026, time.March, 24, 12, 0, 0, 0, time.UTC)
+	rateLimit := remote.GitHubRateLimit{Limit: 5000, Remaining: 80, ResetAt: now.Add(10 * time.Minute), BackoffUntil: now.Add(10 * time.Minute), BackoffReason: "low remaining quota"}
+
+	response := gitHubRateLimitResponse(rateLimit, now)
+	if !response.BackingOff || response.BackoffUntil == nil || response.BackoffReason != "low remaining quota" {
+		t.Fatalf("unexpected active backoff response: %#v", response)
+	}
+	if response.UpdatedAt != nil {
+		t.Fatalf("updatedAt = %v, want omitted", response.UpdatedAt)
+	}
+
+	response = gitHubRateLimitResponse(rateLimit, now.Add(time.Hour))
+	if response.BackingOff || response.BackoffUntil != nil || response.BackoffReason != "" {
+		t.Fatalf("unexpected expired backoff response: %#v", response)
+	}
+}
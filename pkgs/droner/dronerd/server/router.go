@@ -17,11 +17,26 @@ func (s *Server) Router() http.Handler {
 		r.Get("/_session/next", HandlerWithLogger(s.HandlerSessionNext))
 		r.Get("/_session/prev", HandlerWithLogger(s.HandlerSessionPrev))
 		r.Get("/sessions", HandlerWithLogger(s.HandlerListSessions))
+		r.Get("/sessions/watch", HandlerWithLogger(s.HandlerWatchSessions))
 		r.Post("/sessions", HandlerWithLogger(s.HandlerCreateSession))
 		r.Delete("/sessions", HandlerWithLogger(s.HandlerDeleteSession))
 		r.Post("/sessions/complete", HandlerWithLogger(s.HandlerCompleteSession))
 		r.Post("/sessions/reset", HandlerWithLogger(s.HandlerResetSession))
 		r.Post("/sessions/nuke", HandlerWithLogger(s.HandlerNukeSessions))
+		r.Post("/sessions/{id}/pr", HandlerWithLogger(s.HandlerCreateSessionPullRequest))
+		r.Get("/sessions/{id}/diff", HandlerWithLogger(s.HandlerSessionDiff))
+		r.Get("/sessions/{id}/events", HandlerWithLogger(s.HandlerSessionEvents))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Post("/admin/compact", HandlerWithLogger(s.HandlerAdminCompact))
+		r.Get("/diagnostics", HandlerWithLogger(s.HandlerDiagnostics))
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Get("/providers/github/rate-limit", HandlerWithLogger(s.HandlerGitHubRateLimit))
+		r.Get("/providers/opencode/catalog", HandlerWithLogger(s.HandlerOpencodeCatalog))
+		r.Post("/webhooks/github", HandlerWithLogger(s.HandlerGitHubWebhook))
 	})
 
 	return r
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"net"
 	"net/http"
+	"path/filepath"
 	"sync"
 
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/core"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/feedback"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/hooks"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/pullrequests/pullrequestevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/retention"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/sessions/sessionevents"
+	"github.com/Oudwins/droner/pkgs/droner/dronerd/events/webhooks"
 	"github.com/Oudwins/droner/pkgs/droner/dronerd/internals/assert"
 	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
 )
@@ -22,6 +26,9 @@ type Server struct {
 	events       *sessionevents.System
 	prs          *pullrequestevents.System
 	hooks        *hooks.System
+	feedback     *feedback.System
+	webhooks     *webhooks.System
+	retention    *retention.System
 	shutdownOnce sync.Once
 }
 
@@ -32,12 +39,17 @@ func New() *Server {
 	pullRequestsLog, err := base.EventLogs.PullRequests()
 	assert.AssertNil(err, "[SERVER] Failed to initialize pull requests event log")
 
+	events := sessionevents.New(sessionsLog, base.Sessions, base.EventLogs.SessionResetter(), base.Logger, base.Config, base.BackendStore)
+
 	return &Server{
-		Base:     base,
-		canceler: func() {},
-		events:   sessionevents.New(sessionsLog, base.Sessions, base.EventLogs.SessionResetter(), base.Logger, base.Config, base.BackendStore),
-		prs:      pullrequestevents.New(pullRequestsLog, sessionsLog, base.PRSessions, base.PRSnapshots, base.Logger),
-		hooks:    hooks.New(sessionsLog, pullRequestsLog, base.Hooks, base.Logger),
+		Base:      base,
+		canceler:  func() {},
+		events:    events,
+		prs:       pullrequestevents.New(pullRequestsLog, sessionsLog, base.PRSessions, base.PRSnapshots, base.Logger),
+		hooks:     hooks.New(sessionsLog, pullRequestsLog, base.Hooks, base.HookSchedules, events, base.Config.Hooks, base.Logger),
+		feedback:  feedback.New(sessionsLog, base.PRSnapshots, events, base.Config.Feedback, base.Logger),
+		webhooks:  webhooks.New(sessionsLog, pullRequestsLog, events, base.PRSnapshots, base.Config.Webhooks, base.Logger),
+		retention: retention.New(base.EventLogs.Backend(), base.Retention, base.Logger, base.Config.Retention, filepath.Join(base.Env.DATA_DIR, "archive")),
 	}
 }
 
@@ -56,6 +68,9 @@ func (s *Server) Start() error {
 	s.events.Start(ctx)
 	s.prs.Start(ctx)
 	s.hooks.Start(ctx)
+	s.feedback.Start(ctx)
+	s.webhooks.Start(ctx)
+	s.retention.Start(ctx)
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -94,6 +109,15 @@ func (s *Server) Shutdown() {
 		if err := s.hooks.Close(); err != nil {
 			s.Base.Logger.Error("[shutdown] hooks event system shutdown failed", "error", err)
 		}
+		if err := s.feedback.Close(); err != nil {
+			s.Base.Logger.Error("[shutdown] feedback system shutdown failed", "error", err)
+		}
+		if err := s.webhooks.Close(); err != nil {
+			s.Base.Logger.Error("[shutdown] webhooks system shutdown failed", "error", err)
+		}
+		if err := s.retention.Close(); err != nil {
+			s.Base.Logger.Error("[shutdown] retention system shutdown failed", "error", err)
+		}
 		if s.Base != nil {
 			s.Base.Close()
 		}
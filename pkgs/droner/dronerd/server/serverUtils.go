@@ -18,6 +18,7 @@ const (
 	JsonResponseErrorCodeValidationFailed JsonResponseErrorCode = "validation_failed"
 	JsonResponseErroCodeInternal          JsonResponseErrorCode = "internal"
 	JsonResponseErrorCodeNotFound         JsonResponseErrorCode = "not_found"
+	JsonResponseErrorCodeUnauthorized     JsonResponseErrorCode = "unauthorized"
 )
 
 type ErrorResponse struct {
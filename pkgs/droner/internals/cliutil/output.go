@@ -1,12 +1,79 @@
 package cliutil
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"gopkg.in/yaml.v3"
 )
 
+// OutputFormat is how CLI commands print their results.
+type OutputFormat string
+
+const (
+	OutputTable OutputFormat = "table"
+	OutputJSON  OutputFormat = "json"
+	OutputYAML  OutputFormat = "yaml"
+)
+
+var OutputFormats = []OutputFormat{OutputTable, OutputJSON, OutputYAML}
+
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	format := OutputFormat(strings.ToLower(strings.TrimSpace(value)))
+	for _, known := range OutputFormats {
+		if format == known {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q (expected table, json or yaml)", value)
+}
+
+// WriteStructured prints value as JSON or YAML. YAML is converted from the
+// JSON encoding so both use the field names of the API responses.
+func WriteStructured(out io.Writer, format OutputFormat, value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	switch format {
+	case OutputJSON:
+		_, err = fmt.Fprintf(out, "%s\n", encoded)
+		return err
+	case OutputYAML:
+		var node yaml.Node
+		if err := yaml.Unmarshal(encoded, &node); err != nil {
+			return err
+		}
+		clearYAMLStyle(&node)
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&node); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+		_, err = out.Write(buf.Bytes())
+		return err
+	default:
+		return fmt.Errorf("output format %q has no structured encoding", format)
+	}
+}
+
+// clearYAMLStyle drops the flow and quoting styles kept from the JSON input
+// so the encoder picks block style and quotes only where needed.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
 func PrintSessionCreated(response *schemas.SessionCreateResponse) {
 	if harness := strings.TrimSpace(response.Harness.String()); harness != "" {
 		fmt.Printf("harness: %s\n", harness)
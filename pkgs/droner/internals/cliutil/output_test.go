@@ -0,0 +1,38 @@
+package cliutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+func TestWriteStructuredYAMLUsesJSONFieldNames(t *testing.T) {
+	branch := schemas.NewSBranch("feature/login")
+	response := schemas.SessionListResponse{Sessions: []schemas.SessionListItem{{ID: "123", Repo: "api", TmuxSession: "api#feature/login", Branch: &branch, State: schemas.SessionPublicStateActiveIdle}}}
+
+	var out bytes.Buffer
+	if err := WriteStructured(&out, OutputYAML, response); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want := `sessions:
+  - id: "123"
+    repo: api
+    remoteUrl: ""
+    tmuxSession: api#feature/login
+    branch: feature/login
+    state: active.idle
+`
+	if out.String() != want {
+		t.Fatalf("unexpected yaml:\n%s", out.String())
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	if format, err := ParseOutputFormat(" JSON "); err != nil || format != OutputJSON {
+		t.Fatalf("expected json, got %q, %v", format, err)
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Fatal("expected an error for xml")
+	}
+}
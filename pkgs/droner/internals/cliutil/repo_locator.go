@@ -64,43 +64,57 @@ func resolveProjectRepo(repoToken string) (string, error) {
 		return "", fmt.Errorf("repo name %q must not contain path separators", repoToken)
 	}
 
+	matches, err := scanProjectRepos(func(name string) bool { return name == repoToken })
+	if err != nil {
+		return "", err
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("project %q not found under config.projects.parentPaths", repoToken)
+	}
+
+	if len(matches) > 1 {
+		return "", fmt.Errorf("project %q is ambiguous: %s", repoToken, strings.Join(matches, ", "))
+	}
+
+	return matches[0], nil
+}
+
+// ProjectRepos lists the git repos that are direct children of
+// config.projects.parentPaths.
+func ProjectRepos() ([]string, error) {
+	return scanProjectRepos(func(string) bool { return true })
+}
+
+func scanProjectRepos(match func(name string) bool) ([]string, error) {
 	parentPaths := conf.GetConfig().Projects.ParentPaths
 	if len(parentPaths) == 0 {
-		return "", fmt.Errorf("config.projects.parentPaths must contain at least one directory")
+		return nil, fmt.Errorf("config.projects.parentPaths must contain at least one directory")
 	}
 
-	matches := make([]string, 0, 1)
+	repos := []string{}
 	for _, parentPath := range parentPaths {
 		entries, err := os.ReadDir(parentPath)
 		if err != nil {
 			if os.IsNotExist(err) {
 				continue
 			}
-			return "", fmt.Errorf("read parent path %q: %w", parentPath, err)
+			return nil, fmt.Errorf("read parent path %q: %w", parentPath, err)
 		}
 		for _, entry := range entries {
-			if !entry.IsDir() || entry.Name() != repoToken {
+			if !entry.IsDir() || !match(entry.Name()) {
 				continue
 			}
 			repoPath, err := RepoRootFromPath(filepath.Join(parentPath, entry.Name()))
 			if err != nil {
 				continue
 			}
-			matches = append(matches, repoPath)
+			repos = append(repos, repoPath)
 		}
 	}
 
-	if len(matches) == 0 {
-		return "", fmt.Errorf("project %q not found under config.projects.parentPaths", repoToken)
-	}
-
-	sort.Strings(matches)
-	matches = compactStrings(matches)
-	if len(matches) > 1 {
-		return "", fmt.Errorf("project %q is ambiguous: %s", repoToken, strings.Join(matches, ", "))
-	}
-
-	return matches[0], nil
+	sort.Strings(repos)
+	return compactStrings(repos), nil
 }
 
 func compactStrings(values []string) []string {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
@@ -91,6 +92,31 @@ func TestResolveProjectRepoReturnsAmbiguousError(t *testing.T) {
 	}
 }
 
+func TestProjectReposListsGitReposUnderParentPaths(t *testing.T) {
+	root := t.TempDir()
+	firstParent := filepath.Join(root, "first")
+	secondParent := filepath.Join(root, "second")
+	initGitRepo(t, filepath.Join(firstParent, "zeta"))
+	initGitRepo(t, filepath.Join(secondParent, "alpha"))
+	if err := os.MkdirAll(filepath.Join(firstParent, "notes"), 0o755); err != nil {
+		t.Fatalf("MkdirAll notes: %v", err)
+	}
+
+	config := conf.GetConfig()
+	orig := append([]string(nil), config.Projects.ParentPaths...)
+	config.Projects.ParentPaths = []string{firstParent, secondParent, filepath.Join(root, "missing")}
+	t.Cleanup(func() { config.Projects.ParentPaths = orig })
+
+	got, err := ProjectRepos()
+	if err != nil {
+		t.Fatalf("ProjectRepos: %v", err)
+	}
+	want := []string{filepath.Join(firstParent, "zeta"), filepath.Join(secondParent, "alpha")}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("ProjectRepos = %v, want %v", got, want)
+	}
+}
+
 func TestResolveSessionTargetUsesCurrentRepoForBranchOnly(t *testing.T) {
 	repoDir := t.TempDir()
 	initGitRepo(t, repoDir)
@@ -7,7 +7,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/Oudwins/droner/pkgs/droner/internals/env"
 	"github.com/Oudwins/droner/pkgs/droner/internals/version"
@@ -17,36 +16,27 @@ import (
 
 type Config struct {
 	Version   string          `json:"-"`
+	Feedback  FeedbackConfig  `json:"feedback" zog:"feedback"`
+	Hooks     HooksConfig     `json:"hooks" zog:"hooks"`
 	Projects  ProjectsConfig  `json:"projects" zog:"projects"`
-	Providers ProvidersConfig `json:"providers"`
+	Providers ProvidersConfig `json:"providers" zog:"providers"`
+	Retention RetentionConfig `json:"retention" zog:"retention"`
 	Sessions  SessionsConfig  `json:"sessions"`
+	Storage   StorageConfig   `json:"storage" zog:"storage"`
 	TUI       TUIConfig       `json:"tui" zog:"tui"`
+	Webhooks  WebhooksConfig  `json:"webhooks" zog:"webhooks"`
 }
 
-type ProvidersConfig struct {
-	Github GitHubConfig `json:"github"`
-}
-
-type GitHubConfig struct {
-	// Seconds
-	PollInterval int `json:"pollInterval"`
-}
-
-var gitHubSchema = z.Struct(z.Shape{
-	"PollInterval": z.Int().DefaultFunc(func() int {
-		return int(10 * time.Second)
-	}),
-})
-
-var providersSchema = z.Struct(z.Shape{
-	"github": gitHubSchema,
-})
-
 var ConfigSchema = z.Struct(z.Shape{
+	"Feedback":  FeedbackConfigSchema,
+	"Hooks":     HooksConfigSchema,
 	"Projects":  ProjectsConfigSchema,
 	"Providers": providersSchema,
+	"Retention": RetentionConfigSchema,
 	"Sessions":  SessionsConfigSchema,
+	"Storage":   StorageConfigSchema,
 	"TUI":       TUIConfigSchema,
+	"Webhooks":  WebhooksConfigSchema,
 })
 var config *Config
 
@@ -0,0 +1,47 @@
+package conf
+
+import z "github.com/Oudwins/zog"
+
+const (
+	defaultCIFeedbackMaxAttemptsPerCommit = 1
+	defaultCIFeedbackMaxOutputChars       = 4000
+)
+
+// FeedbackConfig controls what the daemon forwards from pull requests back to
+// a session's agent.
+type FeedbackConfig struct {
+	CI      CIFeedbackConfig     `json:"ci" zog:"ci"`
+	Reviews ReviewFeedbackConfig `json:"reviews" zog:"reviews"`
+}
+
+type CIFeedbackConfig struct {
+	// Enabled prompts the agent with the failing checks when CI on the
+	// session's pull request starts failing.
+	Enabled bool `json:"enabled" zog:"enabled"`
+	// MaxAttemptsPerCommit caps automatic prompts for one pull request head
+	// commit, so re-runs of the same failing commit do not loop the agent.
+	MaxAttemptsPerCommit int `json:"maxAttemptsPerCommit" zog:"maxAttemptsPerCommit"`
+	// MaxOutputChars truncates the output of each failing check in the prompt.
+	MaxOutputChars int `json:"maxOutputChars" zog:"maxOutputChars"`
+}
+
+type ReviewFeedbackConfig struct {
+	// Enabled prompts the agent with new unresolved review comments on the
+	// session's pull request.
+	Enabled bool `json:"enabled" zog:"enabled"`
+}
+
+var CIFeedbackConfigSchema = z.Struct(z.Shape{
+	"Enabled":              z.Bool().Default(false),
+	"MaxAttemptsPerCommit": z.Int().GTE(1).Default(defaultCIFeedbackMaxAttemptsPerCommit),
+	"MaxOutputChars":       z.Int().GTE(200).Default(defaultCIFeedbackMaxOutputChars),
+})
+
+var ReviewFeedbackConfigSchema = z.Struct(z.Shape{
+	"Enabled": z.Bool().Default(false),
+})
+
+var FeedbackConfigSchema = z.Struct(z.Shape{
+	"CI":      CIFeedbackConfigSchema,
+	"Reviews": ReviewFeedbackConfigSchema,
+})
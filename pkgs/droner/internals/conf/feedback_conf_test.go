@@ -0,0 +1,34 @@
+package conf
+
+import "testing"
+
+func TestFeedbackConfigSchemaDefaults(t *testing.T) {
+	var parsed Config
+	if err := ConfigSchema.Parse(map[string]any{}, &parsed); err != nil {
+		t.Fatalf("parse defaults: %v", err)
+	}
+	ci := parsed.Feedback.CI
+	if ci.Enabled || parsed.Feedback.Reviews.Enabled {
+		t.Fatal("expected CI and review feedback to be opt-in")
+	}
+	if ci.MaxAttemptsPerCommit != defaultCIFeedbackMaxAttemptsPerCommit || ci.MaxOutputChars != defaultCIFeedbackMaxOutputChars {
+		t.Fatalf("unexpected defaults: %+v", ci)
+	}
+}
+
+func TestFeedbackConfigSchemaParsesCI(t *testing.T) {
+	var parsed Config
+	if err := ConfigSchema.Parse(map[string]any{"feedback": map[string]any{"ci": map[string]any{"enabled": true, "maxAttemptsPerCommit": 3}}}, &parsed); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !parsed.Feedback.CI.Enabled || parsed.Feedback.CI.MaxAttemptsPerCommit != 3 {
+		t.Fatalf("unexpected CI feedback config: %+v", parsed.Feedback.CI)
+	}
+}
+
+func TestFeedbackConfigSchemaRejectsZeroAttempts(t *testing.T) {
+	var parsed CIFeedbackConfig
+	if err := CIFeedbackConfigSchema.Parse(map[string]any{"maxAttemptsPerCommit": 0}, &parsed); err == nil {
+		t.Fatal("expected zero attempts to fail validation")
+	}
+}
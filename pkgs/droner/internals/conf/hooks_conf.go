@@ -0,0 +1,86 @@
+package conf
+
+import z "github.com/Oudwins/zog"
+
+const (
+	HookActionComplete = "complete"
+	HookActionDelete   = "delete"
+	HookActionPrompt   = "prompt"
+	HookActionShell    = "shell"
+	HookActionWebhook  = "webhook"
+	HookActionNotify   = "notify"
+
+	defaultHookActionTimeoutSeconds = 30
+)
+
+// HooksConfig holds the automations the hooks system runs on session and pull
+// request events.
+type HooksConfig struct {
+	// CompleteOnMerge requests completion of a session once its pull request
+	// is merged.
+	CompleteOnMerge bool       `json:"completeOnMerge" zog:"completeOnMerge"`
+	Rules           []HookRule `json:"rules" zog:"rules"`
+}
+
+// HookRule runs Actions for every event matching Event and Match.
+type HookRule struct {
+	// Name identifies the rule in logs and in the events recording its runs.
+	Name string `json:"name" zog:"name"`
+	// Event is an event type such as "session.pr.closed". "*" matches any
+	// part of the type, e.g. "session.pr.*" or "session.*.failed".
+	Event string      `json:"event" zog:"event"`
+	Match []HookMatch `json:"match" zog:"match"`
+	// For delays the rule by this many seconds and drops it if the session
+	// stream records another event in the meantime, e.g. idle for 30 minutes.
+	For     int          `json:"for" zog:"for"`
+	Actions []HookAction `json:"actions" zog:"actions"`
+}
+
+// HookMatch compares a field of the event payload, addressed by a dotted
+// path, with Value.
+type HookMatch struct {
+	Field string `json:"field" zog:"field"`
+	Value string `json:"value" zog:"value"`
+}
+
+type HookAction struct {
+	// Type is one of complete, delete, prompt, shell, webhook or notify.
+	// complete, delete and prompt need an event on a session stream.
+	Type string `json:"type" zog:"type"`
+	// Message is a text/template for prompt and notify actions.
+	Message string `json:"message" zog:"message"`
+	// Command is run with sh -c for shell actions.
+	Command string `json:"command" zog:"command"`
+	// URL receives a JSON POST for webhook actions.
+	URL string `json:"url" zog:"url"`
+	// Timeout in seconds for shell and webhook actions.
+	Timeout int `json:"timeout" zog:"timeout"`
+}
+
+var HookActionSchema = z.Struct(z.Shape{
+	"Type":    z.String().Trim().Required().OneOf([]string{HookActionComplete, HookActionDelete, HookActionPrompt, HookActionShell, HookActionWebhook, HookActionNotify}),
+	"Message": z.String(),
+	"Command": z.String().Trim(),
+	"URL":     z.String().Trim(),
+	"Timeout": z.Int().GTE(1).Default(defaultHookActionTimeoutSeconds),
+})
+
+var HookRuleSchema = z.Struct(z.Shape{
+	"Name":  z.String().Trim().Required(),
+	"Event": z.String().Trim().Required(),
+	"Match": z.Slice(z.Struct(z.Shape{
+		"Field": z.String().Trim().Required(),
+		"Value": z.String(),
+	})).DefaultFunc(func() any {
+		return []HookMatch{}
+	}),
+	"For":     z.Int().GTE(0).Default(0),
+	"Actions": z.Slice(HookActionSchema).Min(1),
+})
+
+var HooksConfigSchema = z.Struct(z.Shape{
+	"CompleteOnMerge": z.Bool().Default(true),
+	"Rules": z.Slice(HookRuleSchema).DefaultFunc(func() any {
+		return []HookRule{}
+	}),
+})
@@ -0,0 +1,53 @@
+package conf
+
+import "testing"
+
+func TestHooksConfigSchemaDefaults(t *testing.T) {
+	var parsed Config
+	if err := ConfigSchema.Parse(map[string]any{}, &parsed); err != nil {
+		t.Fatalf("parse defaults: %v", err)
+	}
+	if !parsed.Hooks.CompleteOnMerge {
+		t.Fatal("expected completion on merge to stay enabled by default")
+	}
+	if len(parsed.Hooks.Rules) != 0 {
+		t.Fatalf("expected no rules, got %+v", parsed.Hooks.Rules)
+	}
+}
+
+func TestHooksConfigSchemaParsesRules(t *testing.T) {
+	var parsed Config
+	err := ConfigSchema.Parse(map[string]any{"hooks": map[string]any{"rules": []any{
+		map[string]any{
+			"name":    "ci-failed",
+			"event":   "session.pr.ci_state_changed",
+			"match":   []any{map[string]any{"field": "ciState", "value": "failing"}},
+			"actions": []any{map[string]any{"type": "notify", "message": "CI failed on {{.Branch}}"}},
+		},
+	}}}, &parsed)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(parsed.Hooks.Rules) != 1 {
+		t.Fatalf("expected one rule, got %+v", parsed.Hooks.Rules)
+	}
+	rule := parsed.Hooks.Rules[0]
+	if rule.Match[0].Field != "ciState" || rule.Match[0].Value != "failing" {
+		t.Fatalf("unexpected match: %+v", rule.Match)
+	}
+	if rule.Actions[0].Type != HookActionNotify || rule.Actions[0].Timeout != defaultHookActionTimeoutSeconds {
+		t.Fatalf("unexpected action: %+v", rule.Actions[0])
+	}
+}
+
+func TestHooksConfigSchemaRejectsUnknownAction(t *testing.T) {
+	var parsed HookRule
+	err := HookRuleSchema.Parse(map[string]any{
+		"name":    "bad",
+		"event":   "session.ready",
+		"actions": []any{map[string]any{"type": "explode"}},
+	}, &parsed)
+	if err == nil {
+		t.Fatal("expected an unknown action type to fail validation")
+	}
+}
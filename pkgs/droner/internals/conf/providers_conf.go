@@ -0,0 +1,170 @@
+package conf
+
+import (
+	"strings"
+	"time"
+
+	z "github.com/Oudwins/zog"
+)
+
+type ProvidersConfig struct {
+	Github GitHubConfig `json:"github" zog:"github"`
+	GitLab GitLabConfig `json:"gitlab" zog:"gitlab"`
+	Gitea  GiteaConfig  `json:"gitea" zog:"gitea"`
+}
+
+type GitHubConfig struct {
+	// Seconds
+	PollInterval int                 `json:"pollInterval" zog:"pollInterval"`
+	Webhook      GitHubWebhookConfig `json:"webhook" zog:"webhook"`
+}
+
+// GitHubWebhookConfig applies once DRONER_GITHUB_WEBHOOK_SECRET is set and the
+// /webhooks/github endpoint accepts deliveries.
+type GitHubWebhookConfig struct {
+	// Seconds between reconciliation polls, replacing pollInterval while
+	// webhooks drive refreshes.
+	ReconcileInterval int `json:"reconcileInterval" zog:"reconcileInterval"`
+}
+
+type GitLabConfig struct {
+	// Seconds
+	PollInterval int `json:"pollInterval" zog:"pollInterval"`
+	// Hosts lists self-managed GitLab hostnames; gitlab.com is always supported.
+	Hosts []string `json:"hosts" zog:"hosts"`
+}
+
+// GiteaConfig covers Gitea and Forgejo instances, which share an API. Unlike
+// GitHub and GitLab there is no public default host, so only listed hosts are
+// watched.
+type GiteaConfig struct {
+	// Seconds
+	PollInterval int               `json:"pollInterval" zog:"pollInterval"`
+	Hosts        []GiteaHostConfig `json:"hosts" zog:"hosts"`
+}
+
+type GiteaHostConfig struct {
+	// Host is the hostname used in git remotes.
+	Host string `json:"host" zog:"host"`
+	// BaseURL is the web root of the instance and defaults to https://<host>.
+	BaseURL string `json:"baseUrl" zog:"baseUrl"`
+	// TokenEnv names an environment variable holding the token for this host.
+	// GITEA_TOKEN and FORGEJO_TOKEN are used only when it is not set.
+	TokenEnv string `json:"tokenEnv" zog:"tokenEnv"`
+}
+
+const (
+	defaultPollIntervalSeconds      = 10
+	defaultReconcileIntervalSeconds = 60
+	maxPollIntervalSeconds          = 24 * 60 * 60
+)
+
+// PollDuration converts a provider poll interval in seconds to a duration.
+func PollDuration(seconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = defaultPollIntervalSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// pollIntervalSchema validates a poll interval in seconds. Intervals above a
+// day are rejected because they almost always mean a unit mix-up.
+func pollIntervalSchema() *z.NumberSchema[int] {
+	return z.Int().DefaultFunc(func() int {
+		return defaultPollIntervalSeconds
+	})
+}
+
+var gitHubSchema = z.Struct(z.Shape{
+	"PollInterval": pollIntervalSchema().Transform(legacyPollIntervalTransform).
+		LTE(maxPollIntervalSeconds, z.Message("must be at most 86400 seconds")),
+	"Webhook": z.Struct(z.Shape{
+		"ReconcileInterval": z.Int().DefaultFunc(func() int {
+			return defaultReconcileIntervalSeconds
+		}),
+	}),
+})
+
+var gitLabSchema = z.Struct(z.Shape{
+	"PollInterval": pollIntervalSchema().
+		LTE(maxPollIntervalSeconds, z.Message("must be at most 86400 seconds")),
+	"Hosts": z.Slice(z.String()).DefaultFunc(func() any {
+		return []string{}
+	}).Transform(normalizeHostsTransform),
+})
+
+var giteaSchema = z.Struct(z.Shape{
+	"PollInterval": pollIntervalSchema().
+		LTE(maxPollIntervalSeconds, z.Message("must be at most 86400 seconds")),
+	"Hosts": z.Slice(z.Struct(z.Shape{
+		"Host":     z.String().Trim().Required(),
+		"BaseURL":  z.String().Trim(),
+		"TokenEnv": z.String().Trim(),
+	})).DefaultFunc(func() any {
+		return []GiteaHostConfig{}
+	}).Transform(normalizeGiteaHostsTransform),
+})
+
+var providersSchema = z.Struct(z.Shape{
+	"Github": gitHubSchema,
+	"GitLab": gitLabSchema,
+	"Gitea":  giteaSchema,
+})
+
+// legacyPollIntervalTransform converts providers.github.pollInterval values
+// written for older releases, which read the setting as nanoseconds, to
+// seconds. Anything from one second's worth of nanoseconds up is treated as
+// legacy.
+func legacyPollIntervalTransform(interval *int, c z.Ctx) error {
+	if *interval < int(time.Second) {
+		return nil
+	}
+	*interval /= int(time.Second)
+	return nil
+}
+
+func normalizeHostsTransform(data any, c z.Ctx) error {
+	hosts, ok := data.(*[]string)
+	if !ok {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(*hosts))
+	for _, host := range *hosts {
+		if host = normalizeHost(host); host != "" {
+			normalized = append(normalized, host)
+		}
+	}
+
+	*hosts = normalized
+	return nil
+}
+
+func normalizeGiteaHostsTransform(data any, c z.Ctx) error {
+	hosts, ok := data.(*[]GiteaHostConfig)
+	if !ok {
+		return nil
+	}
+
+	normalized := make([]GiteaHostConfig, 0, len(*hosts))
+	for _, host := range *hosts {
+		host.Host = normalizeHost(host.Host)
+		if host.Host == "" {
+			continue
+		}
+		host.BaseURL = strings.TrimRight(host.BaseURL, "/")
+		if host.BaseURL == "" {
+			host.BaseURL = "https://" + host.Host
+		}
+		normalized = append(normalized, host)
+	}
+
+	*hosts = normalized
+	return nil
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	return strings.TrimRight(host, "/")
+}
@@ -0,0 +1,128 @@
+package conf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProvidersConfigSchemaDefaults(t *testing.T) {
+	var parsed Config
+	if err := ConfigSchema.Parse(map[string]any{}, &parsed); err != nil {
+		t.Fatalf("parse defaults: %v", err)
+	}
+	if parsed.Providers.Github.PollInterval != defaultPollIntervalSeconds {
+		t.Fatalf("github poll interval = %d, want %d", parsed.Providers.Github.PollInterval, defaultPollIntervalSeconds)
+	}
+	if parsed.Providers.Github.Webhook.ReconcileInterval != defaultReconcileIntervalSeconds {
+		t.Fatalf("github reconcile interval = %d, want %d", parsed.Providers.Github.Webhook.ReconcileInterval, defaultReconcileIntervalSeconds)
+	}
+	if parsed.Providers.GitLab.PollInterval != defaultPollIntervalSeconds {
+		t.Fatalf("gitlab poll interval = %d, want %d", parsed.Providers.GitLab.PollInterval, defaultPollIntervalSeconds)
+	}
+	if len(parsed.Providers.GitLab.Hosts) != 0 {
+		t.Fatalf("gitlab hosts = %#v, want none", parsed.Providers.GitLab.Hosts)
+	}
+	if parsed.Providers.Gitea.PollInterval != defaultPollIntervalSeconds {
+		t.Fatalf("gitea poll interval = %d, want %d", parsed.Providers.Gitea.PollInterval, defaultPollIntervalSeconds)
+	}
+	if len(parsed.Providers.Gitea.Hosts) != 0 {
+		t.Fatalf("gitea hosts = %#v, want none", parsed.Providers.Gitea.Hosts)
+	}
+}
+
+func TestProvidersConfigSchemaParsesProviderBlocks(t *testing.T) {
+	var parsed Config
+	payload := map[string]any{
+		"providers": map[string]any{
+			"github": map[string]any{"pollInterval": 5, "webhook": map[string]any{"reconcileInterval": 300}},
+			"gitlab": map[string]any{
+				"hosts": []any{" GitLab.Example.com ", "https://git.internal/", ""},
+			},
+		},
+	}
+	if err := ConfigSchema.Parse(payload, &parsed); err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if parsed.Providers.Github.PollInterval != 5 {
+		t.Fatalf("github poll interval = %d, want 5", parsed.Providers.Github.PollInterval)
+	}
+	if parsed.Providers.Github.Webhook.ReconcileInterval != 300 {
+		t.Fatalf("github reconcile interval = %d, want 300", parsed.Providers.Github.Webhook.ReconcileInterval)
+	}
+	hosts := parsed.Providers.GitLab.Hosts
+	if len(hosts) != 2 || hosts[0] != "gitlab.example.com" || hosts[1] != "git.internal" {
+		t.Fatalf("gitlab hosts = %#v, want normalized hostnames", hosts)
+	}
+}
+
+func TestProvidersConfigSchemaParsesGiteaHosts(t *testing.T) {
+	var parsed Config
+	payload := map[string]any{
+		"providers": map[string]any{
+			"gitea": map[string]any{
+				"pollInterval": 30,
+				"hosts": []any{
+					map[string]any{"host": "Code.Example.com"},
+					map[string]any{"host": "git.internal", "baseUrl": "https://git.internal/forgejo/", "tokenEnv": "INTERNAL_FORGEJO_TOKEN"},
+				},
+			},
+		},
+	}
+	if err := ConfigSchema.Parse(payload, &parsed); err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	gitea := parsed.Providers.Gitea
+	if gitea.PollInterval != 30 {
+		t.Fatalf("gitea poll interval = %d, want 30", gitea.PollInterval)
+	}
+	want := []GiteaHostConfig{
+		{Host: "code.example.com", BaseURL: "https://code.example.com"},
+		{Host: "git.internal", BaseURL: "https://git.internal/forgejo", TokenEnv: "INTERNAL_FORGEJO_TOKEN"},
+	}
+	if len(gitea.Hosts) != len(want) {
+		t.Fatalf("gitea hosts = %#v, want %#v", gitea.Hosts, want)
+	}
+	for i := range want {
+		if gitea.Hosts[i] != want[i] {
+			t.Fatalf("gitea host %d = %#v, want %#v", i, gitea.Hosts[i], want[i])
+		}
+	}
+}
+
+func TestPollDuration(t *testing.T) {
+	if got := PollDuration(5); got != 5*time.Second {
+		t.Fatalf("PollDuration(5) = %s, want 5s", got)
+	}
+	if got := PollDuration(0); got != defaultPollIntervalSeconds*time.Second {
+		t.Fatalf("PollDuration(0) = %s, want default", got)
+	}
+}
+
+func TestProvidersConfigSchemaConvertsLegacyGitHubPollInterval(t *testing.T) {
+	var parsed Config
+	payload := map[string]any{
+		"providers": map[string]any{
+			"github": map[string]any{"pollInterval": int(10 * time.Second)},
+		},
+	}
+	if err := ConfigSchema.Parse(payload, &parsed); err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if parsed.Providers.Github.PollInterval != 10 {
+		t.Fatalf("github poll interval = %d, want 10", parsed.Providers.Github.PollInterval)
+	}
+}
+
+func TestProvidersConfigSchemaRejectsPollIntervalsAboveADay(t *testing.T) {
+	for _, provider := range []string{"github", "gitlab", "gitea"} {
+		var parsed Config
+		payload := map[string]any{
+			"providers": map[string]any{
+				provider: map[string]any{"pollInterval": maxPollIntervalSeconds + 1},
+			},
+		}
+		if err := ConfigSchema.Parse(payload, &parsed); err == nil {
+			t.Fatalf("expected %s poll interval above a day to fail validation", provider)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package conf
+
+import z "github.com/Oudwins/zog"
+
+const defaultDeletedSessionDays = 30
+
+type RetentionConfig struct {
+	// Days a session has to stay deleted before its events are compacted.
+	DeletedSessionDays int `json:"deletedSessionDays" zog:"deletedSessionDays"`
+	// Archive writes compacted streams as JSON lines under <dataDir>/archive
+	// before they are removed.
+	Archive bool `json:"archive" zog:"archive"`
+	// Auto runs compaction when the daemon starts and once a day after that.
+	Auto bool `json:"auto" zog:"auto"`
+}
+
+var RetentionConfigSchema = z.Struct(z.Shape{
+	"DeletedSessionDays": z.Int().GTE(0).Default(defaultDeletedSessionDays),
+	"Archive":            z.Bool().Default(false),
+	"Auto":               z.Bool().Default(false),
+})
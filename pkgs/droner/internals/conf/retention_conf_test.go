@@ -0,0 +1,23 @@
+package conf
+
+import "testing"
+
+func TestRetentionConfigSchemaDefaults(t *testing.T) {
+	var parsed Config
+	if err := ConfigSchema.Parse(map[string]any{}, &parsed); err != nil {
+		t.Fatalf("parse defaults: %v", err)
+	}
+	if parsed.Retention.DeletedSessionDays != defaultDeletedSessionDays {
+		t.Fatalf("deleted session days = %d, want %d", parsed.Retention.DeletedSessionDays, defaultDeletedSessionDays)
+	}
+	if parsed.Retention.Archive || parsed.Retention.Auto {
+		t.Fatalf("expected archive and auto to default to false, got %+v", parsed.Retention)
+	}
+}
+
+func TestRetentionConfigSchemaRejectsNegativeDays(t *testing.T) {
+	var parsed RetentionConfig
+	if err := RetentionConfigSchema.Parse(map[string]any{"deletedSessionDays": -1}, &parsed); err == nil {
+		t.Fatal("expected negative retention days to fail validation")
+	}
+}
@@ -0,0 +1,30 @@
+package conf
+
+import z "github.com/Oudwins/zog"
+
+const (
+	StorageDriverSQLite   StorageDriver = "sqlite"
+	StorageDriverPostgres StorageDriver = "postgres"
+)
+
+type StorageDriver string
+
+func (d StorageDriver) String() string {
+	return string(d)
+}
+
+type PostgresStorageConfig struct {
+	DSN string `json:"dsn" zog:"dsn"`
+}
+
+type StorageConfig struct {
+	Driver   StorageDriver         `json:"driver" zog:"driver"`
+	Postgres PostgresStorageConfig `json:"postgres" zog:"postgres"`
+}
+
+var StorageConfigSchema = z.Struct(z.Shape{
+	"Driver": z.StringLike[StorageDriver]().OneOf([]StorageDriver{StorageDriverSQLite, StorageDriverPostgres}).Default(StorageDriverSQLite),
+	"Postgres": z.Struct(z.Shape{
+		"DSN": z.String().Trim(),
+	}),
+})
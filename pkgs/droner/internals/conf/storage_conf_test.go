@@ -0,0 +1,39 @@
+package conf
+
+import "testing"
+
+func TestStorageConfigSchemaDefaultsToSQLite(t *testing.T) {
+	var parsed Config
+	if err := ConfigSchema.Parse(map[string]any{}, &parsed); err != nil {
+		t.Fatalf("parse defaults: %v", err)
+	}
+	if parsed.Storage.Driver != StorageDriverSQLite {
+		t.Fatalf("storage driver = %q, want %q", parsed.Storage.Driver, StorageDriverSQLite)
+	}
+}
+
+func TestStorageConfigSchemaParsesPostgres(t *testing.T) {
+	var parsed Config
+	err := ConfigSchema.Parse(map[string]any{
+		"storage": map[string]any{
+			"driver":   "postgres",
+			"postgres": map[string]any{"dsn": "  postgres://droner@localhost/droner  "},
+		},
+	}, &parsed)
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if parsed.Storage.Driver != StorageDriverPostgres {
+		t.Fatalf("storage driver = %q, want %q", parsed.Storage.Driver, StorageDriverPostgres)
+	}
+	if parsed.Storage.Postgres.DSN != "postgres://droner@localhost/droner" {
+		t.Fatalf("postgres dsn = %q", parsed.Storage.Postgres.DSN)
+	}
+}
+
+func TestStorageConfigSchemaRejectsUnknownDriver(t *testing.T) {
+	var parsed StorageConfig
+	if err := StorageConfigSchema.Parse(map[string]any{"driver": "mysql"}, &parsed); err == nil {
+		t.Fatal("expected unknown storage driver to fail validation")
+	}
+}
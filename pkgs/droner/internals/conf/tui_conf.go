@@ -1,21 +1,148 @@
 package conf
 
 import (
+	"regexp"
+	"slices"
 	"strings"
 
 	z "github.com/Oudwins/zog"
 )
 
+const (
+	TUIThemeAuto           = "auto"
+	TUIThemeDark           = "dark"
+	TUIThemeLight          = "light"
+	TUIThemeSolarizedDark  = "solarized-dark"
+	TUIThemeSolarizedLight = "solarized-light"
+)
+
+// TUIPaletteNames are the built-in palettes a theme can name.
+var TUIPaletteNames = []string{TUIThemeDark, TUIThemeLight, TUIThemeSolarizedDark, TUIThemeSolarizedLight}
+
 var defaultTUIAgentNames = []string{"build", "plan"}
 
 type TUIConfig struct {
-	AgentNames []string `json:"agentNames" zog:"agentNames"`
+	AgentNames []string  `json:"agentNames" zog:"agentNames"`
+	Keymap     TUIKeymap `json:"keymap" zog:"keymap"`
+	Theme      TUITheme  `json:"theme" zog:"theme"`
 }
 
+// TUIKeymap lists the keys bound to each composer action, written the way
+// bubbletea names them, e.g. "enter", "ctrl+j" or "alt+enter". The
+// autocomplete keys only apply while suggestions or a picker are open.
+type TUIKeymap struct {
+	Submit             []string `json:"submit" zog:"submit"`
+	Newline            []string `json:"newline" zog:"newline"`
+	CycleAgent         []string `json:"cycleAgent" zog:"cycleAgent"`
+	AutocompleteUp     []string `json:"autocompleteUp" zog:"autocompleteUp"`
+	AutocompleteDown   []string `json:"autocompleteDown" zog:"autocompleteDown"`
+	AutocompleteAccept []string `json:"autocompleteAccept" zog:"autocompleteAccept"`
+	PasteImage         []string `json:"pasteImage" zog:"pasteImage"`
+}
+
+// TUITheme picks a palette by name. "auto" uses Dark or Light depending on
+// the terminal background. Colors overrides single colors of the palette.
+type TUITheme struct {
+	Name   string    `json:"name" zog:"name"`
+	Dark   string    `json:"dark" zog:"dark"`
+	Light  string    `json:"light" zog:"light"`
+	Colors TUIColors `json:"colors" zog:"colors"`
+}
+
+// TUIColors holds the colors of a palette as "#RRGGBB", "#RGB" or an ANSI
+// 256 color number. Empty colors are left to the palette.
+type TUIColors struct {
+	Background        string `json:"background" zog:"background"`
+	Section           string `json:"section" zog:"section"`
+	Input             string `json:"input" zog:"input"`
+	Accent            string `json:"accent" zog:"accent"`
+	AccentStrong      string `json:"accentStrong" zog:"accentStrong"`
+	Text              string `json:"text" zog:"text"`
+	MutedText         string `json:"mutedText" zog:"mutedText"`
+	Logo              string `json:"logo" zog:"logo"`
+	WarningBackground string `json:"warningBackground" zog:"warningBackground"`
+	Error             string `json:"error" zog:"error"`
+	Tip               string `json:"tip" zog:"tip"`
+	DiffAdded         string `json:"diffAdded" zog:"diffAdded"`
+	DiffRemoved       string `json:"diffRemoved" zog:"diffRemoved"`
+	SyntaxKeyword     string `json:"syntaxKeyword" zog:"syntaxKeyword"`
+	SyntaxString      string `json:"syntaxString" zog:"syntaxString"`
+	SyntaxNumber      string `json:"syntaxNumber" zog:"syntaxNumber"`
+}
+
+var tuiKeyPattern = regexp.MustCompile(`^((ctrl|alt|shift)\+)*([a-z0-9]+|\S)$`)
+var tuiColorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{6}|#[0-9A-Fa-f]{3}|[0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])$`)
+
+func tuiKeysSchema(defaults ...string) *z.SliceSchema {
+	return z.Slice(z.String().Trim().Required().Match(tuiKeyPattern, z.Message("must be a key such as enter, ctrl+j or alt+enter"))).
+		DefaultFunc(func() any {
+			return append([]string(nil), defaults...)
+		}).
+		Min(1)
+}
+
+func tuiColorSchema() *z.StringSchema[string] {
+	return z.String().Trim().Optional().Match(tuiColorPattern, z.Message("must be #RRGGBB, #RGB or an ANSI color number"))
+}
+
+var TUIKeymapSchema = z.Struct(z.Shape{
+	"Submit":             tuiKeysSchema("enter"),
+	"Newline":            tuiKeysSchema("alt+enter", "ctrl+j"),
+	"CycleAgent":         tuiKeysSchema("tab"),
+	"AutocompleteUp":     tuiKeysSchema("up", "ctrl+p"),
+	"AutocompleteDown":   tuiKeysSchema("down", "ctrl+n"),
+	"AutocompleteAccept": tuiKeysSchema("tab", "enter"),
+	"PasteImage":         tuiKeysSchema("ctrl+v"),
+}).TestFunc(func(valPtr any, ctx z.Ctx) bool {
+	return !valPtr.(*TUIKeymap).hasConflicts()
+}, z.Message("submit, newline, cycleAgent and pasteImage must not share keys"))
+
+var TUIThemeSchema = z.Struct(z.Shape{
+	"Name":  z.String().Trim().OneOf(append([]string{TUIThemeAuto}, TUIPaletteNames...)).Default(TUIThemeAuto),
+	"Dark":  z.String().Trim().OneOf(TUIPaletteNames).Default(TUIThemeDark),
+	"Light": z.String().Trim().OneOf(TUIPaletteNames).Default(TUIThemeLight),
+	"Colors": z.Struct(z.Shape{
+		"Background":        tuiColorSchema(),
+		"Section":           tuiColorSchema(),
+		"Input":             tuiColorSchema(),
+		"Accent":            tuiColorSchema(),
+		"AccentStrong":      tuiColorSchema(),
+		"Text":              tuiColorSchema(),
+		"MutedText":         tuiColorSchema(),
+		"Logo":              tuiColorSchema(),
+		"WarningBackground": tuiColorSchema(),
+		"Error":             tuiColorSchema(),
+		"Tip":               tuiColorSchema(),
+		"DiffAdded":         tuiColorSchema(),
+		"DiffRemoved":       tuiColorSchema(),
+		"SyntaxKeyword":     tuiColorSchema(),
+		"SyntaxString":      tuiColorSchema(),
+		"SyntaxNumber":      tuiColorSchema(),
+	}),
+})
+
 var TUIConfigSchema = z.Struct(z.Shape{
 	"AgentNames": z.Slice(z.String()).Default(defaultTUIAgentNames).Transform(normalizeAgentNamesTransform),
+	"Keymap":     TUIKeymapSchema,
+	"Theme":      TUIThemeSchema,
 })
 
+// hasConflicts reports whether a key triggers more than one of the actions
+// that are always active. Autocomplete keys only apply while suggestions are
+// shown, so they may reuse these.
+func (k TUIKeymap) hasConflicts() bool {
+	seen := map[string]struct{}{}
+	for _, keys := range [][]string{k.Submit, k.Newline, k.CycleAgent, k.PasteImage} {
+		for _, key := range slices.Compact(slices.Sorted(slices.Values(keys))) {
+			if _, ok := seen[key]; ok {
+				return true
+			}
+			seen[key] = struct{}{}
+		}
+	}
+	return false
+}
+
 func normalizeAgentNamesTransform(data any, c z.Ctx) error {
 	agentNames, ok := data.(*[]string)
 	if !ok {
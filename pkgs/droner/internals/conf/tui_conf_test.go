@@ -49,3 +49,78 @@ func assertAgentNames(t *testing.T, got []string, want []string) {
 		}
 	}
 }
+
+func TestTUIConfigSchemaDefaultsKeymapAndTheme(t *testing.T) {
+	var parsed TUIConfig
+	if err := TUIConfigSchema.Parse(map[string]any{}, &parsed); err != nil {
+		t.Fatalf("parse defaults: %v", err)
+	}
+
+	assertStrings(t, parsed.Keymap.Submit, []string{"enter"})
+	assertStrings(t, parsed.Keymap.Newline, []string{"alt+enter", "ctrl+j"})
+	assertStrings(t, parsed.Keymap.AutocompleteAccept, []string{"tab", "enter"})
+	if parsed.Theme.Name != TUIThemeAuto || parsed.Theme.Dark != TUIThemeDark || parsed.Theme.Light != TUIThemeLight {
+		t.Fatalf("theme = %#v, want auto between dark and light", parsed.Theme)
+	}
+}
+
+func TestTUIConfigSchemaParsesKeymapAndThemeOverrides(t *testing.T) {
+	var parsed TUIConfig
+	err := TUIConfigSchema.Parse(map[string]any{
+		"keymap": map[string]any{
+			"submit":           []any{"ctrl+s"},
+			"newline":          []any{"enter"},
+			"autocompleteUp":   []any{"ctrl+k"},
+			"autocompleteDown": []any{"ctrl+j"},
+		},
+		"theme": map[string]any{
+			"name":   "solarized-light",
+			"colors": map[string]any{"accent": "#268BD2", "text": " 235 "},
+		},
+	}, &parsed)
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+
+	assertStrings(t, parsed.Keymap.Submit, []string{"ctrl+s"})
+	assertStrings(t, parsed.Keymap.AutocompleteDown, []string{"ctrl+j"})
+	assertStrings(t, parsed.Keymap.CycleAgent, []string{"tab"})
+	if parsed.Theme.Name != TUIThemeSolarizedLight || parsed.Theme.Colors.Accent != "#268BD2" || parsed.Theme.Colors.Text != "235" {
+		t.Fatalf("theme = %#v", parsed.Theme)
+	}
+}
+
+func TestTUIConfigSchemaRejectsInvalidKeymapAndTheme(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]any
+	}{
+		{name: "conflicting keys", data: map[string]any{"keymap": map[string]any{"submit": []any{"enter"}, "newline": []any{"enter"}}}},
+		{name: "empty binding", data: map[string]any{"keymap": map[string]any{"submit": []any{}}}},
+		{name: "malformed key", data: map[string]any{"keymap": map[string]any{"submit": []any{"ctrl + s"}}}},
+		{name: "unknown palette", data: map[string]any{"theme": map[string]any{"name": "neon"}}},
+		{name: "unknown auto palette", data: map[string]any{"theme": map[string]any{"light": "auto"}}},
+		{name: "malformed color", data: map[string]any{"theme": map[string]any{"colors": map[string]any{"accent": "blue"}}}},
+		{name: "ansi color out of range", data: map[string]any{"theme": map[string]any{"colors": map[string]any{"accent": "256"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed TUIConfig
+			if err := TUIConfigSchema.Parse(tt.data, &parsed); err == nil {
+				t.Fatalf("expected validation error, got %#v", parsed)
+			}
+		})
+	}
+}
+
+func assertStrings(t *testing.T, got []string, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package conf
+
+import (
+	"os"
+	"strings"
+
+	z "github.com/Oudwins/zog"
+)
+
+const (
+	defaultWebhookMaxAttempts    = 5
+	defaultWebhookTimeoutSeconds = 10
+)
+
+// WebhooksConfig lists receivers notified of session and pull request events.
+type WebhooksConfig struct {
+	Endpoints []WebhookEndpointConfig `json:"endpoints" zog:"endpoints"`
+}
+
+type WebhookEndpointConfig struct {
+	// Name identifies the endpoint in logs and in the delivery records.
+	Name string `json:"name" zog:"name"`
+	URL  string `json:"url" zog:"url"`
+	// Events are event type patterns such as "session.ready" or
+	// "session.*.failed". They default to the session lifecycle milestones.
+	Events []string `json:"events" zog:"events"`
+	// Secret signs each body with HMAC-SHA256 in X-Droner-Signature-256.
+	Secret string `json:"secret" zog:"secret"`
+	// SecretEnv names an environment variable holding the secret and takes
+	// precedence over Secret.
+	SecretEnv string `json:"secretEnv" zog:"secretEnv"`
+	// MaxAttempts caps deliveries of one event, retried with exponential backoff.
+	MaxAttempts int `json:"maxAttempts" zog:"maxAttempts"`
+	// Timeout in seconds for each attempt.
+	Timeout int `json:"timeout" zog:"timeout"`
+}
+
+// SigningSecret resolves the secret used to sign deliveries, or "" when
+// deliveries go out unsigned.
+func (c WebhookEndpointConfig) SigningSecret() string {
+	if name := strings.TrimSpace(c.SecretEnv); name != "" {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+	return c.Secret
+}
+
+func defaultWebhookEvents() []string {
+	return []string{"session.ready", "session.agent.idle", "session.*.failed", "session.pr.merged"}
+}
+
+var WebhookEndpointConfigSchema = z.Struct(z.Shape{
+	"Name":        z.String().Trim().Required(),
+	"URL":         z.String().Trim().Required().URL(),
+	"Events":      z.Slice(z.String().Trim()).DefaultFunc(func() any { return defaultWebhookEvents() }),
+	"Secret":      z.String(),
+	"SecretEnv":   z.String().Trim(),
+	"MaxAttempts": z.Int().GTE(1).Default(defaultWebhookMaxAttempts),
+	"Timeout":     z.Int().GTE(1).Default(defaultWebhookTimeoutSeconds),
+})
+
+var WebhooksConfigSchema = z.Struct(z.Shape{
+	"Endpoints": z.Slice(WebhookEndpointConfigSchema).DefaultFunc(func() any {
+		return []WebhookEndpointConfig{}
+	}),
+})
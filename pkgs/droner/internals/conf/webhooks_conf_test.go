@@ -0,0 +1,39 @@
+package conf
+
+import "testing"
+
+func TestWebhooksConfigSchemaAppliesEndpointDefaults(t *testing.T) {
+	var parsed Config
+	err := ConfigSchema.Parse(map[string]any{"webhooks": map[string]any{"endpoints": []any{
+		map[string]any{"name": "bot", "url": "https://example.com/droner"},
+	}}}, &parsed)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	endpoint := parsed.Webhooks.Endpoints[0]
+	if endpoint.MaxAttempts != defaultWebhookMaxAttempts || endpoint.Timeout != defaultWebhookTimeoutSeconds {
+		t.Fatalf("unexpected defaults: %+v", endpoint)
+	}
+	if len(endpoint.Events) != len(defaultWebhookEvents()) {
+		t.Fatalf("expected default event filters, got %v", endpoint.Events)
+	}
+}
+
+func TestWebhooksConfigSchemaRejectsInvalidURL(t *testing.T) {
+	var parsed WebhookEndpointConfig
+	if err := WebhookEndpointConfigSchema.Parse(map[string]any{"name": "bot", "url": "not a url"}, &parsed); err == nil {
+		t.Fatal("expected an invalid url to fail validation")
+	}
+}
+
+func TestWebhookSigningSecretPrefersEnv(t *testing.T) {
+	t.Setenv("DRONER_TEST_WEBHOOK_SECRET", "from-env")
+	endpoint := WebhookEndpointConfig{Secret: "inline", SecretEnv: "DRONER_TEST_WEBHOOK_SECRET"}
+	if got := endpoint.SigningSecret(); got != "from-env" {
+		t.Fatalf("SigningSecret() = %q, want from-env", got)
+	}
+	endpoint.SecretEnv = "DRONER_TEST_WEBHOOK_SECRET_UNSET"
+	if got := endpoint.SigningSecret(); got != "inline" {
+		t.Fatalf("SigningSecret() = %q, want inline", got)
+	}
+}
@@ -41,28 +41,31 @@ func (l LogLevel) SlogLevel() slog.Level {
 }
 
 type EnvStruct struct {
-	HOME         string    `zog:"HOME"`
-	XDG_HOME     string    `zog:"XDG_HOME"`
-	PORT         int       `zog:"DRONER_ENV_PORT"`
-	GITHUB_TOKEN string    `zog:"GITHUB_TOKEN"`
-	LOG_LEVEL    LogLevel  `zog:"DRONERD_LOG_LEVEL"`
-	LOG_OUTPUT   LogOutput `zog:"DRONERD_LOG_OUTPUT"`
-	DATA_DIR     string    `zog:"DRONERD_DATA_DIR"`
-	LISTEN_ADDR  string
-	LISTEN_PROT  string
-	BASE_URL     string
+	HOME         string `zog:"HOME"`
+	XDG_HOME     string `zog:"XDG_HOME"`
+	PORT         int    `zog:"DRONER_ENV_PORT"`
+	GITHUB_TOKEN string `zog:"GITHUB_TOKEN"`
+	// GITHUB_WEBHOOK_SECRET enables the GitHub webhook receiver when set.
+	GITHUB_WEBHOOK_SECRET string    `zog:"DRONER_GITHUB_WEBHOOK_SECRET"`
+	LOG_LEVEL             LogLevel  `zog:"DRONERD_LOG_LEVEL"`
+	LOG_OUTPUT            LogOutput `zog:"DRONERD_LOG_OUTPUT"`
+	DATA_DIR              string    `zog:"DRONERD_DATA_DIR"`
+	LISTEN_ADDR           string
+	LISTEN_PROT           string
+	BASE_URL              string
 }
 
 var env *EnvStruct
 
 var EnvSchema = z.Struct(z.Shape{
-	"HOME":         z.String(),
-	"XDG_HOME":     z.String(),
-	"PORT":         z.Int().Default(57876),
-	"GITHUB_TOKEN": z.String().Optional(),
-	"DATA_DIR":     z.String().Default("~/.droner").Transform(expandPathTransform),
-	"LOG_LEVEL":    z.StringLike[LogLevel]().OneOf([]LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}).Default(LogLevelDebug),
-	"LOG_OUTPUT":   z.StringLike[LogOutput]().OneOf([]LogOutput{LogOutputStd, LogOutputFile, LogOutputBoth}).Default(LogOutputFile),
+	"HOME":                  z.String(),
+	"XDG_HOME":              z.String(),
+	"PORT":                  z.Int().Default(57876),
+	"GITHUB_TOKEN":          z.String().Optional(),
+	"GITHUB_WEBHOOK_SECRET": z.String().Optional(),
+	"DATA_DIR":              z.String().Default("~/.droner").Transform(expandPathTransform),
+	"LOG_LEVEL":             z.StringLike[LogLevel]().OneOf([]LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}).Default(LogLevelDebug),
+	"LOG_OUTPUT":            z.StringLike[LogOutput]().OneOf([]LogOutput{LogOutputStd, LogOutputFile, LogOutputBoth}).Default(LogOutputFile),
 })
 
 func Get() *EnvStruct {
@@ -9,4 +9,5 @@ var (
 	ErrEventTypeRequired    = errors.New("eventlog event type is required")
 	ErrSubscriptionIDNeeded = errors.New("eventlog subscription id is required")
 	ErrHandlerRequired      = errors.New("eventlog subscription handler is required")
+	ErrEventNotFound        = errors.New("eventlog event not found")
 )
@@ -5,7 +5,11 @@ import (
 	"strings"
 )
 
-type backend interface {
+// Backend stores envelopes for any number of topics. Sequences are assigned per
+// topic and never drop below a stored checkpoint; stream versions are assigned
+// per topic and stream. ReadGlobal blocks until at least one event after
+// afterSequence exists or ctx is done.
+type Backend interface {
 	Append(ctx context.Context, topic Topic, evt PendingEvent) (Envelope, error)
 	LoadStream(ctx context.Context, topic Topic, streamID StreamID, opts LoadStreamOptions) ([]Envelope, error)
 	ReadGlobal(ctx context.Context, topic Topic, afterSequence int64, limit int) ([]Envelope, error)
@@ -14,12 +18,115 @@ type backend interface {
 	Close() error
 }
 
+// StreamResetter is implemented by backends that can rewind a stream. The
+// events at and after eventID are removed and a copy of eventID is appended
+// with a fresh ID, sequence and stream version.
+type StreamResetter interface {
+	ResetStreamToEvent(ctx context.Context, topic Topic, streamID StreamID, eventID EventID) (Envelope, error)
+}
+
+// Compactor is implemented by backends that can drop whole streams.
+// DeleteStream only removes events at or below through, so callers pass the
+// horizon from CompactionHorizon to keep unprocessed events in place.
+// DeleteCheckpoint forgets a subscriber that no longer runs, so it stops
+// holding the horizon back.
+type Compactor interface {
+	ListCheckpoints(ctx context.Context, topic Topic) ([]Checkpoint, error)
+	DeleteCheckpoint(ctx context.Context, topic Topic, subscriber SubscriberID) error
+	LatestSequence(ctx context.Context, topic Topic) (int64, error)
+	StreamInfo(ctx context.Context, topic Topic, streamID StreamID) (StreamInfo, error)
+	DeleteStream(ctx context.Context, topic Topic, streamID StreamID, through int64) (int64, error)
+}
+
+// CompactionHorizon returns the highest sequence that can be removed from a
+// topic. Every subscriber must have checkpointed past it, and without
+// checkpoints the topic head is kept so its sequence is never handed out again.
+func CompactionHorizon(checkpoints []Checkpoint, latest int64) int64 {
+	if len(checkpoints) == 0 {
+		if latest <= 0 {
+			return 0
+		}
+		return latest - 1
+	}
+	horizon := checkpoints[0].Sequence
+	for _, checkpoint := range checkpoints[1:] {
+		if checkpoint.Sequence < horizon {
+			horizon = checkpoint.Sequence
+		}
+	}
+	if horizon > latest {
+		return latest
+	}
+	return horizon
+}
+
+// LoadFullStream loads every event of a stream. LoadStream returns a single
+// page, so callers that scan a whole stream page through it by version.
+func LoadFullStream(ctx context.Context, log EventLog, streamID StreamID) ([]Envelope, error) {
+	all := []Envelope{}
+	afterVersion := int64(0)
+	for {
+		events, err := log.LoadStream(ctx, streamID, LoadStreamOptions{AfterVersion: afterVersion})
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			return all, nil
+		}
+		all = append(all, events...)
+		afterVersion = events[len(events)-1].StreamVersion
+	}
+}
+
+// StartAtHead gives subscriber a checkpoint at the head of log's topic when it
+// has none yet, so a consumer that is switched on later skips the history.
+// Backends that cannot list checkpoints are left alone.
+func StartAtHead(ctx context.Context, el EventLog, subscriber SubscriberID) error {
+	l, compactor, ok := compactorOf(el)
+	if !ok {
+		return nil
+	}
+	checkpoints, err := compactor.ListCheckpoints(ctx, l.topic)
+	if err != nil {
+		return err
+	}
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Subscriber == subscriber {
+			return nil
+		}
+	}
+	latest, err := compactor.LatestSequence(ctx, l.topic)
+	if err != nil {
+		return err
+	}
+	return l.backend.StoreCheckpoint(ctx, l.topic, subscriber, latest)
+}
+
+// ForgetSubscriber deletes subscriber's checkpoint on log's topic, so a
+// consumer that is switched off stops holding back the compaction horizon.
+func ForgetSubscriber(ctx context.Context, el EventLog, subscriber SubscriberID) error {
+	l, compactor, ok := compactorOf(el)
+	if !ok {
+		return nil
+	}
+	return compactor.DeleteCheckpoint(ctx, l.topic, subscriber)
+}
+
+func compactorOf(el EventLog) (*log, Compactor, bool) {
+	l, ok := el.(*log)
+	if !ok {
+		return nil, nil, false
+	}
+	compactor, ok := l.backend.(Compactor)
+	return l, compactor, ok
+}
+
 type log struct {
 	topic   Topic
-	backend backend
+	backend Backend
 }
 
-func New(cfg Config, b backend) (EventLog, error) {
+func New(cfg Config, b Backend) (EventLog, error) {
 	if strings.TrimSpace(string(cfg.Topic)) == "" {
 		return nil, ErrTopicRequired
 	}
@@ -49,6 +49,28 @@ func TestAppendAndLoadStream(t *testing.T) {
 	}
 }
 
+func TestLoadFullStreamPagesPastDefaultLimit(t *testing.T) {
+	log := newTestLog(t, "sessions")
+	ctx := context.Background()
+
+	for i := 0; i < 650; i++ {
+		if _, err := log.Append(ctx, eventlog.PendingEvent{StreamID: "session/a", Type: "session.note", Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	events, err := eventlog.LoadFullStream(ctx, log, "session/a")
+	if err != nil {
+		t.Fatalf("LoadFullStream: %v", err)
+	}
+	if len(events) != 650 {
+		t.Fatalf("expected 650 events, got %d", len(events))
+	}
+	if events[0].StreamVersion != 1 || events[649].StreamVersion != 650 {
+		t.Fatalf("unexpected stream versions: %d..%d", events[0].StreamVersion, events[649].StreamVersion)
+	}
+}
+
 func TestTopicSequencesAreIndependent(t *testing.T) {
 	backend := newBackend(t)
 	sessions := newLogWithBackend(t, backend, "sessions")
@@ -169,6 +191,36 @@ func TestSubscribersAdvanceIndependently(t *testing.T) {
 	}
 }
 
+func TestStartAtHeadOnlyInitializesMissingCheckpoints(t *testing.T) {
+	backend := newBackend(t)
+	log := newLogWithBackend(t, backend, "sessions")
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := log.Append(ctx, eventlog.PendingEvent{StreamID: "session/a", Type: "session.note", Payload: []byte(`{}`)}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if err := backend.StoreCheckpoint(ctx, "sessions", "existing", 1); err != nil {
+		t.Fatalf("StoreCheckpoint: %v", err)
+	}
+
+	for _, subscriber := range []eventlog.SubscriberID{"existing", "new"} {
+		if err := eventlog.StartAtHead(ctx, log, subscriber); err != nil {
+			t.Fatalf("StartAtHead %s: %v", subscriber, err)
+		}
+	}
+
+	for subscriber, want := range map[eventlog.SubscriberID]int64{"existing": 1, "new": 3} {
+		got, err := backend.LoadCheckpoint(ctx, "sessions", subscriber)
+		if err != nil {
+			t.Fatalf("LoadCheckpoint %s: %v", subscriber, err)
+		}
+		if got != want {
+			t.Fatalf("%s checkpoint = %d, want %d", subscriber, got, want)
+		}
+	}
+}
+
 func newTestLog(t *testing.T, topic eventlog.Topic) eventlog.EventLog {
 	t.Helper()
 	return newLogWithBackend(t, newBackend(t), topic)
@@ -199,3 +251,24 @@ func newBackend(t *testing.T) *sqliteeventlog.Backend {
 	})
 	return backend
 }
+
+func TestCompactionHorizon(t *testing.T) {
+	tests := []struct {
+		name        string
+		checkpoints []eventlog.Checkpoint
+		latest      int64
+		want        int64
+	}{
+		{name: "empty topic", want: 0},
+		{name: "no subscribers keeps head", latest: 5, want: 4},
+		{name: "slowest subscriber wins", checkpoints: []eventlog.Checkpoint{{Subscriber: "a", Sequence: 7}, {Subscriber: "b", Sequence: 3}}, latest: 9, want: 3},
+		{name: "clamped to head", checkpoints: []eventlog.Checkpoint{{Subscriber: "a", Sequence: 12}}, latest: 9, want: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventlog.CompactionHorizon(tt.checkpoints, tt.latest); got != tt.want {
+				t.Fatalf("expected horizon %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
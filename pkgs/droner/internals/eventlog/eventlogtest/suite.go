@@ -0,0 +1,453 @@
+package eventlogtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/eventlog"
+)
+
+// Backend is what the conformance suite exercises. Every backend shipped with
+// droner implements all three interfaces.
+type Backend interface {
+	eventlog.Backend
+	eventlog.StreamResetter
+	eventlog.Compactor
+}
+
+// RunBackendSuite runs the conformance tests every eventlog backend must pass.
+// newBackend is called once per subtest and must return an empty backend.
+func RunBackendSuite(t *testing.T, newBackend func(t *testing.T) Backend) {
+	t.Helper()
+
+	t.Run("AppendAssignsSequenceAndStreamVersion", func(t *testing.T) {
+		backend := newBackend(t)
+		first := appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		second := appendEvent(t, backend, "sessions", "session/b", "session.queued")
+		third := appendEvent(t, backend, "sessions", "session/a", "session.ready")
+
+		if first.Sequence != 1 || second.Sequence != 2 || third.Sequence != 3 {
+			t.Fatalf("unexpected sequences: %d %d %d", first.Sequence, second.Sequence, third.Sequence)
+		}
+		if first.StreamVersion != 1 || second.StreamVersion != 1 || third.StreamVersion != 2 {
+			t.Fatalf("unexpected stream versions: %d %d %d", first.StreamVersion, second.StreamVersion, third.StreamVersion)
+		}
+		if first.ID == "" || first.ID == second.ID {
+			t.Fatalf("expected unique event ids, got %q %q", first.ID, second.ID)
+		}
+		if first.Topic != "sessions" || first.OccurredAt.IsZero() {
+			t.Fatalf("unexpected envelope: %+v", first)
+		}
+	})
+
+	t.Run("AppendPreservesEventFields", func(t *testing.T) {
+		backend := newBackend(t)
+		payload := []byte(`{"step":1}`)
+		appended, err := backend.Append(context.Background(), "sessions", eventlog.PendingEvent{
+			StreamID:      "session/a",
+			Type:          "session.queued",
+			SchemaVersion: 2,
+			Payload:       payload,
+			CausationID:   "cause",
+			CorrelationID: "correlation",
+		})
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		payload[0] = 'x'
+
+		events := loadStream(t, backend, "sessions", "session/a", eventlog.LoadStreamOptions{})
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+		got := events[0]
+		if got.ID != appended.ID || got.Type != "session.queued" || got.SchemaVersion != 2 {
+			t.Fatalf("unexpected event: %+v", got)
+		}
+		if got.CausationID != "cause" || got.CorrelationID != "correlation" {
+			t.Fatalf("unexpected causation/correlation: %q %q", got.CausationID, got.CorrelationID)
+		}
+		if string(got.Payload) != `{"step":1}` {
+			t.Fatalf("expected payload to be copied on append, got %s", got.Payload)
+		}
+		if !got.OccurredAt.Equal(appended.OccurredAt) {
+			t.Fatalf("expected occurred at %s, got %s", appended.OccurredAt, got.OccurredAt)
+		}
+	})
+
+	t.Run("TopicSequencesAreIndependent", func(t *testing.T) {
+		backend := newBackend(t)
+		sessionEvent := appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		prEvent := appendEvent(t, backend, "pullrequests", "session/a", "pr.linked")
+
+		if sessionEvent.Sequence != 1 || prEvent.Sequence != 1 {
+			t.Fatalf("expected independent sequences, got %d %d", sessionEvent.Sequence, prEvent.Sequence)
+		}
+		if prEvent.StreamVersion != 1 {
+			t.Fatalf("expected independent stream versions, got %d", prEvent.StreamVersion)
+		}
+		if events := loadStream(t, backend, "sessions", "session/a", eventlog.LoadStreamOptions{}); len(events) != 1 {
+			t.Fatalf("expected topic isolation, got %d events", len(events))
+		}
+	})
+
+	t.Run("LoadStreamHonorsAfterVersionAndLimit", func(t *testing.T) {
+		backend := newBackend(t)
+		for _, eventType := range []eventlog.EventType{"one", "two", "three", "four"} {
+			appendEvent(t, backend, "sessions", "session/a", eventType)
+			appendEvent(t, backend, "sessions", "session/b", eventType)
+		}
+
+		events := loadStream(t, backend, "sessions", "session/a", eventlog.LoadStreamOptions{AfterVersion: 1, Limit: 2})
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+		if events[0].Type != "two" || events[1].Type != "three" {
+			t.Fatalf("unexpected events: %s %s", events[0].Type, events[1].Type)
+		}
+		if events[0].StreamVersion != 2 || events[1].StreamVersion != 3 {
+			t.Fatalf("unexpected stream versions: %d %d", events[0].StreamVersion, events[1].StreamVersion)
+		}
+		if events := loadStream(t, backend, "sessions", "session/missing", eventlog.LoadStreamOptions{}); len(events) != 0 {
+			t.Fatalf("expected no events for missing stream, got %d", len(events))
+		}
+	})
+
+	t.Run("ReadGlobalReturnsEventsInSequenceOrder", func(t *testing.T) {
+		backend := newBackend(t)
+		appendEvent(t, backend, "sessions", "session/a", "one")
+		appendEvent(t, backend, "sessions", "session/b", "two")
+		appendEvent(t, backend, "sessions", "session/a", "three")
+
+		events := readGlobal(t, backend, "sessions", 1, 10)
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+		if events[0].Sequence != 2 || events[1].Sequence != 3 {
+			t.Fatalf("unexpected sequences: %d %d", events[0].Sequence, events[1].Sequence)
+		}
+
+		limited := readGlobal(t, backend, "sessions", 0, 2)
+		if len(limited) != 2 || limited[0].Type != "one" || limited[1].Type != "two" {
+			t.Fatalf("unexpected limited read: %+v", limited)
+		}
+	})
+
+	t.Run("ReadGlobalWaitsForNewEvents", func(t *testing.T) {
+		backend := newBackend(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		type result struct {
+			events []eventlog.Envelope
+			err    error
+		}
+		done := make(chan result, 1)
+		go func() {
+			events, err := backend.ReadGlobal(ctx, "sessions", 0, 10)
+			done <- result{events: events, err: err}
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		appendEvent(t, backend, "sessions", "session/a", "session.queued")
+
+		got := <-done
+		if got.err != nil {
+			t.Fatalf("ReadGlobal: %v", got.err)
+		}
+		if len(got.events) != 1 || got.events[0].Type != "session.queued" {
+			t.Fatalf("unexpected events: %+v", got.events)
+		}
+	})
+
+	t.Run("ReadGlobalStopsOnContextCancel", func(t *testing.T) {
+		backend := newBackend(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := backend.ReadGlobal(ctx, "sessions", 0, 10)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("CheckpointsArePerTopicAndSubscriber", func(t *testing.T) {
+		backend := newBackend(t)
+		ctx := context.Background()
+
+		if got := loadCheckpoint(t, backend, "sessions", "projection"); got != 0 {
+			t.Fatalf("expected missing checkpoint to be 0, got %d", got)
+		}
+		if err := backend.StoreCheckpoint(ctx, "sessions", "projection", 3); err != nil {
+			t.Fatalf("StoreCheckpoint: %v", err)
+		}
+		if err := backend.StoreCheckpoint(ctx, "sessions", "projection", 5); err != nil {
+			t.Fatalf("StoreCheckpoint overwrite: %v", err)
+		}
+		if got := loadCheckpoint(t, backend, "sessions", "projection"); got != 5 {
+			t.Fatalf("expected checkpoint 5, got %d", got)
+		}
+		if got := loadCheckpoint(t, backend, "sessions", "other"); got != 0 {
+			t.Fatalf("expected other subscriber checkpoint 0, got %d", got)
+		}
+		if got := loadCheckpoint(t, backend, "pullrequests", "projection"); got != 0 {
+			t.Fatalf("expected other topic checkpoint 0, got %d", got)
+		}
+	})
+
+	t.Run("ResetStreamToEventReplaysTarget", func(t *testing.T) {
+		backend := newBackend(t)
+		target := appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		appendEvent(t, backend, "sessions", "session/a", "session.failed")
+		other := appendEvent(t, backend, "sessions", "session/b", "session.queued")
+
+		replayed, err := backend.ResetStreamToEvent(context.Background(), "sessions", "session/a", target.ID)
+		if err != nil {
+			t.Fatalf("ResetStreamToEvent: %v", err)
+		}
+		if replayed.ID == target.ID || replayed.Type != target.Type || string(replayed.Payload) != string(target.Payload) {
+			t.Fatalf("unexpected replayed event: %+v", replayed)
+		}
+		if replayed.StreamVersion != 1 {
+			t.Fatalf("expected replayed stream version 1, got %d", replayed.StreamVersion)
+		}
+		if replayed.Sequence != other.Sequence+1 {
+			t.Fatalf("expected replayed sequence %d, got %d", other.Sequence+1, replayed.Sequence)
+		}
+
+		events := loadStream(t, backend, "sessions", "session/a", eventlog.LoadStreamOptions{})
+		if len(events) != 1 || events[0].ID != replayed.ID {
+			t.Fatalf("expected only the replayed event, got %+v", events)
+		}
+		if events := loadStream(t, backend, "sessions", "session/b", eventlog.LoadStreamOptions{}); len(events) != 1 {
+			t.Fatalf("expected other streams untouched, got %d events", len(events))
+		}
+	})
+
+	t.Run("ResetStreamToEventDoesNotReuseCheckpointedSequences", func(t *testing.T) {
+		backend := newBackend(t)
+		target := appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		failed := appendEvent(t, backend, "sessions", "session/a", "session.failed")
+		if err := backend.StoreCheckpoint(context.Background(), "sessions", "projection", failed.Sequence); err != nil {
+			t.Fatalf("StoreCheckpoint: %v", err)
+		}
+
+		replayed, err := backend.ResetStreamToEvent(context.Background(), "sessions", "session/a", target.ID)
+		if err != nil {
+			t.Fatalf("ResetStreamToEvent: %v", err)
+		}
+		if replayed.Sequence <= failed.Sequence {
+			t.Fatalf("expected replayed sequence after checkpoint %d, got %d", failed.Sequence, replayed.Sequence)
+		}
+
+		events := readGlobal(t, backend, "sessions", failed.Sequence, 10)
+		if len(events) != 1 || events[0].ID != replayed.ID {
+			t.Fatalf("expected checkpointed subscriber to see the replayed event, got %+v", events)
+		}
+	})
+
+	t.Run("ResetStreamToEventMissingTarget", func(t *testing.T) {
+		backend := newBackend(t)
+		appendEvent(t, backend, "sessions", "session/a", "session.queued")
+
+		_, err := backend.ResetStreamToEvent(context.Background(), "sessions", "session/a", "missing")
+		if !errors.Is(err, eventlog.ErrEventNotFound) {
+			t.Fatalf("expected ErrEventNotFound, got %v", err)
+		}
+		if events := loadStream(t, backend, "sessions", "session/a", eventlog.LoadStreamOptions{}); len(events) != 1 {
+			t.Fatalf("expected stream untouched, got %d events", len(events))
+		}
+	})
+
+	t.Run("ListCheckpointsAndLatestSequence", func(t *testing.T) {
+		backend := newBackend(t)
+		ctx := context.Background()
+
+		if latest, err := backend.LatestSequence(ctx, "sessions"); err != nil || latest != 0 {
+			t.Fatalf("expected empty topic latest 0, got %d (%v)", latest, err)
+		}
+		appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		last := appendEvent(t, backend, "sessions", "session/b", "session.queued")
+		if err := backend.StoreCheckpoint(ctx, "sessions", "projection", 2); err != nil {
+			t.Fatalf("StoreCheckpoint: %v", err)
+		}
+		if err := backend.StoreCheckpoint(ctx, "sessions", "complete", 1); err != nil {
+			t.Fatalf("StoreCheckpoint: %v", err)
+		}
+		if err := backend.StoreCheckpoint(ctx, "pullrequests", "projection", 9); err != nil {
+			t.Fatalf("StoreCheckpoint: %v", err)
+		}
+
+		latest, err := backend.LatestSequence(ctx, "sessions")
+		if err != nil {
+			t.Fatalf("LatestSequence: %v", err)
+		}
+		if latest != last.Sequence {
+			t.Fatalf("expected latest %d, got %d", last.Sequence, latest)
+		}
+		checkpoints, err := backend.ListCheckpoints(ctx, "sessions")
+		if err != nil {
+			t.Fatalf("ListCheckpoints: %v", err)
+		}
+		if len(checkpoints) != 2 || checkpoints[0].Subscriber != "complete" || checkpoints[0].Sequence != 1 || checkpoints[1].Subscriber != "projection" || checkpoints[1].Sequence != 2 {
+			t.Fatalf("unexpected checkpoints: %+v", checkpoints)
+		}
+		if checkpoints[0].UpdatedAt.IsZero() {
+			t.Fatalf("expected checkpoint updated at to be set")
+		}
+
+		if err := backend.DeleteCheckpoint(ctx, "sessions", "complete"); err != nil {
+			t.Fatalf("DeleteCheckpoint: %v", err)
+		}
+		if err := backend.DeleteCheckpoint(ctx, "sessions", "missing"); err != nil {
+			t.Fatalf("DeleteCheckpoint of a missing subscriber: %v", err)
+		}
+		checkpoints, err = backend.ListCheckpoints(ctx, "sessions")
+		if err != nil {
+			t.Fatalf("ListCheckpoints: %v", err)
+		}
+		if len(checkpoints) != 1 || checkpoints[0].Subscriber != "projection" {
+			t.Fatalf("expected only the projection checkpoint left, got %+v", checkpoints)
+		}
+		if sequence, err := backend.LoadCheckpoint(ctx, "pullrequests", "projection"); err != nil || sequence != 9 {
+			t.Fatalf("expected other topics untouched, got %d (%v)", sequence, err)
+		}
+	})
+
+	t.Run("DeleteStreamStopsAtHorizon", func(t *testing.T) {
+		backend := newBackend(t)
+		ctx := context.Background()
+		first := appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		appendEvent(t, backend, "sessions", "session/b", "session.queued")
+		appendEvent(t, backend, "sessions", "session/a", "session.deleted")
+
+		info, err := backend.StreamInfo(ctx, "sessions", "session/a")
+		if err != nil {
+			t.Fatalf("StreamInfo: %v", err)
+		}
+		if info.Events != 2 || info.LastSequence != 3 {
+			t.Fatalf("unexpected stream info: %+v", info)
+		}
+
+		deleted, err := backend.DeleteStream(ctx, "sessions", "session/a", first.Sequence)
+		if err != nil {
+			t.Fatalf("DeleteStream: %v", err)
+		}
+		if deleted != 1 {
+			t.Fatalf("expected 1 deleted event, got %d", deleted)
+		}
+		events := loadStream(t, backend, "sessions", "session/a", eventlog.LoadStreamOptions{})
+		if len(events) != 1 || events[0].Type != "session.deleted" {
+			t.Fatalf("expected events past the horizon to survive, got %+v", events)
+		}
+		if events := loadStream(t, backend, "sessions", "session/b", eventlog.LoadStreamOptions{}); len(events) != 1 {
+			t.Fatalf("expected other streams untouched, got %d events", len(events))
+		}
+		if info, err := backend.StreamInfo(ctx, "sessions", "session/missing"); err != nil || info.Events != 0 || info.LastSequence != 0 {
+			t.Fatalf("expected empty stream info, got %+v (%v)", info, err)
+		}
+	})
+
+	t.Run("DeleteStreamDoesNotReuseCheckpointedSequences", func(t *testing.T) {
+		backend := newBackend(t)
+		ctx := context.Background()
+		appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		last := appendEvent(t, backend, "sessions", "session/a", "session.deleted")
+		if err := backend.StoreCheckpoint(ctx, "sessions", "projection", last.Sequence); err != nil {
+			t.Fatalf("StoreCheckpoint: %v", err)
+		}
+		checkpoints, err := backend.ListCheckpoints(ctx, "sessions")
+		if err != nil {
+			t.Fatalf("ListCheckpoints: %v", err)
+		}
+		horizon := eventlog.CompactionHorizon(checkpoints, last.Sequence)
+		if horizon != last.Sequence {
+			t.Fatalf("expected horizon %d, got %d", last.Sequence, horizon)
+		}
+		if _, err := backend.DeleteStream(ctx, "sessions", "session/a", horizon); err != nil {
+			t.Fatalf("DeleteStream: %v", err)
+		}
+
+		next := appendEvent(t, backend, "sessions", "session/b", "session.queued")
+		if next.Sequence <= last.Sequence {
+			t.Fatalf("expected sequence after %d, got %d", last.Sequence, next.Sequence)
+		}
+	})
+
+	t.Run("SubscribeThroughEventLog", func(t *testing.T) {
+		backend := newBackend(t)
+		log, err := eventlog.New(eventlog.Config{Topic: "sessions"}, backend)
+		if err != nil {
+			t.Fatalf("eventlog.New: %v", err)
+		}
+		appendEvent(t, backend, "sessions", "session/a", "session.queued")
+		appendEvent(t, backend, "sessions", "session/a", "session.ready")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		seen := make([]eventlog.EventType, 0, 2)
+		err = log.Subscribe(ctx, eventlog.Subscription{
+			ID:     "projection",
+			Filter: func(evt eventlog.Envelope) bool { return evt.Type != "session.queued" },
+			Handle: func(_ context.Context, evt eventlog.Envelope) error {
+				seen = append(seen, evt.Type)
+				cancel()
+				return nil
+			},
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context cancellation, got %v", err)
+		}
+		if len(seen) != 1 || seen[0] != "session.ready" {
+			t.Fatalf("unexpected handled events: %v", seen)
+		}
+		if got := loadCheckpoint(t, backend, "sessions", "projection"); got != 1 {
+			t.Fatalf("expected filtered event to advance checkpoint to 1, got %d", got)
+		}
+	})
+}
+
+func appendEvent(t *testing.T, backend Backend, topic eventlog.Topic, streamID eventlog.StreamID, eventType eventlog.EventType) eventlog.Envelope {
+	t.Helper()
+	envelope, err := backend.Append(context.Background(), topic, eventlog.PendingEvent{
+		StreamID:      streamID,
+		Type:          eventType,
+		SchemaVersion: 1,
+		Payload:       []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("Append %s: %v", eventType, err)
+	}
+	return envelope
+}
+
+func loadStream(t *testing.T, backend Backend, topic eventlog.Topic, streamID eventlog.StreamID, opts eventlog.LoadStreamOptions) []eventlog.Envelope {
+	t.Helper()
+	events, err := backend.LoadStream(context.Background(), topic, streamID, opts)
+	if err != nil {
+		t.Fatalf("LoadStream: %v", err)
+	}
+	return events
+}
+
+func readGlobal(t *testing.T, backend Backend, topic eventlog.Topic, afterSequence int64, limit int) []eventlog.Envelope {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := backend.ReadGlobal(ctx, topic, afterSequence, limit)
+	if err != nil {
+		t.Fatalf("ReadGlobal: %v", err)
+	}
+	return events
+}
+
+func loadCheckpoint(t *testing.T, backend Backend, topic eventlog.Topic, subscriber eventlog.SubscriberID) int64 {
+	t.Helper()
+	sequence, err := backend.LoadCheckpoint(context.Background(), topic, subscriber)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	return sequence
+}
@@ -55,3 +55,14 @@ type EventLog interface {
 	Subscribe(ctx context.Context, sub Subscription) error
 	Close() error
 }
+
+type Checkpoint struct {
+	Subscriber SubscriberID
+	Sequence   int64
+	UpdatedAt  time.Time
+}
+
+type StreamInfo struct {
+	Events       int64
+	LastSequence int64
+}
@@ -0,0 +1,54 @@
+package schemas
+
+import (
+	"time"
+
+	z "github.com/Oudwins/zog"
+)
+
+type AdminCompactRequest struct {
+	DryRun bool `json:"dryRun"`
+	// OlderThanDays overrides retention.deletedSessionDays when positive.
+	OlderThanDays int `json:"olderThanDays,omitempty"`
+	// DropSubscribers forgets the checkpoints of subscribers that no longer
+	// run before the horizon is computed.
+	DropSubscribers []string `json:"dropSubscribers,omitempty"`
+}
+
+var AdminCompactSchema = z.Struct(z.Shape{
+	"DryRun":          z.Bool().Default(false),
+	"OlderThanDays":   z.Int().GTE(0).Default(0),
+	"DropSubscribers": z.Slice(z.String().Trim().Min(1)).Optional(),
+})
+
+type AdminCompactCheckpoint struct {
+	Subscriber string    `json:"subscriber"`
+	Sequence   int64     `json:"sequence"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type AdminCompactTopic struct {
+	Topic       string                   `json:"topic"`
+	Latest      int64                    `json:"latest"`
+	Horizon     int64                    `json:"horizon"`
+	PinnedBy    string                   `json:"pinnedBy,omitempty"`
+	Checkpoints []AdminCompactCheckpoint `json:"checkpoints"`
+	Dropped     []AdminCompactCheckpoint `json:"dropped,omitempty"`
+}
+
+type AdminCompactStream struct {
+	Topic    string `json:"topic"`
+	StreamID string `json:"streamId"`
+	Reason   string `json:"reason"`
+	Events   int64  `json:"events"`
+	Action   string `json:"action"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+type AdminCompactResponse struct {
+	DryRun        bool                 `json:"dryRun"`
+	Cutoff        time.Time            `json:"cutoff"`
+	Topics        []AdminCompactTopic  `json:"topics"`
+	Streams       []AdminCompactStream `json:"streams"`
+	EventsRemoved int64                `json:"eventsRemoved"`
+}
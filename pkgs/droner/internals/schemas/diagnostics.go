@@ -0,0 +1,51 @@
+package schemas
+
+import "time"
+
+type DiagnosticStatus string
+
+const (
+	DiagnosticStatusOK   DiagnosticStatus = "ok"
+	DiagnosticStatusWarn DiagnosticStatus = "warn"
+	DiagnosticStatusFail DiagnosticStatus = "fail"
+)
+
+// DiagnosticCheck is one environment check. Fix says what to do when the
+// status is not ok.
+type DiagnosticCheck struct {
+	Name   string           `json:"name"`
+	Status DiagnosticStatus `json:"status"`
+	Detail string           `json:"detail"`
+	Fix    string           `json:"fix,omitempty"`
+}
+
+type DiagnosticDatabase struct {
+	Name   string           `json:"name"`
+	Driver string           `json:"driver"`
+	Status DiagnosticStatus `json:"status"`
+	// Version is the applied migration, Latest the newest one this build
+	// knows about.
+	Version int64  `json:"version"`
+	Latest  int64  `json:"latest"`
+	Error   string `json:"error,omitempty"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+type DiagnosticSubscriber struct {
+	Topic      string           `json:"topic"`
+	Subscriber string           `json:"subscriber"`
+	Status     DiagnosticStatus `json:"status"`
+	Sequence   int64            `json:"sequence"`
+	Latest     int64            `json:"latest"`
+	Lag        int64            `json:"lag"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+	Fix        string           `json:"fix,omitempty"`
+}
+
+// DiagnosticsResponse is returned by GET /diagnostics.
+type DiagnosticsResponse struct {
+	Version     string                 `json:"version"`
+	Checks      []DiagnosticCheck      `json:"checks"`
+	Databases   []DiagnosticDatabase   `json:"databases"`
+	Subscribers []DiagnosticSubscriber `json:"subscribers"`
+}
@@ -0,0 +1,37 @@
+package schemas
+
+import "time"
+
+type GitHubRateLimitResponse struct {
+	Resource  string     `json:"resource,omitempty"`
+	Limit     int        `json:"limit"`
+	Remaining int        `json:"remaining"`
+	Used      int        `json:"used"`
+	ResetAt   *time.Time `json:"resetAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	// BackingOff is true while polling is paused until BackoffUntil.
+	BackingOff    bool       `json:"backingOff"`
+	BackoffUntil  *time.Time `json:"backoffUntil,omitempty"`
+	BackoffReason string     `json:"backoffReason,omitempty"`
+	Requests      int64      `json:"requests"`
+	NotModified   int64      `json:"notModified"`
+}
+
+type OpencodeAgent struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpencodeModel struct {
+	// ID is "<provider>/<model>", as accepted by SessionAgentConfig.Model.
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ProviderName string `json:"providerName"`
+}
+
+// OpencodeCatalogResponse lists the primary agents and the models of the
+// running opencode server.
+type OpencodeCatalogResponse struct {
+	Agents []OpencodeAgent `json:"agents"`
+	Models []OpencodeModel `json:"models"`
+}
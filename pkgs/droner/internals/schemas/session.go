@@ -1,10 +1,12 @@
 package schemas
 
 import (
+	"encoding/json"
 	"log/slog"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
@@ -89,10 +91,13 @@ func (c SessionAgentConfig) ToDescription() string {
 var branchRegex = regexp.MustCompile(`^[A-Za-z0-9/\-]+$`)
 var multiupleSlashes = regexp.MustCompile(`//+`)
 
+// SessionBranchSchema validates the optional branch of a new session.
+var SessionBranchSchema = branch().Optional().Trim().Match(branchRegex).Not().Match(multiupleSlashes)
+
 var SessionCreateSchema = z.Struct(z.Shape{
 	"Path":      z.String().Required().Trim().Transform(cleanPathTransform),
 	"Harness":   conf.HarnessIDSchema,
-	"Branch":    branch().Optional().Trim().Match(branchRegex).Not().Match(multiupleSlashes),
+	"Branch":    SessionBranchSchema,
 	"BackendID": conf.BackendIDSchema,
 	"AgentConfig": z.Ptr(z.Struct(z.Shape{
 		"Model":     z.String().Default(conf.GetConfig().Sessions.Harness.DefaultModel()).Trim(),
@@ -118,16 +123,37 @@ type SessionDeleteRequest struct {
 type SessionListItem struct {
 	ID          string             `json:"id"`
 	Repo        string             `json:"repo"`
+	RepoPath    string             `json:"repoPath,omitempty"`
 	RemoteURL   string             `json:"remoteUrl"`
 	TmuxSession string             `json:"tmuxSession"`
 	Branch      *SBranch           `json:"branch,omitempty"`
 	State       SessionPublicState `json:"state"`
+	PRNumber    int64              `json:"prNumber,omitempty"`
+	PRState     string             `json:"prState,omitempty"`
+	PRCIState   string             `json:"prCiState,omitempty"`
+	PRURL       string             `json:"prUrl,omitempty"`
 }
 
 type SessionListResponse struct {
 	Sessions []SessionListItem `json:"sessions"`
 }
 
+// SessionWatchEventType names the server-sent events of GET /sessions/watch.
+type SessionWatchEventType string
+
+const (
+	// SessionWatchEventReady is sent once the stream is subscribed, so
+	// clients can load the list without missing changes made meanwhile.
+	SessionWatchEventReady SessionWatchEventType = "ready"
+	// SessionWatchEventSession carries a session whose list item changed.
+	SessionWatchEventSession SessionWatchEventType = "session"
+)
+
+type SessionWatchEvent struct {
+	Type    SessionWatchEventType `json:"type"`
+	Session *SessionListItem      `json:"session,omitempty"`
+}
+
 type SessionListDirection string
 
 type SessionPublicState string
@@ -169,6 +195,103 @@ var SessionCompleteSchema = z.Struct(z.Shape{
 	"Branch": branch().Required().Trim(),
 })
 
+// SessionPullRequestRequest opens a pull request for a session's branch. Empty
+// fields default to the session's initial prompt and the branch the session
+// was created from.
+type SessionPullRequestRequest struct {
+	Title         string `json:"title,omitempty"`
+	Body          string `json:"body,omitempty"`
+	Base          string `json:"base,omitempty"`
+	Draft         bool   `json:"draft,omitempty"`
+	BodyFromAgent bool   `json:"bodyFromAgent,omitempty"`
+}
+
+var SessionPullRequestSchema = z.Struct(z.Shape{
+	"Title":         z.String().Optional().Trim(),
+	"Body":          z.String().Optional().Trim(),
+	"Base":          z.String().Optional().Trim(),
+	"Draft":         z.Bool().Default(false),
+	"BodyFromAgent": z.Bool().Default(false),
+})
+
+type SessionPullRequestResponse struct {
+	ID         string  `json:"id"`
+	Branch     SBranch `json:"branch"`
+	Number     int     `json:"number"`
+	URL        string  `json:"url"`
+	Title      string  `json:"title"`
+	BaseRef    string  `json:"baseRef"`
+	Draft      bool    `json:"draft"`
+	PRStreamID string  `json:"prStreamId"`
+}
+
+// SessionDiffQuery holds the query parameters of GET /sessions/{id}/diff.
+// Base defaults to the branch the session was created from; Patch=false
+// leaves out the per-file patches when only the summary is needed.
+type SessionDiffQuery struct {
+	Base  string `zog:"base"`
+	Patch bool   `zog:"patch"`
+}
+
+// baseRefRegex keeps a diff base to branch-like names; a leading "-" would
+// otherwise reach git as an option.
+var baseRefRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/\-]*$`)
+var doubleDots = regexp.MustCompile(`\.\.`)
+
+var SessionDiffQuerySchema = z.Struct(z.Shape{
+	"Base":  z.String().Optional().Trim().Match(baseRefRegex).Not().Match(doubleDots).Not().Match(multiupleSlashes),
+	"Patch": z.Bool().Default(true),
+})
+
+type SessionDiffSummary struct {
+	Files      int `json:"files"`
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+type SessionDiffFile struct {
+	Path       string `json:"path"`
+	OldPath    string `json:"oldPath,omitempty"`
+	Status     string `json:"status"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+	Binary     bool   `json:"binary,omitempty"`
+	Patch      string `json:"patch,omitempty"`
+}
+
+// SessionDiffResponse separates the changes of a session's worktree into
+// commits since the merge base with BaseRef, staged and unstaged changes.
+// Unstaged includes untracked files.
+type SessionDiffResponse struct {
+	ID        string             `json:"id"`
+	Branch    SBranch            `json:"branch"`
+	BaseRef   string             `json:"baseRef"`
+	MergeBase string             `json:"mergeBase"`
+	Summary   SessionDiffSummary `json:"summary"`
+	Committed []SessionDiffFile  `json:"committed"`
+	Staged    []SessionDiffFile  `json:"staged"`
+	Unstaged  []SessionDiffFile  `json:"unstaged"`
+}
+
+// SessionEvent is one event of a session stream. Error and BackendDetails
+// are set on lifecycle failures.
+type SessionEvent struct {
+	ID             string          `json:"id"`
+	Type           string          `json:"type"`
+	Version        int64           `json:"version"`
+	OccurredAt     time.Time       `json:"occurredAt"`
+	CausationID    string          `json:"causationId,omitempty"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	BackendDetails string          `json:"backendDetails,omitempty"`
+}
+
+type SessionEventsResponse struct {
+	ID     string         `json:"id"`
+	Branch SBranch        `json:"branch"`
+	Events []SessionEvent `json:"events"`
+}
+
 type SessionResetRequest struct {
 	StreamID string `json:"streamId"`
 	EventID  string `json:"eventId"`
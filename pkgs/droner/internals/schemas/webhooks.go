@@ -0,0 +1,8 @@
+package schemas
+
+type GitHubWebhookResponse struct {
+	Event    string `json:"event"`
+	Delivery string `json:"delivery,omitempty"`
+	// Refreshed counts the subscriptions queued for an immediate poll.
+	Refreshed int `json:"refreshed"`
+}
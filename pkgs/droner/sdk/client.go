@@ -220,6 +220,117 @@ func (c *Client) CompleteSession(ctx context.Context, request schemas.SessionCom
 	return &payload, nil
 }
 
+func (c *Client) CreateSessionPullRequest(ctx context.Context, id string, request schemas.SessionPullRequestRequest) (*schemas.SessionPullRequestResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/sessions/"+url.PathEscape(id)+"/pr", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.SessionPullRequestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+// SessionDiff loads the changes of a session's worktree against base, or the
+// branch the session was created from when base is empty. withPatch=false only fetches the
+// file list and summary.
+func (c *Client) SessionDiff(ctx context.Context, id string, base string, withPatch bool) (*schemas.SessionDiffResponse, error) {
+	q := url.Values{}
+	if base != "" {
+		q.Set("base", base)
+	}
+	if !withPatch {
+		q.Set("patch", "false")
+	}
+	path := "/sessions/" + url.PathEscape(id) + "/diff"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.SessionDiffResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+// SessionEvents loads the full event stream of a session, oldest first.
+func (c *Client) SessionEvents(ctx context.Context, id string) (*schemas.SessionEventsResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/sessions/"+url.PathEscape(id)+"/events", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.SessionEventsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+// NextSession returns the idle session after the one running in tmuxSession,
+// wrapping around to the newest. An empty tmuxSession starts from the newest.
+func (c *Client) NextSession(ctx context.Context, tmuxSession string) (*schemas.SessionListResponse, error) {
+	return c.navigateSession(ctx, "/_session/next", tmuxSession)
+}
+
+// PrevSession is NextSession in the other direction.
+func (c *Client) PrevSession(ctx context.Context, tmuxSession string) (*schemas.SessionListResponse, error) {
+	return c.navigateSession(ctx, "/_session/prev", tmuxSession)
+}
+
+func (c *Client) navigateSession(ctx context.Context, path string, tmuxSession string) (*schemas.SessionListResponse, error) {
+	if tmuxSession = strings.TrimSpace(tmuxSession); tmuxSession != "" {
+		path += "?tmuxsession=" + url.QueryEscape(tmuxSession)
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.SessionListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
 func (c *Client) ListSessions(ctx context.Context) (*schemas.SessionListResponse, error) {
 	resp, err := c.doRequest(ctx, http.MethodGet, "/sessions", nil)
 	if err != nil {
@@ -259,6 +370,93 @@ func (c *Client) ListSessionsAll(ctx context.Context) (*schemas.SessionListRespo
 	return &payload, nil
 }
 
+func (c *Client) Diagnostics(ctx context.Context) (*schemas.DiagnosticsResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/diagnostics", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.DiagnosticsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+func (c *Client) CompactEventLog(ctx context.Context, request schemas.AdminCompactRequest) (*schemas.AdminCompactResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/admin/compact", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.AdminCompactResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+func (c *Client) GitHubRateLimit(ctx context.Context) (*schemas.GitHubRateLimitResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/providers/github/rate-limit", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.GitHubRateLimitResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+// OpencodeCatalog lists the agents and models the running opencode server
+// offers for directory.
+func (c *Client) OpencodeCatalog(ctx context.Context, directory string) (*schemas.OpencodeCatalogResponse, error) {
+	path := "/providers/opencode/catalog"
+	if directory != "" {
+		path += "?" + url.Values{"directory": []string{directory}}.Encode()
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var payload schemas.OpencodeCatalogResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
 func (c *Client) doRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
 	if err != nil {
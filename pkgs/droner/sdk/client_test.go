@@ -150,6 +150,34 @@ func TestClientListSessionsWithParamsIncludesDirection(t *testing.T) {
 	}
 }
 
+func TestClientNextAndPrevSessionPassTmuxSession(t *testing.T) {
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.Query().Get("tmuxsession"))
+		branch := schemas.SBranch("next")
+		_ = json.NewEncoder(w).Encode(&schemas.SessionListResponse{Sessions: []schemas.SessionListItem{{ID: "stream-next", Branch: &branch, TmuxSession: "repo#next"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	response, err := client.NextSession(ctx, "repo#current")
+	if err != nil {
+		t.Fatalf("NextSession: %v", err)
+	}
+	if len(response.Sessions) != 1 || response.Sessions[0].TmuxSession != "repo#next" {
+		t.Fatalf("response = %#v", response)
+	}
+	if _, err := client.PrevSession(ctx, ""); err != nil {
+		t.Fatalf("PrevSession: %v", err)
+	}
+	if strings.Join(paths, ",") != "/_session/next?repo#current,/_session/prev?" {
+		t.Fatalf("paths = %v", paths)
+	}
+}
+
 func assertQueryValues(t *testing.T, query url.Values, key string, want []string) {
 	t.Helper()
 
@@ -163,3 +191,34 @@ func assertQueryValues(t *testing.T, query url.Values, key string, want []string
 		}
 	}
 }
+
+func TestClientWatchSessions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sessions/watch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("event: ready\ndata: {\"type\":\"ready\"}\n\n: keep-alive\n\n"))
+		_, _ = w.Write([]byte("event: session\ndata: {\"type\":\"session\",\"session\":{\"id\":\"stream-1\",\"repo\":\"repo\",\"state\":\"active.busy\",\"prNumber\":7,\"prCiState\":\"failure\"}}\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithHTTPClient(server.Client()))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var events []schemas.SessionWatchEvent
+	if err := client.WatchSessions(ctx, func(evt schemas.SessionWatchEvent) {
+		events = append(events, evt)
+	}); err != nil {
+		t.Fatalf("WatchSessions: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != schemas.SessionWatchEventReady {
+		t.Fatalf("events = %#v, want ready then session", events)
+	}
+	session := events[1].Session
+	if session == nil || session.ID != "stream-1" || session.State != schemas.SessionPublicStateActiveBusy || session.PRNumber != 7 || session.PRCIState != "failure" {
+		t.Fatalf("session event = %#v", events[1])
+	}
+}
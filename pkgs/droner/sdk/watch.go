@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+)
+
+// WatchSessions streams session changes from GET /sessions/watch and calls
+// handle for each event until ctx ends or the server closes the stream. The
+// first event is always SessionWatchEventReady; list sessions after it to
+// avoid missing changes. The server closes the stream of a client that falls
+// too far behind, so callers reconnect when it returns. It returns nil when
+// ctx is canceled.
+func (c *Client) WatchSessions(ctx context.Context, handle func(schemas.SessionWatchEvent)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/sessions/watch", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open indefinitely, so the request timeout of the
+	// regular client does not apply.
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var evt schemas.SessionWatchEvent
+			if err := json.Unmarshal([]byte(data.String()), &evt); err != nil {
+				return err
+			}
+			data.Reset()
+			handle(evt)
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// AttachSession attaches the terminal to a session's tmux session, or
+// switches the client when already inside tmux.
+func AttachSession(tmuxSession string) error {
+	cmd := attachCommand(tmuxSession)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// PickSession lets the user fuzzy find one of sessions by branch or repo. It
+// returns nil when the picker is cancelled.
+func PickSession(sessions []schemas.SessionListItem) (*schemas.SessionListItem, error) {
+	applyConfiguredTheme()
+	items := make([]pickerItem, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, pickerItem{
+			Title:  sessionBranch(session),
+			Detail: session.Repo + "  " + string(session.State),
+			Search: session.Repo + " " + session.TmuxSession,
+		})
+	}
+	picker := newListPicker(pickerKindSession, "Session", "No sessions to attach to", items)
+	result, err := tea.NewProgram(listPickerModel{picker: picker, subtitle: "attach", chosen: -1}, tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, err
+	}
+	final, ok := result.(listPickerModel)
+	if !ok || final.chosen < 0 {
+		return nil, nil
+	}
+	return &sessions[final.chosen], nil
+}
+
+// listPickerModel runs a listPicker as a program of its own.
+type listPickerModel struct {
+	picker   *listPicker
+	subtitle string
+	chosen   int
+	width    int
+	height   int
+}
+
+func (m listPickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m listPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if closed, item := m.picker.handleKey(msg); closed {
+			m.chosen = item
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m listPickerModel) View() string {
+	width := max(composerPanelWidth(m.width)-panelStyle.GetHorizontalFrameSize(), 1)
+	brand := lipgloss.JoinHorizontal(lipgloss.Left, brandMutedStyle.Render("D R O "), brandStyle.Render("N E R"))
+	help := strings.Join([]string{
+		shortcutKeyStyle.Render("enter") + " " + shortcutLabelStyle.Render("select"),
+		shortcutKeyStyle.Render("esc") + " " + shortcutLabelStyle.Render("cancel"),
+	}, "   ")
+	panel := panelStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Bold(true).Render(brand),
+		lipgloss.NewStyle().Width(width).Align(lipgloss.Center).MarginBottom(1).Render(subtitleStyle.Render(m.subtitle)),
+		m.picker.view(width),
+		lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(help),
+	))
+	if m.width <= 0 || m.height <= 0 {
+		return panel
+	}
+	return appStyle.Width(m.width).Height(m.height).Render(lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel))
+}
@@ -26,6 +26,7 @@ type autocompleteQuery struct {
 
 type autocompleteResult struct {
 	Value       string
+	Hint        string
 	Description string
 }
 
@@ -143,7 +144,7 @@ func rankCommandSearchResults(commands []slashCommand, query string, limit int)
 		if !ok {
 			continue
 		}
-		results = append(results, scoredResult{command: autocompleteResult{Value: command.Name, Description: command.Description}, score: score})
+		results = append(results, scoredResult{command: autocompleteResult{Value: command.Name, Hint: command.argumentHint(), Description: command.Description}, score: score})
 	}
 	sort.Slice(results, func(i int, j int) bool {
 		if results[i].score != results[j].score {
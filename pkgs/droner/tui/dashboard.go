@@ -0,0 +1,671 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	dashboardPageSize       = 100
+	dashboardReconnectDelay = 3 * time.Second
+	dashboardStateWidth     = 12
+	dashboardRepoWidth      = 18
+	dashboardPRWidth        = 7
+	dashboardCIWidth        = 9
+)
+
+// dashboardStatuses are listed by the dashboard. Completed sessions stay so
+// they can be resumed; deleted ones are gone for good.
+var dashboardStatuses = []sdk.SessionStatus{
+	sdk.SessionStatusQueued,
+	sdk.SessionStatusActiveBusy,
+	sdk.SessionStatusActiveIdle,
+	sdk.SessionStatusCompleting,
+	sdk.SessionStatusFailed,
+	sdk.SessionStatusCompleted,
+}
+
+var (
+	dashboardHeaderStyle lipgloss.Style
+	dashboardBusyStyle   lipgloss.Style
+	dashboardFailedStyle lipgloss.Style
+)
+
+func buildDashboardStyles() {
+	dashboardHeaderStyle = lipgloss.NewStyle().
+		Foreground(mutedTextColor).
+		Bold(true)
+	dashboardBusyStyle = lipgloss.NewStyle().
+		Foreground(accentStrongColor)
+	dashboardFailedStyle = lipgloss.NewStyle().
+		Foreground(errorTextColor)
+}
+
+type dashboardClient interface {
+	ListSessionsWithParams(ctx context.Context, statuses []sdk.SessionStatus, limit int, cursor string, direction string) (*schemas.SessionListResponse, error)
+	CreateSession(ctx context.Context, request schemas.SessionCreateRequest) (*schemas.SessionCreateResponse, error)
+	CompleteSession(ctx context.Context, request schemas.SessionCompleteRequest) (*schemas.TaskResponse, error)
+	DeleteSession(ctx context.Context, request schemas.SessionDeleteRequest) (*schemas.TaskResponse, error)
+	WatchSessions(ctx context.Context, handle func(schemas.SessionWatchEvent)) error
+	SessionDiff(ctx context.Context, id string, base string, withPatch bool) (*schemas.SessionDiffResponse, error)
+	SessionEvents(ctx context.Context, id string) (*schemas.SessionEventsResponse, error)
+}
+
+type sessionsLoadedMsg struct {
+	sessions []schemas.SessionListItem
+	err      error
+}
+
+type watchEventMsg struct {
+	event schemas.SessionWatchEvent
+}
+
+type watchEndedMsg struct {
+	err error
+}
+
+type watchRetryMsg struct{}
+
+type dashboardActionMsg struct {
+	status string
+	err    error
+}
+
+type dashboardModel struct {
+	ctx           context.Context
+	client        dashboardClient
+	repoPath      string
+	watchEvents   chan tea.Msg
+	sessions      []schemas.SessionListItem
+	cursor        int
+	live          bool
+	confirmDelete bool
+	status        string
+	width         int
+	height        int
+	compose       bool
+	composeRepo   string
+	diff          *diffView
+	timeline      *timelineView
+	// diffSummaries caches the diff summary of each session by ID until the
+	// session changes again.
+	diffSummaries map[string]schemas.SessionDiffSummary
+}
+
+// RunDashboard shows the full-screen session dashboard. New sessions are
+// created in repoPath, or in the repo of the selected session when repoPath
+// is empty.
+func RunDashboard(client *sdk.Client, repoPath string) error {
+	applyConfiguredTheme()
+	if err := cliutil.EnsureDaemonRunning(client); err != nil {
+		return err
+	}
+	status := ""
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		model := newDashboardModel(ctx, client, repoPath)
+		model.status = status
+		result, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		cancel()
+		if err != nil {
+			return err
+		}
+		final, ok := result.(dashboardModel)
+		if !ok || !final.compose {
+			return nil
+		}
+
+		// The composer runs as its own program, after which the dashboard
+		// starts over with the outcome in its status line.
+		response, err := composeSession(client, final.composeRepo, "")
+		switch {
+		case err != nil:
+			status = "Create failed: " + err.Error()
+		case response == nil:
+			status = ""
+		case response.Branch != nil:
+			status = "Created " + response.Branch.String()
+		default:
+			status = "Created " + response.ID
+		}
+	}
+}
+
+func newDashboardModel(ctx context.Context, client dashboardClient, repoPath string) dashboardModel {
+	return dashboardModel{
+		ctx:           ctx,
+		client:        client,
+		repoPath:      repoPath,
+		watchEvents:   make(chan tea.Msg),
+		width:         defaultPanelWidth,
+		diffSummaries: map[string]schemas.SessionDiffSummary{},
+	}
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return tea.Batch(m.loadSessions(), m.watchSessions())
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case sessionsLoadedMsg:
+		if msg.err != nil {
+			m.status = "Failed to load sessions: " + msg.err.Error()
+			return m, nil
+		}
+		m.sessions = msg.sessions
+		m.clampCursor()
+		return m, m.loadSelectedDiffSummary()
+	case watchEventMsg:
+		switch msg.event.Type {
+		case schemas.SessionWatchEventReady:
+			m.live = true
+			return m, tea.Batch(m.loadSessions(), m.waitForWatchEvent())
+		case schemas.SessionWatchEventSession:
+			if msg.event.Session != nil {
+				delete(m.diffSummaries, msg.event.Session.ID)
+				m.applySession(*msg.event.Session)
+				// Every projection change comes from a new event, so an open
+				// timeline of the session reloads to show it.
+				if m.timeline != nil && m.timeline.session.ID == msg.event.Session.ID {
+					m.timeline.session = *msg.event.Session
+					return m, tea.Batch(m.waitForWatchEvent(), m.loadTimeline(msg.event.Session.ID))
+				}
+			}
+		}
+		return m, tea.Batch(m.waitForWatchEvent(), m.loadSelectedDiffSummary())
+	case watchEndedMsg:
+		m.live = false
+		if m.ctx.Err() != nil {
+			return m, nil
+		}
+		// Without a stream the list is reloaded on every reconnect attempt,
+		// so an older daemon still gets a polled dashboard.
+		return m, tea.Batch(m.loadSessions(), tea.Tick(dashboardReconnectDelay, func(time.Time) tea.Msg {
+			return watchRetryMsg{}
+		}))
+	case watchRetryMsg:
+		return m, m.watchSessions()
+	case diffLoadedMsg:
+		if msg.withPatch {
+			if m.diff == nil || m.diff.session.ID != msg.sessionID {
+				return m, nil
+			}
+			if msg.err != nil {
+				m.diff.err = "Failed to load diff: " + msg.err.Error()
+				return m, nil
+			}
+			m.diff.setResponse(msg.response)
+			return m, nil
+		}
+		if msg.err == nil {
+			m.diffSummaries[msg.sessionID] = msg.response.Summary
+		}
+		return m, nil
+	case timelineLoadedMsg:
+		if m.timeline == nil || m.timeline.session.ID != msg.sessionID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.timeline.err = "Failed to load events: " + msg.err.Error()
+			return m, nil
+		}
+		m.timeline.err = ""
+		m.timeline.response = msg.response
+		return m, nil
+	case dashboardActionMsg:
+		m.status = msg.status
+		if msg.err != nil {
+			m.status = msg.status + ": " + msg.err.Error()
+		}
+		if !m.live {
+			return m, m.loadSessions()
+		}
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m dashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.diff != nil {
+		if m.diff.handleKey(msg.String(), max(m.height-4, 3)) {
+			m.diff = nil
+		}
+		return m, nil
+	}
+	if m.timeline != nil {
+		if m.timeline.handleKey(msg.String(), max(m.height-4, 3)) {
+			m.timeline = nil
+		}
+		return m, nil
+	}
+	if m.confirmDelete {
+		m.confirmDelete = false
+		session, ok := m.selected()
+		if msg.String() != "y" || !ok {
+			m.status = "Delete cancelled"
+			return m, nil
+		}
+		m.status = "Deleting " + sessionBranch(session) + "..."
+		return m, m.deleteSession(session)
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.cursor--
+		m.clampCursor()
+		return m, m.loadSelectedDiffSummary()
+	case "down", "j":
+		m.cursor++
+		m.clampCursor()
+		return m, m.loadSelectedDiffSummary()
+	case "n":
+		m.composeRepo = m.repoPath
+		if session, ok := m.selected(); ok && m.composeRepo == "" {
+			m.composeRepo = session.RepoPath
+		}
+		if m.composeRepo == "" {
+			m.status = "Open the dashboard inside a repo to create sessions"
+			return m, nil
+		}
+		m.compose = true
+		return m, tea.Quit
+	}
+
+	session, ok := m.selected()
+	if !ok {
+		return m, nil
+	}
+	switch msg.String() {
+	case "c", "d", "r":
+		// Complete, delete and resume address sessions by branch.
+		if session.Branch == nil {
+			m.status = session.ID + " has no branch"
+			return m, nil
+		}
+	}
+	switch msg.String() {
+	case "enter", "a":
+		if session.TmuxSession == "" {
+			m.status = "Session has no tmux session yet"
+			return m, nil
+		}
+		return m, tea.ExecProcess(attachCommand(session.TmuxSession), func(err error) tea.Msg {
+			if err != nil {
+				return dashboardActionMsg{status: "Attach failed", err: err}
+			}
+			return dashboardActionMsg{}
+		})
+	case "c":
+		m.status = "Completing " + sessionBranch(session) + "..."
+		return m, m.completeSession(session)
+	case "d":
+		m.confirmDelete = true
+		m.status = "Delete " + sessionBranch(session) + "? y/n"
+	case "r":
+		if session.State != schemas.SessionPublicStateCompleted && session.State != schemas.SessionPublicStateFailed {
+			m.status = sessionBranch(session) + " is still " + string(session.State)
+			return m, nil
+		}
+		m.status = "Resuming " + sessionBranch(session) + "..."
+		return m, m.resumeSession(session)
+	case "v":
+		if !hasWorktree(session) {
+			m.status = sessionBranch(session) + " has no worktree to diff"
+			return m, nil
+		}
+		m.diff = newDiffView(session)
+		return m, m.loadDiff(session.ID, true)
+	case "t":
+		m.timeline = newTimelineView(session)
+		return m, m.loadTimeline(session.ID)
+	case "o":
+		if session.PRURL == "" {
+			m.status = sessionBranch(session) + " has no pull request"
+			return m, nil
+		}
+		url := session.PRURL
+		return m, func() tea.Msg {
+			if err := openURLCommand(url).Start(); err != nil {
+				return dashboardActionMsg{status: "Open failed", err: err}
+			}
+			return dashboardActionMsg{status: "Opened " + url}
+		}
+	}
+	return m, nil
+}
+
+func (m dashboardModel) View() string {
+	width := m.width
+	if width < minRenderableWidth {
+		width = defaultPanelWidth
+	}
+	if m.diff != nil || m.timeline != nil {
+		var content string
+		if m.diff != nil {
+			content = m.diff.view(width, m.height)
+		} else {
+			content = m.timeline.view(width, m.height)
+		}
+		if m.height > 0 {
+			return appStyle.Width(m.width).Height(m.height).Render(content)
+		}
+		return appStyle.Render(content)
+	}
+
+	live := dashboardFailedStyle.Render("reconnecting")
+	if m.live {
+		live = subtitleStyle.Render("live")
+	}
+	brand := lipgloss.JoinHorizontal(lipgloss.Left, brandMutedStyle.Render("D R O "), brandStyle.Render("N E R"))
+	lines := []string{
+		brand + "   " + subtitleStyle.Render(fmt.Sprintf("%d sessions", len(m.sessions))) + "  " + live,
+		"",
+		dashboardHeaderStyle.Render(m.row(width, "STATE", "REPO", "BRANCH", "PR", "CI")),
+	}
+	if len(m.sessions) == 0 {
+		lines = append(lines, helpStyle.Render("No sessions. Press n to start one."))
+	}
+	for i, session := range m.sessions {
+		pr := ""
+		if session.PRNumber > 0 {
+			pr = fmt.Sprintf("#%d", session.PRNumber)
+		}
+		line := m.row(width, string(session.State), session.Repo, sessionBranch(session), pr, session.PRCIState)
+		switch {
+		case i == m.cursor:
+			line = selectionStyle.Render(line)
+		case session.State == schemas.SessionPublicStateFailed || session.PRCIState == "failure":
+			line = dashboardFailedStyle.Render(line)
+		case session.State == schemas.SessionPublicStateActiveBusy:
+			line = dashboardBusyStyle.Render(line)
+		case session.State == schemas.SessionPublicStateCompleted:
+			line = helpStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "")
+	if session, ok := m.selected(); ok {
+		if summary, ok := m.diffSummaries[session.ID]; ok {
+			lines = append(lines, subtitleStyle.Render(sessionBranch(session)+": ")+diffSummaryText(summary))
+		}
+	}
+	lines = append(lines, m.helpView())
+	if m.status != "" {
+		lines = append(lines, subtitleStyle.Render(m.status))
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	if m.height > 0 {
+		return appStyle.Width(m.width).Height(m.height).Render(content)
+	}
+	return appStyle.Render(content)
+}
+
+func (m dashboardModel) helpView() string {
+	items := []string{
+		shortcutKeyStyle.Render("enter") + " " + shortcutLabelStyle.Render("attach"),
+		shortcutKeyStyle.Render("n") + " " + shortcutLabelStyle.Render("new"),
+		shortcutKeyStyle.Render("c") + " " + shortcutLabelStyle.Render("complete"),
+		shortcutKeyStyle.Render("d") + " " + shortcutLabelStyle.Render("delete"),
+		shortcutKeyStyle.Render("r") + " " + shortcutLabelStyle.Render("resume"),
+		shortcutKeyStyle.Render("v") + " " + shortcutLabelStyle.Render("diff"),
+		shortcutKeyStyle.Render("t") + " " + shortcutLabelStyle.Render("timeline"),
+		shortcutKeyStyle.Render("o") + " " + shortcutLabelStyle.Render("open PR"),
+		shortcutKeyStyle.Render("q") + " " + shortcutLabelStyle.Render("quit"),
+	}
+	return strings.Join(items, "   ")
+}
+
+func (m dashboardModel) row(width int, state, repo, branch, pr, ci string) string {
+	branchWidth := width - dashboardStateWidth - dashboardRepoWidth - dashboardPRWidth - dashboardCIWidth - 2
+	if branchWidth < 8 {
+		branchWidth = 8
+	}
+	return "  " + fitColumn(state, dashboardStateWidth) + fitColumn(repo, dashboardRepoWidth) + fitColumn(branch, branchWidth) + fitColumn(pr, dashboardPRWidth) + fitColumn(ci, dashboardCIWidth)
+}
+
+// fitColumn pads value to width, truncating it with an ellipsis when it
+// leaves no room for the column gap.
+func fitColumn(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) >= width {
+		runes = append(runes[:width-2], '…')
+	}
+	return string(runes) + strings.Repeat(" ", width-len(runes))
+}
+
+func (m *dashboardModel) clampCursor() {
+	if m.cursor >= len(m.sessions) {
+		m.cursor = len(m.sessions) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m dashboardModel) selected() (schemas.SessionListItem, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.sessions) {
+		return schemas.SessionListItem{}, false
+	}
+	return m.sessions[m.cursor], true
+}
+
+// applySession replaces the listed session with the same ID, appends new
+// ones and drops sessions that left the listed states.
+func (m *dashboardModel) applySession(session schemas.SessionListItem) {
+	listed := false
+	for _, status := range dashboardStatuses {
+		if schemas.SessionPublicState(status) == session.State {
+			listed = true
+			break
+		}
+	}
+	for i := range m.sessions {
+		if m.sessions[i].ID != session.ID {
+			continue
+		}
+		if listed {
+			m.sessions[i] = session
+		} else {
+			m.sessions = append(m.sessions[:i], m.sessions[i+1:]...)
+			m.clampCursor()
+		}
+		return
+	}
+	if listed {
+		m.sessions = append(m.sessions, session)
+	}
+}
+
+func (m dashboardModel) loadSessions() tea.Cmd {
+	client := m.client
+	ctx := m.ctx
+	return func() tea.Msg {
+		sessions := []schemas.SessionListItem{}
+		cursor := ""
+		for {
+			reqCtx, cancel := context.WithTimeout(ctx, timeouts.SecondLong)
+			response, err := client.ListSessionsWithParams(reqCtx, dashboardStatuses, dashboardPageSize, cursor, string(schemas.SessionListDirectionAfter))
+			cancel()
+			if err != nil {
+				return sessionsLoadedMsg{err: err}
+			}
+			sessions = append(sessions, response.Sessions...)
+			if len(response.Sessions) < dashboardPageSize {
+				return sessionsLoadedMsg{sessions: sessions}
+			}
+			cursor = response.Sessions[len(response.Sessions)-1].ID
+		}
+	}
+}
+
+// watchSessions streams session changes into m.watchEvents from a goroutine
+// and returns the command reading the first of them.
+func (m dashboardModel) watchSessions() tea.Cmd {
+	client := m.client
+	ctx := m.ctx
+	events := m.watchEvents
+	go func() {
+		err := client.WatchSessions(ctx, func(evt schemas.SessionWatchEvent) {
+			select {
+			case events <- watchEventMsg{event: evt}:
+			case <-ctx.Done():
+			}
+		})
+		if err == nil {
+			err = errors.New("watch stream closed")
+		}
+		select {
+		case events <- watchEndedMsg{err: err}:
+		case <-ctx.Done():
+		}
+	}()
+	return m.waitForWatchEvent()
+}
+
+func (m dashboardModel) waitForWatchEvent() tea.Cmd {
+	events := m.watchEvents
+	ctx := m.ctx
+	return func() tea.Msg {
+		select {
+		case msg := <-events:
+			return msg
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (m dashboardModel) loadDiff(sessionID string, withPatch bool) tea.Cmd {
+	client := m.client
+	ctx := m.ctx
+	return func() tea.Msg {
+		reqCtx, cancel := context.WithTimeout(ctx, timeouts.SecondLong)
+		defer cancel()
+		response, err := client.SessionDiff(reqCtx, sessionID, "", withPatch)
+		return diffLoadedMsg{sessionID: sessionID, withPatch: withPatch, response: response, err: err}
+	}
+}
+
+func (m dashboardModel) loadTimeline(sessionID string) tea.Cmd {
+	client := m.client
+	ctx := m.ctx
+	return func() tea.Msg {
+		reqCtx, cancel := context.WithTimeout(ctx, timeouts.SecondLong)
+		defer cancel()
+		response, err := client.SessionEvents(reqCtx, sessionID)
+		return timelineLoadedMsg{sessionID: sessionID, response: response, err: err}
+	}
+}
+
+// loadSelectedDiffSummary fetches the summary line of the selected session
+// unless it is cached.
+func (m dashboardModel) loadSelectedDiffSummary() tea.Cmd {
+	session, ok := m.selected()
+	if !ok || !hasWorktree(session) {
+		return nil
+	}
+	if _, cached := m.diffSummaries[session.ID]; cached {
+		return nil
+	}
+	return m.loadDiff(session.ID, false)
+}
+
+func hasWorktree(session schemas.SessionListItem) bool {
+	switch session.State {
+	case schemas.SessionPublicStateActiveIdle, schemas.SessionPublicStateActiveBusy, schemas.SessionPublicStateCompleted:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m dashboardModel) completeSession(session schemas.SessionListItem) tea.Cmd {
+	client := m.client
+	ctx := m.ctx
+	branch := sessionBranch(session)
+	return func() tea.Msg {
+		reqCtx, cancel := context.WithTimeout(ctx, timeouts.SecondLong)
+		defer cancel()
+		if _, err := client.CompleteSession(reqCtx, schemas.SessionCompleteRequest{Branch: schemas.NewSBranch(branch)}); err != nil {
+			return dashboardActionMsg{status: "Complete failed", err: err}
+		}
+		return dashboardActionMsg{status: "Completion requested for " + branch}
+	}
+}
+
+func (m dashboardModel) deleteSession(session schemas.SessionListItem) tea.Cmd {
+	client := m.client
+	ctx := m.ctx
+	branch := sessionBranch(session)
+	return func() tea.Msg {
+		reqCtx, cancel := context.WithTimeout(ctx, timeouts.SecondLong)
+		defer cancel()
+		if _, err := client.DeleteSession(reqCtx, schemas.SessionDeleteRequest{Branch: schemas.NewSBranch(branch)}); err != nil {
+			return dashboardActionMsg{status: "Delete failed", err: err}
+		}
+		return dashboardActionMsg{status: "Deletion requested for " + branch}
+	}
+}
+
+// resumeSession starts a new session on the branch of a finished one, which
+// picks up the branch where the previous session left it.
+func (m dashboardModel) resumeSession(session schemas.SessionListItem) tea.Cmd {
+	client := m.client
+	ctx := m.ctx
+	branch := sessionBranch(session)
+	request := schemas.SessionCreateRequest{Path: session.RepoPath, Branch: schemas.NewSBranch(branch)}
+	return func() tea.Msg {
+		reqCtx, cancel := context.WithTimeout(ctx, timeouts.SecondLong)
+		defer cancel()
+		if _, err := client.CreateSession(reqCtx, request); err != nil {
+			return dashboardActionMsg{status: "Resume failed", err: err}
+		}
+		return dashboardActionMsg{status: "Resumed " + branch}
+	}
+}
+
+func sessionBranch(session schemas.SessionListItem) string {
+	if session.Branch == nil {
+		return session.ID
+	}
+	return session.Branch.String()
+}
+
+// attachCommand switches the current tmux client when run inside tmux and
+// attaches the terminal otherwise.
+func attachCommand(tmuxSession string) *exec.Cmd {
+	if os.Getenv("TMUX") != "" {
+		return exec.Command("tmux", "switch-client", "-t", tmuxSession)
+	}
+	return exec.Command("tmux", "attach-session", "-t", tmuxSession)
+}
+
+func openURLCommand(url string) *exec.Cmd {
+	if runtime.GOOS == "darwin" {
+		return exec.Command("open", url)
+	}
+	return exec.Command("xdg-open", url)
+}
@@ -0,0 +1,301 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fakeDashboardClient struct {
+	pages     [][]schemas.SessionListItem
+	cursors   []string
+	created   []schemas.SessionCreateRequest
+	completed []schemas.SessionCompleteRequest
+	deleted   []schemas.SessionDeleteRequest
+	diff      *schemas.SessionDiffResponse
+	events    *schemas.SessionEventsResponse
+}
+
+func (c *fakeDashboardClient) ListSessionsWithParams(ctx context.Context, statuses []sdk.SessionStatus, limit int, cursor string, direction string) (*schemas.SessionListResponse, error) {
+	c.cursors = append(c.cursors, cursor)
+	page := c.pages[0]
+	c.pages = c.pages[1:]
+	return &schemas.SessionListResponse{Sessions: page}, nil
+}
+
+func (c *fakeDashboardClient) CreateSession(ctx context.Context, request schemas.SessionCreateRequest) (*schemas.SessionCreateResponse, error) {
+	c.created = append(c.created, request)
+	return &schemas.SessionCreateResponse{ID: "stream-new"}, nil
+}
+
+func (c *fakeDashboardClient) CompleteSession(ctx context.Context, request schemas.SessionCompleteRequest) (*schemas.TaskResponse, error) {
+	c.completed = append(c.completed, request)
+	return &schemas.TaskResponse{}, nil
+}
+
+func (c *fakeDashboardClient) DeleteSession(ctx context.Context, request schemas.SessionDeleteRequest) (*schemas.TaskResponse, error) {
+	c.deleted = append(c.deleted, request)
+	return &schemas.TaskResponse{}, nil
+}
+
+func (c *fakeDashboardClient) WatchSessions(ctx context.Context, handle func(schemas.SessionWatchEvent)) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (c *fakeDashboardClient) SessionDiff(ctx context.Context, id string, base string, withPatch bool) (*schemas.SessionDiffResponse, error) {
+	response := *c.diff
+	if !withPatch {
+		response.Committed = nil
+	}
+	return &response, nil
+}
+
+func (c *fakeDashboardClient) SessionEvents(ctx context.Context, id string) (*schemas.SessionEventsResponse, error) {
+	return c.events, nil
+}
+
+func dashboardSession(id string, branch string, state schemas.SessionPublicState) schemas.SessionListItem {
+	sbranch := schemas.NewSBranch(branch)
+	return schemas.SessionListItem{ID: id, Repo: "repo", RepoPath: "/tmp/repo", TmuxSession: "repo#" + branch, Branch: &sbranch, State: state}
+}
+
+func pressDashboardKey(t *testing.T, model dashboardModel, key string) (dashboardModel, tea.Cmd) {
+	t.Helper()
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	return updated.(dashboardModel), cmd
+}
+
+func TestDashboardLoadsEveryPage(t *testing.T) {
+	first := make([]schemas.SessionListItem, dashboardPageSize)
+	for i := range first {
+		first[i] = dashboardSession("stream-"+strings.Repeat("a", i+1), "branch", schemas.SessionPublicStateActiveIdle)
+	}
+	client := &fakeDashboardClient{pages: [][]schemas.SessionListItem{first, {dashboardSession("stream-last", "last", schemas.SessionPublicStateQueued)}}}
+	model := newDashboardModel(context.Background(), client, "/tmp/repo")
+
+	msg := model.loadSessions()().(sessionsLoadedMsg)
+	if msg.err != nil {
+		t.Fatalf("loadSessions: %v", msg.err)
+	}
+	if len(msg.sessions) != dashboardPageSize+1 {
+		t.Fatalf("loaded %d sessions, want %d", len(msg.sessions), dashboardPageSize+1)
+	}
+	if len(client.cursors) != 2 || client.cursors[0] != "" || client.cursors[1] != first[len(first)-1].ID {
+		t.Fatalf("cursors = %q, want the last ID of the first page", client.cursors)
+	}
+}
+
+func TestDashboardAppliesWatchedSessions(t *testing.T) {
+	model := newDashboardModel(context.Background(), &fakeDashboardClient{}, "")
+	updated, _ := model.Update(sessionsLoadedMsg{sessions: []schemas.SessionListItem{
+		dashboardSession("stream-1", "one", schemas.SessionPublicStateActiveIdle),
+		dashboardSession("stream-2", "two", schemas.SessionPublicStateActiveIdle),
+	}})
+	model = updated.(dashboardModel)
+
+	busy := dashboardSession("stream-1", "one", schemas.SessionPublicStateActiveBusy)
+	busy.PRNumber = 12
+	busy.PRCIState = "failure"
+	for _, session := range []schemas.SessionListItem{
+		busy,
+		dashboardSession("stream-2", "two", schemas.SessionPublicStateDeleted),
+		dashboardSession("stream-3", "three", schemas.SessionPublicStateQueued),
+	} {
+		session := session
+		updated, _ = model.Update(watchEventMsg{event: schemas.SessionWatchEvent{Type: schemas.SessionWatchEventSession, Session: &session}})
+		model = updated.(dashboardModel)
+	}
+
+	if len(model.sessions) != 2 || model.sessions[0].ID != "stream-1" || model.sessions[1].ID != "stream-3" {
+		t.Fatalf("sessions = %#v, want stream-1 and stream-3", model.sessions)
+	}
+	if model.sessions[0].State != schemas.SessionPublicStateActiveBusy {
+		t.Fatalf("stream-1 state = %q, want active.busy", model.sessions[0].State)
+	}
+	view := model.View()
+	for _, want := range []string{"active.busy", "#12", "failure", "three"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("view is missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestDashboardSessionActions(t *testing.T) {
+	client := &fakeDashboardClient{}
+	model := newDashboardModel(context.Background(), client, "")
+	updated, _ := model.Update(sessionsLoadedMsg{sessions: []schemas.SessionListItem{
+		dashboardSession("stream-1", "running", schemas.SessionPublicStateActiveIdle),
+		dashboardSession("stream-2", "done", schemas.SessionPublicStateCompleted),
+	}})
+	model = updated.(dashboardModel)
+
+	model, cmd := pressDashboardKey(t, model, "c")
+	cmd()
+	if len(client.completed) != 1 || client.completed[0].Branch != "running" {
+		t.Fatalf("completed = %#v, want running", client.completed)
+	}
+
+	model, cmd = pressDashboardKey(t, model, "d")
+	if cmd != nil || !model.confirmDelete {
+		t.Fatal("delete should ask for confirmation first")
+	}
+	model, cmd = pressDashboardKey(t, model, "n")
+	if cmd != nil || len(client.deleted) != 0 {
+		t.Fatal("answering n should cancel the delete")
+	}
+	model, _ = pressDashboardKey(t, model, "d")
+	model, cmd = pressDashboardKey(t, model, "y")
+	cmd()
+	if len(client.deleted) != 1 || client.deleted[0].Branch != "running" {
+		t.Fatalf("deleted = %#v, want running", client.deleted)
+	}
+
+	model, cmd = pressDashboardKey(t, model, "r")
+	if cmd != nil || len(client.created) != 0 {
+		t.Fatal("an active session should not be resumed")
+	}
+	model, _ = pressDashboardKey(t, model, "j")
+	_, cmd = pressDashboardKey(t, model, "r")
+	cmd()
+	if len(client.created) != 1 || client.created[0].Path != "/tmp/repo" || client.created[0].Branch != "done" {
+		t.Fatalf("created = %#v, want done in /tmp/repo", client.created)
+	}
+}
+
+func TestDashboardSessionActionsNeedABranch(t *testing.T) {
+	client := &fakeDashboardClient{}
+	model := newDashboardModel(context.Background(), client, "")
+	session := dashboardSession("stream-1", "unused", schemas.SessionPublicStateCompleted)
+	session.Branch = nil
+	updated, _ := model.Update(sessionsLoadedMsg{sessions: []schemas.SessionListItem{session}})
+	model = updated.(dashboardModel)
+
+	for _, key := range []string{"c", "d", "r"} {
+		var cmd tea.Cmd
+		model, cmd = pressDashboardKey(t, model, key)
+		if cmd != nil || model.confirmDelete {
+			t.Fatalf("%s should be disabled for a session without a branch", key)
+		}
+		if model.status != "stream-1 has no branch" {
+			t.Fatalf("%s status = %q", key, model.status)
+		}
+	}
+}
+
+func TestDashboardComposeUsesSelectedRepoWithoutTarget(t *testing.T) {
+	model := newDashboardModel(context.Background(), &fakeDashboardClient{}, "")
+	updated, _ := model.Update(sessionsLoadedMsg{sessions: []schemas.SessionListItem{
+		dashboardSession("stream-1", "one", schemas.SessionPublicStateActiveIdle),
+	}})
+	model, _ = pressDashboardKey(t, updated.(dashboardModel), "n")
+	if !model.compose || model.composeRepo != "/tmp/repo" {
+		t.Fatalf("compose = %v repo = %q, want compose in /tmp/repo", model.compose, model.composeRepo)
+	}
+}
+
+func TestAttachCommandSwitchesClientInsideTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	if got := strings.Join(attachCommand("repo#one").Args, " "); got != "tmux switch-client -t repo#one" {
+		t.Fatalf("attach inside tmux = %q", got)
+	}
+	t.Setenv("TMUX", "")
+	if got := strings.Join(attachCommand("repo#one").Args, " "); got != "tmux attach-session -t repo#one" {
+		t.Fatalf("attach outside tmux = %q", got)
+	}
+}
+
+func TestDashboardShowsDiffSummaryAndViewer(t *testing.T) {
+	client := &fakeDashboardClient{diff: &schemas.SessionDiffResponse{
+		ID:      "stream-1",
+		BaseRef: "origin/main",
+		Summary: schemas.SessionDiffSummary{Files: 1, Insertions: 1, Deletions: 1},
+		Committed: []schemas.SessionDiffFile{{
+			Path:       "main.go",
+			Status:     "modified",
+			Insertions: 1,
+			Deletions:  1,
+			Patch:      "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-func old() {}\n+func main() { return \"hi\" } // greet\n",
+		}},
+	}}
+	model := newDashboardModel(context.Background(), client, "")
+	updated, cmd := model.Update(sessionsLoadedMsg{sessions: []schemas.SessionListItem{
+		dashboardSession("stream-1", "one", schemas.SessionPublicStateActiveIdle),
+	}})
+	updated, _ = updated.Update(cmd())
+	model = updated.(dashboardModel)
+	if view := model.View(); !strings.Contains(view, "1 file") || !strings.Contains(view, "+1") {
+		t.Fatalf("dashboard view is missing the diff summary:\n%s", view)
+	}
+
+	model, cmd = pressDashboardKey(t, model, "v")
+	updated, _ = model.Update(cmd())
+	model = updated.(dashboardModel)
+	view := model.View()
+	for _, want := range []string{"against origin/main", "Committed", "main.go", "return", "// greet"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("diff view is missing %q:\n%s", want, view)
+		}
+	}
+
+	model, _ = pressDashboardKey(t, model, "q")
+	if model.diff != nil {
+		t.Fatal("q should close the diff view before quitting")
+	}
+}
+
+func TestHighlightCodeColorsKeywordsStringsAndComments(t *testing.T) {
+	plain := highlightCode(`return "x" // done`, nil)
+	if plain != `return "x" // done` {
+		t.Fatalf("unknown languages should stay plain, got %q", plain)
+	}
+	highlighted := highlightCode(`return "x" // done`, goLanguage)
+	for _, part := range []string{syntaxKeywordStyle.Render("return"), syntaxStringStyle.Render(`"x"`), syntaxCommentStyle.Render("// done")} {
+		if !strings.Contains(highlighted, part) {
+			t.Fatalf("highlighted %q is missing %q", highlighted, part)
+		}
+	}
+}
+
+func TestDashboardTimelineShowsDurationsAndFailures(t *testing.T) {
+	start := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	client := &fakeDashboardClient{events: &schemas.SessionEventsResponse{
+		ID: "stream-1",
+		Events: []schemas.SessionEvent{
+			{Type: "session.queued", OccurredAt: start, Payload: json.RawMessage(`{"harness":"opencode"}`)},
+			{Type: "session.environment_provisioning.started", OccurredAt: start.Add(1500 * time.Millisecond), Payload: json.RawMessage(`{"branch":"one"}`)},
+			{
+				Type:           "session.environment_provisioning.failed",
+				OccurredAt:     start.Add(2*time.Minute + 4*time.Second),
+				Payload:        json.RawMessage(`{"error":"setup hook exited with 1"}`),
+				Error:          "setup hook exited with 1",
+				BackendDetails: "npm ERR! missing script: setup",
+			},
+		},
+	}}
+	model := newDashboardModel(context.Background(), client, "")
+	updated, _ := model.Update(sessionsLoadedMsg{sessions: []schemas.SessionListItem{
+		dashboardSession("stream-1", "one", schemas.SessionPublicStateFailed),
+	}})
+	model, cmd := pressDashboardKey(t, updated.(dashboardModel), "t")
+	updated, _ = model.Update(cmd())
+	model = updated.(dashboardModel)
+
+	view := model.View()
+	for _, want := range []string{"3 events over 2m04s", "Queued", "opencode", "+1.5s", "Provisioning failed", "+2m02s", "error: setup hook exited with 1", "backend: npm ERR! missing script: setup"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("timeline view is missing %q:\n%s", want, view)
+		}
+	}
+
+	model, _ = pressDashboardKey(t, model, "q")
+	if model.timeline != nil {
+		t.Fatal("q should close the timeline before quitting")
+	}
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	diffAddedColor   lipgloss.Color
+	diffRemovedColor lipgloss.Color
+	syntaxKeyword    lipgloss.Color
+	syntaxString     lipgloss.Color
+	syntaxNumber     lipgloss.Color
+
+	diffAddedStyle     lipgloss.Style
+	diffRemovedStyle   lipgloss.Style
+	diffHunkStyle      lipgloss.Style
+	diffMetaStyle      lipgloss.Style
+	syntaxKeywordStyle lipgloss.Style
+	syntaxStringStyle  lipgloss.Style
+	syntaxNumberStyle  lipgloss.Style
+	syntaxCommentStyle lipgloss.Style
+)
+
+func buildDiffStyles() {
+	diffAddedStyle = lipgloss.NewStyle().Foreground(diffAddedColor)
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(diffRemovedColor)
+	diffHunkStyle = lipgloss.NewStyle().Foreground(accentColor)
+	diffMetaStyle = lipgloss.NewStyle().Foreground(mutedTextColor).Bold(true)
+	syntaxKeywordStyle = lipgloss.NewStyle().Foreground(syntaxKeyword)
+	syntaxStringStyle = lipgloss.NewStyle().Foreground(syntaxString)
+	syntaxNumberStyle = lipgloss.NewStyle().Foreground(syntaxNumber)
+	syntaxCommentStyle = lipgloss.NewStyle().Foreground(mutedTextColor).Italic(true)
+}
+
+// syntaxLanguage is just enough of a language to color diffs: its keywords,
+// line comment markers and string quotes.
+type syntaxLanguage struct {
+	keywords map[string]struct{}
+	comments []string
+	quotes   string
+}
+
+func newSyntaxLanguage(comments []string, quotes string, keywords string) *syntaxLanguage {
+	language := &syntaxLanguage{keywords: map[string]struct{}{}, comments: comments, quotes: quotes}
+	for _, keyword := range strings.Fields(keywords) {
+		language.keywords[keyword] = struct{}{}
+	}
+	return language
+}
+
+var (
+	goLanguage = newSyntaxLanguage([]string{"//"}, "\"'`",
+		"break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false")
+	jsLanguage = newSyntaxLanguage([]string{"//"}, "\"'`",
+		"async await break case catch class const continue default delete do else export extends false finally for from function if import in instanceof interface let new null return super switch this throw true try type typeof undefined var void while yield")
+	pythonLanguage = newSyntaxLanguage([]string{"#"}, "\"'",
+		"and as assert async await break class continue def del elif else except False finally for from global if import in is lambda None nonlocal not or pass raise return True try while with yield")
+	shellLanguage = newSyntaxLanguage([]string{"#"}, "\"'",
+		"case do done elif else esac export fi for function if in local return then until while")
+	rustLanguage = newSyntaxLanguage([]string{"//"}, "\"",
+		"as async await break const continue crate else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while")
+	sqlLanguage = newSyntaxLanguage([]string{"--"}, "'",
+		"SELECT FROM WHERE AND OR NOT INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE INDEX ON JOIN LEFT INNER ORDER BY GROUP LIMIT AS NULL IS IN EXISTS PRIMARY KEY DEFAULT select from where and or not insert into values update set delete create table index on join left inner order by group limit as null is in exists primary key default")
+	yamlLanguage = newSyntaxLanguage([]string{"#"}, "\"'", "true false null")
+)
+
+func syntaxLanguageForPath(path string) *syntaxLanguage {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return goLanguage
+	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".java", ".c", ".h", ".cc", ".cpp", ".cs", ".swift", ".kt":
+		return jsLanguage
+	case ".py":
+		return pythonLanguage
+	case ".sh", ".bash", ".zsh":
+		return shellLanguage
+	case ".rs":
+		return rustLanguage
+	case ".sql":
+		return sqlLanguage
+	case ".yml", ".yaml", ".toml", ".nix":
+		return yamlLanguage
+	default:
+		return nil
+	}
+}
+
+// renderPatchLines colors a unified diff line by line: the +/- marker and
+// diff metadata by diff role, the code after the marker by syntax.
+func renderPatchLines(patch string, path string) []string {
+	language := syntaxLanguageForPath(path)
+	lines := strings.Split(strings.TrimRight(patch, "\n"), "\n")
+	rendered := make([]string, 0, len(lines))
+	inHunk := false
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			rendered = append(rendered, diffHunkStyle.Render(line))
+		case strings.HasPrefix(line, "diff --git "):
+			inHunk = false
+			rendered = append(rendered, diffMetaStyle.Render(line))
+		case !inHunk:
+			rendered = append(rendered, diffMetaStyle.Render(line))
+		case strings.HasPrefix(line, "+"):
+			rendered = append(rendered, diffAddedStyle.Render("+")+highlightCode(line[1:], language))
+		case strings.HasPrefix(line, "-"):
+			rendered = append(rendered, diffRemovedStyle.Render("-")+highlightCode(line[1:], language))
+		case strings.HasPrefix(line, "\\"):
+			rendered = append(rendered, helpStyle.Render(line))
+		default:
+			rendered = append(rendered, highlightCode(line, language))
+		}
+	}
+	return rendered
+}
+
+func highlightCode(code string, language *syntaxLanguage) string {
+	code = strings.ReplaceAll(code, "\t", "    ")
+	if language == nil {
+		return code
+	}
+	var out strings.Builder
+	runes := []rune(code)
+	for i := 0; i < len(runes); {
+		rest := string(runes[i:])
+		if comment := language.commentAt(rest); comment {
+			out.WriteString(syntaxCommentStyle.Render(rest))
+			break
+		}
+		r := runes[i]
+		switch {
+		case strings.ContainsRune(language.quotes, r):
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				end = len(runes) - 1
+			}
+			out.WriteString(syntaxStringStyle.Render(string(runes[i : end+1])))
+			i = end + 1
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.' || runes[end] == '_' || unicode.IsLetter(runes[end])) {
+				end++
+			}
+			out.WriteString(syntaxNumberStyle.Render(string(runes[i:end])))
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			word := string(runes[i:end])
+			if _, ok := language.keywords[word]; ok {
+				word = syntaxKeywordStyle.Render(word)
+			}
+			out.WriteString(word)
+			i = end
+		default:
+			out.WriteRune(r)
+			i++
+		}
+	}
+	return out.String()
+}
+
+func (l *syntaxLanguage) commentAt(code string) bool {
+	for _, marker := range l.comments {
+		if strings.HasPrefix(code, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	diffFileListMaxWidth = 44
+	diffDefaultHeight    = 24
+)
+
+type diffLoadedMsg struct {
+	sessionID string
+	withPatch bool
+	response  *schemas.SessionDiffResponse
+	err       error
+}
+
+type diffViewFile struct {
+	section string
+	file    schemas.SessionDiffFile
+}
+
+// diffView shows the changed files of one session next to the highlighted
+// patch of the selected file.
+type diffView struct {
+	session  schemas.SessionListItem
+	response *schemas.SessionDiffResponse
+	files    []diffViewFile
+	index    int
+	scroll   int
+	err      string
+}
+
+func newDiffView(session schemas.SessionListItem) *diffView {
+	return &diffView{session: session}
+}
+
+func (v *diffView) setResponse(response *schemas.SessionDiffResponse) {
+	v.response = response
+	v.files = nil
+	for _, section := range []struct {
+		name  string
+		files []schemas.SessionDiffFile
+	}{
+		{"Committed", response.Committed},
+		{"Staged", response.Staged},
+		{"Unstaged", response.Unstaged},
+	} {
+		for _, file := range section.files {
+			v.files = append(v.files, diffViewFile{section: section.name, file: file})
+		}
+	}
+	v.index = 0
+	v.scroll = 0
+}
+
+// handleKey reports whether the view should close.
+func (v *diffView) handleKey(key string, pageSize int) bool {
+	switch key {
+	case "esc", "q":
+		return true
+	case "down", "j":
+		if v.index < len(v.files)-1 {
+			v.index++
+			v.scroll = 0
+		}
+	case "up", "k":
+		if v.index > 0 {
+			v.index--
+			v.scroll = 0
+		}
+	case "ctrl+d", "pgdown", " ":
+		v.scroll += max(pageSize/2, 1)
+	case "ctrl+u", "pgup":
+		v.scroll = max(v.scroll-max(pageSize/2, 1), 0)
+	case "g":
+		v.scroll = 0
+	}
+	return false
+}
+
+func (v *diffView) view(width int, height int) string {
+	if height <= 0 {
+		height = diffDefaultHeight
+	}
+	header := brandStyle.Render("diff ") + subtitleStyle.Render(sessionBranch(v.session))
+	if v.response != nil {
+		header += subtitleStyle.Render(" against "+v.response.BaseRef) + "   " + diffSummaryText(v.response.Summary)
+	}
+	help := strings.Join([]string{
+		shortcutKeyStyle.Render("j/k") + " " + shortcutLabelStyle.Render("file"),
+		shortcutKeyStyle.Render("ctrl+d/ctrl+u") + " " + shortcutLabelStyle.Render("scroll"),
+		shortcutKeyStyle.Render("esc") + " " + shortcutLabelStyle.Render("back"),
+	}, "   ")
+
+	bodyHeight := max(height-4, 3)
+	var body string
+	switch {
+	case v.err != "":
+		body = validationStyle.Render(v.err)
+	case v.response == nil:
+		body = helpStyle.Render("Loading diff...")
+	case len(v.files) == 0:
+		body = helpStyle.Render("No changes against " + v.response.BaseRef)
+	default:
+		listWidth := min(diffFileListMaxWidth, max(width/3, 20))
+		list := lipgloss.NewStyle().Width(listWidth).Height(bodyHeight).MaxHeight(bodyHeight).Render(v.fileListView(listWidth))
+		patch := lipgloss.NewStyle().PaddingLeft(1).Height(bodyHeight).MaxHeight(bodyHeight).Render(v.patchView(max(width-listWidth-1, 10), bodyHeight))
+		body = lipgloss.JoinHorizontal(lipgloss.Top, list, patch)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", body, help)
+}
+
+func (v *diffView) fileListView(width int) string {
+	lines := []string{}
+	section := ""
+	for i, entry := range v.files {
+		if entry.section != section {
+			section = entry.section
+			lines = append(lines, sectionTitleStyle.Render(section))
+		}
+		stats := fmt.Sprintf("+%d -%d", entry.file.Insertions, entry.file.Deletions)
+		if entry.file.Binary {
+			stats = "binary"
+		}
+		pathWidth := max(width-len(stats)-4, 4)
+		line := diffStatusLetter(entry.file.Status) + " " + fitColumn(entry.file.Path, pathWidth) + stats
+		if i == v.index {
+			line = selectionStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (v *diffView) patchView(width int, height int) string {
+	if v.index >= len(v.files) {
+		return ""
+	}
+	file := v.files[v.index].file
+	if file.Binary {
+		return helpStyle.Render("Binary file")
+	}
+	lines := renderPatchLines(file.Patch, file.Path)
+	v.scroll = min(v.scroll, max(len(lines)-height, 0))
+	end := min(v.scroll+height, len(lines))
+	clip := lipgloss.NewStyle().MaxWidth(width)
+	visible := make([]string, 0, end-v.scroll)
+	for _, line := range lines[v.scroll:end] {
+		visible = append(visible, clip.Render(line))
+	}
+	return strings.Join(visible, "\n")
+}
+
+func diffStatusLetter(status string) string {
+	switch status {
+	case "added":
+		return diffAddedStyle.Render("A")
+	case "deleted":
+		return diffRemovedStyle.Render("D")
+	case "renamed":
+		return diffHunkStyle.Render("R")
+	case "untracked":
+		return diffAddedStyle.Render("?")
+	default:
+		return diffHunkStyle.Render("M")
+	}
+}
+
+func diffSummaryText(summary schemas.SessionDiffSummary) string {
+	files := "files"
+	if summary.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s ", summary.Files, files) + diffAddedStyle.Render(fmt.Sprintf("+%d", summary.Insertions)) + " " + diffRemovedStyle.Render(fmt.Sprintf("-%d", summary.Deletions))
+}
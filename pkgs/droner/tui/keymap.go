@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"slices"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+)
+
+// keyBinding lists the keys, as named by tea.KeyMsg.String, that trigger an
+// action.
+type keyBinding []string
+
+func (b keyBinding) matches(key string) bool {
+	return slices.Contains(b, key)
+}
+
+// keyHelpLabels keeps the way the shortcut hints always spelled some keys.
+var keyHelpLabels = map[string]string{"tab": "Tab"}
+
+// help names the first key of the binding for the shortcut hints.
+func (b keyBinding) help() string {
+	if len(b) == 0 {
+		return ""
+	}
+	if label, ok := keyHelpLabels[b[0]]; ok {
+		return label
+	}
+	return b[0]
+}
+
+type composerKeymap struct {
+	submit             keyBinding
+	newline            keyBinding
+	cycleAgent         keyBinding
+	autocompleteUp     keyBinding
+	autocompleteDown   keyBinding
+	autocompleteAccept keyBinding
+	pasteImage         keyBinding
+}
+
+func newComposerKeymap(keymap conf.TUIKeymap) composerKeymap {
+	return composerKeymap{
+		submit:             keyBinding(keymap.Submit),
+		newline:            keyBinding(keymap.Newline),
+		cycleAgent:         keyBinding(keymap.CycleAgent),
+		autocompleteUp:     keyBinding(keymap.AutocompleteUp),
+		autocompleteDown:   keyBinding(keymap.AutocompleteDown),
+		autocompleteAccept: keyBinding(keymap.AutocompleteAccept),
+		pasteImage:         keyBinding(keymap.PasteImage),
+	}
+}
+
+// defaultComposerKeymap returns the bindings used when none are configured.
+func defaultComposerKeymap() composerKeymap {
+	var keymap conf.TUIKeymap
+	_ = conf.TUIKeymapSchema.Parse(map[string]any{}, &keymap)
+	return newComposerKeymap(keymap)
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+)
+
+// loadOpencodeCatalog asks the daemon for the agents and models of the
+// running opencode server. It returns nil when either is not running, in
+// which case the composer falls back to the configured agents.
+func loadOpencodeCatalog(client *sdk.Client, repoPath string) *schemas.OpencodeCatalogResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondShort)
+	defer cancel()
+	catalog, err := client.OpencodeCatalog(ctx, repoPath)
+	if err != nil {
+		return nil
+	}
+	return catalog
+}
+
+// composerAgentNames keeps the configured agents the server knows about in
+// their configured order, followed by the other discovered ones.
+func composerAgentNames(configured []string, catalog *schemas.OpencodeCatalogResponse) []string {
+	if catalog == nil || len(catalog.Agents) == 0 {
+		return configured
+	}
+	discovered := map[string]struct{}{}
+	for _, agent := range catalog.Agents {
+		discovered[agent.Name] = struct{}{}
+	}
+	names := make([]string, 0, len(catalog.Agents))
+	listed := map[string]struct{}{}
+	for _, name := range configured {
+		if _, ok := discovered[name]; ok {
+			names = append(names, name)
+			listed[name] = struct{}{}
+		}
+	}
+	for _, agent := range catalog.Agents {
+		if _, ok := listed[agent.Name]; !ok {
+			names = append(names, agent.Name)
+		}
+	}
+	return names
+}
+
+func (m *sessionComposerModel) setModels(models []schemas.OpencodeModel, selected string) {
+	m.models = append([]schemas.OpencodeModel(nil), models...)
+	m.selectedModel = strings.TrimSpace(selected)
+}
+
+func (m *sessionComposerModel) openModelPicker() {
+	items := make([]pickerItem, 0, len(m.models))
+	selected := -1
+	for i, model := range m.models {
+		items = append(items, pickerItem{Title: model.ID, Detail: model.Name, Search: model.Name})
+		if model.ID == m.selectedModel {
+			selected = i
+		}
+	}
+	m.openPicker(newListPicker(pickerKindModel, "Models", "No models discovered. Is the opencode server running?", items))
+	m.picker.focus(selected)
+}
+
+// selectedModelLabel names the selected model for the meta line, falling
+// back to its ID when the server did not list it.
+func (m sessionComposerModel) selectedModelLabel() string {
+	if m.selectedModel == "" {
+		return "default model"
+	}
+	for _, model := range m.models {
+		if model.ID == m.selectedModel {
+			return model.Name + " " + model.ProviderName
+		}
+	}
+	return m.selectedModel
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type pickerKind string
+
+const (
+	pickerKindModel    pickerKind = "model"
+	pickerKindTemplate pickerKind = "template"
+	pickerKindHistory  pickerKind = "history"
+	pickerKindProject  pickerKind = "project"
+	pickerKindSession  pickerKind = "session"
+)
+
+type pickerItem struct {
+	Title  string
+	Detail string
+	// Search is matched against the query as well as Title.
+	Search string
+}
+
+// listPicker is a filterable list the composer shows in place of
+// autocomplete. Items keep their order among equally good matches, so
+// callers list them by preference.
+type listPicker struct {
+	kind  pickerKind
+	title string
+	empty string
+	items []pickerItem
+	query string
+	index int
+	keys  composerKeymap
+}
+
+func newListPicker(kind pickerKind, title string, empty string, items []pickerItem) *listPicker {
+	return &listPicker{kind: kind, title: title, empty: empty, items: items, keys: defaultComposerKeymap()}
+}
+
+// handleKey reports whether the picker closed and, if an item was chosen,
+// its index in items. The index is -1 when the picker was cancelled.
+func (p *listPicker) handleKey(msg tea.KeyMsg) (bool, int) {
+	results := p.results()
+	switch key := msg.String(); {
+	case key == "esc" || key == "ctrl+c":
+		return true, -1
+	case p.keys.autocompleteUp.matches(key):
+		if len(results) > 0 {
+			p.index = (p.index - 1 + len(results)) % len(results)
+		}
+	case p.keys.autocompleteDown.matches(key):
+		if len(results) > 0 {
+			p.index = (p.index + 1) % len(results)
+		}
+	case p.keys.autocompleteAccept.matches(key):
+		if p.index < len(results) {
+			return true, results[p.index]
+		}
+		return true, -1
+	case key == "backspace":
+		if runes := []rune(p.query); len(runes) > 0 {
+			p.query = string(runes[:len(runes)-1])
+			p.index = 0
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			p.query += string(msg.Runes)
+			p.index = 0
+		}
+	}
+	return false, -1
+}
+
+// focus moves the selection to items[item] while the query is empty.
+func (p *listPicker) focus(item int) {
+	for i, result := range p.results() {
+		if result == item {
+			p.index = i
+		}
+	}
+}
+
+func (p listPicker) results() []int {
+	type scoredItem struct {
+		item  int
+		score int
+	}
+	query := strings.ToLower(strings.TrimSpace(p.query))
+	scored := make([]scoredItem, 0, len(p.items))
+	for i, item := range p.items {
+		score, ok := commandSearchScore(item.Title, query)
+		if !ok && strings.Contains(strings.ToLower(item.Search), query) {
+			score, ok = 2, true
+		}
+		if ok {
+			scored = append(scored, scoredItem{item: i, score: score})
+		}
+	}
+	sort.SliceStable(scored, func(i int, j int) bool {
+		return scored[i].score < scored[j].score
+	})
+	results := make([]int, 0, len(scored))
+	for _, item := range scored {
+		results = append(results, item.item)
+	}
+	return results
+}
+
+func (p listPicker) view(width int) string {
+	title := sectionTitleStyle.Width(width).Render(p.title + "  " + helpStyle.Render("filter: "+p.query))
+	results := p.results()
+	if len(results) == 0 {
+		empty := "No matches"
+		if len(p.items) == 0 {
+			empty = p.empty
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, title, sectionShellStyle.Width(width).Render(helpStyle.Render(empty)))
+	}
+	// Keep the selection visible by scrolling the window of results.
+	start := max(p.index-maxAutocompleteResults+1, 0)
+	end := min(start+maxAutocompleteResults, len(results))
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		item := p.items[results[i]]
+		prefix := "  "
+		style := helpStyle
+		if i == p.index {
+			prefix = "> "
+			style = selectionStyle
+		}
+		line := prefix + item.Title
+		if item.Detail != "" {
+			line += "  " + item.Detail
+		}
+		lines = append(lines, style.Width(width).Render(fitColumn(line, max(width-2, 4))))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, sectionShellStyle.Width(width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+}
@@ -0,0 +1,275 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
+	"github.com/Oudwins/droner/pkgs/droner/sdk"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// projectRecentCount is how many of the most recently used projects are
+// listed before the others, which are ordered by how often they were used.
+const projectRecentCount = 3
+
+type projectEntry struct {
+	Path     string
+	Sessions int
+	// recent is the position of the project among the most recently used
+	// ones, -1 when no session used it.
+	recent int
+}
+
+// rankProjects merges the repos found under the parent paths with those of
+// previous sessions, newest first, and orders them recent then most used.
+func rankProjects(repos []string, sessions []schemas.SessionListItem) []projectEntry {
+	byPath := map[string]*projectEntry{}
+	entries := []*projectEntry{}
+	add := func(path string) *projectEntry {
+		if entry, ok := byPath[path]; ok {
+			return entry
+		}
+		entry := &projectEntry{Path: path, recent: -1}
+		byPath[path] = entry
+		entries = append(entries, entry)
+		return entry
+	}
+	for _, repo := range repos {
+		add(filepath.Clean(repo))
+	}
+	recent := 0
+	for _, session := range sessions {
+		repoPath := strings.TrimSpace(session.RepoPath)
+		if repoPath == "" {
+			continue
+		}
+		repoPath = filepath.Clean(repoPath)
+		entry, ok := byPath[repoPath]
+		if !ok {
+			// Repos outside the parent paths are kept while they exist.
+			if !pathExists(repoPath) {
+				continue
+			}
+			entry = add(repoPath)
+		}
+		if entry.recent < 0 {
+			entry.recent = recent
+			recent++
+		}
+		entry.Sessions++
+	}
+
+	isRecent := func(entry *projectEntry) bool {
+		return entry.recent >= 0 && entry.recent < projectRecentCount
+	}
+	sort.SliceStable(entries, func(i int, j int) bool {
+		left, right := entries[i], entries[j]
+		if isRecent(left) != isRecent(right) {
+			return isRecent(left)
+		}
+		if isRecent(left) {
+			return left.recent < right.recent
+		}
+		if left.Sessions != right.Sessions {
+			return left.Sessions > right.Sessions
+		}
+		return left.Path < right.Path
+	})
+	ranked := make([]projectEntry, 0, len(entries))
+	for _, entry := range entries {
+		ranked = append(ranked, *entry)
+	}
+	return ranked
+}
+
+// validateSessionBranch returns why branch cannot be used for a new session
+// in repoPath, or "" when it can. An empty branch lets the server name it.
+func validateSessionBranch(repoPath string, branch string, sessions []schemas.SessionListItem) string {
+	requested := schemas.NewSBranch(strings.TrimSpace(branch))
+	if requested == "" {
+		return ""
+	}
+	if errs := schemas.SessionBranchSchema.Validate(&requested); errs != nil {
+		return "Branch may only contain letters, digits, '-' and single '/'"
+	}
+	for _, session := range sessions {
+		if session.Branch == nil || *session.Branch != requested || filepath.Clean(session.RepoPath) != filepath.Clean(repoPath) {
+			continue
+		}
+		switch session.State {
+		case schemas.SessionPublicStateQueued, schemas.SessionPublicStateActiveIdle, schemas.SessionPublicStateActiveBusy, schemas.SessionPublicStateCompleting:
+			return fmt.Sprintf("Branch %s is already used by a session in %s (status=%s)", requested, filepath.Base(repoPath), session.State)
+		}
+	}
+	return ""
+}
+
+// loadSessionHistory returns the latest sessions, newest first. Like the
+// opencode catalog it is best effort: without a running server the picker
+// falls back to alphabetical projects and the server checks the branch.
+func loadSessionHistory(client *sdk.Client) []schemas.SessionListItem {
+	ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondShort)
+	defer cancel()
+	response, err := client.ListSessions(ctx)
+	if err != nil {
+		return nil
+	}
+	return response.Sessions
+}
+
+// projectPickerModel picks the repo and branch of a new session before the
+// composer opens.
+type projectPickerModel struct {
+	projects    []projectEntry
+	sessions    []schemas.SessionListItem
+	picker      *listPicker
+	branchInput textinput.Model
+	repoPath    string
+	validation  string
+	width       int
+	height      int
+	done        bool
+	cancelled   bool
+}
+
+func newProjectPickerModel(projects []projectEntry, sessions []schemas.SessionListItem) projectPickerModel {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "leave empty to generate one"
+	input.CharLimit = 200
+	m := projectPickerModel{projects: projects, sessions: sessions, branchInput: input}
+	m.openPicker()
+	return m
+}
+
+func (m *projectPickerModel) openPicker() {
+	items := make([]pickerItem, 0, len(m.projects))
+	for _, project := range m.projects {
+		detail := filepath.Dir(project.Path)
+		switch {
+		case project.Sessions == 1:
+			detail = "1 session  " + detail
+		case project.Sessions > 1:
+			detail = fmt.Sprintf("%d sessions  %s", project.Sessions, detail)
+		}
+		items = append(items, pickerItem{Title: filepath.Base(project.Path), Detail: detail, Search: project.Path})
+	}
+	m.picker = newListPicker(pickerKindProject, "Project", "No git repos under config.projects.parentPaths", items)
+}
+
+func (m projectPickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m projectPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case tea.KeyMsg:
+		if m.picker != nil {
+			closed, item := m.picker.handleKey(msg)
+			if !closed {
+				return m, nil
+			}
+			m.picker = nil
+			if item < 0 {
+				m.cancelled = true
+				return m, tea.Quit
+			}
+			m.repoPath = m.projects[item].Path
+			m.validation = ""
+			return m, m.branchInput.Focus()
+		}
+		switch msg.String() {
+		case "ctrl+c":
+			m.cancelled = true
+			return m, tea.Quit
+		case "esc":
+			m.branchInput.Blur()
+			m.repoPath = ""
+			m.validation = ""
+			m.openPicker()
+			return m, nil
+		case "enter":
+			m.validation = validateSessionBranch(m.repoPath, m.branchInput.Value(), m.sessions)
+			if m.validation != "" {
+				return m, nil
+			}
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	m.branchInput, cmd = m.branchInput.Update(msg)
+	m.validation = ""
+	return m, cmd
+}
+
+func (m projectPickerModel) View() string {
+	panelWidth := composerPanelWidth(m.width)
+	width := max(panelWidth-panelStyle.GetHorizontalFrameSize(), 1)
+
+	brand := lipgloss.JoinHorizontal(lipgloss.Left, brandMutedStyle.Render("D R O "), brandStyle.Render("N E R"))
+	sections := []string{
+		lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Bold(true).Render(brand),
+		lipgloss.NewStyle().Width(width).Align(lipgloss.Center).MarginBottom(1).Render(subtitleStyle.Render("new session")),
+	}
+	help := []string{
+		shortcutKeyStyle.Render("enter") + " " + shortcutLabelStyle.Render("select"),
+		shortcutKeyStyle.Render("esc") + " " + shortcutLabelStyle.Render("cancel"),
+	}
+	if m.picker != nil {
+		sections = append(sections, m.picker.view(width))
+	} else {
+		title := sectionTitleStyle.Width(width).Render("Branch  " + helpStyle.Render("for "+repoBadgeStyle.Render(filepath.Base(m.repoPath))))
+		sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, title, sectionShellStyle.Width(width).Render(m.branchInput.View())))
+		if m.validation != "" {
+			sections = append(sections, validationStyle.Width(width).Render(m.validation))
+		}
+		help = []string{
+			shortcutKeyStyle.Render("enter") + " " + shortcutLabelStyle.Render("compose"),
+			shortcutKeyStyle.Render("esc") + " " + shortcutLabelStyle.Render("back"),
+		}
+	}
+	sections = append(sections, lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(strings.Join(help, "   ")))
+
+	panel := panelStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	if m.width <= 0 || m.height <= 0 {
+		return panel
+	}
+	return appStyle.Width(m.width).Height(m.height).Render(lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel))
+}
+
+// pickProject asks for the repo and branch of a new session. It returns an
+// empty repo path when the picker is cancelled.
+func pickProject(client *sdk.Client) (string, string, error) {
+	repos, err := cliutil.ProjectRepos()
+	sessions := loadSessionHistory(client)
+	projects := rankProjects(repos, sessions)
+	if len(projects) == 0 {
+		if err == nil {
+			err = errors.New("no git repos found under config.projects.parentPaths")
+		}
+		return "", "", fmt.Errorf("%w; run droner inside a repo or pass repo[@branch]", err)
+	}
+
+	result, err := tea.NewProgram(newProjectPickerModel(projects, sessions), tea.WithAltScreen()).Run()
+	if err != nil {
+		return "", "", err
+	}
+	final, ok := result.(projectPickerModel)
+	if !ok || !final.done {
+		return "", "", nil
+	}
+	return final.repoPath, strings.TrimSpace(final.branchInput.Value()), nil
+}
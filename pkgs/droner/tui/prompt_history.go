@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+)
+
+const (
+	promptHistoryFile  = "prompt_history.jsonl"
+	promptHistoryLimit = 500
+	// promptHistoryCompactBytes is the file size past which an append also
+	// trims the file, keeping at most promptHistoryLimit lines in half the
+	// size so the next trim is many appends away.
+	promptHistoryCompactBytes = 1 << 20
+)
+
+// promptHistoryEntry stores a submitted prompt with its structured tokens,
+// so recalling it restores file references as parts. Pasted images only
+// exist as inline data URLs, so they are left out and recall as their label.
+type promptHistoryEntry struct {
+	Text      string               `json:"text"`
+	Tokens    []promptHistoryToken `json:"tokens,omitempty"`
+	RepoPath  string               `json:"repoPath,omitempty"`
+	CreatedAt time.Time            `json:"createdAt"`
+}
+
+type promptHistoryToken struct {
+	Start   int                  `json:"start"`
+	End     int                  `json:"end"`
+	Display string               `json:"display"`
+	Part    messages.MessagePart `json:"part"`
+}
+
+func newPromptHistoryEntry(prompt composerPrompt, repoPath string, now time.Time) promptHistoryEntry {
+	entry := promptHistoryEntry{Text: prompt.PlainText(), RepoPath: repoPath, CreatedAt: now.UTC()}
+	for _, token := range prompt.sortedTokens() {
+		if isInlineImagePart(token.Part) {
+			continue
+		}
+		entry.Tokens = append(entry.Tokens, promptHistoryToken{Start: token.Start, End: token.End, Display: token.Display, Part: token.Part})
+	}
+	return entry
+}
+
+func isInlineImagePart(part messages.MessagePart) bool {
+	return part.File != nil && part.File.URL != nil && strings.HasPrefix(strings.TrimSpace(*part.File.URL), "data:")
+}
+
+func (e promptHistoryEntry) prompt() composerPrompt {
+	prompt := newComposerPrompt()
+	prompt.SetPlainText(e.Text)
+	for _, token := range e.Tokens {
+		prompt.AddStructuredPart(token.Start, token.End, token.Display, token.Part)
+	}
+	return prompt
+}
+
+// loadPromptHistory returns the entries of the history file newest first,
+// keeping only the latest use of each prompt text. Lines that fail to parse
+// are skipped.
+func loadPromptHistory(path string) ([]promptHistoryEntry, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries := []promptHistoryEntry{}
+	seen := map[string]struct{}{}
+	lines := bytes.Split(bytes.TrimSpace(contents), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var entry promptHistoryEntry
+		if err := json.Unmarshal(lines[i], &entry); err != nil || strings.TrimSpace(entry.Text) == "" {
+			continue
+		}
+		if _, ok := seen[entry.Text]; ok {
+			continue
+		}
+		seen[entry.Text] = struct{}{}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// appendPromptHistory adds entry to the end of the history file, rewriting
+// it with only the newest lines once it outgrows promptHistoryCompactBytes.
+func appendPromptHistory(path string, entry promptHistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() <= promptHistoryCompactBytes {
+		return nil
+	}
+	return compactPromptHistory(path)
+}
+
+func compactPromptHistory(path string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := [][]byte{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(contents)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) > 0 {
+			lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+		}
+	}
+	keep, size := 0, 0
+	for keep < len(lines) && keep < promptHistoryLimit {
+		size += len(lines[len(lines)-1-keep]) + 1
+		if size > promptHistoryCompactBytes/2 {
+			break
+		}
+		keep++
+	}
+	lines = lines[len(lines)-keep:]
+	return os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0o600)
+}
+
+func (m *sessionComposerModel) openHistoryPicker() {
+	items := make([]pickerItem, 0, len(m.history))
+	for _, entry := range m.history {
+		title := strings.Join(strings.Fields(entry.Text), " ")
+		detail := ""
+		if entry.RepoPath != "" {
+			detail = filepath.Base(entry.RepoPath)
+		}
+		items = append(items, pickerItem{Title: title, Detail: detail, Search: entry.Text})
+	}
+	m.openPicker(newListPicker(pickerKindHistory, "History", "No prompts submitted yet", items))
+}
+
+// restorePrompt replaces the composer input with a previous prompt.
+func (m *sessionComposerModel) restorePrompt(prompt composerPrompt) {
+	m.input.SetValue(prompt.PlainText())
+	m.prompt = prompt
+	m.syncPromptFromInput()
+	m.validationMessage = ""
+	m.refreshAutocomplete()
+}
@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const promptTemplatesHint = "~/.droner/prompts/ or .droner/prompts/"
+
+var promptPlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// promptTemplate is a reusable prompt read from "<name>.md". Its body may
+// reference files as @path and ask for values as {{placeholder}}.
+type promptTemplate struct {
+	Name        string
+	Description string
+	Body        string
+}
+
+// templateFill collects the placeholder values of a template one at a time
+// before it is inserted into the composer.
+type templateFill struct {
+	template promptTemplate
+	names    []string
+	values   map[string]string
+	index    int
+	value    string
+}
+
+// loadPromptTemplates reads the global templates in globalDir and those of
+// the repo in <repoRoot>/.droner/prompts. A repo template replaces a global
+// one with the same name.
+func loadPromptTemplates(globalDir string, repoRoot string) ([]promptTemplate, error) {
+	dirs := []string{globalDir}
+	if strings.TrimSpace(repoRoot) != "" {
+		dirs = append(dirs, filepath.Join(repoRoot, ".droner", "prompts"))
+	}
+	byName := map[string]promptTemplate{}
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+				continue
+			}
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			contents, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			frontmatter, body := parseSlashCommandFile(string(contents))
+			byName[name] = promptTemplate{Name: name, Description: frontmatter["description"], Body: strings.TrimSpace(body)}
+		}
+	}
+	templates := make([]promptTemplate, 0, len(byName))
+	for _, template := range byName {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i int, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// placeholders lists the distinct placeholder names of the template in the
+// order they first appear.
+func (t promptTemplate) placeholders() []string {
+	seen := map[string]struct{}{}
+	names := []string{}
+	for _, match := range promptPlaceholderPattern.FindAllStringSubmatch(t.Body, -1) {
+		if _, ok := seen[match[1]]; ok {
+			continue
+		}
+		seen[match[1]] = struct{}{}
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func (t promptTemplate) render(values map[string]string) string {
+	return promptPlaceholderPattern.ReplaceAllStringFunc(t.Body, func(match string) string {
+		name := promptPlaceholderPattern.FindStringSubmatch(match)[1]
+		return values[name]
+	})
+}
+
+func (m *sessionComposerModel) setPromptLibrary(templates []promptTemplate, history []promptHistoryEntry) {
+	m.templates = append([]promptTemplate(nil), templates...)
+	m.history = append([]promptHistoryEntry(nil), history...)
+}
+
+func (m *sessionComposerModel) openTemplatePicker() {
+	items := make([]pickerItem, 0, len(m.templates))
+	for _, template := range m.templates {
+		items = append(items, pickerItem{Title: template.Name, Detail: template.Description, Search: template.Body})
+	}
+	m.openPicker(newListPicker(pickerKindTemplate, "Templates", "No templates. Add them to "+promptTemplatesHint, items))
+}
+
+func (m *sessionComposerModel) startTemplate(template promptTemplate) {
+	fill := &templateFill{template: template, names: template.placeholders(), values: map[string]string{}}
+	if len(fill.names) == 0 {
+		m.insertPromptText(template.render(nil))
+		return
+	}
+	m.templateFill = fill
+}
+
+// handleTemplateFillKey edits the value of the current placeholder and
+// inserts the template once the last one is confirmed.
+func (m *sessionComposerModel) handleTemplateFillKey(key string, runes []rune, isText bool) {
+	fill := m.templateFill
+	switch key {
+	case "esc", "ctrl+c":
+		m.templateFill = nil
+	case "enter", "tab":
+		fill.values[fill.names[fill.index]] = fill.value
+		fill.value = ""
+		fill.index++
+		if fill.index == len(fill.names) {
+			m.templateFill = nil
+			m.insertPromptText(fill.template.render(fill.values))
+		}
+	case "backspace":
+		if current := []rune(fill.value); len(current) > 0 {
+			fill.value = string(current[:len(current)-1])
+		}
+	default:
+		if isText {
+			fill.value += string(runes)
+		}
+	}
+}
+
+func (m sessionComposerModel) templateFillView(width int) string {
+	fill := m.templateFill
+	title := sectionTitleStyle.Width(width).Render(fmt.Sprintf("Template %s  %s", fill.template.Name, helpStyle.Render(fmt.Sprintf("%d/%d", fill.index+1, len(fill.names)))))
+	line := shortcutKeyStyle.Render(fill.names[fill.index]+": ") + fill.value + promptGlyphStyle.Render("▏")
+	return lipgloss.JoinVertical(lipgloss.Left, title, sectionShellStyle.Width(width).Render(line))
+}
+
+// insertPromptText inserts text at the cursor and turns its @path
+// references to files of the repo into file parts.
+func (m *sessionComposerModel) insertPromptText(text string) {
+	start := textareaCursorIndex(m.input)
+	m.input.InsertString(text)
+	m.syncPromptFromInput()
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && !isFileRefBoundaryRune(runes[i-1])) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && isFileRefQueryRune(runes[end]) {
+			end++
+		}
+		// A reference ending a sentence keeps its period outside the path.
+		for end > i+1 && runes[end-1] == '.' {
+			end--
+		}
+		path := string(runes[i+1 : end])
+		if path != "" && m.isRepoFile(path) {
+			m.prompt.AddFileRef(start+i, start+end, path)
+		}
+		i = end - 1
+	}
+	m.validationMessage = ""
+	m.refreshAutocomplete()
+}
+
+func (m sessionComposerModel) isRepoFile(path string) bool {
+	clean := filepath.ToSlash(filepath.Clean(path))
+	for _, candidate := range m.fileCandidates {
+		if filepath.ToSlash(filepath.Clean(candidate)) == clean {
+			return true
+		}
+	}
+	return strings.TrimSpace(m.repoRoot) != "" && pathExists(filepath.Join(m.repoRoot, clean))
+}
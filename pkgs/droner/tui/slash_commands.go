@@ -1,9 +1,12 @@
 package tui
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
@@ -12,14 +15,56 @@ import (
 type slashCommand struct {
 	Name        string
 	Description string
+	// Arguments lists the placeholders the command template uses, either
+	// "$ARGUMENTS" or positional "$1", "$2", ...
+	Arguments []string
 }
 
-func loadGlobalOpencodeSlashCommands() ([]slashCommand, error) {
+var slashCommandArgumentPattern = regexp.MustCompile(`\$(ARGUMENTS|[1-9][0-9]*)`)
+
+// loadOpencodeSlashCommands merges the global opencode commands with those
+// of the repo at repoRoot, from command markdown files and the "command"
+// section of opencode.json. A repo reads its config from opencode.json at its
+// root and its command files from .opencode only. A repo command replaces a
+// global one of the same name.
+func loadOpencodeSlashCommands(repoRoot string) ([]slashCommand, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, err
 	}
-	return loadSlashCommandsFromDir(filepath.Join(homeDir, ".config", "opencode", "commands"))
+	globalDir := filepath.Join(homeDir, ".config", "opencode")
+	sources := []slashCommandSource{{config: filepath.Join(globalDir, "opencode.json"), dir: globalDir}}
+	if strings.TrimSpace(repoRoot) != "" {
+		sources = append(sources, slashCommandSource{config: filepath.Join(repoRoot, "opencode.json"), dir: filepath.Join(repoRoot, ".opencode")})
+	}
+
+	byName := map[string]slashCommand{}
+	for _, source := range sources {
+		commands, err := loadSlashCommandsFromConfig(source.config)
+		if err != nil {
+			return nil, err
+		}
+		// opencode reads both the singular and the plural directory.
+		for _, dir := range []string{"command", "commands"} {
+			fromDir, err := loadSlashCommandsFromDir(filepath.Join(source.dir, dir))
+			if err != nil {
+				return nil, err
+			}
+			commands = append(commands, fromDir...)
+		}
+		for _, command := range commands {
+			byName[command.Name] = command
+		}
+	}
+	return sortedSlashCommands(byName), nil
+}
+
+// slashCommandSource is where one opencode scope keeps its commands: the
+// config file with inline commands and the directory holding command/ and
+// commands/.
+type slashCommandSource struct {
+	config string
+	dir    string
 }
 
 func loadSlashCommandsFromDir(dir string) ([]slashCommand, error) {
@@ -39,12 +84,14 @@ func loadSlashCommandsFromDir(dir string) ([]slashCommand, error) {
 		if strings.TrimSpace(name) == "" {
 			continue
 		}
-		description := ""
+		command := slashCommand{Name: name}
 		contents, err := os.ReadFile(filepath.Join(dir, entry.Name()))
 		if err == nil {
-			description = parseSlashCommandDescription(string(contents))
+			frontmatter, body := parseSlashCommandFile(string(contents))
+			command.Description = frontmatter["description"]
+			command.Arguments = slashCommandArguments(body)
 		}
-		commands = append(commands, slashCommand{Name: name, Description: description})
+		commands = append(commands, command)
 	}
 	sort.Slice(commands, func(i int, j int) bool {
 		return commands[i].Name < commands[j].Name
@@ -52,29 +99,109 @@ func loadSlashCommandsFromDir(dir string) ([]slashCommand, error) {
 	return commands, nil
 }
 
-func parseSlashCommandDescription(contents string) string {
+// loadSlashCommandsFromConfig reads the commands defined inline in an
+// opencode.json. Files that do not parse as plain JSON are skipped, since
+// opencode itself reports those.
+func loadSlashCommandsFromConfig(path string) ([]slashCommand, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var config struct {
+		Command map[string]struct {
+			Template    string `json:"template"`
+			Description string `json:"description"`
+		} `json:"command"`
+	}
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return nil, nil
+	}
+	commands := make([]slashCommand, 0, len(config.Command))
+	for name, command := range config.Command {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		commands = append(commands, slashCommand{Name: name, Description: command.Description, Arguments: slashCommandArguments(command.Template)})
+	}
+	return commands, nil
+}
+
+func sortedSlashCommands(byName map[string]slashCommand) []slashCommand {
+	commands := make([]slashCommand, 0, len(byName))
+	for _, command := range byName {
+		commands = append(commands, command)
+	}
+	sort.Slice(commands, func(i int, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+	return commands
+}
+
+// parseSlashCommandFile splits a command file into its frontmatter keys and
+// the template body.
+func parseSlashCommandFile(contents string) (map[string]string, string) {
+	frontmatter := map[string]string{}
 	trimmed := strings.TrimSpace(contents)
 	if !strings.HasPrefix(trimmed, "---") {
-		return ""
+		return frontmatter, contents
 	}
 	lines := strings.Split(trimmed, "\n")
 	if len(lines) < 3 || strings.TrimSpace(lines[0]) != "---" {
-		return ""
+		return frontmatter, contents
 	}
-	for _, line := range lines[1:] {
+	for i, line := range lines[1:] {
 		line = strings.TrimSpace(line)
 		if line == "---" {
-			return ""
+			return frontmatter, strings.Join(lines[i+2:], "\n")
 		}
 		key, value, ok := strings.Cut(line, ":")
 		if !ok {
 			continue
 		}
-		if strings.TrimSpace(key) == "description" {
-			return strings.TrimSpace(value)
+		frontmatter[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return map[string]string{}, contents
+}
+
+func slashCommandArguments(template string) []string {
+	seen := map[string]struct{}{}
+	arguments := []string{}
+	for _, match := range slashCommandArgumentPattern.FindAllString(template, -1) {
+		if _, ok := seen[match]; ok {
+			continue
+		}
+		seen[match] = struct{}{}
+		arguments = append(arguments, match)
+	}
+	sort.SliceStable(arguments, func(i int, j int) bool {
+		return slashArgumentOrder(arguments[i]) < slashArgumentOrder(arguments[j])
+	})
+	return arguments
+}
+
+func slashArgumentOrder(argument string) int {
+	position, err := strconv.Atoi(strings.TrimPrefix(argument, "$"))
+	if err != nil {
+		return 0
+	}
+	return position
+}
+
+// argumentHint renders the arguments of a command for autocomplete, e.g.
+// "<args>" or "<1> <2>".
+func (c slashCommand) argumentHint() string {
+	hints := make([]string, 0, len(c.Arguments))
+	for _, argument := range c.Arguments {
+		if argument == "$ARGUMENTS" {
+			hints = append(hints, "<args>")
+			continue
 		}
+		hints = append(hints, "<"+strings.TrimPrefix(argument, "$")+">")
 	}
-	return ""
+	return strings.Join(hints, " ")
 }
 
 func lookupSlashCommand(rawInput string, commands []slashCommand) (slashCommand, bool) {
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var themePalettes = map[string]conf.TUIColors{
+	conf.TUIThemeDark: {
+		Background:        "#050505",
+		Section:           "#101010",
+		Input:             "#1F1F1F",
+		Accent:            "#3B82F6",
+		AccentStrong:      "#60A5FA",
+		Text:              "#E7E5E4",
+		MutedText:         "#8A8A8A",
+		Logo:              "#7A7A7A",
+		WarningBackground: "#231A0B",
+		Error:             "#FDBA74",
+		Tip:               "#F59E0B",
+		DiffAdded:         "#4ADE80",
+		DiffRemoved:       "#F87171",
+		SyntaxKeyword:     "#C084FC",
+		SyntaxString:      "#FBBF24",
+		SyntaxNumber:      "#F472B6",
+	},
+	conf.TUIThemeLight: {
+		Background:        "#FAFAF9",
+		Section:           "#EEEEEC",
+		Input:             "#FFFFFF",
+		Accent:            "#2563EB",
+		AccentStrong:      "#1D4ED8",
+		Text:              "#1C1917",
+		MutedText:         "#6B6B6B",
+		Logo:              "#8A8A8A",
+		WarningBackground: "#FFF7ED",
+		Error:             "#C2410C",
+		Tip:               "#B45309",
+		DiffAdded:         "#15803D",
+		DiffRemoved:       "#B91C1C",
+		SyntaxKeyword:     "#7E22CE",
+		SyntaxString:      "#A16207",
+		SyntaxNumber:      "#BE185D",
+	},
+	conf.TUIThemeSolarizedDark: {
+		Background:        "#002B36",
+		Section:           "#073642",
+		Input:             "#073642",
+		Accent:            "#268BD2",
+		AccentStrong:      "#2AA198",
+		Text:              "#93A1A1",
+		MutedText:         "#657B83",
+		Logo:              "#586E75",
+		WarningBackground: "#3B2F05",
+		Error:             "#CB4B16",
+		Tip:               "#B58900",
+		DiffAdded:         "#859900",
+		DiffRemoved:       "#DC322F",
+		SyntaxKeyword:     "#6C71C4",
+		SyntaxString:      "#B58900",
+		SyntaxNumber:      "#D33682",
+	},
+	conf.TUIThemeSolarizedLight: {
+		Background:        "#FDF6E3",
+		Section:           "#EEE8D5",
+		Input:             "#FDF6E3",
+		Accent:            "#268BD2",
+		AccentStrong:      "#2AA198",
+		Text:              "#586E75",
+		MutedText:         "#93A1A1",
+		Logo:              "#839496",
+		WarningBackground: "#F5E6C4",
+		Error:             "#CB4B16",
+		Tip:               "#B58900",
+		DiffAdded:         "#859900",
+		DiffRemoved:       "#DC322F",
+		SyntaxKeyword:     "#6C71C4",
+		SyntaxString:      "#B58900",
+		SyntaxNumber:      "#D33682",
+	},
+}
+
+var applyConfiguredThemeOnce sync.Once
+
+func init() {
+	applyPalette(themePalettes[conf.TUIThemeDark])
+}
+
+// applyConfiguredTheme styles the TUI with the configured theme. It must run
+// before the first program starts: auto themes ask the terminal for its
+// background color.
+func applyConfiguredTheme() {
+	applyConfiguredThemeOnce.Do(func() {
+		applyPalette(resolveTheme(conf.GetConfig().TUI.Theme, lipgloss.HasDarkBackground))
+	})
+}
+
+// resolveTheme returns the palette named by theme with its color overrides
+// applied.
+func resolveTheme(theme conf.TUITheme, hasDarkBackground func() bool) conf.TUIColors {
+	name := theme.Name
+	if name == "" || name == conf.TUIThemeAuto {
+		name = theme.Dark
+		if !hasDarkBackground() {
+			name = theme.Light
+		}
+	}
+	palette, ok := themePalettes[name]
+	if !ok {
+		palette = themePalettes[conf.TUIThemeDark]
+	}
+
+	resolved := reflect.ValueOf(&palette).Elem()
+	overrides := reflect.ValueOf(theme.Colors)
+	for i := range overrides.NumField() {
+		if color := overrides.Field(i).String(); color != "" {
+			resolved.Field(i).SetString(color)
+		}
+	}
+	return palette
+}
+
+func applyPalette(palette conf.TUIColors) {
+	appBackgroundColor = lipgloss.Color(palette.Background)
+	sectionBackgroundColor = lipgloss.Color(palette.Section)
+	textareaBackgroundColor = lipgloss.Color(palette.Input)
+	accentColor = lipgloss.Color(palette.Accent)
+	accentStrongColor = lipgloss.Color(palette.AccentStrong)
+	textColor = lipgloss.Color(palette.Text)
+	mutedTextColor = lipgloss.Color(palette.MutedText)
+	logoMutedColor = lipgloss.Color(palette.Logo)
+	warningBackgroundColor = lipgloss.Color(palette.WarningBackground)
+	errorTextColor = lipgloss.Color(palette.Error)
+	tipAccentColor = lipgloss.Color(palette.Tip)
+	diffAddedColor = lipgloss.Color(palette.DiffAdded)
+	diffRemovedColor = lipgloss.Color(palette.DiffRemoved)
+	syntaxKeyword = lipgloss.Color(palette.SyntaxKeyword)
+	syntaxString = lipgloss.Color(palette.SyntaxString)
+	syntaxNumber = lipgloss.Color(palette.SyntaxNumber)
+
+	buildComposerStyles()
+	buildDashboardStyles()
+	buildDiffStyles()
+}
@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	timelineTimeWidth  = 10
+	timelineDeltaWidth = 9
+	timelineLabelWidth = 24
+	timelineIndent     = timelineTimeWidth + timelineDeltaWidth
+)
+
+var timelineEventLabels = map[string]string{
+	"session.queued":                           "Queued",
+	"session.enrichment.requested":             "Enrichment requested",
+	"session.enrichment.succeeded":             "Enriched",
+	"session.enrichment.failed":                "Enrichment failed",
+	"session.hydration.requested":              "Hydration requested",
+	"session.environment_provisioning.started": "Provisioning",
+	"session.environment_provisioning.success": "Provisioned",
+	"session.environment_provisioning.failed":  "Provisioning failed",
+	"session.ready":                            "Ready",
+	"session.agent.busy":                       "Agent busy",
+	"session.agent.idle":                       "Agent idle",
+	"session.completion.requested":             "Completion requested",
+	"session.completion.started":               "Completing",
+	"session.completion.success":               "Completed",
+	"session.completion.failed":                "Completion failed",
+	"session.deletion.requested":               "Deletion requested",
+	"session.deletion.started":                 "Deleting",
+	"session.deletion.success":                 "Deleted",
+	"session.deletion.failed":                  "Deletion failed",
+	"session.pr.linked":                        "PR linked",
+	"session.pr.state_changed":                 "PR state changed",
+	"session.pr.ci_state_changed":              "PR checks changed",
+	"session.pr.closed":                        "PR closed",
+	"session.pr.merged":                        "PR merged",
+	"session.pr.ci_feedback.sent":              "CI feedback sent",
+	"session.pr.review_comments.added":         "Review comments",
+	"session.pr.review_feedback.sent":          "Review feedback sent",
+	"session.hook.fired":                       "Hook fired",
+	"session.webhook.delivered":                "Webhook delivered",
+	"session.webhook.failed":                   "Webhook failed",
+}
+
+type timelineLoadedMsg struct {
+	sessionID string
+	response  *schemas.SessionEventsResponse
+	err       error
+}
+
+// timelinePayload picks the fields worth showing out of every session event
+// payload; each event type only fills a few of them.
+type timelinePayload struct {
+	Branch       string   `json:"branch"`
+	WorktreePath string   `json:"worktreePath"`
+	Mode         string   `json:"mode"`
+	Harness      string   `json:"harness"`
+	PRNumber     int      `json:"prNumber"`
+	State        string   `json:"state"`
+	CIState      string   `json:"ciState"`
+	Checks       []string `json:"checks"`
+	CommentIDs   []int64  `json:"commentIds"`
+	Rule         string   `json:"rule"`
+	Actions      []string `json:"actions"`
+	Errors       []string `json:"errors"`
+	Endpoint     string   `json:"endpoint"`
+	StatusCode   int      `json:"statusCode"`
+	Attempts     int      `json:"attempts"`
+	Error        string   `json:"error"`
+}
+
+// timelineView lists the events of one session with the time spent between
+// them, expanding the errors of failed steps.
+type timelineView struct {
+	session  schemas.SessionListItem
+	response *schemas.SessionEventsResponse
+	scroll   int
+	err      string
+}
+
+func newTimelineView(session schemas.SessionListItem) *timelineView {
+	return &timelineView{session: session}
+}
+
+// handleKey reports whether the view should close.
+func (v *timelineView) handleKey(key string, pageSize int) bool {
+	switch key {
+	case "esc", "q":
+		return true
+	case "down", "j":
+		v.scroll++
+	case "up", "k":
+		v.scroll = max(v.scroll-1, 0)
+	case "ctrl+d", "pgdown", " ":
+		v.scroll += max(pageSize/2, 1)
+	case "ctrl+u", "pgup":
+		v.scroll = max(v.scroll-max(pageSize/2, 1), 0)
+	case "g":
+		v.scroll = 0
+	case "G":
+		// Clamped to the last page when rendering.
+		v.scroll = int(^uint(0) >> 1)
+	}
+	return false
+}
+
+func (v *timelineView) view(width int, height int) string {
+	if height <= 0 {
+		height = diffDefaultHeight
+	}
+	header := brandStyle.Render("timeline ") + subtitleStyle.Render(sessionBranch(v.session)+"  "+string(v.session.State))
+	if v.response != nil && len(v.response.Events) > 0 {
+		events := v.response.Events
+		total := events[len(events)-1].OccurredAt.Sub(events[0].OccurredAt)
+		header += "   " + subtitleStyle.Render(fmt.Sprintf("%d events over %s", len(events), formatTimelineDuration(total)))
+	}
+	help := strings.Join([]string{
+		shortcutKeyStyle.Render("j/k") + " " + shortcutLabelStyle.Render("scroll"),
+		shortcutKeyStyle.Render("g/G") + " " + shortcutLabelStyle.Render("top/bottom"),
+		shortcutKeyStyle.Render("esc") + " " + shortcutLabelStyle.Render("back"),
+	}, "   ")
+
+	bodyHeight := max(height-4, 3)
+	var body string
+	switch {
+	case v.err != "":
+		body = validationStyle.Render(v.err)
+	case v.response == nil:
+		body = helpStyle.Render("Loading events...")
+	default:
+		lines := renderTimelineLines(v.response.Events, width)
+		v.scroll = min(v.scroll, max(len(lines)-bodyHeight, 0))
+		end := min(v.scroll+bodyHeight, len(lines))
+		clip := lipgloss.NewStyle().MaxWidth(width)
+		visible := make([]string, 0, end-v.scroll)
+		for _, line := range lines[v.scroll:end] {
+			visible = append(visible, clip.Render(line))
+		}
+		body = lipgloss.NewStyle().Height(bodyHeight).MaxHeight(bodyHeight).Render(strings.Join(visible, "\n"))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", body, help)
+}
+
+// renderTimelineLines renders one line per event followed by the expanded
+// errors of failures, wrapped to width.
+func renderTimelineLines(events []schemas.SessionEvent, width int) []string {
+	lines := []string{}
+	detailWidth := max(width-timelineIndent, 20)
+	for i, evt := range events {
+		delta := ""
+		if i > 0 {
+			delta = "+" + formatTimelineDuration(evt.OccurredAt.Sub(events[i-1].OccurredAt))
+		}
+		var payload timelinePayload
+		_ = json.Unmarshal(evt.Payload, &payload)
+
+		label := timelineEventLabels[evt.Type]
+		if label == "" {
+			label = evt.Type
+		}
+		failed := strings.HasSuffix(evt.Type, ".failed")
+		labelText := fitColumn(label, timelineLabelWidth)
+		switch {
+		case failed:
+			labelText = dashboardFailedStyle.Render(labelText)
+		case evt.Type == "session.agent.busy":
+			labelText = dashboardBusyStyle.Render(labelText)
+		}
+		line := helpStyle.Render(fitColumn(evt.OccurredAt.Local().Format("15:04:05"), timelineTimeWidth)) +
+			subtitleStyle.Render(fitColumn(delta, timelineDeltaWidth)) +
+			labelText + timelineDetail(evt.Type, payload)
+		lines = append(lines, line)
+
+		errorText := evt.Error
+		if errorText == "" && failed {
+			errorText = payload.Error
+		}
+		if errorText != "" {
+			lines = append(lines, indentTimelineBlock("error: "+errorText, detailWidth, dashboardFailedStyle)...)
+		}
+		// Backends currently repeat the error as their details; only show
+		// them when they add something.
+		if evt.BackendDetails != "" && evt.BackendDetails != evt.Error {
+			lines = append(lines, indentTimelineBlock("backend: "+evt.BackendDetails, detailWidth, helpStyle)...)
+		}
+		for _, hookErr := range payload.Errors {
+			lines = append(lines, indentTimelineBlock("error: "+hookErr, detailWidth, dashboardFailedStyle)...)
+		}
+	}
+	return lines
+}
+
+func timelineDetail(eventType string, payload timelinePayload) string {
+	parts := []string{}
+	switch {
+	case payload.Rule != "":
+		parts = append(parts, "rule "+payload.Rule)
+		if len(payload.Actions) > 0 {
+			parts = append(parts, strings.Join(payload.Actions, ", "))
+		}
+	case payload.Endpoint != "":
+		parts = append(parts, payload.Endpoint)
+		if payload.StatusCode > 0 {
+			parts = append(parts, fmt.Sprintf("HTTP %d", payload.StatusCode))
+		}
+		if payload.Attempts > 1 {
+			parts = append(parts, fmt.Sprintf("%d attempts", payload.Attempts))
+		}
+	case payload.PRNumber > 0:
+		parts = append(parts, fmt.Sprintf("#%d", payload.PRNumber))
+		if eventType != "session.pr.ci_state_changed" && payload.State != "" {
+			parts = append(parts, payload.State)
+		}
+		if payload.CIState != "" {
+			parts = append(parts, "checks "+payload.CIState)
+		}
+		if len(payload.Checks) > 0 {
+			parts = append(parts, strings.Join(payload.Checks, ", "))
+		}
+		if len(payload.CommentIDs) > 0 {
+			parts = append(parts, fmt.Sprintf("%d comments", len(payload.CommentIDs)))
+		}
+	default:
+		if payload.Harness != "" {
+			parts = append(parts, payload.Harness)
+		}
+		if payload.Branch != "" {
+			parts = append(parts, payload.Branch)
+		}
+		if payload.Mode != "" && payload.Mode != "initial" {
+			parts = append(parts, payload.Mode)
+		}
+		if payload.WorktreePath != "" {
+			parts = append(parts, payload.WorktreePath)
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+func indentTimelineBlock(text string, width int, style lipgloss.Style) []string {
+	wrapped := lipgloss.NewStyle().Width(width).Render(strings.TrimRight(text, "\n"))
+	lines := strings.Split(wrapped, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Repeat(" ", timelineIndent) + style.Render(strings.TrimRight(line, " "))
+	}
+	return lines
+}
+
+func formatTimelineDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Oudwins/droner/pkgs/droner/internals/cliutil"
 	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
+	"github.com/Oudwins/droner/pkgs/droner/internals/env"
 	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
 	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
 	"github.com/Oudwins/droner/pkgs/droner/internals/timeouts"
@@ -28,89 +30,115 @@ const (
 )
 
 var (
-	appBackgroundColor      = lipgloss.Color("#050505")
-	panelBackgroundColor    = lipgloss.Color("#1C1C1C")
-	sectionBackgroundColor  = lipgloss.Color("#101010")
-	textareaBackgroundColor = lipgloss.Color("#1F1F1F")
-	borderColor             = lipgloss.Color("#2D2D2D")
-	accentColor             = lipgloss.Color("#3B82F6")
-	accentStrongColor       = lipgloss.Color("#60A5FA")
-	textColor               = lipgloss.Color("#E7E5E4")
-	mutedTextColor          = lipgloss.Color("#8A8A8A")
-	logoMutedColor          = lipgloss.Color("#7A7A7A")
-	warningBackgroundColor  = lipgloss.Color("#231A0B")
-	errorTextColor          = lipgloss.Color("#FDBA74")
-	tipAccentColor          = lipgloss.Color("#F59E0B")
+	appBackgroundColor      lipgloss.Color
+	sectionBackgroundColor  lipgloss.Color
+	textareaBackgroundColor lipgloss.Color
+	accentColor             lipgloss.Color
+	accentStrongColor       lipgloss.Color
+	textColor               lipgloss.Color
+	mutedTextColor          lipgloss.Color
+	logoMutedColor          lipgloss.Color
+	warningBackgroundColor  lipgloss.Color
+	errorTextColor          lipgloss.Color
+	tipAccentColor          lipgloss.Color
+
+	appStyle             lipgloss.Style
+	panelStyle           lipgloss.Style
+	repoBadgeStyle       lipgloss.Style
+	brandMutedStyle      lipgloss.Style
+	brandStyle           lipgloss.Style
+	subtitleStyle        lipgloss.Style
+	sectionTitleStyle    lipgloss.Style
+	sectionShellStyle    lipgloss.Style
+	inputCardStyle       lipgloss.Style
+	inputShellStyle      lipgloss.Style
+	inputMetaStyle       lipgloss.Style
+	helpStyle            lipgloss.Style
+	validationStyle      lipgloss.Style
+	imageMarkerStyle     lipgloss.Style
+	promptGlyphStyle     lipgloss.Style
+	attachmentLabelStyle lipgloss.Style
+	agentChipStyle       lipgloss.Style
+	activeAgentChipStyle lipgloss.Style
+	metaLabelStyle       lipgloss.Style
+	selectionStyle       lipgloss.Style
+	shortcutKeyStyle     lipgloss.Style
+	shortcutLabelStyle   lipgloss.Style
+	tipBulletStyle       lipgloss.Style
+	tipLabelStyle        lipgloss.Style
+)
 
+// buildComposerStyles derives the composer styles from the palette colors.
+func buildComposerStyles() {
 	appStyle = lipgloss.NewStyle().
-			Background(appBackgroundColor).
-			Foreground(textColor)
+		Background(appBackgroundColor).
+		Foreground(textColor)
 	panelStyle = lipgloss.NewStyle().
-			Padding(0, 0)
+		Padding(0, 0)
 	repoBadgeStyle = lipgloss.NewStyle().
-			Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	brandMutedStyle = lipgloss.NewStyle().
-			Foreground(logoMutedColor).
-			Bold(true)
+		Foreground(logoMutedColor).
+		Bold(true)
 	brandStyle = lipgloss.NewStyle().
-			Foreground(textColor).
-			Bold(true)
+		Foreground(textColor).
+		Bold(true)
 	subtitleStyle = lipgloss.NewStyle().
-			Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	sectionTitleStyle = lipgloss.NewStyle().
-				Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	sectionShellStyle = lipgloss.NewStyle().
-				Background(sectionBackgroundColor).
-				Padding(0, 1)
+		Background(sectionBackgroundColor).
+		Padding(0, 1)
 	inputCardStyle = lipgloss.NewStyle().
-			Padding(0, 0)
+		Padding(0, 0)
 	inputShellStyle = lipgloss.NewStyle().
-			Background(textareaBackgroundColor).
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(accentColor).
-			BorderLeft(true).
-			BorderTop(false).
-			BorderRight(false).
-			BorderBottom(false).
-			Padding(0, 1, 0, 1)
+		Background(textareaBackgroundColor).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(accentColor).
+		BorderLeft(true).
+		BorderTop(false).
+		BorderRight(false).
+		BorderBottom(false).
+		Padding(0, 1, 0, 1)
 	inputMetaStyle = lipgloss.NewStyle().
-			Foreground(mutedTextColor).
-			Padding(0, 1)
+		Foreground(mutedTextColor).
+		Padding(0, 1)
 	helpStyle = lipgloss.NewStyle().
-			Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	validationStyle = lipgloss.NewStyle().
-			Foreground(errorTextColor).
-			Background(warningBackgroundColor).
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(errorTextColor).
-			Padding(0, 1)
+		Foreground(errorTextColor).
+		Background(warningBackgroundColor).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(errorTextColor).
+		Padding(0, 1)
 	imageMarkerStyle = lipgloss.NewStyle().
-				Foreground(accentColor).
-				Bold(true)
+		Foreground(accentColor).
+		Bold(true)
 	promptGlyphStyle = lipgloss.NewStyle().
-				Foreground(accentColor)
+		Foreground(accentColor)
 	attachmentLabelStyle = lipgloss.NewStyle().
-				Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	agentChipStyle = lipgloss.NewStyle().
-			Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	activeAgentChipStyle = lipgloss.NewStyle().
-				Foreground(accentStrongColor)
+		Foreground(accentStrongColor)
 	metaLabelStyle = lipgloss.NewStyle().
-			Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	selectionStyle = lipgloss.NewStyle().
-			Foreground(textColor).
-			Background(sectionBackgroundColor).
-			Bold(true)
+		Foreground(textColor).
+		Background(sectionBackgroundColor).
+		Bold(true)
 	shortcutKeyStyle = lipgloss.NewStyle().
-				Foreground(textColor)
+		Foreground(textColor)
 	shortcutLabelStyle = lipgloss.NewStyle().
-				Foreground(mutedTextColor)
+		Foreground(mutedTextColor)
 	tipBulletStyle = lipgloss.NewStyle().
-			Foreground(tipAccentColor).
-			Bold(true)
+		Foreground(tipAccentColor).
+		Bold(true)
 	tipLabelStyle = lipgloss.NewStyle().
-			Foreground(tipAccentColor)
-)
+		Foreground(tipAccentColor)
+}
 
 type sessionComposerModel struct {
 	input               textarea.Model
@@ -120,6 +148,13 @@ type sessionComposerModel struct {
 	slashCommands       []slashCommand
 	agentNames          []string
 	selectedAgentIndex  int
+	models              []schemas.OpencodeModel
+	selectedModel       string
+	keys                composerKeymap
+	picker              *listPicker
+	templates           []promptTemplate
+	templateFill        *templateFill
+	history             []promptHistoryEntry
 	autocompleteActive  bool
 	autocompleteQuery   autocompleteQuery
 	autocompleteResults []autocompleteResult
@@ -136,52 +171,108 @@ type sessionComposerModel struct {
 	pasteFallbackValue  string
 }
 
+// Run composes and creates a new session. Without a repo path the project
+// and branch are picked first.
 func Run(client *sdk.Client, repoPath string, branch string) error {
+	applyConfiguredTheme()
+	if repoPath == "" {
+		var err error
+		repoPath, branch, err = pickProject(client)
+		if err != nil || repoPath == "" {
+			return err
+		}
+	}
+	response, err := composeSession(client, repoPath, branch)
+	if err != nil || response == nil {
+		return err
+	}
+	cliutil.PrintSessionCreated(response)
+	return nil
+}
+
+// composeSession runs the composer and creates the session it describes. It
+// returns a nil response when the composer is cancelled.
+func composeSession(client *sdk.Client, repoPath string, branch string) (*schemas.SessionCreateResponse, error) {
 	fileCandidates, err := loadRepoFileCandidates(repoPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	slashCommands, err := loadGlobalOpencodeSlashCommands()
+	slashCommands, err := loadOpencodeSlashCommands(repoPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	agentNames := conf.GetConfig().TUI.AgentNames
-	prompt, rawInput, agentName, submitted, err := runSessionComposer(repoPath, fileCandidates, slashCommands, agentNames)
+	config := conf.GetConfig()
+	catalog := loadOpencodeCatalog(client, repoPath)
+	agentNames := composerAgentNames(config.TUI.AgentNames, catalog)
+	var models []schemas.OpencodeModel
+	if catalog != nil {
+		models = catalog.Models
+	}
+	dataDir := filepath.Clean(env.Get().DATA_DIR)
+	templates, err := loadPromptTemplates(filepath.Join(dataDir, "prompts"), repoPath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	historyPath := filepath.Join(dataDir, promptHistoryFile)
+	history, err := loadPromptHistory(historyPath)
+	if err != nil {
+		return nil, err
 	}
-	if !submitted {
-		return nil
+	result, err := runSessionComposer(repoPath, fileCandidates, slashCommands, agentNames, models, config.Sessions.Harness.DefaultModel(), templates, history)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	// History is recorded before the session is created so a prompt is not
+	// lost when creating fails.
+	if err := appendPromptHistory(historyPath, result.history); err != nil {
+		return nil, err
 	}
 	if err := cliutil.EnsureDaemonRunning(client); err != nil {
-		return err
+		return nil, err
 	}
-	request := buildSessionCreateRequest(repoPath, branch, agentName, rawInput, prompt, slashCommands)
+	request := buildSessionCreateRequest(repoPath, branch, result.agentName, result.model, result.rawInput, result.prompt, slashCommands)
 	ctx, cancel := context.WithTimeout(context.Background(), timeouts.SecondLong)
 	defer cancel()
-	response, err := client.CreateSession(ctx, request)
-	if err != nil {
-		return err
-	}
-	cliutil.PrintSessionCreated(response)
-	return nil
+	return client.CreateSession(ctx, request)
+}
+
+type composerResult struct {
+	prompt    *messages.Message
+	rawInput  string
+	agentName string
+	model     string
+	history   promptHistoryEntry
 }
 
-func runSessionComposer(repoRoot string, fileCandidates []string, slashCommands []slashCommand, agentNames []string) (*messages.Message, string, string, bool, error) {
+// runSessionComposer returns nil when the composer is cancelled.
+func runSessionComposer(repoRoot string, fileCandidates []string, slashCommands []slashCommand, agentNames []string, models []schemas.OpencodeModel, selectedModel string, templates []promptTemplate, history []promptHistoryEntry) (*composerResult, error) {
 	model := newSessionComposerModelWithCommands(repoRoot, fileCandidates, slashCommands, agentNames)
+	model.setModels(models, selectedModel)
+	model.setPromptLibrary(templates, history)
+	model.keys = newComposerKeymap(conf.GetConfig().TUI.Keymap)
 	program := tea.NewProgram(model, tea.WithAltScreen())
 	result, err := program.Run()
 	if err != nil {
-		return nil, "", "", false, err
+		return nil, err
 	}
 	finalModel, ok := result.(sessionComposerModel)
 	if !ok {
-		return nil, "", "", false, nil
+		return nil, nil
+	}
+	prompt, rawInput, agentName, submitted, err := extractComposerResult(finalModel)
+	if err != nil || !submitted {
+		return nil, err
 	}
-	return extractComposerResult(finalModel)
+	return &composerResult{
+		prompt:    prompt,
+		rawInput:  rawInput,
+		agentName: agentName,
+		model:     finalModel.selectedModel,
+		history:   newPromptHistoryEntry(finalModel.prompt, repoRoot, time.Now()),
+	}, nil
 }
 
-func buildSessionCreateRequest(path string, branch string, agentName string, rawInput string, prompt *messages.Message, slashCommands []slashCommand) schemas.SessionCreateRequest {
+func buildSessionCreateRequest(path string, branch string, agentName string, model string, rawInput string, prompt *messages.Message, slashCommands []slashCommand) schemas.SessionCreateRequest {
 	request := schemas.SessionCreateRequest{Path: path, Branch: schemas.NewSBranch(branch)}
 	command := commandInvocationFromPrompt(rawInput, prompt, slashCommands)
 	if !messageHasContent(prompt) && (command == nil || !command.HasContent()) {
@@ -194,6 +285,7 @@ func buildSessionCreateRequest(path string, branch string, agentName string, raw
 		config.Message = messages.CloneMessage(prompt)
 	}
 	request.AgentConfig = &schemas.SessionAgentConfig{
+		Model:     strings.TrimSpace(model),
 		AgentName: config.AgentName,
 		Message:   config.Message,
 		Command:   config.Command,
@@ -239,6 +331,9 @@ func newSessionComposerModelWithCommands(repoRoot string, fileCandidates []strin
 	input.SetHeight(composerTextareaRows)
 	input.FocusedStyle = focusedStyle
 	input.BlurredStyle = blurredStyle
+	// Newlines are inserted by the composer's own binding so that enter can
+	// be bound to submit or newline.
+	input.KeyMap.InsertNewline.SetEnabled(false)
 	input.Focus()
 
 	model := sessionComposerModel{
@@ -248,6 +343,7 @@ func newSessionComposerModelWithCommands(repoRoot string, fileCandidates []strin
 		fileCandidates:     append([]string(nil), fileCandidates...),
 		slashCommands:      append([]slashCommand(nil), slashCommands...),
 		agentNames:         append([]string(nil), agentNames...),
+		keys:               defaultComposerKeymap(),
 		width:              defaultPanelWidth,
 		height:             composerTextareaRows + 8,
 		readClipboardImage: defaultReadClipboardImage,
@@ -271,18 +367,38 @@ func (m sessionComposerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case tea.KeyMsg:
 		m.syncPromptFromInput()
+		if m.templateFill != nil {
+			m.handleTemplateFillKey(msg.String(), msg.Runes, msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace)
+			return m, nil
+		}
+		if m.picker != nil {
+			m.handlePickerKey(msg)
+			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+o":
+			m.openModelPicker()
+			return m, nil
+		case "ctrl+t":
+			m.openTemplatePicker()
+			return m, nil
+		case "ctrl+r":
+			m.openHistoryPicker()
+			return m, nil
+		}
+		key := msg.String()
 		if m.autocompleteActive {
-			switch msg.String() {
-			case "esc":
+			switch {
+			case key == "esc":
 				m.clearAutocomplete()
 				return m, nil
-			case "up", "ctrl+p":
+			case m.keys.autocompleteUp.matches(key):
 				m.moveAutocomplete(-1)
 				return m, nil
-			case "down", "ctrl+n":
+			case m.keys.autocompleteDown.matches(key):
 				m.moveAutocomplete(1)
 				return m, nil
-			case "tab", "enter":
+			case m.keys.autocompleteAccept.matches(key):
 				if m.applyAutocompleteSelection() {
 					return m, nil
 				}
@@ -290,27 +406,26 @@ func (m sessionComposerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
-		switch msg.String() {
-		case "ctrl+c", "esc":
+		switch {
+		case key == "ctrl+c" || key == "esc":
 			m.cancelled = true
 			return m, tea.Quit
-		case "enter":
+		case m.keys.submit.matches(key):
 			if m.prompt.IsEmpty() {
 				m.validationMessage = validationEmptyPrompt
 				return m, nil
 			}
 			m.submitted = true
 			return m, tea.Quit
-		case "alt+enter", "ctrl+j":
+		case m.keys.newline.matches(key):
 			m.input.InsertString("\n")
 			m.syncPromptFromInput()
 			m.validationMessage = ""
 			return m, nil
-		case "tab":
+		case m.keys.cycleAgent.matches(key):
 			m.cycleAgent(1)
 			return m, nil
-		}
-		if msg.Type == tea.KeyCtrlV {
+		case m.keys.pasteImage.matches(key):
 			handled, cmd := m.handleClipboardPaste()
 			if handled {
 				return m, cmd
@@ -344,6 +459,11 @@ func (m sessionComposerModel) View() string {
 	if attachmentView := m.imageAttachmentView(panelInnerWidth); attachmentView != "" {
 		sections = append(sections, attachmentView)
 	}
+	if m.templateFill != nil {
+		sections = append(sections, m.templateFillView(panelInnerWidth))
+	} else if m.picker != nil {
+		sections = append(sections, m.picker.view(panelInnerWidth))
+	}
 	if autocompleteView := m.autocompleteView(panelInnerWidth); autocompleteView != "" {
 		sections = append(sections, autocompleteView)
 	}
@@ -403,6 +523,32 @@ func (m sessionComposerModel) selectedAgentName() string {
 	return m.agentNames[m.selectedAgentIndex]
 }
 
+func (m *sessionComposerModel) openPicker(picker *listPicker) {
+	m.clearAutocomplete()
+	picker.keys = m.keys
+	m.picker = picker
+}
+
+func (m *sessionComposerModel) handlePickerKey(msg tea.KeyMsg) {
+	closed, item := m.picker.handleKey(msg)
+	if !closed {
+		return
+	}
+	kind := m.picker.kind
+	m.picker = nil
+	if item < 0 {
+		return
+	}
+	switch kind {
+	case pickerKindModel:
+		m.selectedModel = m.models[item].ID
+	case pickerKindTemplate:
+		m.startTemplate(m.templates[item])
+	case pickerKindHistory:
+		m.restorePrompt(m.history[item].prompt())
+	}
+}
+
 func (m *sessionComposerModel) refreshAutocomplete() {
 	query, ok := detectAutocompleteQuery(m.input.Value(), textareaCursorIndex(m.input))
 	if !ok {
@@ -574,7 +720,7 @@ func (m sessionComposerModel) renderInputSection(width int) string {
 		Render(m.renderInputView())
 	content := inputShellStyle.Width(width).Render(inputBody)
 	agentSummary := inputMetaStyle.Width(width).Render(metaLabelStyle.Render(fmt.Sprintf("Agent: %s", m.selectedAgentName())))
-	metaLine := lipgloss.JoinHorizontal(lipgloss.Left, m.agentTabsView(), "   ", subtitleStyle.Render("GPT-5.4 OpenAI"))
+	metaLine := lipgloss.JoinHorizontal(lipgloss.Left, m.agentTabsView(), "   ", subtitleStyle.Render(m.selectedModelLabel()))
 	meta := inputMetaStyle.Width(width).Render(metaLine)
 	return inputCardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, content, agentSummary, meta))
 }
@@ -643,7 +789,14 @@ func (m sessionComposerModel) autocompleteView(width int) string {
 			prefix = "> "
 			style = selectionStyle
 		}
-		lines = append(lines, style.Width(width).Render(prefix+result.Value))
+		line := prefix + result.Value
+		if result.Hint != "" {
+			line += " " + result.Hint
+		}
+		if result.Description != "" {
+			line += "  " + result.Description
+		}
+		lines = append(lines, style.Width(width).Render(fitColumn(line, max(width-2, 4))))
 	}
 	title := "Files"
 	if m.autocompleteQuery.Mode == autocompleteModeCommand {
@@ -658,12 +811,15 @@ func (m sessionComposerModel) autocompleteView(width int) string {
 
 func (m sessionComposerModel) helpView(width int) string {
 	items := []string{
-		shortcutKeyStyle.Render("Tab") + " " + shortcutLabelStyle.Render("agent"),
+		shortcutKeyStyle.Render(m.keys.cycleAgent.help()) + " " + shortcutLabelStyle.Render("agent"),
+		shortcutKeyStyle.Render("ctrl+o") + " " + shortcutLabelStyle.Render("model"),
+		shortcutKeyStyle.Render("ctrl+t") + " " + shortcutLabelStyle.Render("template"),
+		shortcutKeyStyle.Render("ctrl+r") + " " + shortcutLabelStyle.Render("history"),
 		shortcutKeyStyle.Render("/") + " " + shortcutLabelStyle.Render("command"),
 		shortcutKeyStyle.Render("@") + " " + shortcutLabelStyle.Render("file ref"),
-		shortcutKeyStyle.Render("ctrl+v") + " " + shortcutLabelStyle.Render("paste"),
-		shortcutKeyStyle.Render("enter") + " " + shortcutLabelStyle.Render("submit"),
-		shortcutKeyStyle.Render("alt+enter") + " " + shortcutLabelStyle.Render("newline"),
+		shortcutKeyStyle.Render(m.keys.pasteImage.help()) + " " + shortcutLabelStyle.Render("paste"),
+		shortcutKeyStyle.Render(m.keys.submit.help()) + " " + shortcutLabelStyle.Render("submit"),
+		shortcutKeyStyle.Render(m.keys.newline.help()) + " " + shortcutLabelStyle.Render("newline"),
 		shortcutKeyStyle.Render("esc") + " " + shortcutLabelStyle.Render("cancel"),
 	}
 	return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(strings.Join(items, "   "))
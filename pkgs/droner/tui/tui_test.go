@@ -1,12 +1,16 @@
 package tui
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Oudwins/droner/pkgs/droner/internals/conf"
 	"github.com/Oudwins/droner/pkgs/droner/internals/messages"
+	"github.com/Oudwins/droner/pkgs/droner/internals/schemas"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -17,7 +21,7 @@ func TestBuildSessionCreateRequestPreservesMultilinePrompt(t *testing.T) {
 			messages.NewTextPart("first line\n\nsecond line\n"),
 		},
 	}
-	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "first line\n\nsecond line\n", prompt, nil)
+	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "", "first line\n\nsecond line\n", prompt, nil)
 
 	if request.Path != "/tmp/repo" {
 		t.Fatalf("expected path to be preserved, got %q", request.Path)
@@ -51,7 +55,7 @@ func TestBuildSessionCreateRequestPreservesFileParts(t *testing.T) {
 			messages.NewFilePart("pkgs/droner/tui/tui.go"),
 		},
 	}
-	request := buildSessionCreateRequest("/tmp/repo", "", "build", "inspect @pkgs/droner/tui/tui.go", prompt, nil)
+	request := buildSessionCreateRequest("/tmp/repo", "", "build", "", "inspect @pkgs/droner/tui/tui.go", prompt, nil)
 
 	if request.AgentConfig == nil || request.AgentConfig.Message == nil {
 		t.Fatal("expected agent message to be included")
@@ -75,7 +79,7 @@ func TestBuildSessionCreateRequestPreservesInlineImageParts(t *testing.T) {
 			messages.NewDataURLFilePart("image/png", "pasted-image-1.png", "data:image/png;base64,ZmFrZQ=="),
 		},
 	}
-	request := buildSessionCreateRequest("/tmp/repo", "", "build", "[Image 1]", prompt, nil)
+	request := buildSessionCreateRequest("/tmp/repo", "", "build", "", "[Image 1]", prompt, nil)
 
 	if request.AgentConfig == nil || request.AgentConfig.Message == nil {
 		t.Fatal("expected agent message to be included")
@@ -93,7 +97,7 @@ func TestBuildSessionCreateRequestPreservesInlineImageParts(t *testing.T) {
 }
 
 func TestBuildSessionCreateRequestOmitsAgentConfigForEmptyPrompt(t *testing.T) {
-	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "  \n\t  ", &messages.Message{
+	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "", "  \n\t  ", &messages.Message{
 		Role:  messages.MessageRoleUser,
 		Parts: []messages.MessagePart{messages.NewTextPart("  \n\t  ")},
 	}, nil)
@@ -199,7 +203,7 @@ func TestSessionComposerEscCancels(t *testing.T) {
 }
 
 func TestBuildSessionCreateRequestIncludesBranch(t *testing.T) {
-	request := buildSessionCreateRequest("/tmp/repo", "feature/test", "plan", "hello", &messages.Message{
+	request := buildSessionCreateRequest("/tmp/repo", "feature/test", "plan", "", "hello", &messages.Message{
 		Role:  messages.MessageRoleUser,
 		Parts: []messages.MessagePart{messages.NewTextPart("hello")},
 	}, nil)
@@ -218,7 +222,7 @@ func TestBuildSessionCreateRequestUsesKnownSlashCommands(t *testing.T) {
 			messages.NewDataURLFilePart("image/png", "shot.png", "data:image/png;base64,ZmFrZQ=="),
 		},
 	}
-	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "/review check this @README.md [Image 1]", prompt, []slashCommand{{Name: "review", Description: "Review a change"}})
+	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "", "/review check this @README.md [Image 1]", prompt, []slashCommand{{Name: "review", Description: "Review a change"}})
 
 	if request.AgentConfig == nil {
 		t.Fatal("expected agent config")
@@ -245,7 +249,7 @@ func TestBuildSessionCreateRequestUsesKnownSlashCommands(t *testing.T) {
 
 func TestBuildSessionCreateRequestLeavesUnknownSlashAsMessage(t *testing.T) {
 	prompt := &messages.Message{Role: messages.MessageRoleUser, Parts: []messages.MessagePart{messages.NewTextPart("/unknown do not special case")}}
-	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "/unknown do not special case", prompt, []slashCommand{{Name: "review"}})
+	request := buildSessionCreateRequest("/tmp/repo", "", "plan", "", "/unknown do not special case", prompt, []slashCommand{{Name: "review"}})
 
 	if request.AgentConfig == nil || request.AgentConfig.Message == nil {
 		t.Fatal("expected normal message payload")
@@ -591,3 +595,449 @@ func TestSessionComposerViewShowsInlineImageFeedback(t *testing.T) {
 		t.Fatalf("view = %q, want image marker", view)
 	}
 }
+
+func TestLoadOpencodeSlashCommandsPrefersRepoCommands(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+	files := map[string]string{
+		filepath.Join(home, ".config", "opencode", "command", "review.md"):  "---\ndescription: Global review\n---\nReview $ARGUMENTS",
+		filepath.Join(home, ".config", "opencode", "commands", "deploy.md"): "---\ndescription: Deploy\n---\nDeploy $2 to $1",
+		filepath.Join(repo, ".opencode", "command", "review.md"):            "---\ndescription: Repo review\n---\nReview the diff",
+		filepath.Join(repo, "opencode.json"):                                `{"command":{"lint":{"template":"Lint $ARGUMENTS","description":"Run lints"}}}`,
+		filepath.Join(home, ".config", "opencode", "opencode.json"):         `{"command":{"lint":{"template":"Lint everything"}}}`,
+		filepath.Join(repo, ".opencode", "commands", "not-a-command.txt"):   "ignored",
+		// opencode does not look for commands in these places.
+		filepath.Join(repo, "command", "stray.md"):         "Not a command",
+		filepath.Join(repo, "commands", "stray-plural.md"): "Not a command",
+		filepath.Join(repo, ".opencode", "opencode.json"):  `{"command":{"nested":{"template":"Not a command"}}}`,
+	}
+	for path, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	commands, err := loadOpencodeSlashCommands(repo)
+	if err != nil {
+		t.Fatalf("loadOpencodeSlashCommands: %v", err)
+	}
+	if len(commands) != 3 || commands[0].Name != "deploy" || commands[1].Name != "lint" || commands[2].Name != "review" {
+		t.Fatalf("commands = %#v, want deploy, lint and review", commands)
+	}
+	if commands[2].Description != "Repo review" || len(commands[2].Arguments) != 0 {
+		t.Fatalf("review = %#v, want the repo command", commands[2])
+	}
+	if commands[1].Description != "Run lints" || commands[1].argumentHint() != "<args>" {
+		t.Fatalf("lint = %#v, want the repo opencode.json command", commands[1])
+	}
+	if got := commands[0].argumentHint(); got != "<1> <2>" {
+		t.Fatalf("deploy hint = %q, want positional arguments in order", got)
+	}
+}
+
+func TestSessionComposerAutocompleteShowsCommandArgumentsAndDescription(t *testing.T) {
+	model := newSessionComposerModelWithCommands("", nil, []slashCommand{{Name: "review", Description: "Review a change", Arguments: []string{"$ARGUMENTS"}}}, []string{"build"})
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updated.(sessionComposerModel)
+	model.input.SetValue("/rev")
+	model.syncPromptFromInput()
+	model.refreshAutocomplete()
+
+	if view := model.View(); !strings.Contains(view, "review <args>  Review a change") {
+		t.Fatalf("autocomplete is missing the command hint:\n%s", view)
+	}
+}
+
+func TestComposerAgentNamesPreferConfiguredOrderOfDiscoveredAgents(t *testing.T) {
+	catalog := &schemas.OpencodeCatalogResponse{Agents: []schemas.OpencodeAgent{{Name: "build"}, {Name: "plan"}, {Name: "review"}}}
+	got := composerAgentNames([]string{"plan", "missing", "build"}, catalog)
+	if strings.Join(got, ",") != "plan,build,review" {
+		t.Fatalf("agents = %q, want plan,build,review", got)
+	}
+	if got := composerAgentNames([]string{"build", "plan"}, nil); strings.Join(got, ",") != "build,plan" {
+		t.Fatalf("agents without a catalog = %q, want the configured ones", got)
+	}
+}
+
+func TestSessionComposerModelPickerSelectsModel(t *testing.T) {
+	model := newSessionComposerModel("", nil, []string{"build"})
+	model.setModels([]schemas.OpencodeModel{
+		{ID: "anthropic/claude-sonnet", Name: "Claude Sonnet", ProviderName: "Anthropic"},
+		{ID: "openai/gpt-5.5", Name: "GPT-5.5", ProviderName: "OpenAI"},
+	}, "openai/gpt-5.5")
+	if got := model.selectedModelLabel(); got != "GPT-5.5 OpenAI" {
+		t.Fatalf("label = %q, want GPT-5.5 OpenAI", got)
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	model = updated.(sessionComposerModel)
+	if model.picker == nil || model.picker.kind != pickerKindModel || model.picker.index != 1 {
+		t.Fatalf("picker = %#v, want the model picker open on the selected model", model.picker)
+	}
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("son")}, {Type: tea.KeyEnter}} {
+		updated, _ = model.Update(key)
+		model = updated.(sessionComposerModel)
+	}
+	if model.picker != nil || model.selectedModel != "anthropic/claude-sonnet" {
+		t.Fatalf("picker = %#v model = %q, want claude-sonnet selected", model.picker, model.selectedModel)
+	}
+	if model.input.Value() != "" {
+		t.Fatalf("picker keys leaked into the prompt: %q", model.input.Value())
+	}
+
+	request := buildSessionCreateRequest("/tmp/repo", "", "build", model.selectedModel, "hi", &messages.Message{Role: messages.MessageRoleUser, Parts: []messages.MessagePart{messages.NewTextPart("hi")}}, nil)
+	if request.AgentConfig == nil || request.AgentConfig.Model != "anthropic/claude-sonnet" {
+		t.Fatalf("agent config = %#v, want the picked model", request.AgentConfig)
+	}
+}
+
+func TestLoadPromptTemplatesPrefersRepoTemplates(t *testing.T) {
+	globalDir := t.TempDir()
+	repoRoot := t.TempDir()
+	repoDir := filepath.Join(repoRoot, ".droner", "prompts")
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile := func(path string, contents string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+	writeFile(filepath.Join(globalDir, "fix.md"), "---\ndescription: Global fix\n---\nFix {{issue}}\n")
+	writeFile(filepath.Join(globalDir, "explain.md"), "Explain @README.md\n")
+	writeFile(filepath.Join(globalDir, "notes.txt"), "ignored")
+	writeFile(filepath.Join(repoDir, "fix.md"), "---\ndescription: Repo fix\n---\nFix {{ issue }} in @{{file}} then {{issue}} again\n")
+
+	templates, err := loadPromptTemplates(globalDir, repoRoot)
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "explain" || templates[1].Name != "fix" {
+		t.Fatalf("templates = %#v, want explain and fix", templates)
+	}
+	if templates[1].Description != "Repo fix" {
+		t.Fatalf("fix description = %q, want the repo template", templates[1].Description)
+	}
+	if got := templates[1].placeholders(); strings.Join(got, ",") != "issue,file" {
+		t.Fatalf("placeholders = %v, want issue,file", got)
+	}
+}
+
+func TestSessionComposerTemplateFillsPlaceholdersAndKeepsFileParts(t *testing.T) {
+	model := newSessionComposerModel("/tmp/repo", []string{"pkgs/droner/tui/tui.go"}, []string{"build"})
+	model.setPromptLibrary([]promptTemplate{
+		{Name: "explain", Body: "Explain @README.md"},
+		{Name: "fix", Description: "Fix a bug", Body: "Fix {{issue}} in @{{file}}."},
+	}, nil)
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyCtrlT},
+		{Type: tea.KeyRunes, Runes: []rune("fix")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("the")},
+		{Type: tea.KeySpace, Runes: []rune(" ")},
+		{Type: tea.KeyRunes, Runes: []rune("crash")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("pkgs/droner/tui/tui.go")},
+	}
+	for _, key := range keys {
+		updated, _ := model.Update(key)
+		model = updated.(sessionComposerModel)
+	}
+	if model.templateFill == nil || !strings.Contains(model.View(), "file: pkgs/droner/tui/tui.go") {
+		t.Fatalf("expected the file placeholder prompt, got view:\n%s", model.View())
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(sessionComposerModel)
+
+	if model.templateFill != nil || model.picker != nil {
+		t.Fatalf("template fill = %#v picker = %#v, want both closed", model.templateFill, model.picker)
+	}
+	if got := model.input.Value(); got != "Fix the crash in @pkgs/droner/tui/tui.go." {
+		t.Fatalf("input = %q", got)
+	}
+	message := model.prompt.Message()
+	if len(message.Parts) != 3 || message.Parts[1].File == nil || message.Parts[1].File.Source == nil || message.Parts[1].File.Source.Path != "pkgs/droner/tui/tui.go" {
+		t.Fatalf("expected text, file and text parts, got %#v", message.Parts)
+	}
+}
+
+func TestPromptHistoryRoundTripKeepsStructuredParts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", promptHistoryFile)
+	prompt := newComposerPrompt()
+	text := "inspect @README.md and [Image 1]"
+	prompt.SetPlainText(text)
+	fileStart := strings.Index(text, "@README.md")
+	imageStart := strings.Index(text, "[Image 1]")
+	prompt.AddFileRef(fileStart, fileStart+len("@README.md"), "README.md")
+	prompt.AddStructuredPart(imageStart, imageStart+len("[Image 1]"), "[Image 1]", messages.NewDataURLFilePart("image/png", "pasted-image-1.png", "data:image/png;base64,ZmFrZQ=="))
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, entry := range []promptHistoryEntry{
+		newPromptHistoryEntry(prompt, "/tmp/repo", now),
+		{Text: "older prompt", CreatedAt: now.Add(time.Minute)},
+		newPromptHistoryEntry(prompt, "/tmp/repo", now.Add(2*time.Minute)),
+	} {
+		if err := appendPromptHistory(path, entry); err != nil {
+			t.Fatalf("append history: %v", err)
+		}
+	}
+
+	history, err := loadPromptHistory(path)
+	if err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(history) != 2 || history[0].Text != text || history[1].Text != "older prompt" {
+		t.Fatalf("history = %#v, want the latest use of each prompt newest first", history)
+	}
+	message := history[0].prompt().Message()
+	if len(message.Parts) != 3 {
+		t.Fatalf("expected text, file and text parts, got %#v", message.Parts)
+	}
+	if message.Parts[1].File == nil || message.Parts[1].File.Source == nil || message.Parts[1].File.Source.Path != "README.md" {
+		t.Fatalf("expected repo file part, got %#v", message.Parts[1])
+	}
+	if message.Parts[2].Text != " and [Image 1]" {
+		t.Fatalf("expected the image to recall as its label, got %#v", message.Parts[2])
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read history: %v", err)
+	}
+	if strings.Contains(string(contents), "base64") {
+		t.Fatalf("expected pasted images to stay out of the history file:\n%s", contents)
+	}
+}
+
+func TestPromptHistoryTrimsOnceTheFileOutgrowsItsLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), promptHistoryFile)
+	padding := strings.Repeat("x", 4*1024)
+	total := promptHistoryLimit + 100
+	for i := 0; i < total; i++ {
+		if err := appendPromptHistory(path, promptHistoryEntry{Text: fmt.Sprintf("prompt %d %s", i, padding)}); err != nil {
+			t.Fatalf("append history %d: %v", i, err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat history: %v", err)
+	}
+	if info.Size() > promptHistoryCompactBytes {
+		t.Fatalf("expected the history file trimmed below %d bytes, got %d", promptHistoryCompactBytes, info.Size())
+	}
+	history, err := loadPromptHistory(path)
+	if err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(history) == 0 || len(history) >= total {
+		t.Fatalf("expected the oldest prompts trimmed, got %d of %d", len(history), total)
+	}
+	if !strings.HasPrefix(history[0].Text, fmt.Sprintf("prompt %d ", total-1)) {
+		t.Fatalf("expected the newest prompt first, got %q", history[0].Text[:20])
+	}
+}
+
+func TestSessionComposerCtrlRRestoresHistoryPrompt(t *testing.T) {
+	prompt := newComposerPrompt()
+	prompt.SetPlainText("review @README.md")
+	prompt.AddFileRef(len("review "), len("review @README.md"), "README.md")
+	model := newSessionComposerModel("", nil, []string{"build"})
+	model.setPromptLibrary(nil, []promptHistoryEntry{
+		{Text: "write the changelog"},
+		newPromptHistoryEntry(prompt, "/tmp/droner", time.Now()),
+	})
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyCtrlR}, {Type: tea.KeyRunes, Runes: []rune("readme")}} {
+		updated, _ := model.Update(key)
+		model = updated.(sessionComposerModel)
+	}
+	if model.picker == nil || model.picker.kind != pickerKindHistory || !strings.Contains(model.View(), "review @README.md  droner") {
+		t.Fatalf("expected the history picker filtered to the review prompt, got view:\n%s", model.View())
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(sessionComposerModel)
+
+	if model.picker != nil || model.input.Value() != "review @README.md" {
+		t.Fatalf("picker = %#v input = %q, want the prompt restored", model.picker, model.input.Value())
+	}
+	message := model.prompt.Message()
+	if len(message.Parts) != 2 || message.Parts[1].File == nil || message.Parts[1].File.Source.Path != "README.md" {
+		t.Fatalf("expected the file part restored, got %#v", message.Parts)
+	}
+}
+
+func TestRankProjectsListsRecentThenMostUsed(t *testing.T) {
+	parent := t.TempDir()
+	repo := func(name string) string {
+		path := filepath.Join(parent, name)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		return path
+	}
+	alpha, beta, gamma, delta, epsilon, outside := repo("alpha"), repo("beta"), repo("gamma"), repo("delta"), repo("epsilon"), repo("outside")
+	session := func(repoPath string) schemas.SessionListItem {
+		return schemas.SessionListItem{RepoPath: repoPath}
+	}
+	// Newest first, as listed by the server.
+	sessions := []schemas.SessionListItem{
+		session(gamma), session(outside), session(gamma), session(beta),
+		session(delta), session(delta), session(delta), session(filepath.Join(parent, "removed")),
+	}
+
+	ranked := rankProjects([]string{alpha, beta, gamma, delta, epsilon}, sessions)
+	got := []string{}
+	for _, project := range ranked {
+		got = append(got, filepath.Base(project.Path))
+	}
+	if want := "gamma,outside,beta,delta,alpha,epsilon"; strings.Join(got, ",") != want {
+		t.Fatalf("ranked = %v, want %s", got, want)
+	}
+	if ranked[0].Sessions != 2 || ranked[3].Sessions != 3 {
+		t.Fatalf("session counts = %#v", ranked)
+	}
+}
+
+func TestValidateSessionBranchRejectsActiveSessionBranch(t *testing.T) {
+	branch := func(name string) *schemas.SBranch {
+		b := schemas.SBranch(name)
+		return &b
+	}
+	sessions := []schemas.SessionListItem{
+		{RepoPath: "/code/api", Branch: branch("feature/login"), State: schemas.SessionPublicStateActiveIdle},
+		{RepoPath: "/code/api", Branch: branch("old"), State: schemas.SessionPublicStateCompleted},
+		{RepoPath: "/code/web", Branch: branch("shared"), State: schemas.SessionPublicStateActiveBusy},
+	}
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{branch: "", want: ""},
+		{branch: "old", want: ""},
+		{branch: "shared", want: ""},
+		{branch: "feature.login", want: "already used by a session in api (status=active.idle)"},
+		{branch: "bad branch", want: "may only contain"},
+		{branch: "a//b", want: "may only contain"},
+	}
+	for _, tt := range tests {
+		got := validateSessionBranch("/code/api", tt.branch, sessions)
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Fatalf("validateSessionBranch(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestProjectPickerSelectsProjectAndValidatesBranch(t *testing.T) {
+	active := schemas.SBranch("busy")
+	sessions := []schemas.SessionListItem{{RepoPath: "/code/api", Branch: &active, State: schemas.SessionPublicStateActiveBusy}}
+	model := newProjectPickerModel([]projectEntry{{Path: "/code/web", recent: -1}, {Path: "/code/api", Sessions: 1}}, sessions)
+
+	update := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, key := range keys {
+			updated, _ := model.Update(key)
+			model = updated.(projectPickerModel)
+		}
+	}
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("api")}, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.picker != nil || model.repoPath != "/code/api" {
+		t.Fatalf("picker = %#v repo = %q, want api picked", model.picker, model.repoPath)
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("busy")}, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.done || !strings.Contains(model.View(), "status=active.busy") {
+		t.Fatalf("expected the branch to be rejected, got view:\n%s", model.View())
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-2")}, tea.KeyMsg{Type: tea.KeyEnter})
+	if !model.done || model.branchInput.Value() != "busy-2" {
+		t.Fatalf("done = %v branch = %q, want busy-2 accepted", model.done, model.branchInput.Value())
+	}
+}
+
+func TestSessionComposerUsesConfiguredKeymap(t *testing.T) {
+	model := newSessionComposerModel("/tmp/repo", []string{"README.md", "go.mod"}, []string{"build", "plan"})
+	model.keys = newComposerKeymap(conf.TUIKeymap{
+		Submit:             []string{"ctrl+s"},
+		Newline:            []string{"enter"},
+		CycleAgent:         []string{"ctrl+a"},
+		AutocompleteUp:     []string{"ctrl+k"},
+		AutocompleteDown:   []string{"ctrl+j"},
+		AutocompleteAccept: []string{"ctrl+y"},
+		PasteImage:         []string{"ctrl+v"},
+	})
+	update := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, key := range keys {
+			updated, _ := model.Update(key)
+			model = updated.(sessionComposerModel)
+		}
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("first")}, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyCtrlA})
+	if model.submitted || model.input.Value() != "first\n" || model.selectedAgentName() != "plan" {
+		t.Fatalf("submitted = %v input = %q agent = %q, want a newline and the next agent", model.submitted, model.input.Value(), model.selectedAgentName())
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("@")})
+	if !model.autocompleteActive || len(model.autocompleteResults) < 2 {
+		t.Fatalf("expected file suggestions, got %#v", model.autocompleteResults)
+	}
+	update(tea.KeyMsg{Type: tea.KeyCtrlJ}, tea.KeyMsg{Type: tea.KeyCtrlK}, tea.KeyMsg{Type: tea.KeyCtrlJ})
+	if model.autocompleteIndex != 1 {
+		t.Fatalf("autocomplete index = %d, want 1", model.autocompleteIndex)
+	}
+	want := "@" + model.autocompleteResults[1].Value
+	update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	if !strings.HasSuffix(model.input.Value(), want) {
+		t.Fatalf("input = %q, want it to end with %q", model.input.Value(), want)
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if !model.submitted {
+		t.Fatal("expected ctrl+s to submit")
+	}
+	if help := model.helpView(200); !strings.Contains(help, "ctrl+s submit") || !strings.Contains(help, "enter newline") {
+		t.Fatalf("help = %q, want the configured keys", help)
+	}
+}
+
+func TestResolveThemePicksPaletteAndAppliesOverrides(t *testing.T) {
+	for _, name := range conf.TUIPaletteNames {
+		if _, ok := themePalettes[name]; !ok {
+			t.Fatalf("palette %q has no colors", name)
+		}
+	}
+	dark := func() bool { return true }
+	light := func() bool { return false }
+	theme := conf.TUITheme{Name: conf.TUIThemeAuto, Dark: conf.TUIThemeSolarizedDark, Light: conf.TUIThemeLight}
+
+	if got := resolveTheme(theme, dark); got.Background != themePalettes[conf.TUIThemeSolarizedDark].Background {
+		t.Fatalf("auto on a dark terminal = %#v, want solarized-dark", got)
+	}
+	if got := resolveTheme(theme, light); got.Background != themePalettes[conf.TUIThemeLight].Background {
+		t.Fatalf("auto on a light terminal = %#v, want light", got)
+	}
+
+	theme.Name = conf.TUIThemeSolarizedLight
+	theme.Colors.Accent = "#FF0000"
+	got := resolveTheme(theme, dark)
+	if got.Accent != "#FF0000" || got.Text != themePalettes[conf.TUIThemeSolarizedLight].Text {
+		t.Fatalf("resolved = %#v, want solarized-light with a red accent", got)
+	}
+	if themePalettes[conf.TUIThemeSolarizedLight].Accent == "#FF0000" {
+		t.Fatal("overrides must not change the built-in palette")
+	}
+
+	t.Cleanup(func() { applyPalette(themePalettes[conf.TUIThemeDark]) })
+	applyPalette(got)
+	if accentColor != "#FF0000" || promptGlyphStyle.GetForeground() != accentColor {
+		t.Fatalf("accent = %q glyph = %v, want styles rebuilt from the palette", accentColor, promptGlyphStyle.GetForeground())
+	}
+}